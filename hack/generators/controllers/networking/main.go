@@ -154,6 +154,7 @@ var inputControllersNeeded = &typesNeeded{
 		RBACVerbs:                         []string{"get", "list", "watch"},
 		ConfigStatusNotificationsEnabled:  true,
 		ProgrammedConditionUpdatesEnabled: true,
+		CredentialRotationConditionUpdatesEnabled: true,
 	},
 	typeNeeded{
 		Group:                             "configuration.konghq.com",
@@ -350,6 +351,10 @@ type typeNeeded struct {
 	// resource.
 	ProgrammedConditionUpdatesEnabled bool
 
+	// CredentialRotationConditionUpdatesEnabled indicates that the controllers should manage the credential rotation
+	// condition for the resource. It requires ProgrammedConditionUpdatesEnabled.
+	CredentialRotationConditionUpdatesEnabled bool
+
 	// NeedUpdateReferences is true if we need to update the reference relationships
 	// between reconciled object and other objects.
 	NeedsUpdateReferences bool
@@ -660,6 +665,14 @@ func (r *{{.PackageAlias}}{{.Kind}}Reconciler) Reconcile(ctx context.Context, re
 		log.V(util.DebugLevel).Info("updating programmed condition status", "namespace", req.Namespace, "name", req.Name)
 		configurationStatus := r.DataplaneClient.KubernetesObjectConfigurationStatus(obj)
 		conditions, updateNeeded := ctrlutils.EnsureProgrammedCondition(configurationStatus, obj.Generation, obj.Status.Conditions)
+		fallbackStatus := r.DataplaneClient.KubernetesObjectFallbackStatus(obj)
+		conditions, excludedUpdateNeeded := ctrlutils.EnsureExcludedCondition(fallbackStatus, obj.Generation, conditions)
+		updateNeeded = updateNeeded || excludedUpdateNeeded
+		{{- if .CredentialRotationConditionUpdatesEnabled }}
+		credentialRotations := r.DataplaneClient.KubernetesObjectCredentialRotations(obj)
+		conditions, credentialRotationUpdateNeeded := ctrlutils.EnsureCredentialRotationCondition(credentialRotations, obj.Generation, conditions)
+		updateNeeded = updateNeeded || credentialRotationUpdateNeeded
+		{{- end }}
 		obj.Status.Conditions = conditions
 		{{- end }}
 		if updateNeeded {
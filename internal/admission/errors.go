@@ -12,11 +12,20 @@ const (
 	ErrTextConsumerUsernameEmpty              = "username cannot be empty"
 	ErrTextFailedToRetrieveSecret             = "could not retrieve secrets from the kubernetes API" //nolint:revive,gosec
 	ErrTextPluginConfigInvalid                = "could not parse plugin configuration"
+	ErrTextPluginConfigPatchesInvalid         = "could not apply plugin configuration patches: %s"
 	ErrTextPluginConfigValidationFailed       = "unable to validate plugin schema"
 	ErrTextPluginConfigViolatesSchema         = "plugin failed schema validation: %s"
 	ErrTextPluginNameEmpty                    = "plugin name cannot be empty"
 	ErrTextPluginSecretConfigUnretrievable    = "could not load secret plugin configuration"
 	ErrTextPluginUsesBothConfigTypes          = "plugin cannot use both Config and ConfigFrom"
+	ErrTextPluginUsesBothConfigFromAndPatches = "plugin cannot use both ConfigFrom and ConfigPatches"
+	ErrTextUpstreamPolicyInvalid              = "KongUpstreamPolicy is invalid: %s"
+	ErrTextServiceNotFound                    = "referenced service %s/%s does not exist"
+	ErrTextServiceUnretrievable               = "could not retrieve referenced service"
+	ErrTextIngressClassUnretrievable          = "could not retrieve ingress class"
+	ErrTextIngressesUnretrievable             = "could not list ingresses"
+	ErrTextRouteConflict                      = "routes exactly overlap with routes of %s"
+	ErrTextRoutesUnretrievable                = "could not list routes for conflicts detection"
 )
 
 const (
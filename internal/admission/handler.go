@@ -11,10 +11,15 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	netv1 "k8s.io/api/networking/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/kong/kubernetes-ingress-controller/v2/internal/admission/lint"
 
 	"github.com/kong/kubernetes-ingress-controller/v2/internal/gatewayapi"
 	"github.com/kong/kubernetes-ingress-controller/v2/internal/util"
 	kongv1 "github.com/kong/kubernetes-ingress-controller/v2/pkg/apis/configuration/v1"
+	kongv1alpha1 "github.com/kong/kubernetes-ingress-controller/v2/pkg/apis/configuration/v1alpha1"
 	kongv1beta1 "github.com/kong/kubernetes-ingress-controller/v2/pkg/apis/configuration/v1beta1"
 )
 
@@ -24,6 +29,9 @@ type RequestHandler struct {
 	// Validator validates the entities that the k8s API-server asks
 	// it the server to validate.
 	Validator KongValidator
+	// Linters run non-fatal checks of the validated entities. Problems they
+	// find are returned as warnings.
+	Linters []lint.Linter
 
 	Logger logr.Logger
 }
@@ -31,8 +39,20 @@ type RequestHandler struct {
 // ServeHTTP parses AdmissionReview requests and responds back
 // with the validation result of the entity.
 func (h RequestHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	serveAdmissionReview(w, r, h.Logger, "validation", h.handleValidation)
+}
+
+// serveAdmissionReview decodes an AdmissionReview request, handles it with handleFn
+// and responds back with the review containing its response.
+func serveAdmissionReview(
+	w http.ResponseWriter,
+	r *http.Request,
+	logger logr.Logger,
+	operation string,
+	handleFn func(context.Context, admissionv1.AdmissionRequest) (*admissionv1.AdmissionResponse, error),
+) {
 	if r.Body == nil {
-		h.Logger.Error(nil, "received request with empty body")
+		logger.Error(nil, "received request with empty body")
 		http.Error(w, "admission review object is missing",
 			http.StatusBadRequest)
 		return
@@ -40,20 +60,20 @@ func (h RequestHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 
 	review := admissionv1.AdmissionReview{}
 	if err := json.NewDecoder(r.Body).Decode(&review); err != nil {
-		h.Logger.Error(err, "failed to decode admission review")
+		logger.Error(err, "failed to decode admission review")
 		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
 	}
-	response, err := h.handleValidation(r.Context(), *review.Request)
+	response, err := handleFn(r.Context(), *review.Request)
 	if err != nil {
-		h.Logger.Error(err, "failed to run validation")
+		logger.Error(err, "failed to run "+operation)
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
 	review.Response = response
 
 	if err := json.NewEncoder(w).Encode(&review); err != nil {
-		h.Logger.Error(err, "failed to encode response")
+		logger.Error(err, "failed to encode response")
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
@@ -90,6 +110,26 @@ var (
 		Version:  corev1.SchemeGroupVersion.Version,
 		Resource: "secrets",
 	}
+	upstreamPolicyGVResource = metav1.GroupVersionResource{
+		Group:    kongv1beta1.SchemeGroupVersion.Group,
+		Version:  kongv1beta1.SchemeGroupVersion.Version,
+		Resource: "kongupstreampolicies",
+	}
+	tcpIngressGVResource = metav1.GroupVersionResource{
+		Group:    kongv1beta1.SchemeGroupVersion.Group,
+		Version:  kongv1beta1.SchemeGroupVersion.Version,
+		Resource: "tcpingresses",
+	}
+	udpIngressGVResource = metav1.GroupVersionResource{
+		Group:    kongv1beta1.SchemeGroupVersion.Group,
+		Version:  kongv1beta1.SchemeGroupVersion.Version,
+		Resource: "udpingresses",
+	}
+	ingressClassParametersGVResource = metav1.GroupVersionResource{
+		Group:    kongv1alpha1.SchemeGroupVersion.Group,
+		Version:  kongv1alpha1.SchemeGroupVersion.Version,
+		Resource: "ingressclassparameterses",
+	}
 	ingressGVResource = metav1.GroupVersionResource{
 		Group:    netv1.SchemeGroupVersion.Group,
 		Version:  netv1.SchemeGroupVersion.Version,
@@ -101,6 +141,7 @@ func (h RequestHandler) handleValidation(ctx context.Context, request admissionv
 	*admissionv1.AdmissionResponse, error,
 ) {
 	responseBuilder := NewResponseBuilder(request.UID)
+	h.lint(ctx, request, responseBuilder)
 
 	switch request.Resource {
 	case consumerGVResource:
@@ -117,10 +158,26 @@ func (h RequestHandler) handleValidation(ctx context.Context, request admissionv
 		return h.handleGateway(ctx, request, responseBuilder)
 	case gatewayapi.V1HTTPRouteGVResource, gatewayapi.V1beta1HTTPRouteGVResource:
 		return h.handleHTTPRoute(ctx, request, responseBuilder)
+	case gatewayapi.GRPCRouteGVResource:
+		return h.handleGRPCRoute(ctx, request, responseBuilder)
+	case gatewayapi.TCPRouteGVResource:
+		return h.handleTCPRoute(ctx, request, responseBuilder)
+	case gatewayapi.TLSRouteGVResource:
+		return h.handleTLSRoute(ctx, request, responseBuilder)
+	case gatewayapi.UDPRouteGVResource:
+		return h.handleUDPRoute(ctx, request, responseBuilder)
 	case kongIngressGVResource:
 		return h.handleKongIngress(ctx, request, responseBuilder)
 	case ingressGVResource:
 		return h.handleIngress(ctx, request, responseBuilder)
+	case upstreamPolicyGVResource:
+		return h.handleKongUpstreamPolicy(ctx, request, responseBuilder)
+	case tcpIngressGVResource:
+		return h.handleTCPIngress(ctx, request, responseBuilder)
+	case udpIngressGVResource:
+		return h.handleUDPIngress(ctx, request, responseBuilder)
+	case ingressClassParametersGVResource:
+		return h.handleIngressClassParameters(ctx, request, responseBuilder)
 	default:
 		return nil, fmt.Errorf("unknown resource type to validate: %s/%s %s",
 			request.Resource.Group, request.Resource.Version,
@@ -128,6 +185,61 @@ func (h RequestHandler) handleValidation(ctx context.Context, request admissionv
 	}
 }
 
+// lintedObjects maps resources validated by the webhook to constructors of objects they're decoded to
+// before running linters.
+var lintedObjects = map[metav1.GroupVersionResource]func() client.Object{
+	consumerGVResource:                    func() client.Object { return &kongv1.KongConsumer{} },
+	consumerGroupGVResource:               func() client.Object { return &kongv1beta1.KongConsumerGroup{} },
+	pluginGVResource:                      func() client.Object { return &kongv1.KongPlugin{} },
+	clusterPluginGVResource:               func() client.Object { return &kongv1.KongClusterPlugin{} },
+	secretGVResource:                      func() client.Object { return &corev1.Secret{} },
+	gatewayapi.V1GatewayGVResource:        func() client.Object { return &gatewayapi.Gateway{} },
+	gatewayapi.V1beta1GatewayGVResource:   func() client.Object { return &gatewayapi.Gateway{} },
+	gatewayapi.V1HTTPRouteGVResource:      func() client.Object { return &gatewayapi.HTTPRoute{} },
+	gatewayapi.V1beta1HTTPRouteGVResource: func() client.Object { return &gatewayapi.HTTPRoute{} },
+	gatewayapi.GRPCRouteGVResource:        func() client.Object { return &gatewayapi.GRPCRoute{} },
+	gatewayapi.TCPRouteGVResource:         func() client.Object { return &gatewayapi.TCPRoute{} },
+	gatewayapi.TLSRouteGVResource:         func() client.Object { return &gatewayapi.TLSRoute{} },
+	gatewayapi.UDPRouteGVResource:         func() client.Object { return &gatewayapi.UDPRoute{} },
+	kongIngressGVResource:                 func() client.Object { return &kongv1.KongIngress{} },
+	ingressGVResource:                     func() client.Object { return &netv1.Ingress{} },
+	upstreamPolicyGVResource:              func() client.Object { return &kongv1beta1.KongUpstreamPolicy{} },
+	tcpIngressGVResource:                  func() client.Object { return &kongv1beta1.TCPIngress{} },
+	udpIngressGVResource:                  func() client.Object { return &kongv1beta1.UDPIngress{} },
+	ingressClassParametersGVResource:      func() client.Object { return &kongv1alpha1.IngressClassParameters{} },
+}
+
+// lint runs the linters against the object from the request and adds the problems they
+// find to the response as warnings. Linter failures are logged and don't affect the response.
+func (h RequestHandler) lint(ctx context.Context, request admissionv1.AdmissionRequest, responseBuilder *ResponseBuilder) {
+	newObject, ok := lintedObjects[request.Resource]
+	if !ok || len(h.Linters) == 0 || len(request.Object.Raw) == 0 {
+		return
+	}
+	obj := newObject()
+	if _, _, err := codecs.UniversalDeserializer().Decode(request.Object.Raw, nil, obj); err != nil {
+		// Decoding errors are reported by the handlers.
+		return
+	}
+	obj.GetObjectKind().SetGroupVersionKind(schema.GroupVersionKind{
+		Group:   request.Kind.Group,
+		Version: request.Kind.Version,
+		Kind:    request.Kind.Kind,
+	})
+
+	for _, linter := range h.Linters {
+		warnings, err := linter.Lint(ctx, obj)
+		if err != nil {
+			h.Logger.Error(err, "failed to lint object", "kind", request.Kind.Kind,
+				"namespace", obj.GetNamespace(), "name", obj.GetName())
+			continue
+		}
+		for _, warning := range warnings {
+			responseBuilder.WithWarning(warning)
+		}
+	}
+}
+
 func (h RequestHandler) handleKongConsumer(
 	ctx context.Context,
 	request admissionv1.AdmissionRequest,
@@ -281,7 +393,82 @@ func (h RequestHandler) handleHTTPRoute(
 	if err != nil {
 		return nil, err
 	}
-	ok, message, err := h.Validator.ValidateHTTPRoute(ctx, httproute)
+	ok, message, warnings, err := h.Validator.ValidateHTTPRoute(ctx, httproute)
+	if err != nil {
+		return nil, err
+	}
+	for _, warning := range warnings {
+		responseBuilder = responseBuilder.WithWarning(warning)
+	}
+
+	return responseBuilder.Allowed(ok).WithMessage(message).Build(), nil
+}
+
+func (h RequestHandler) handleGRPCRoute(
+	ctx context.Context,
+	request admissionv1.AdmissionRequest,
+	responseBuilder *ResponseBuilder,
+) (*admissionv1.AdmissionResponse, error) {
+	grpcroute := gatewayapi.GRPCRoute{}
+	_, _, err := codecs.UniversalDeserializer().Decode(request.Object.Raw, nil, &grpcroute)
+	if err != nil {
+		return nil, err
+	}
+	ok, message, err := h.Validator.ValidateGRPCRoute(ctx, grpcroute)
+	if err != nil {
+		return nil, err
+	}
+
+	return responseBuilder.Allowed(ok).WithMessage(message).Build(), nil
+}
+
+func (h RequestHandler) handleTCPRoute(
+	ctx context.Context,
+	request admissionv1.AdmissionRequest,
+	responseBuilder *ResponseBuilder,
+) (*admissionv1.AdmissionResponse, error) {
+	tcproute := gatewayapi.TCPRoute{}
+	_, _, err := codecs.UniversalDeserializer().Decode(request.Object.Raw, nil, &tcproute)
+	if err != nil {
+		return nil, err
+	}
+	ok, message, err := h.Validator.ValidateTCPRoute(ctx, tcproute)
+	if err != nil {
+		return nil, err
+	}
+
+	return responseBuilder.Allowed(ok).WithMessage(message).Build(), nil
+}
+
+func (h RequestHandler) handleTLSRoute(
+	ctx context.Context,
+	request admissionv1.AdmissionRequest,
+	responseBuilder *ResponseBuilder,
+) (*admissionv1.AdmissionResponse, error) {
+	tlsroute := gatewayapi.TLSRoute{}
+	_, _, err := codecs.UniversalDeserializer().Decode(request.Object.Raw, nil, &tlsroute)
+	if err != nil {
+		return nil, err
+	}
+	ok, message, err := h.Validator.ValidateTLSRoute(ctx, tlsroute)
+	if err != nil {
+		return nil, err
+	}
+
+	return responseBuilder.Allowed(ok).WithMessage(message).Build(), nil
+}
+
+func (h RequestHandler) handleUDPRoute(
+	ctx context.Context,
+	request admissionv1.AdmissionRequest,
+	responseBuilder *ResponseBuilder,
+) (*admissionv1.AdmissionResponse, error) {
+	udproute := gatewayapi.UDPRoute{}
+	_, _, err := codecs.UniversalDeserializer().Decode(request.Object.Raw, nil, &udproute)
+	if err != nil {
+		return nil, err
+	}
+	ok, message, err := h.Validator.ValidateUDPRoute(ctx, udproute)
 	if err != nil {
 		return nil, err
 	}
@@ -318,7 +505,82 @@ func (h RequestHandler) handleIngress(ctx context.Context, request admissionv1.A
 	if err != nil {
 		return nil, err
 	}
-	ok, message, err := h.Validator.ValidateIngress(ctx, ingress)
+	ok, message, warnings, err := h.Validator.ValidateIngress(ctx, ingress)
+	if err != nil {
+		return nil, err
+	}
+	for _, warning := range warnings {
+		responseBuilder = responseBuilder.WithWarning(warning)
+	}
+
+	return responseBuilder.Allowed(ok).WithMessage(message).Build(), nil
+}
+
+func (h RequestHandler) handleKongUpstreamPolicy(
+	ctx context.Context,
+	request admissionv1.AdmissionRequest,
+	responseBuilder *ResponseBuilder,
+) (*admissionv1.AdmissionResponse, error) {
+	policy := kongv1beta1.KongUpstreamPolicy{}
+	_, _, err := codecs.UniversalDeserializer().Decode(request.Object.Raw, nil, &policy)
+	if err != nil {
+		return nil, err
+	}
+	ok, message, err := h.Validator.ValidateUpstreamPolicy(ctx, policy)
+	if err != nil {
+		return nil, err
+	}
+
+	return responseBuilder.Allowed(ok).WithMessage(message).Build(), nil
+}
+
+func (h RequestHandler) handleTCPIngress(
+	ctx context.Context,
+	request admissionv1.AdmissionRequest,
+	responseBuilder *ResponseBuilder,
+) (*admissionv1.AdmissionResponse, error) {
+	tcpIngress := kongv1beta1.TCPIngress{}
+	_, _, err := codecs.UniversalDeserializer().Decode(request.Object.Raw, nil, &tcpIngress)
+	if err != nil {
+		return nil, err
+	}
+	ok, message, err := h.Validator.ValidateTCPIngress(ctx, tcpIngress)
+	if err != nil {
+		return nil, err
+	}
+
+	return responseBuilder.Allowed(ok).WithMessage(message).Build(), nil
+}
+
+func (h RequestHandler) handleUDPIngress(
+	ctx context.Context,
+	request admissionv1.AdmissionRequest,
+	responseBuilder *ResponseBuilder,
+) (*admissionv1.AdmissionResponse, error) {
+	udpIngress := kongv1beta1.UDPIngress{}
+	_, _, err := codecs.UniversalDeserializer().Decode(request.Object.Raw, nil, &udpIngress)
+	if err != nil {
+		return nil, err
+	}
+	ok, message, err := h.Validator.ValidateUDPIngress(ctx, udpIngress)
+	if err != nil {
+		return nil, err
+	}
+
+	return responseBuilder.Allowed(ok).WithMessage(message).Build(), nil
+}
+
+func (h RequestHandler) handleIngressClassParameters(
+	ctx context.Context,
+	request admissionv1.AdmissionRequest,
+	responseBuilder *ResponseBuilder,
+) (*admissionv1.AdmissionResponse, error) {
+	params := kongv1alpha1.IngressClassParameters{}
+	_, _, err := codecs.UniversalDeserializer().Decode(request.Object.Raw, nil, &params)
+	if err != nil {
+		return nil, err
+	}
+	ok, message, err := h.Validator.ValidateIngressClassParameters(ctx, params)
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,93 @@
+package lint
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/samber/lo"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/kong/kubernetes-ingress-controller/v2/internal/annotations"
+)
+
+// RegexPriorityLinter warns about the konghq.com/regex-priority annotation being used when Kong routes are
+// translated to expressions, as the expressions router ignores it.
+type RegexPriorityLinter struct {
+	ExpressionRoutes bool
+}
+
+func (l *RegexPriorityLinter) Lint(_ context.Context, obj client.Object) ([]string, error) {
+	if !l.ExpressionRoutes {
+		return nil, nil
+	}
+	key := annotations.AnnotationPrefix + annotations.RegexPriorityKey
+	if _, ok := obj.GetAnnotations()[key]; !ok {
+		return nil, nil
+	}
+	return []string{fmt.Sprintf("%s annotation has no effect with the expressions router.", key)}, nil
+}
+
+var (
+	serviceKinds = []string{"Service"}
+	routeKinds   = []string{
+		"Ingress", "TCPIngress", "UDPIngress",
+		"HTTPRoute", "GRPCRoute", "TCPRoute", "TLSRoute", "UDPRoute",
+	}
+
+	// annotationKinds maps konghq.com annotations to kinds of objects they have effect on.
+	annotationKinds = map[string][]string{
+		annotations.ProtocolKey:       serviceKinds,
+		annotations.PathKey:           serviceKinds,
+		annotations.HostHeaderKey:     serviceKinds,
+		annotations.ClientCertKey:     serviceKinds,
+		annotations.ConnectTimeoutKey: serviceKinds,
+		annotations.ReadTimeoutKey:    serviceKinds,
+		annotations.WriteTimeoutKey:   serviceKinds,
+		annotations.RetriesKey:        serviceKinds,
+
+		annotations.ProtocolsKey:         routeKinds,
+		annotations.StripPathKey:         routeKinds,
+		annotations.HTTPSRedirectCodeKey: routeKinds,
+		annotations.PreserveHostKey:      routeKinds,
+		annotations.RegexPriorityKey:     routeKinds,
+		annotations.MethodsKey:           routeKinds,
+		annotations.SNIsKey:              routeKinds,
+		annotations.RequestBuffering:     routeKinds,
+		annotations.ResponseBuffering:    routeKinds,
+		annotations.HostAliasesKey:       routeKinds,
+		annotations.PathHandlingKey:      routeKinds,
+
+		annotations.RegexPrefixKey: {"Ingress"},
+		annotations.RewriteURIKey:  {"Ingress"},
+
+		annotations.SessionPersistenceCookieKey:     {"HTTPRoute"},
+		annotations.SessionPersistenceCookiePathKey: {"HTTPRoute"},
+		annotations.SessionPersistenceHeaderKey:     {"HTTPRoute"},
+		annotations.SessionPersistenceRulesKey:      {"HTTPRoute"},
+	}
+)
+
+// AnnotationsKind warns about konghq.com annotations set on kinds of objects they have no effect on.
+func AnnotationsKind(_ context.Context, obj client.Object) ([]string, error) {
+	kind := obj.GetObjectKind().GroupVersionKind().Kind
+	var warnings []string
+	for key := range obj.GetAnnotations() {
+		if !strings.HasPrefix(key, annotations.AnnotationPrefix+"/") {
+			continue
+		}
+		kinds, ok := annotationKinds[strings.TrimPrefix(key, annotations.AnnotationPrefix)]
+		if !ok && strings.HasPrefix(key, annotations.AnnotationPrefix+annotations.HeadersKey+".") {
+			kinds, ok = routeKinds, true
+		}
+		if !ok || lo.Contains(kinds, kind) {
+			continue
+		}
+		warnings = append(warnings, fmt.Sprintf(
+			"%s annotation has no effect on %s, it is supported on: %s.", key, kind, strings.Join(kinds, ", "),
+		))
+	}
+	sort.Strings(warnings)
+	return warnings, nil
+}
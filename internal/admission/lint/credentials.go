@@ -0,0 +1,29 @@
+package lint
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/kong/kubernetes-ingress-controller/v2/internal/labels"
+	"github.com/kong/kubernetes-ingress-controller/v2/internal/util"
+)
+
+// DeprecatedCredentialTypeField warns about credential Secrets using the deprecated kongCredType field
+// instead of the credential label.
+func DeprecatedCredentialTypeField(_ context.Context, obj client.Object) ([]string, error) {
+	secret, ok := obj.(*corev1.Secret)
+	if !ok {
+		return nil, nil
+	}
+	credType, source := util.ExtractKongCredentialType(secret)
+	if source != util.CredentialTypeFromField {
+		return nil, nil
+	}
+	return []string{fmt.Sprintf(
+		"'kongCredType' field is DEPRECATED. Use the %s=%s label instead.",
+		labels.LabelPrefix+labels.CredentialKey, credType,
+	)}, nil
+}
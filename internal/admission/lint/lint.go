@@ -0,0 +1,36 @@
+// Package lint includes non-fatal checks of objects validated by the admission webhook.
+// Problems found by them don't prevent objects from being admitted, but are returned to
+// the user applying them as warnings.
+package lint
+
+import (
+	"context"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/kong/kubernetes-ingress-controller/v2/internal/dataplane/parser"
+)
+
+// Linter checks an object validated by the admission webhook and returns warnings describing
+// problems that should not block it from being admitted. The object's GroupVersionKind is always set.
+type Linter interface {
+	Lint(ctx context.Context, obj client.Object) ([]string, error)
+}
+
+// LinterFunc is a function implementing the Linter interface.
+type LinterFunc func(ctx context.Context, obj client.Object) ([]string, error)
+
+// Lint calls f(ctx, obj).
+func (f LinterFunc) Lint(ctx context.Context, obj client.Object) ([]string, error) {
+	return f(ctx, obj)
+}
+
+// DefaultLinters returns the linters run by the admission webhook.
+func DefaultLinters(managerClient client.Reader, parserFeatures parser.FeatureFlags) []Linter {
+	return []Linter{
+		LinterFunc(DeprecatedCredentialTypeField),
+		&PluginSecretsLinter{Client: managerClient},
+		&RegexPriorityLinter{ExpressionRoutes: parserFeatures.ExpressionRoutes},
+		LinterFunc(AnnotationsKind),
+	}
+}
@@ -0,0 +1,124 @@
+package lint
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	netv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	fakeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	kongv1 "github.com/kong/kubernetes-ingress-controller/v2/pkg/apis/configuration/v1"
+)
+
+func TestDeprecatedCredentialTypeField(t *testing.T) {
+	withField := &corev1.Secret{Data: map[string][]byte{"kongCredType": []byte("key-auth")}}
+	warnings, err := DeprecatedCredentialTypeField(context.Background(), withField)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"'kongCredType' field is DEPRECATED. Use the konghq.com/credential=key-auth label instead."}, warnings)
+
+	withLabel := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"konghq.com/credential": "key-auth"}},
+		Data:       map[string][]byte{"kongCredType": []byte("key-auth")},
+	}
+	warnings, err = DeprecatedCredentialTypeField(context.Background(), withLabel)
+	require.NoError(t, err)
+	assert.Empty(t, warnings)
+}
+
+func TestPluginSecretsLinter(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, corev1.AddToScheme(scheme))
+	linter := &PluginSecretsLinter{
+		Client: fakeclient.NewClientBuilder().WithScheme(scheme).WithObjects(&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "conf"},
+			Data:       map[string][]byte{"config": []byte(`{}`)},
+		}).Build(),
+	}
+
+	testCases := []struct {
+		name     string
+		plugin   client.Object
+		expected []string
+	}{
+		{
+			name: "KongPlugin referencing an existing Secret",
+			plugin: &kongv1.KongPlugin{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "plugin"},
+				ConfigFrom: &kongv1.ConfigSource{SecretValue: kongv1.SecretValueFromSource{Secret: "conf", Key: "config"}},
+			},
+		},
+		{
+			name: "KongPlugin referencing a missing Secret",
+			plugin: &kongv1.KongPlugin{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "plugin"},
+				ConfigFrom: &kongv1.ConfigSource{SecretValue: kongv1.SecretValueFromSource{Secret: "missing", Key: "config"}},
+			},
+			expected: []string{
+				"configFrom references Secret default/missing which does not exist, the plugin will not be configured until it is created",
+			},
+		},
+		{
+			name: "KongClusterPlugin referencing a missing key",
+			plugin: &kongv1.KongClusterPlugin{
+				ObjectMeta: metav1.ObjectMeta{Name: "plugin"},
+				ConfigFrom: &kongv1.NamespacedConfigSource{SecretValue: kongv1.NamespacedSecretValueFromSource{
+					Namespace: "default", Secret: "conf", Key: "other",
+				}},
+			},
+			expected: []string{`configFrom references key "other" which does not exist in Secret default/conf`},
+		},
+		{
+			name:   "KongPlugin without configFrom",
+			plugin: &kongv1.KongPlugin{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "plugin"}},
+		},
+	}
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			warnings, err := linter.Lint(context.Background(), tc.plugin)
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, warnings)
+		})
+	}
+}
+
+func TestRegexPriorityLinter(t *testing.T) {
+	ingress := &netv1.Ingress{ObjectMeta: metav1.ObjectMeta{
+		Annotations: map[string]string{"konghq.com/regex-priority": "10"},
+	}}
+
+	warnings, err := (&RegexPriorityLinter{ExpressionRoutes: true}).Lint(context.Background(), ingress)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"konghq.com/regex-priority annotation has no effect with the expressions router."}, warnings)
+
+	warnings, err = (&RegexPriorityLinter{ExpressionRoutes: false}).Lint(context.Background(), ingress)
+	require.NoError(t, err)
+	assert.Empty(t, warnings)
+}
+
+func TestAnnotationsKind(t *testing.T) {
+	ingress := &netv1.Ingress{
+		TypeMeta: metav1.TypeMeta{APIVersion: "networking.k8s.io/v1", Kind: "Ingress"},
+		ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{
+			"konghq.com/strip-path":     "true",
+			"konghq.com/headers.x-test": "value",
+			"konghq.com/protocol":       "https",
+			"konghq.com/read-timeout":   "1000",
+			"konghq.com/unknown":        "value",
+			"example.com/protocol":      "https",
+		}},
+	}
+
+	warnings, err := AnnotationsKind(context.Background(), ingress)
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		"konghq.com/protocol annotation has no effect on Ingress, it is supported on: Service.",
+		"konghq.com/read-timeout annotation has no effect on Ingress, it is supported on: Service.",
+	}, warnings)
+}
@@ -0,0 +1,63 @@
+package lint
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	k8stypes "k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	kongv1 "github.com/kong/kubernetes-ingress-controller/v2/pkg/apis/configuration/v1"
+)
+
+// PluginSecretsLinter warns about KongPlugins and KongClusterPlugins referencing Secrets (or their keys) that
+// don't exist (yet). Such plugins are not configured in Kong until the Secrets are created.
+type PluginSecretsLinter struct {
+	Client client.Reader
+}
+
+func (l *PluginSecretsLinter) Lint(ctx context.Context, obj client.Object) ([]string, error) {
+	var (
+		secretRef k8stypes.NamespacedName
+		key       string
+	)
+	switch plugin := obj.(type) {
+	case *kongv1.KongPlugin:
+		if plugin.ConfigFrom == nil {
+			return nil, nil
+		}
+		secretRef = k8stypes.NamespacedName{Namespace: plugin.Namespace, Name: plugin.ConfigFrom.SecretValue.Secret}
+		key = plugin.ConfigFrom.SecretValue.Key
+	case *kongv1.KongClusterPlugin:
+		if plugin.ConfigFrom == nil {
+			return nil, nil
+		}
+		secretRef = k8stypes.NamespacedName{
+			Namespace: plugin.ConfigFrom.SecretValue.Namespace,
+			Name:      plugin.ConfigFrom.SecretValue.Secret,
+		}
+		key = plugin.ConfigFrom.SecretValue.Key
+	default:
+		return nil, nil
+	}
+	if secretRef.Name == "" {
+		return nil, nil
+	}
+
+	var secret corev1.Secret
+	if err := l.Client.Get(ctx, secretRef, &secret); err != nil {
+		if apierrors.IsNotFound(err) {
+			return []string{fmt.Sprintf(
+				"configFrom references Secret %s which does not exist, the plugin will not be configured until it is created",
+				secretRef,
+			)}, nil
+		}
+		return nil, fmt.Errorf("failed to get Secret %s: %w", secretRef, err)
+	}
+	if _, ok := secret.Data[key]; !ok {
+		return []string{fmt.Sprintf("configFrom references key %q which does not exist in Secret %s", key, secretRef)}, nil
+	}
+	return nil, nil
+}
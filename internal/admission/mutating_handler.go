@@ -0,0 +1,258 @@
+package admission
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/go-logr/logr"
+	"github.com/kong/go-kong/kong"
+	"github.com/samber/lo"
+	"gomodules.xyz/jsonpatch/v2"
+	admissionv1 "k8s.io/api/admission/v1"
+	corev1 "k8s.io/api/core/v1"
+	netv1 "k8s.io/api/networking/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8stypes "k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/kong/kubernetes-ingress-controller/v2/internal/annotations"
+	"github.com/kong/kubernetes-ingress-controller/v2/internal/dataplane/kongstate"
+	"github.com/kong/kubernetes-ingress-controller/v2/internal/dataplane/parser"
+	"github.com/kong/kubernetes-ingress-controller/v2/internal/dataplane/parser/translators"
+	"github.com/kong/kubernetes-ingress-controller/v2/internal/labels"
+	"github.com/kong/kubernetes-ingress-controller/v2/internal/util"
+	kongv1alpha1 "github.com/kong/kubernetes-ingress-controller/v2/pkg/apis/configuration/v1alpha1"
+)
+
+// MutatingWebhookPath is the path the mutating admission webhook is served at.
+const MutatingWebhookPath = "/mutate"
+
+// MutatingRequestHandler is an HTTP server that normalizes deprecated forms of Kong Ingress
+// Controller's configuration using Kubernetes Mutating Admission Webhooks, so that stored
+// objects show what the controller does with them:
+//   - credential Secrets using the kongCredType field get the konghq.com/credential label instead,
+//   - Ingress paths treated as regexes by the legacy regex detection get the regex prefix,
+//   - Ingresses get the konghq.com/effective-route-settings annotation recording settings of
+//     Kong routes generated from them, including defaults.
+type MutatingRequestHandler struct {
+	ManagerClient  client.Client
+	ParserFeatures parser.FeatureFlags
+	Logger         logr.Logger
+
+	ingressClassName      string
+	ingressClassMatcher   func(*metav1.ObjectMeta, string, annotations.ClassMatching) bool
+	ingressV1ClassMatcher func(*netv1.Ingress, annotations.ClassMatching) bool
+}
+
+// NewMutatingRequestHandler provides a new MutatingRequestHandler mutating objects of the given ingress class.
+func NewMutatingRequestHandler(
+	logger logr.Logger,
+	managerClient client.Client,
+	ingressClass string,
+	parserFeatures parser.FeatureFlags,
+) MutatingRequestHandler {
+	return MutatingRequestHandler{
+		ManagerClient:  managerClient,
+		ParserFeatures: parserFeatures,
+		Logger:         logger,
+
+		ingressClassName:      ingressClass,
+		ingressClassMatcher:   annotations.IngressClassValidatorFuncFromObjectMeta(ingressClass),
+		ingressV1ClassMatcher: annotations.IngressClassValidatorFuncFromV1Ingress(ingressClass),
+	}
+}
+
+// ServeHTTP parses AdmissionReview requests and responds back
+// with patches normalizing the entity.
+func (h MutatingRequestHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	serveAdmissionReview(w, r, h.Logger, "mutation", h.handleMutation)
+}
+
+func (h MutatingRequestHandler) handleMutation(ctx context.Context, request admissionv1.AdmissionRequest) (
+	*admissionv1.AdmissionResponse, error,
+) {
+	// Mutations never reject objects.
+	responseBuilder := NewResponseBuilder(request.UID).Allowed(true)
+	if request.Operation != admissionv1.Create && request.Operation != admissionv1.Update {
+		return responseBuilder.Build(), nil
+	}
+
+	var (
+		mutated []byte
+		err     error
+	)
+	switch request.Resource {
+	case secretGVResource:
+		mutated, err = h.mutateSecret(request.Object.Raw)
+	case ingressGVResource:
+		mutated, err = h.mutateIngress(ctx, request.Object.Raw)
+	default:
+		return responseBuilder.Build(), nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if mutated == nil {
+		return responseBuilder.Build(), nil
+	}
+
+	patch, err := jsonpatch.CreatePatch(request.Object.Raw, mutated)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create patch: %w", err)
+	}
+	if len(patch) == 0 {
+		return responseBuilder.Build(), nil
+	}
+	patchBytes, err := json.Marshal(patch)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal patch: %w", err)
+	}
+	return responseBuilder.WithPatch(patchBytes).Build(), nil
+}
+
+// mutateSecret replaces the deprecated kongCredType field of a credential Secret with the credential label.
+// It returns nil if the Secret doesn't need to be mutated.
+func (h MutatingRequestHandler) mutateSecret(raw []byte) ([]byte, error) {
+	secret := corev1.Secret{}
+	if _, _, err := codecs.UniversalDeserializer().Decode(raw, nil, &secret); err != nil {
+		return nil, err
+	}
+	credType, source := util.ExtractKongCredentialType(&secret)
+	if source != util.CredentialTypeFromField {
+		return nil, nil
+	}
+
+	if secret.Labels == nil {
+		secret.Labels = map[string]string{}
+	}
+	secret.Labels[labels.LabelPrefix+labels.CredentialKey] = credType
+	delete(secret.Data, "kongCredType")
+	return json.Marshal(secret)
+}
+
+// mutateIngress adds the regex prefix to paths of an Ingress detected as regexes by the legacy regex
+// detection and records the effective settings of its Kong routes. It returns nil if the Ingress is not
+// managed by the controller.
+func (h MutatingRequestHandler) mutateIngress(ctx context.Context, raw []byte) ([]byte, error) {
+	ingress := netv1.Ingress{}
+	if _, _, err := codecs.UniversalDeserializer().Decode(raw, nil, &ingress); err != nil {
+		return nil, err
+	}
+	if !h.ingressClassMatcher(&ingress.ObjectMeta, annotations.IngressClassKey, annotations.ExactClassMatch) &&
+		!h.ingressV1ClassMatcher(&ingress, annotations.ExactClassMatch) {
+		return nil, nil
+	}
+
+	icp, err := h.ingressClassParameters(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if icp.EnableLegacyRegexDetection {
+		prefixLegacyRegexPaths(&ingress)
+	}
+
+	settings, err := h.effectiveRouteSettings(&ingress, icp)
+	if err != nil {
+		return nil, err
+	}
+	if settings != "" {
+		if ingress.Annotations == nil {
+			ingress.Annotations = map[string]string{}
+		}
+		ingress.Annotations[annotations.AnnotationPrefix+annotations.EffectiveRouteSettingsKey] = settings
+	}
+	return json.Marshal(ingress)
+}
+
+// ingressClassParameters returns the spec of IngressClassParameters referenced by the controller's IngressClass.
+// Defaults are returned when there's no such IngressClass or it doesn't reference IngressClassParameters.
+func (h MutatingRequestHandler) ingressClassParameters(ctx context.Context) (kongv1alpha1.IngressClassParametersSpec, error) {
+	var ingressClass netv1.IngressClass
+	if err := h.ManagerClient.Get(ctx, client.ObjectKey{Name: h.ingressClassName}, &ingressClass); err != nil {
+		if apierrors.IsNotFound(err) {
+			return kongv1alpha1.IngressClassParametersSpec{}, nil
+		}
+		return kongv1alpha1.IngressClassParametersSpec{}, fmt.Errorf("failed to get IngressClass %s: %w", h.ingressClassName, err)
+	}
+	ref := ingressClass.Spec.Parameters
+	if ref == nil ||
+		lo.FromPtr(ref.APIGroup) != kongv1alpha1.GroupVersion.Group ||
+		ref.Kind != kongv1alpha1.IngressClassParametersKind {
+		return kongv1alpha1.IngressClassParametersSpec{}, nil
+	}
+
+	var params kongv1alpha1.IngressClassParameters
+	key := k8stypes.NamespacedName{Namespace: lo.FromPtr(ref.Namespace), Name: ref.Name}
+	if err := h.ManagerClient.Get(ctx, key, &params); err != nil {
+		if apierrors.IsNotFound(err) {
+			return kongv1alpha1.IngressClassParametersSpec{}, nil
+		}
+		return kongv1alpha1.IngressClassParametersSpec{}, fmt.Errorf("failed to get IngressClassParameters %s: %w", key, err)
+	}
+	return params.Spec, nil
+}
+
+// prefixLegacyRegexPaths prepends the regex prefix to ImplementationSpecific paths of an Ingress that the legacy
+// regex detection treats as regexes, so that they're translated the same way once the detection is disabled.
+func prefixLegacyRegexPaths(ingress *netv1.Ingress) {
+	regexPrefix := translators.ControllerPathRegexPrefix
+	if prefix, ok := ingress.Annotations[annotations.AnnotationPrefix+annotations.RegexPrefixKey]; ok {
+		regexPrefix = prefix
+	}
+
+	for i := range ingress.Spec.Rules {
+		if ingress.Spec.Rules[i].HTTP == nil {
+			continue
+		}
+		paths := ingress.Spec.Rules[i].HTTP.Paths
+		for j := range paths {
+			if paths[j].PathType == nil || *paths[j].PathType != netv1.PathTypeImplementationSpecific || paths[j].Path == "" {
+				continue
+			}
+			path := paths[j].Path
+			if translators.MaybePrependRegexPrefix(path, regexPrefix, true) != translators.MaybePrependRegexPrefix(path, regexPrefix, false) &&
+				!strings.HasPrefix(path, translators.KongPathRegexPrefix) {
+				paths[j].Path = regexPrefix + path
+			}
+		}
+	}
+}
+
+// effectiveRouteSettings returns JSON encoded settings of the Kong routes generated from an Ingress,
+// the way they're sent to Kong. It returns an empty string if no routes are generated from the Ingress.
+func (h MutatingRequestHandler) effectiveRouteSettings(
+	ingress *netv1.Ingress, icp kongv1alpha1.IngressClassParametersSpec,
+) (string, error) {
+	services := parser.IngressesV1ToKongServices(
+		h.ParserFeatures,
+		[]*netv1.Ingress{ingress},
+		icp,
+		&parser.ObjectsCollector{}, // It's irrelevant for mutation.
+	)
+	for _, svc := range services {
+		for _, route := range svc.Routes {
+			route := route
+			kongstate.OverrideRoute(h.Logger, &route)
+			b, err := json.Marshal(kong.Route{
+				Protocols:               route.Protocols,
+				StripPath:               route.StripPath,
+				PreserveHost:            route.PreserveHost,
+				HTTPSRedirectStatusCode: route.HTTPSRedirectStatusCode,
+				RegexPriority:           route.RegexPriority,
+				PathHandling:            route.PathHandling,
+				RequestBuffering:        route.RequestBuffering,
+				ResponseBuffering:       route.ResponseBuffering,
+			})
+			if err != nil {
+				return "", fmt.Errorf("failed to marshal route settings: %w", err)
+			}
+			// Settings controlled by annotations are the same for all routes of an Ingress.
+			return string(b), nil
+		}
+	}
+	return "", nil
+}
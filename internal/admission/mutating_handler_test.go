@@ -0,0 +1,151 @@
+package admission
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/go-logr/logr"
+	"github.com/samber/lo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gomodules.xyz/jsonpatch/v2"
+	admissionv1 "k8s.io/api/admission/v1"
+	corev1 "k8s.io/api/core/v1"
+	netv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	fakeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/kong/kubernetes-ingress-controller/v2/internal/dataplane/parser"
+	kongv1alpha1 "github.com/kong/kubernetes-ingress-controller/v2/pkg/apis/configuration/v1alpha1"
+)
+
+func TestMutatingRequestHandler(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, netv1.AddToScheme(scheme))
+	require.NoError(t, kongv1alpha1.AddToScheme(scheme))
+
+	ingressClass := &netv1.IngressClass{
+		ObjectMeta: metav1.ObjectMeta{Name: "kong"},
+		Spec: netv1.IngressClassSpec{
+			Controller: "ingress-controllers.konghq.com/kong",
+			Parameters: &netv1.IngressClassParametersReference{
+				APIGroup:  lo.ToPtr(kongv1alpha1.GroupVersion.Group),
+				Kind:      kongv1alpha1.IngressClassParametersKind,
+				Name:      "params",
+				Scope:     lo.ToPtr(netv1.IngressClassParametersReferenceScopeNamespace),
+				Namespace: lo.ToPtr("kong"),
+			},
+		},
+	}
+	params := &kongv1alpha1.IngressClassParameters{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "kong", Name: "params"},
+		Spec:       kongv1alpha1.IngressClassParametersSpec{EnableLegacyRegexDetection: true},
+	}
+	newIngress := func(class string) *netv1.Ingress {
+		return &netv1.Ingress{
+			TypeMeta:   metav1.TypeMeta{APIVersion: "networking.k8s.io/v1", Kind: "Ingress"},
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "ingress"},
+			Spec: netv1.IngressSpec{
+				IngressClassName: lo.ToPtr(class),
+				Rules: []netv1.IngressRule{{
+					IngressRuleValue: netv1.IngressRuleValue{HTTP: &netv1.HTTPIngressRuleValue{
+						Paths: []netv1.HTTPIngressPath{
+							{
+								Path:     "/users/[0-9]+",
+								PathType: lo.ToPtr(netv1.PathTypeImplementationSpecific),
+								Backend: netv1.IngressBackend{Service: &netv1.IngressServiceBackend{
+									Name: "svc", Port: netv1.ServiceBackendPort{Number: 80},
+								}},
+							},
+							{
+								Path:     "/static",
+								PathType: lo.ToPtr(netv1.PathTypeImplementationSpecific),
+								Backend: netv1.IngressBackend{Service: &netv1.IngressServiceBackend{
+									Name: "svc", Port: netv1.ServiceBackendPort{Number: 80},
+								}},
+							},
+						},
+					}},
+				}},
+			},
+		}
+	}
+
+	newHandler := func(objs ...client.Object) MutatingRequestHandler {
+		return NewMutatingRequestHandler(
+			logr.Discard(),
+			fakeclient.NewClientBuilder().WithScheme(scheme).WithObjects(objs...).Build(),
+			"kong",
+			parser.FeatureFlags{},
+		)
+	}
+	mutate := func(t *testing.T, h MutatingRequestHandler, resource metav1.GroupVersionResource, operation admissionv1.Operation, obj any) []jsonpatch.Operation {
+		raw, err := json.Marshal(obj)
+		require.NoError(t, err)
+		response, err := h.handleMutation(context.Background(), admissionv1.AdmissionRequest{
+			UID:       "b2df61dd-ab5b-4cb4-9be0-878533c83892",
+			Resource:  resource,
+			Operation: operation,
+			Object:    runtime.RawExtension{Raw: raw},
+		})
+		require.NoError(t, err)
+		require.True(t, response.Allowed)
+		if response.Patch == nil {
+			require.Nil(t, response.PatchType)
+			return nil
+		}
+		require.Equal(t, admissionv1.PatchTypeJSONPatch, *response.PatchType)
+		var patch []jsonpatch.Operation
+		require.NoError(t, json.Unmarshal(response.Patch, &patch))
+		return patch
+	}
+
+	t.Run("credential Secret using kongCredType field", func(t *testing.T) {
+		secret := &corev1.Secret{
+			TypeMeta:   metav1.TypeMeta{APIVersion: "v1", Kind: "Secret"},
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "credential"},
+			Data:       map[string][]byte{"kongCredType": []byte("key-auth"), "key": []byte("secret")},
+		}
+		patch := mutate(t, newHandler(), secretGVResource, admissionv1.Create, secret)
+		assert.ElementsMatch(t, []jsonpatch.Operation{
+			{Operation: "add", Path: "/metadata/labels", Value: map[string]any{"konghq.com/credential": "key-auth"}},
+			{Operation: "remove", Path: "/data/kongCredType"},
+		}, patch)
+	})
+
+	t.Run("credential Secret using the label", func(t *testing.T) {
+		secret := &corev1.Secret{
+			TypeMeta: metav1.TypeMeta{APIVersion: "v1", Kind: "Secret"},
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: "default", Name: "credential",
+				Labels: map[string]string{"konghq.com/credential": "key-auth"},
+			},
+			Data: map[string][]byte{"key": []byte("secret")},
+		}
+		assert.Empty(t, mutate(t, newHandler(), secretGVResource, admissionv1.Update, secret))
+	})
+
+	t.Run("Ingress with legacy regex paths", func(t *testing.T) {
+		patch := mutate(t, newHandler(ingressClass, params), ingressGVResource, admissionv1.Create, newIngress("kong"))
+		assert.ElementsMatch(t, []jsonpatch.Operation{
+			{Operation: "replace", Path: "/spec/rules/0/http/paths/0/path", Value: "/~/users/[0-9]+"},
+			{Operation: "add", Path: "/metadata/annotations", Value: map[string]any{
+				"konghq.com/effective-route-settings": `{"preserve_host":true,"protocols":["http","https"],"regex_priority":0,` +
+					`"strip_path":false,"request_buffering":true,"response_buffering":true}`,
+			}},
+		}, patch)
+	})
+
+	t.Run("Ingress without legacy regex detection", func(t *testing.T) {
+		patch := mutate(t, newHandler(ingressClass), ingressGVResource, admissionv1.Create, newIngress("kong"))
+		require.Len(t, patch, 1)
+		assert.Equal(t, "/metadata/annotations", patch[0].Path)
+	})
+
+	t.Run("Ingress of another class", func(t *testing.T) {
+		assert.Empty(t, mutate(t, newHandler(ingressClass, params), ingressGVResource, admissionv1.Create, newIngress("other")))
+	})
+}
@@ -1,6 +1,7 @@
 package admission
 
 import (
+	"github.com/samber/lo"
 	admissionv1 "k8s.io/api/admission/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	k8stypes "k8s.io/apimachinery/pkg/types"
@@ -11,6 +12,7 @@ type ResponseBuilder struct {
 	message  string
 	allowed  bool
 	warnings []string
+	patch    []byte
 }
 
 func NewResponseBuilder(uid k8stypes.UID) *ResponseBuilder {
@@ -32,13 +34,19 @@ func (r *ResponseBuilder) WithWarning(warning string) *ResponseBuilder {
 	return r
 }
 
+// WithPatch sets a JSON patch mutating the object.
+func (r *ResponseBuilder) WithPatch(patch []byte) *ResponseBuilder {
+	r.patch = patch
+	return r
+}
+
 func (r *ResponseBuilder) Build() *admissionv1.AdmissionResponse {
 	var code int32
 	if !r.allowed {
 		code = 400
 	}
 
-	return &admissionv1.AdmissionResponse{
+	response := &admissionv1.AdmissionResponse{
 		UID:     r.uid,
 		Allowed: r.allowed,
 		Result: &metav1.Status{
@@ -47,4 +55,9 @@ func (r *ResponseBuilder) Build() *admissionv1.AdmissionResponse {
 		},
 		Warnings: r.warnings,
 	}
+	if len(r.patch) > 0 {
+		response.Patch = r.patch
+		response.PatchType = lo.ToPtr(admissionv1.PatchTypeJSONPatch)
+	}
+	return response
 }
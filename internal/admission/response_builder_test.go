@@ -4,6 +4,7 @@ import (
 	"net/http"
 	"testing"
 
+	"github.com/samber/lo"
 	"github.com/stretchr/testify/require"
 	admissionv1 "k8s.io/api/admission/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -86,6 +87,19 @@ func TestResponseBuilder(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "allowed with a patch",
+			modifyBuilderFn: func(b *admission.ResponseBuilder) {
+				b.Allowed(true).WithPatch([]byte(`[{"op":"remove","path":"/data/kongCredType"}]`))
+			},
+			expectedResponse: &admissionv1.AdmissionResponse{
+				UID:       someUID,
+				Allowed:   true,
+				Result:    &metav1.Status{},
+				Patch:     []byte(`[{"op":"remove","path":"/data/kongCredType"}]`),
+				PatchType: lo.ToPtr(admissionv1.PatchTypeJSONPatch),
+			},
+		},
 	}
 
 	for _, tc := range testCases {
@@ -0,0 +1,352 @@
+package admission
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/kong/go-kong/kong"
+	"github.com/samber/lo"
+	netv1 "k8s.io/api/networking/v1"
+	k8stypes "k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+
+	"github.com/kong/kubernetes-ingress-controller/v2/internal/dataplane/parser"
+	"github.com/kong/kubernetes-ingress-controller/v2/internal/dataplane/parser/translators"
+	"github.com/kong/kubernetes-ingress-controller/v2/internal/gatewayapi"
+	kongv1alpha1 "github.com/kong/kubernetes-ingress-controller/v2/pkg/apis/configuration/v1alpha1"
+)
+
+// RouteConflictPolicy defines how the admission webhook handles Ingresses and HTTPRoutes generating
+// Kong routes that exactly overlap with routes generated from other objects.
+type RouteConflictPolicy string
+
+const (
+	// RouteConflictPolicyOff disables route conflicts detection.
+	RouteConflictPolicyOff RouteConflictPolicy = "off"
+	// RouteConflictPolicyWarn admits conflicting objects and returns a warning naming the objects they conflict with.
+	RouteConflictPolicyWarn RouteConflictPolicy = "warn"
+	// RouteConflictPolicyDeny rejects conflicting objects.
+	RouteConflictPolicyDeny RouteConflictPolicy = "deny"
+)
+
+func (p RouteConflictPolicy) Validate() error {
+	switch p {
+	case RouteConflictPolicyOff, RouteConflictPolicyWarn, RouteConflictPolicyDeny:
+		return nil
+	default:
+		return fmt.Errorf("unknown route conflict policy: %s", p)
+	}
+}
+
+func (p RouteConflictPolicy) String() string {
+	return string(p)
+}
+
+// RoutesLister lists objects already translated to Kong routes that validated objects' routes are compared
+// against when detecting route conflicts. It is implemented by store.Storer.
+type RoutesLister interface {
+	ListIngressesV1() []*netv1.Ingress
+	ListHTTPRoutes() ([]*gatewayapi.HTTPRoute, error)
+}
+
+// routeOwner identifies an object Kong routes were generated from.
+type routeOwner struct {
+	kind      string
+	namespace string
+	name      string
+}
+
+func (o routeOwner) String() string {
+	return fmt.Sprintf("%s %s/%s", o.kind, o.namespace, o.name)
+}
+
+// routeParent is an ingress class or a Gateway listener routes are exposed on. Empty sectionName
+// and zero port of a Gateway parent stand for all listeners of the Gateway.
+type routeParent struct {
+	kind        string
+	namespace   string
+	name        string
+	sectionName string
+	port        int32
+}
+
+// overlaps tells whether routes exposed on both parents are served by the same listener.
+func (p routeParent) overlaps(other routeParent) bool {
+	if p.kind != other.kind || p.namespace != other.namespace || p.name != other.name {
+		return false
+	}
+	if p.sectionName != "" && other.sectionName != "" && p.sectionName != other.sectionName {
+		return false
+	}
+	if p.port != 0 && other.port != 0 && p.port != other.port {
+		return false
+	}
+	return true
+}
+
+// routeParentsOverlap tells whether any of the parents overlap.
+func routeParentsOverlap(parents, others []routeParent) bool {
+	return lo.SomeBy(parents, func(p routeParent) bool {
+		return lo.SomeBy(others, p.overlaps)
+	})
+}
+
+// ingressRouteParents returns the parent of routes generated from Ingresses. Only Ingresses of the controller's
+// ingress class are validated and listed, so they all share the same parent.
+func (validator KongHTTPValidator) ingressRouteParents() []routeParent {
+	return []routeParent{{kind: "IngressClass", name: validator.ingressClassName}}
+}
+
+// httpRouteParents returns the Gateway listeners an HTTPRoute is attached to.
+func httpRouteParents(httproute *gatewayapi.HTTPRoute) []routeParent {
+	var parents []routeParent
+	for _, parentRef := range httproute.Spec.ParentRefs {
+		if (parentRef.Group != nil && string(*parentRef.Group) != gatewayv1.GroupVersion.Group) ||
+			(parentRef.Kind != nil && *parentRef.Kind != "Gateway") {
+			continue
+		}
+		parent := routeParent{
+			kind:      "Gateway",
+			namespace: httproute.Namespace,
+			name:      string(parentRef.Name),
+		}
+		if parentRef.Namespace != nil && *parentRef.Namespace != "" {
+			parent.namespace = string(*parentRef.Namespace)
+		}
+		if parentRef.SectionName != nil {
+			parent.sectionName = string(*parentRef.SectionName)
+		}
+		if parentRef.Port != nil {
+			parent.port = int32(*parentRef.Port)
+		}
+		parents = append(parents, parent)
+	}
+	return parents
+}
+
+// routeConflicts checks whether routes generated from the owner exactly overlap with routes generated from
+// Ingresses and HTTPRoutes in the store exposed on the same parents (ingress class or Gateway listeners).
+// Depending on the RouteConflictPolicy, conflicts are either reported as a rejection message or as a warning.
+func (validator KongHTTPValidator) routeConflicts(
+	owner routeOwner, parents []routeParent, routes []kong.Route,
+) (ok bool, msg string, warnings []string, err error) {
+	if validator.RoutesLister == nil || validator.RouteConflictPolicy == "" || validator.RouteConflictPolicy == RouteConflictPolicyOff {
+		return true, "", nil, nil
+	}
+
+	conflicting, err := validator.findConflictingRouteOwners(owner, parents, routes)
+	if err != nil {
+		return false, ErrTextRoutesUnretrievable, nil, err
+	}
+	if len(conflicting) == 0 {
+		return true, "", nil, nil
+	}
+
+	msg = fmt.Sprintf(ErrTextRouteConflict, strings.Join(lo.Map(conflicting, func(o routeOwner, _ int) string {
+		return o.String()
+	}), ", "))
+	if validator.RouteConflictPolicy == RouteConflictPolicyDeny {
+		return false, msg, nil, nil
+	}
+	return true, "", []string{msg}, nil
+}
+
+// findConflictingRouteOwners returns sorted owners of routes from the store exposed on the same parents
+// and matching the same requests as any of the given routes. The owner itself is never reported.
+// Route matches of objects from the store are cached, so that only objects changed since the previous
+// admission request are translated.
+func (validator KongHTTPValidator) findConflictingRouteOwners(
+	owner routeOwner, parents []routeParent, routes []kong.Route,
+) ([]routeOwner, error) {
+	candidateMatches := map[routeMatch]struct{}{}
+	for _, route := range routes {
+		for _, m := range routeMatches(route) {
+			candidateMatches[m] = struct{}{}
+		}
+	}
+	if len(candidateMatches) == 0 {
+		return nil, nil
+	}
+
+	httproutes, err := validator.RoutesLister.ListHTTPRoutes()
+	if err != nil {
+		return nil, err
+	}
+
+	cache := validator.routeMatchesCache
+	if cache == nil {
+		cache = newRouteMatchesCache()
+	}
+	cache.lock.Lock()
+	defer cache.lock.Unlock()
+	seen := make(map[k8stypes.UID]routeMatchesCacheEntry, len(cache.entries))
+
+	var conflicting []routeOwner
+	checkOwner := func(o routeOwner, obj client.Object, matches func() []routeMatch) {
+		if o == owner {
+			return
+		}
+		for _, m := range cache.get(seen, obj, matches) {
+			if _, ok := candidateMatches[m]; ok {
+				conflicting = append(conflicting, o)
+				return
+			}
+		}
+	}
+
+	if routeParentsOverlap(parents, validator.ingressRouteParents()) {
+		for _, ingress := range validator.RoutesLister.ListIngressesV1() {
+			ingress := ingress
+			checkOwner(
+				routeOwner{kind: "Ingress", namespace: ingress.Namespace, name: ingress.Name},
+				ingress,
+				func() []routeMatch { return routesMatches(validator.ingressRoutesForConflicts(ingress)) },
+			)
+		}
+	}
+	for _, httproute := range httproutes {
+		httproute := httproute
+		if !routeParentsOverlap(parents, httpRouteParents(httproute)) {
+			continue
+		}
+		checkOwner(
+			routeOwner{kind: "HTTPRoute", namespace: httproute.Namespace, name: httproute.Name},
+			httproute,
+			func() []routeMatch { return routesMatches(httpRouteRoutesForConflicts(httproute)) },
+		)
+	}
+	cache.entries = seen
+
+	sort.Slice(conflicting, func(i, j int) bool {
+		return conflicting[i].String() < conflicting[j].String()
+	})
+	return conflicting, nil
+}
+
+// routeMatchesCache caches route matches of objects from the store by their UIDs and resource versions.
+type routeMatchesCache struct {
+	lock    sync.Mutex
+	entries map[k8stypes.UID]routeMatchesCacheEntry
+}
+
+type routeMatchesCacheEntry struct {
+	resourceVersion string
+	matches         []routeMatch
+}
+
+func newRouteMatchesCache() *routeMatchesCache {
+	return &routeMatchesCache{entries: map[k8stypes.UID]routeMatchesCacheEntry{}}
+}
+
+// get returns cached route matches of the object, computing them when the object changed. Entries of objects
+// that are returned are recorded in seen, so that entries of deleted objects can be dropped.
+// Objects without a UID or a resource version are never cached.
+func (c *routeMatchesCache) get(
+	seen map[k8stypes.UID]routeMatchesCacheEntry, obj client.Object, matches func() []routeMatch,
+) []routeMatch {
+	uid, resourceVersion := obj.GetUID(), obj.GetResourceVersion()
+	if uid == "" || resourceVersion == "" {
+		return matches()
+	}
+	entry, ok := c.entries[uid]
+	if !ok || entry.resourceVersion != resourceVersion {
+		entry = routeMatchesCacheEntry{resourceVersion: resourceVersion, matches: matches()}
+	}
+	seen[uid] = entry
+	return entry.matches
+}
+
+// ingressRoutesForConflicts generates traditional Kong routes from an Ingress. Traditional routes are used
+// regardless of the configured router flavor as their matching fields can be compared directly.
+func (validator KongHTTPValidator) ingressRoutesForConflicts(ingress *netv1.Ingress) []kong.Route {
+	features := validator.ParserFeatures
+	features.ExpressionRoutes = false
+	services := parser.IngressesV1ToKongServices(
+		features,
+		[]*netv1.Ingress{ingress},
+		kongv1alpha1.IngressClassParametersSpec{},
+		&parser.ObjectsCollector{}, // It's irrelevant for conflicts detection.
+	)
+
+	var routes []kong.Route
+	for _, svc := range services {
+		for _, route := range svc.Routes {
+			routes = append(routes, route.Route)
+		}
+	}
+	return routes
+}
+
+// httpRouteRoutesForConflicts generates traditional Kong routes from an HTTPRoute. Rules that fail
+// to translate are skipped as they're reported by the HTTPRoute validation.
+func httpRouteRoutesForConflicts(httproute *gatewayapi.HTTPRoute) []kong.Route {
+	var routes []kong.Route
+	for _, rule := range httproute.Spec.Rules {
+		translation := translators.KongRouteTranslation{
+			Name:    "conflicts-detection",
+			Matches: rule.Matches,
+			Filters: rule.Filters,
+		}
+		kongRoutes, err := parser.GenerateKongRouteFromTranslation(httproute, translation, false)
+		if err != nil {
+			continue
+		}
+		for _, route := range kongRoutes {
+			routes = append(routes, route.Route)
+		}
+	}
+	return routes
+}
+
+// routeMatch is a single combination of a host, a path and a method matched by a traditional Kong route
+// along with the headers it requires. Empty fields match any value.
+type routeMatch struct {
+	host    string
+	path    string
+	method  string
+	headers string
+}
+
+// routesMatches expands routes into all combinations of their hosts, paths and methods.
+func routesMatches(routes []kong.Route) []routeMatch {
+	return lo.FlatMap(routes, func(route kong.Route, _ int) []routeMatch {
+		return routeMatches(route)
+	})
+}
+
+// routeMatches expands a route into all combinations of its hosts, paths and methods.
+func routeMatches(route kong.Route) []routeMatch {
+	orAny := func(values []*string) []string {
+		if len(values) == 0 {
+			return []string{""}
+		}
+		return lo.Map(values, func(v *string, _ int) string { return lo.FromPtr(v) })
+	}
+
+	headerNames := lo.Keys(route.Headers)
+	sort.Strings(headerNames)
+	headers := make([]string, 0, len(headerNames))
+	for _, name := range headerNames {
+		values := append([]string{}, route.Headers[name]...)
+		sort.Strings(values)
+		headers = append(headers, strings.ToLower(name)+":"+strings.Join(values, ","))
+	}
+
+	var matches []routeMatch
+	for _, host := range orAny(route.Hosts) {
+		for _, path := range orAny(route.Paths) {
+			for _, method := range orAny(route.Methods) {
+				matches = append(matches, routeMatch{
+					host:    host,
+					path:    path,
+					method:  method,
+					headers: strings.Join(headers, ";"),
+				})
+			}
+		}
+	}
+	return matches
+}
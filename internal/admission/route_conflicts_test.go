@@ -0,0 +1,145 @@
+package admission
+
+import (
+	"testing"
+
+	"github.com/samber/lo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	netv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8stypes "k8s.io/apimachinery/pkg/types"
+
+	"github.com/kong/kubernetes-ingress-controller/v2/internal/gatewayapi"
+)
+
+type fakeRoutesLister struct {
+	httproutes []*gatewayapi.HTTPRoute
+}
+
+func (l fakeRoutesLister) ListIngressesV1() []*netv1.Ingress {
+	return nil
+}
+
+func (l fakeRoutesLister) ListHTTPRoutes() ([]*gatewayapi.HTTPRoute, error) {
+	return l.httproutes, nil
+}
+
+func TestFindConflictingRouteOwners_HTTPRouteParents(t *testing.T) {
+	newHTTPRoute := func(name string, parentRefs ...gatewayapi.ParentReference) *gatewayapi.HTTPRoute {
+		return &gatewayapi.HTTPRoute{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace:       "default",
+				Name:            name,
+				UID:             k8stypes.UID("uid-" + name),
+				ResourceVersion: "1",
+			},
+			Spec: gatewayapi.HTTPRouteSpec{
+				CommonRouteSpec: gatewayapi.CommonRouteSpec{ParentRefs: parentRefs},
+				Hostnames:       []gatewayapi.Hostname{"example.com"},
+				Rules: []gatewayapi.HTTPRouteRule{{
+					Matches: []gatewayapi.HTTPRouteMatch{{
+						Path: &gatewayapi.HTTPPathMatch{
+							Type:  lo.ToPtr(gatewayapi.PathMatchPathPrefix),
+							Value: lo.ToPtr("/api"),
+						},
+					}},
+				}},
+			},
+		}
+	}
+	parentRef := func(gateway, sectionName string) gatewayapi.ParentReference {
+		ref := gatewayapi.ParentReference{Name: gatewayapi.ObjectName(gateway)}
+		if sectionName != "" {
+			ref.SectionName = lo.ToPtr(gatewayapi.SectionName(sectionName))
+		}
+		return ref
+	}
+
+	existing := []*gatewayapi.HTTPRoute{
+		newHTTPRoute("gateway-a-http", parentRef("gateway-a", "http")),
+		newHTTPRoute("gateway-a-https", parentRef("gateway-a", "https")),
+		newHTTPRoute("gateway-b", parentRef("gateway-b", "")),
+	}
+	validator := KongHTTPValidator{
+		RoutesLister:      fakeRoutesLister{httproutes: existing},
+		routeMatchesCache: newRouteMatchesCache(),
+	}
+
+	testCases := []struct {
+		name                string
+		httproute           *gatewayapi.HTTPRoute
+		expectedConflicting []string
+	}{
+		{
+			name:                "same listener",
+			httproute:           newHTTPRoute("new", parentRef("gateway-a", "http")),
+			expectedConflicting: []string{"HTTPRoute default/gateway-a-http"},
+		},
+		{
+			name:                "all listeners of a Gateway",
+			httproute:           newHTTPRoute("new", parentRef("gateway-a", "")),
+			expectedConflicting: []string{"HTTPRoute default/gateway-a-http", "HTTPRoute default/gateway-a-https"},
+		},
+		{
+			name:                "listener of a Gateway the existing route is attached to as a whole",
+			httproute:           newHTTPRoute("new", parentRef("gateway-b", "http")),
+			expectedConflicting: []string{"HTTPRoute default/gateway-b"},
+		},
+		{
+			name:      "another Gateway",
+			httproute: newHTTPRoute("new", parentRef("gateway-c", "")),
+		},
+		{
+			name:      "updated route doesn't conflict with itself",
+			httproute: newHTTPRoute("gateway-a-http", parentRef("gateway-a", "http")),
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			conflicting, err := validator.findConflictingRouteOwners(
+				routeOwner{kind: "HTTPRoute", namespace: tc.httproute.Namespace, name: tc.httproute.Name},
+				httpRouteParents(tc.httproute),
+				httpRouteRoutesForConflicts(tc.httproute),
+			)
+			require.NoError(t, err)
+			assert.ElementsMatch(t, tc.expectedConflicting, lo.Map(conflicting, func(o routeOwner, _ int) string { return o.String() }))
+		})
+	}
+}
+
+func TestRouteMatchesCache(t *testing.T) {
+	cache := newRouteMatchesCache()
+	computed := 0
+	matches := func() []routeMatch {
+		computed++
+		return []routeMatch{{path: "/api"}}
+	}
+	obj := &gatewayapi.HTTPRoute{ObjectMeta: metav1.ObjectMeta{UID: "uid", ResourceVersion: "1"}}
+
+	get := func() []routeMatch {
+		seen := map[k8stypes.UID]routeMatchesCacheEntry{}
+		result := cache.get(seen, obj, matches)
+		cache.entries = seen
+		return result
+	}
+
+	require.Equal(t, []routeMatch{{path: "/api"}}, get())
+	require.Equal(t, []routeMatch{{path: "/api"}}, get())
+	require.Equal(t, 1, computed, "matches of an unchanged object should be cached")
+
+	obj.ResourceVersion = "2"
+	get()
+	require.Equal(t, 2, computed, "matches of a changed object should be computed again")
+
+	cache.entries = map[k8stypes.UID]routeMatchesCacheEntry{}
+	get()
+	require.Equal(t, 3, computed, "matches of objects without cache entries should be computed")
+
+	obj.UID = ""
+	get()
+	get()
+	require.Equal(t, 5, computed, "objects without a UID should never be cached")
+}
@@ -31,6 +31,9 @@ type ServerConfig struct {
 
 	KeyPath string
 	Key     string
+
+	RouteConflictPolicy RouteConflictPolicy
+	EnableMutation      bool
 }
 
 func MakeTLSServer(
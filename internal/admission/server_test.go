@@ -9,17 +9,23 @@ import (
 	"net/http/httptest"
 	"testing"
 
+	"github.com/go-logr/logr"
 	"github.com/go-logr/zapr"
 	"github.com/lithammer/dedent"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"go.uber.org/zap"
 	admissionv1 "k8s.io/api/admission/v1"
 	corev1 "k8s.io/api/core/v1"
 	netv1 "k8s.io/api/networking/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 
+	"github.com/kong/kubernetes-ingress-controller/v2/internal/admission/lint"
 	"github.com/kong/kubernetes-ingress-controller/v2/internal/gatewayapi"
 	kongv1 "github.com/kong/kubernetes-ingress-controller/v2/pkg/apis/configuration/v1"
+	kongv1alpha1 "github.com/kong/kubernetes-ingress-controller/v2/pkg/apis/configuration/v1alpha1"
 	kongv1beta1 "github.com/kong/kubernetes-ingress-controller/v2/pkg/apis/configuration/v1beta1"
 )
 
@@ -67,11 +73,43 @@ func (v KongFakeValidator) ValidateGateway(_ context.Context, _ gatewayapi.Gatew
 	return v.Result, v.Message, v.Error
 }
 
-func (v KongFakeValidator) ValidateHTTPRoute(_ context.Context, _ gatewayapi.HTTPRoute) (bool, string, error) {
+func (v KongFakeValidator) ValidateHTTPRoute(_ context.Context, _ gatewayapi.HTTPRoute) (bool, string, []string, error) {
+	return v.Result, v.Message, nil, v.Error
+}
+
+func (v KongFakeValidator) ValidateGRPCRoute(_ context.Context, _ gatewayapi.GRPCRoute) (bool, string, error) {
+	return v.Result, v.Message, v.Error
+}
+
+func (v KongFakeValidator) ValidateTCPRoute(_ context.Context, _ gatewayapi.TCPRoute) (bool, string, error) {
+	return v.Result, v.Message, v.Error
+}
+
+func (v KongFakeValidator) ValidateTLSRoute(_ context.Context, _ gatewayapi.TLSRoute) (bool, string, error) {
 	return v.Result, v.Message, v.Error
 }
 
-func (v KongFakeValidator) ValidateIngress(_ context.Context, _ netv1.Ingress) (bool, string, error) {
+func (v KongFakeValidator) ValidateUDPRoute(_ context.Context, _ gatewayapi.UDPRoute) (bool, string, error) {
+	return v.Result, v.Message, v.Error
+}
+
+func (v KongFakeValidator) ValidateIngress(_ context.Context, _ netv1.Ingress) (bool, string, []string, error) {
+	return v.Result, v.Message, nil, v.Error
+}
+
+func (v KongFakeValidator) ValidateUpstreamPolicy(_ context.Context, _ kongv1beta1.KongUpstreamPolicy) (bool, string, error) {
+	return v.Result, v.Message, v.Error
+}
+
+func (v KongFakeValidator) ValidateTCPIngress(_ context.Context, _ kongv1beta1.TCPIngress) (bool, string, error) {
+	return v.Result, v.Message, v.Error
+}
+
+func (v KongFakeValidator) ValidateUDPIngress(_ context.Context, _ kongv1beta1.UDPIngress) (bool, string, error) {
+	return v.Result, v.Message, v.Error
+}
+
+func (v KongFakeValidator) ValidateIngressClassParameters(_ context.Context, _ kongv1alpha1.IngressClassParameters) (bool, string, error) {
 	return v.Result, v.Message, v.Error
 }
 
@@ -375,3 +413,39 @@ func TestValidationWebhook(t *testing.T) {
 		}
 	}
 }
+
+func TestValidationWebhookLinters(t *testing.T) {
+	server := RequestHandler{
+		Validator: KongFakeValidator{Result: true},
+		Linters: []lint.Linter{
+			lint.LinterFunc(lint.AnnotationsKind),
+			lint.LinterFunc(func(_ context.Context, obj client.Object) ([]string, error) {
+				return []string{"linted " + obj.GetObjectKind().GroupVersionKind().Kind + " " + obj.GetName()}, nil
+			}),
+			lint.LinterFunc(func(context.Context, client.Object) ([]string, error) {
+				return nil, errors.New("linter failure")
+			}),
+		},
+		Logger: logr.Discard(),
+	}
+
+	response, err := server.handleValidation(context.Background(), admissionv1.AdmissionRequest{
+		UID:       "b2df61dd-ab5b-4cb4-9be0-878533c83892",
+		Kind:      metav1.GroupVersionKind{Group: "configuration.konghq.com", Version: "v1", Kind: "KongPlugin"},
+		Resource:  pluginGVResource,
+		Operation: admissionv1.Create,
+		Object: runtime.RawExtension{Raw: []byte(`{
+			"apiVersion": "configuration.konghq.com/v1",
+			"kind": "KongPlugin",
+			"metadata": {"name": "plugin", "annotations": {"konghq.com/strip-path": "true"}},
+			"plugin": "key-auth"
+		}`)},
+	})
+	require.NoError(t, err)
+	assert.True(t, response.Allowed)
+	assert.Equal(t, []string{
+		"konghq.com/strip-path annotation has no effect on KongPlugin, it is supported on: " +
+			"Ingress, TCPIngress, UDPIngress, HTTPRoute, GRPCRoute, TCPRoute, TLSRoute, UDPRoute.",
+		"linted KongPlugin plugin",
+	}, response.Warnings)
+}
@@ -0,0 +1,378 @@
+package credentials
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// -----------------------------------------------------------------------------
+// Registry - Types
+// -----------------------------------------------------------------------------
+
+// SchemaGetter retrieves schemas of Kong entities from a Kong Gateway.
+type SchemaGetter interface {
+	// EntitySchema returns the schema of the entity (e.g. keyauth_credentials) as returned by
+	// the Admin API's GET /schemas/{entity} endpoint.
+	EntitySchema(ctx context.Context, entityName string) (map[string]interface{}, error)
+}
+
+// Field describes a field of a credential.
+type Field struct {
+	// Type is the type of the field as named by Kong's schemas, e.g. string, integer, boolean or array.
+	Type string
+	// ElementsType is the type of elements of array and set fields.
+	ElementsType string
+}
+
+// Type describes a type of credentials.
+type Type struct {
+	// Name is the name of the type. For the types known to the controller it's the
+	// name used in credential Secrets (e.g. key-auth), for others it's the name of
+	// the Kong entity.
+	Name string
+	// Entity is the name of the Kong entity credentials of this type are stored as.
+	Entity string
+	// Builtin indicates whether the type is known to the controller. Credentials of
+	// other types are sent to Kong as custom entities, which only DB-less gateways accept.
+	Builtin bool
+	// RequiredFields are fields which have to be present in credentials of this type.
+	RequiredFields []string
+	// Fields are the fields of credentials of this type. Fields that are absent
+	// are treated as strings.
+	Fields map[string]Field
+}
+
+// builtinTypes are the credential types known to the controller. Their fields are used
+// when the schemas of their entities can't be retrieved from a Gateway.
+var builtinTypes = []Type{
+	{
+		Name:           "basic-auth",
+		Entity:         "basicauth_credentials",
+		RequiredFields: BasicAuthFields,
+	},
+	{
+		Name:           "hmac-auth",
+		Entity:         "hmacauth_credentials",
+		RequiredFields: HMACAuthFields,
+	},
+	{
+		Name:           "jwt",
+		Entity:         "jwt_secrets",
+		RequiredFields: JWTAuthFields,
+	},
+	{
+		Name:           "key-auth",
+		Entity:         "keyauth_credentials",
+		RequiredFields: KeyAuthFields,
+		Fields: map[string]Field{
+			"ttl": {Type: "integer"},
+		},
+	},
+	{
+		Name:           "oauth2",
+		Entity:         "oauth2_credentials",
+		RequiredFields: OAUTH2AuthFields,
+		Fields: map[string]Field{
+			"redirect_uris": {Type: "array", ElementsType: "string"},
+			"hash_secret":   {Type: "boolean"},
+		},
+	},
+	{
+		Name:           "acl",
+		Entity:         "acls",
+		RequiredFields: ACLAuthFields,
+	},
+	{
+		Name:           "mtls-auth",
+		Entity:         "mtls_auth_credentials",
+		RequiredFields: MTLsAuthFields,
+	},
+}
+
+// schemaRequestTimeout limits the time spent on retrieving a single schema from a Gateway.
+const schemaRequestTimeout = 5 * time.Second
+
+// typesCacheTTL is the time for which resolved types, including failures to resolve them,
+// are reused before their schemas are requested from the Gateway again.
+const typesCacheTTL = time.Minute
+
+// Registry resolves credential types. Types known to the controller are always available,
+// other types are resolved from schemas of Kong entities provided by plugins installed in
+// the Gateway (e.g. keyauth_enc_credentials).
+type Registry struct {
+	schemas            SchemaGetter
+	customTypesEnabled bool
+
+	lock      sync.Mutex
+	cache     map[string]cachedType
+	resolving map[string]struct{}
+	now       func() time.Time
+}
+
+// cachedType is the result of resolving a type, kept until expires.
+type cachedType struct {
+	t       Type
+	err     error
+	expires time.Time
+}
+
+// NewRegistry creates a Registry. When schemas is nil, only the types known to the controller
+// are supported and their fields are not resolved from the Gateway. customTypesEnabled enables
+// types unknown to the controller, which should only be enabled for DB-less gateways.
+func NewRegistry(schemas SchemaGetter, customTypesEnabled bool) *Registry {
+	return &Registry{
+		schemas:            schemas,
+		customTypesEnabled: customTypesEnabled,
+		cache:              make(map[string]cachedType),
+		resolving:          make(map[string]struct{}),
+		now:                time.Now,
+	}
+}
+
+// -----------------------------------------------------------------------------
+// Registry - Public Methods
+// -----------------------------------------------------------------------------
+
+// Lookup returns the credential type with the given name. The name is either a name of a type
+// known to the controller or the name of a Kong entity. Results, including failures, are cached
+// for typesCacheTTL; on a cache miss the schema of the type is requested from the Gateway.
+func (r *Registry) Lookup(ctx context.Context, name string) (Type, error) {
+	if r.schemas == nil {
+		return r.resolve(ctx, name)
+	}
+	if c, ok := r.cached(name); ok && !r.now().After(c.expires) {
+		return c.t, c.err
+	}
+	t, err := r.resolve(ctx, name)
+	r.store(name, t, err)
+	return t, err
+}
+
+// LookupCached returns the credential type with the given name without making requests to the
+// Gateway, so it can be used during translation. Types not cached yet or expired are resolved in
+// the background: until then, types known to the controller are returned with their builtin fields,
+// expired types are returned as last resolved and other types are reported as not resolved yet.
+func (r *Registry) LookupCached(name string) (Type, error) {
+	if r.schemas == nil {
+		return r.resolve(context.Background(), name)
+	}
+	c, ok := r.cached(name)
+	if ok && !r.now().After(c.expires) {
+		return c.t, c.err
+	}
+	r.resolveInBackground(name)
+	if ok {
+		return c.t, c.err
+	}
+	if t, ok := lookupBuiltinType(name); ok {
+		return t, nil
+	}
+	if !r.customTypesEnabled {
+		return Type{}, fmt.Errorf("unsupported credential type: %q", name)
+	}
+	return Type{}, fmt.Errorf("unsupported credential type: %q: its schema hasn't been retrieved from the Gateway yet", name)
+}
+
+// Coerce converts data of a credential Secret to the configuration of the credential, parsing
+// values according to the types of their fields. Values that can't be parsed are skipped and
+// reported in the returned errors.
+func (t Type) Coerce(data map[string][]byte) (map[string]interface{}, []error) {
+	config := make(map[string]interface{}, len(data))
+	var errs []error
+	for k, v := range data {
+		if k == TypeKey {
+			continue
+		}
+		field, ok := t.Fields[k]
+		if !ok {
+			config[k] = string(v)
+			continue
+		}
+		value, err := coerceValue(field, string(v))
+		if err != nil {
+			errs = append(errs, fmt.Errorf("failed to parse %s as %s: %w, skipping the field", k, field.Type, err))
+			continue
+		}
+		config[k] = value
+	}
+	return config, errs
+}
+
+// -----------------------------------------------------------------------------
+// Registry - Private Functions
+// -----------------------------------------------------------------------------
+
+// resolve resolves the type with the given name, requesting its schema from the Gateway when
+// schemas are available.
+func (r *Registry) resolve(ctx context.Context, name string) (Type, error) {
+	if t, ok := lookupBuiltinType(name); ok {
+		if r.schemas == nil {
+			return t, nil
+		}
+		schema, err := r.entitySchema(ctx, t.Entity)
+		if err != nil {
+			// Fields known to the controller are good enough when the Gateway is unavailable.
+			return t, nil //nolint:nilerr
+		}
+		t.Fields = fieldsFromSchema(schema)
+		return t, nil
+	}
+
+	if r.schemas == nil || !r.customTypesEnabled {
+		return Type{}, fmt.Errorf("unsupported credential type: %q", name)
+	}
+	schema, err := r.entitySchema(ctx, name)
+	if err != nil {
+		return Type{}, fmt.Errorf("unsupported credential type: %q: %w", name, err)
+	}
+	if !isConsumerCredentialSchema(schema) {
+		return Type{}, fmt.Errorf("unsupported credential type: %q: entity does not belong to consumers", name)
+	}
+	return Type{
+		Name:           name,
+		Entity:         name,
+		RequiredFields: requiredFieldsFromSchema(schema),
+		Fields:         fieldsFromSchema(schema),
+	}, nil
+}
+
+// resolveInBackground resolves the type with the given name in a separate goroutine and caches
+// the result, unless the type is already being resolved.
+func (r *Registry) resolveInBackground(name string) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	if _, ok := r.resolving[name]; ok {
+		return
+	}
+	r.resolving[name] = struct{}{}
+	go func() {
+		t, err := r.resolve(context.Background(), name)
+		r.lock.Lock()
+		defer r.lock.Unlock()
+		delete(r.resolving, name)
+		r.cache[name] = cachedType{t: t, err: err, expires: r.now().Add(typesCacheTTL)}
+	}()
+}
+
+func (r *Registry) cached(name string) (cachedType, bool) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	c, ok := r.cache[name]
+	return c, ok
+}
+
+func (r *Registry) store(name string, t Type, err error) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.cache[name] = cachedType{t: t, err: err, expires: r.now().Add(typesCacheTTL)}
+}
+
+func (r *Registry) entitySchema(ctx context.Context, entityName string) (map[string]interface{}, error) {
+	ctx, cancel := context.WithTimeout(ctx, schemaRequestTimeout)
+	defer cancel()
+	return r.schemas.EntitySchema(ctx, entityName)
+}
+
+func lookupBuiltinType(name string) (Type, bool) {
+	for _, t := range builtinTypes {
+		if t.Name == name || t.Entity == name {
+			t.Builtin = true
+			return t, true
+		}
+	}
+	return Type{}, false
+}
+
+func coerceValue(field Field, value string) (interface{}, error) {
+	switch field.Type {
+	case "integer":
+		return strconv.Atoi(value)
+	case "number":
+		return strconv.ParseFloat(value, 64)
+	case "boolean":
+		return strconv.ParseBool(value)
+	case "array", "set":
+		elements := strings.Split(value, ",")
+		res := make([]interface{}, 0, len(elements))
+		for _, e := range elements {
+			v, err := coerceValue(Field{Type: field.ElementsType}, e)
+			if err != nil {
+				return nil, err
+			}
+			res = append(res, v)
+		}
+		return res, nil
+	case "map", "record", "json":
+		var v interface{}
+		if err := json.Unmarshal([]byte(value), &v); err != nil {
+			return nil, err
+		}
+		return v, nil
+	default:
+		return value, nil
+	}
+}
+
+// schemaFields returns the fields of an entity schema, which are listed as single-key objects
+// mapping field names to their definitions.
+func schemaFields(schema map[string]interface{}) map[string]map[string]interface{} {
+	fields := make(map[string]map[string]interface{})
+	list, _ := schema["fields"].([]interface{})
+	for _, f := range list {
+		field, ok := f.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		for name, definition := range field {
+			if d, ok := definition.(map[string]interface{}); ok {
+				fields[name] = d
+			}
+		}
+	}
+	return fields
+}
+
+func fieldsFromSchema(schema map[string]interface{}) map[string]Field {
+	fields := make(map[string]Field)
+	for name, definition := range schemaFields(schema) {
+		field := Field{}
+		field.Type, _ = definition["type"].(string)
+		if elements, ok := definition["elements"].(map[string]interface{}); ok {
+			field.ElementsType, _ = elements["type"].(string)
+		}
+		fields[name] = field
+	}
+	return fields
+}
+
+func requiredFieldsFromSchema(schema map[string]interface{}) []string {
+	var required []string
+	for name, definition := range schemaFields(schema) {
+		if isRequired, _ := definition["required"].(bool); !isRequired {
+			continue
+		}
+		if isAuto, _ := definition["auto"].(bool); isAuto {
+			continue
+		}
+		if _, hasDefault := definition["default"]; hasDefault {
+			continue
+		}
+		if definition["type"] == "foreign" {
+			continue
+		}
+		required = append(required, name)
+	}
+	sort.Strings(required)
+	return required
+}
+
+func isConsumerCredentialSchema(schema map[string]interface{}) bool {
+	consumer, ok := schemaFields(schema)["consumer"]
+	return ok && consumer["type"] == "foreign" && consumer["reference"] == "consumers"
+}
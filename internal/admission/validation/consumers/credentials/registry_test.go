@@ -0,0 +1,312 @@
+package credentials
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/kong/kubernetes-ingress-controller/v2/internal/labels"
+)
+
+type fakeSchemaGetter map[string]map[string]interface{}
+
+func (f fakeSchemaGetter) EntitySchema(_ context.Context, entityName string) (map[string]interface{}, error) {
+	schema, ok := f[entityName]
+	if !ok {
+		return nil, errors.New("Not found")
+	}
+	return schema, nil
+}
+
+// countingSchemaGetter counts schema requests and fails those for entities it doesn't know.
+type countingSchemaGetter struct {
+	schemas fakeSchemaGetter
+	lock    sync.Mutex
+	calls   map[string]int
+}
+
+func (f *countingSchemaGetter) EntitySchema(ctx context.Context, entityName string) (map[string]interface{}, error) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	if f.calls == nil {
+		f.calls = make(map[string]int)
+	}
+	f.calls[entityName]++
+	return f.schemas.EntitySchema(ctx, entityName)
+}
+
+func (f *countingSchemaGetter) Calls(entityName string) int {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	return f.calls[entityName]
+}
+
+var keyAuthEncSchema = map[string]interface{}{
+	"fields": []interface{}{
+		map[string]interface{}{"id": map[string]interface{}{"type": "string", "auto": true, "required": true}},
+		map[string]interface{}{"consumer": map[string]interface{}{"type": "foreign", "reference": "consumers", "required": true}},
+		map[string]interface{}{"key": map[string]interface{}{"type": "string", "required": true}},
+		map[string]interface{}{"ttl": map[string]interface{}{"type": "integer"}},
+		map[string]interface{}{"tags": map[string]interface{}{"type": "set", "elements": map[string]interface{}{"type": "string"}}},
+	},
+}
+
+func TestRegistry_Lookup(t *testing.T) {
+	schemas := fakeSchemaGetter{
+		"keyauth_enc_credentials": keyAuthEncSchema,
+		"oauth2_credentials": {
+			"fields": []interface{}{
+				map[string]interface{}{"redirect_uris": map[string]interface{}{"type": "array", "elements": map[string]interface{}{"type": "string"}}},
+				map[string]interface{}{"hash_secret": map[string]interface{}{"type": "boolean", "default": false}},
+			},
+		},
+		"vaults": {
+			"fields": []interface{}{
+				map[string]interface{}{"prefix": map[string]interface{}{"type": "string", "required": true}},
+			},
+		},
+	}
+
+	testCases := []struct {
+		name          string
+		registry      *Registry
+		credType      string
+		expectedType  Type
+		expectedError string
+	}{
+		{
+			name:     "built-in type without schemas",
+			registry: NewRegistry(nil, false),
+			credType: "key-auth",
+			expectedType: Type{
+				Name:           "key-auth",
+				Entity:         "keyauth_credentials",
+				Builtin:        true,
+				RequiredFields: KeyAuthFields,
+				Fields:         map[string]Field{"ttl": {Type: "integer"}},
+			},
+		},
+		{
+			name:     "built-in type referenced by its entity name",
+			registry: NewRegistry(nil, false),
+			credType: "keyauth_credentials",
+			expectedType: Type{
+				Name:           "key-auth",
+				Entity:         "keyauth_credentials",
+				Builtin:        true,
+				RequiredFields: KeyAuthFields,
+				Fields:         map[string]Field{"ttl": {Type: "integer"}},
+			},
+		},
+		{
+			name:     "built-in type with fields from schema",
+			registry: NewRegistry(schemas, false),
+			credType: "oauth2",
+			expectedType: Type{
+				Name:           "oauth2",
+				Entity:         "oauth2_credentials",
+				Builtin:        true,
+				RequiredFields: OAUTH2AuthFields,
+				Fields: map[string]Field{
+					"redirect_uris": {Type: "array", ElementsType: "string"},
+					"hash_secret":   {Type: "boolean"},
+				},
+			},
+		},
+		{
+			name:     "built-in type falls back to its fields when schema is unavailable",
+			registry: NewRegistry(schemas, false),
+			credType: "key-auth",
+			expectedType: Type{
+				Name:           "key-auth",
+				Entity:         "keyauth_credentials",
+				Builtin:        true,
+				RequiredFields: KeyAuthFields,
+				Fields:         map[string]Field{"ttl": {Type: "integer"}},
+			},
+		},
+		{
+			name:     "custom type",
+			registry: NewRegistry(schemas, true),
+			credType: "keyauth_enc_credentials",
+			expectedType: Type{
+				Name:           "keyauth_enc_credentials",
+				Entity:         "keyauth_enc_credentials",
+				RequiredFields: []string{"key"},
+				Fields: map[string]Field{
+					"id":       {Type: "string"},
+					"consumer": {Type: "foreign"},
+					"key":      {Type: "string"},
+					"ttl":      {Type: "integer"},
+					"tags":     {Type: "set", ElementsType: "string"},
+				},
+			},
+		},
+		{
+			name:          "custom type with custom types disabled",
+			registry:      NewRegistry(schemas, false),
+			credType:      "keyauth_enc_credentials",
+			expectedError: `unsupported credential type: "keyauth_enc_credentials"`,
+		},
+		{
+			name:          "custom type without schemas",
+			registry:      NewRegistry(nil, true),
+			credType:      "keyauth_enc_credentials",
+			expectedError: `unsupported credential type: "keyauth_enc_credentials"`,
+		},
+		{
+			name:          "unknown entity",
+			registry:      NewRegistry(schemas, true),
+			credType:      "bee-auth",
+			expectedError: `unsupported credential type: "bee-auth": Not found`,
+		},
+		{
+			name:          "entity not belonging to consumers",
+			registry:      NewRegistry(schemas, true),
+			credType:      "vaults",
+			expectedError: `unsupported credential type: "vaults": entity does not belong to consumers`,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			credType, err := tc.registry.Lookup(context.Background(), tc.credType)
+			if tc.expectedError != "" {
+				require.EqualError(t, err, tc.expectedError)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedType, credType)
+		})
+	}
+}
+
+func TestType_Coerce(t *testing.T) {
+	credType := Type{
+		Fields: map[string]Field{
+			"ttl":           {Type: "integer"},
+			"hash_secret":   {Type: "boolean"},
+			"redirect_uris": {Type: "array", ElementsType: "string"},
+			"ports":         {Type: "set", ElementsType: "integer"},
+			"weight":        {Type: "number"},
+			"metadata":      {Type: "map"},
+		},
+	}
+
+	config, errs := credType.Coerce(map[string][]byte{
+		TypeKey:         []byte("key-auth"),
+		"key":           []byte("little-rabbits-be-good"),
+		"ttl":           []byte("1024"),
+		"hash_secret":   []byte("true"),
+		"redirect_uris": []byte("http://example.com,http://example.org"),
+		"ports":         []byte("80,443"),
+		"weight":        []byte("0.5"),
+		"metadata":      []byte(`{"team":"a"}`),
+	})
+	require.Empty(t, errs)
+	assert.Equal(t, map[string]interface{}{
+		"key":           "little-rabbits-be-good",
+		"ttl":           1024,
+		"hash_secret":   true,
+		"redirect_uris": []interface{}{"http://example.com", "http://example.org"},
+		"ports":         []interface{}{80, 443},
+		"weight":        0.5,
+		"metadata":      map[string]interface{}{"team": "a"},
+	}, config)
+
+	config, errs = credType.Coerce(map[string][]byte{
+		"key": []byte("little-rabbits-be-good"),
+		"ttl": []byte("forever"),
+	})
+	require.Len(t, errs, 1)
+	assert.EqualError(t, errs[0], `failed to parse ttl as integer: strconv.Atoi: parsing "forever": invalid syntax, skipping the field`)
+	assert.Equal(t, map[string]interface{}{"key": "little-rabbits-be-good"}, config)
+}
+
+func TestRegistry_ValidateCustomCredentials(t *testing.T) {
+	registry := NewRegistry(fakeSchemaGetter{"keyauth_enc_credentials": keyAuthEncSchema}, true)
+
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "secret",
+			Namespace: "default",
+			Labels: map[string]string{
+				labels.LabelPrefix + labels.CredentialKey: "keyauth_enc_credentials",
+			},
+		},
+		Data: map[string][]byte{
+			"ttl": []byte("60"),
+		},
+	}
+	require.EqualError(t, registry.ValidateCredentials(context.Background(), secret), "missing required field(s): key")
+
+	secret.Data["key"] = []byte("little-rabbits-be-good")
+	require.NoError(t, registry.ValidateCredentials(context.Background(), secret))
+}
+
+func TestRegistry_Lookup_Caching(t *testing.T) {
+	schemas := &countingSchemaGetter{schemas: fakeSchemaGetter{"keyauth_enc_credentials": keyAuthEncSchema}}
+	registry := NewRegistry(schemas, true)
+	now := time.Now()
+	registry.now = func() time.Time { return now }
+
+	t.Log("resolved types are cached")
+	_, err := registry.Lookup(context.Background(), "keyauth_enc_credentials")
+	require.NoError(t, err)
+	_, err = registry.Lookup(context.Background(), "keyauth_enc_credentials")
+	require.NoError(t, err)
+	require.Equal(t, 1, schemas.Calls("keyauth_enc_credentials"))
+
+	t.Log("failures to resolve types are cached as well")
+	_, err = registry.Lookup(context.Background(), "bee-auth")
+	require.Error(t, err)
+	_, err = registry.Lookup(context.Background(), "bee-auth")
+	require.EqualError(t, err, `unsupported credential type: "bee-auth": Not found`)
+	require.Equal(t, 1, schemas.Calls("bee-auth"))
+
+	t.Log("types are resolved again once their cache entries expire")
+	now = now.Add(typesCacheTTL + time.Second)
+	_, err = registry.Lookup(context.Background(), "bee-auth")
+	require.Error(t, err)
+	require.Equal(t, 2, schemas.Calls("bee-auth"))
+}
+
+func TestRegistry_LookupCached(t *testing.T) {
+	schemas := &countingSchemaGetter{schemas: fakeSchemaGetter{"keyauth_enc_credentials": keyAuthEncSchema}}
+	registry := NewRegistry(schemas, true)
+
+	t.Log("types not cached yet are reported without waiting for the Gateway")
+	_, err := registry.LookupCached("keyauth_enc_credentials")
+	require.EqualError(t, err, `unsupported credential type: "keyauth_enc_credentials": its schema hasn't been retrieved from the Gateway yet`)
+	builtin, err := registry.LookupCached("key-auth")
+	require.NoError(t, err)
+	require.Equal(t, "keyauth_credentials", builtin.Entity)
+	require.Equal(t, map[string]Field{"ttl": {Type: "integer"}}, builtin.Fields)
+
+	t.Log("types are resolved in the background and returned from the cache afterwards")
+	require.Eventually(t, func() bool {
+		_, err := registry.LookupCached("keyauth_enc_credentials")
+		return err == nil
+	}, time.Second, 10*time.Millisecond)
+	credType, err := registry.LookupCached("keyauth_enc_credentials")
+	require.NoError(t, err)
+	require.Equal(t, []string{"key"}, credType.RequiredFields)
+	require.Equal(t, 1, schemas.Calls("keyauth_enc_credentials"))
+
+	t.Log("failures to resolve types are cached as well")
+	require.Eventually(t, func() bool {
+		_, err := registry.LookupCached("bee-auth")
+		return err != nil && err.Error() == `unsupported credential type: "bee-auth": Not found`
+	}, time.Second, 10*time.Millisecond)
+	_, err = registry.LookupCached("bee-auth")
+	require.Error(t, err)
+	require.Equal(t, 1, schemas.Calls("bee-auth"))
+}
@@ -2,6 +2,7 @@
 package credentials
 
 import (
+	"context"
 	"fmt"
 	"strings"
 
@@ -17,9 +18,8 @@ import (
 
 // ValidateCredentials performs basic validation on a credential secret given
 // the Kubernetes secret which contains credentials data.
-func ValidateCredentials(secret *corev1.Secret) error {
+func (r *Registry) ValidateCredentials(ctx context.Context, secret *corev1.Secret) error {
 	credentialType, credentialSource := util.ExtractKongCredentialType(secret)
-
 	if credentialSource == util.CredentialTypeAbsent {
 		// this shouldn't occur, since we check this earlier in the admission controller's handleSecret function, but
 		// checking here also in case a refactor removes that
@@ -27,14 +27,15 @@ func ValidateCredentials(secret *corev1.Secret) error {
 	}
 
 	// verify that the credential type provided is valid
-	if !SupportedTypes.Has(credentialType) {
+	t, err := r.Lookup(ctx, credentialType)
+	if err != nil {
 		return fmt.Errorf("invalid credential type %s", credentialType)
 	}
 
 	// verify that all required fields are present
 	var missingFields []string
 	var missingDataFields []string
-	for _, field := range CredTypeToFields[credentialType] {
+	for _, field := range t.RequiredFields {
 		// verify whether the required field is missing
 		requiredData, ok := secret.Data[field]
 		if !ok {
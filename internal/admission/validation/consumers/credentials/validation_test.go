@@ -1,6 +1,7 @@
 package credentials
 
 import (
+	"context"
 	"fmt"
 	"testing"
 
@@ -166,7 +167,7 @@ func TestValidateCredentials(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			err := ValidateCredentials(tt.secret)
+			err := NewRegistry(nil, false).ValidateCredentials(context.Background(), tt.secret)
 			require.Equal(t, tt.wantErr, err)
 		})
 	}
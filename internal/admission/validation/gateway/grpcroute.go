@@ -0,0 +1,72 @@
+package gateway
+
+import (
+	"context"
+
+	"github.com/samber/lo"
+
+	"github.com/kong/kubernetes-ingress-controller/v2/internal/dataplane/parser"
+	"github.com/kong/kubernetes-ingress-controller/v2/internal/gatewayapi"
+)
+
+const grpcRouteKind = "GRPCRoute"
+
+// -----------------------------------------------------------------------------
+// Validation - GRPCRoute - Public Functions
+// -----------------------------------------------------------------------------
+
+// ValidateGRPCRoute provides a suite of validation for a given GRPCRoute and
+// any number of Gateway resources it's attached to that the caller wants to
+// have it validated against. It checks supported features, linked objects,
+// and uses provided routesValidator to validate the route against Kong Gateway
+// validation endpoint.
+func ValidateGRPCRoute(
+	ctx context.Context,
+	routesValidator routeValidator,
+	parserFeatures parser.FeatureFlags,
+	grpcroute *gatewayapi.GRPCRoute,
+	attachedGateways ...*gatewayapi.Gateway,
+) (bool, string, error) {
+	// validate that no unsupported features are in use
+	if err := validateGRPCRouteFeatures(grpcroute); err != nil {
+		return false, "grpcroute spec did not pass validation", err
+	}
+
+	// perform Gateway validations for the GRPCRoute (e.g. listener validation, namespace validation, e.t.c.)
+	if msg, err := validateRouteGateways(
+		grpcRouteKind,
+		grpcroute.Namespace,
+		grpcroute.Spec.ParentRefs,
+		[]gatewayapi.ProtocolType{gatewayapi.HTTPProtocolType, gatewayapi.HTTPSProtocolType},
+		attachedGateways,
+	); err != nil {
+		return false, msg, err
+	}
+
+	// Translate GRPCRoute to Kong Route object(s) that can be sent directly to the Admin API for validation.
+	kongRoutes, err := parser.GenerateKongRoutesFromGRPCRoute(grpcroute, parserFeatures.ExpressionRoutes)
+	if err != nil {
+		return false, validationMsg(grpcRouteKind, []string{err.Error()}), nil
+	}
+	// Validate by using feature of Kong Gateway.
+	return validateKongRoutes(ctx, routesValidator, grpcRouteKind, kongRoutes)
+}
+
+// -----------------------------------------------------------------------------
+// Validation - GRPCRoute - Private Functions
+// -----------------------------------------------------------------------------
+
+// validateGRPCRouteFeatures checks for features that are not supported by this
+// GRPCRoute implementation and validates that the provided object is not using
+// any of those unsupported features.
+func validateGRPCRouteFeatures(grpcroute *gatewayapi.GRPCRoute) error {
+	for _, rule := range grpcroute.Spec.Rules {
+		backendRefs := lo.Map(rule.BackendRefs, func(ref gatewayapi.GRPCBackendRef, _ int) gatewayapi.BackendRef {
+			return ref.BackendRef
+		})
+		if err := validateBackendRefsFeatures(grpcRouteKind, backendRefs); err != nil {
+			return err
+		}
+	}
+	return nil
+}
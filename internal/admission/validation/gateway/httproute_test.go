@@ -11,6 +11,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	"github.com/kong/kubernetes-ingress-controller/v2/internal/dataplane/parser"
+	"github.com/kong/kubernetes-ingress-controller/v2/internal/dataplane/parser/translators"
 	"github.com/kong/kubernetes-ingress-controller/v2/internal/gatewayapi"
 )
 
@@ -24,12 +25,13 @@ func TestValidateHTTPRoute(t *testing.T) {
 	)
 
 	for _, tt := range []struct {
-		msg           string
-		route         *gatewayapi.HTTPRoute
-		gateways      []*gatewayapi.Gateway
-		valid         bool
-		validationMsg string
-		err           error
+		msg            string
+		route          *gatewayapi.HTTPRoute
+		gateways       []*gatewayapi.Gateway
+		parserFeatures parser.FeatureFlags
+		valid          bool
+		validationMsg  string
+		err            error
 	}{
 		{
 			msg: "if you provide errant gateways for validation, it fails validation",
@@ -204,7 +206,59 @@ func TestValidateHTTPRoute(t *testing.T) {
 			err:           fmt.Errorf("HTTPRoute not supported by listener http-alternate"),
 		},
 		{
-			msg: "if an HTTPRoute is using queryparams matching it fails validation due to only supporting expression router",
+			msg: "if an HTTPRoute is using queryparams matching it passes validation with traditional router when query params guard plugin is available",
+			route: &gatewayapi.HTTPRoute{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: corev1.NamespaceDefault,
+					Name:      "testing-httproute",
+				},
+				Spec: gatewayapi.HTTPRouteSpec{
+					CommonRouteSpec: gatewayapi.CommonRouteSpec{
+						ParentRefs: []gatewayapi.ParentReference{{
+							Name: "testing-gateway",
+						}},
+					},
+					Rules: []gatewayapi.HTTPRouteRule{{
+						Matches: []gatewayapi.HTTPRouteMatch{{
+							QueryParams: []gatewayapi.HTTPQueryParamMatch{{
+								Name:  "user-agent",
+								Value: "netscape navigator",
+							}},
+						}},
+						BackendRefs: []gatewayapi.HTTPBackendRef{{
+							BackendRef: gatewayapi.BackendRef{
+								BackendObjectReference: gatewayapi.BackendObjectReference{
+									Namespace: &defaultGWNamespace,
+								},
+							},
+						}},
+					}},
+				},
+			},
+			gateways: []*gatewayapi.Gateway{{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: corev1.NamespaceDefault,
+					Name:      "testing-gateway",
+				},
+				Spec: gatewayapi.GatewaySpec{
+					Listeners: []gatewayapi.Listener{{
+						Name:     "http",
+						Port:     80,
+						Protocol: (gatewayapi.HTTPProtocolType),
+						AllowedRoutes: &gatewayapi.AllowedRoutes{
+							Kinds: []gatewayapi.RouteGroupKind{{
+								Group: &group,
+								Kind:  "HTTPRoute",
+							}},
+						},
+					}},
+				},
+			}},
+			parserFeatures: parser.FeatureFlags{QueryParamsGuardPlugin: true},
+			valid:          true,
+		},
+		{
+			msg: "if an HTTPRoute is using queryparams matching it fails validation with traditional router when query params guard plugin is not available",
 			route: &gatewayapi.HTTPRoute{
 				ObjectMeta: metav1.ObjectMeta{
 					Namespace: corev1.NamespaceDefault,
@@ -254,7 +308,7 @@ func TestValidateHTTPRoute(t *testing.T) {
 			}},
 			valid:         false,
 			validationMsg: "httproute spec did not pass validation",
-			err:           fmt.Errorf("queryparam matching is supported with expression router only"),
+			err:           translators.ErrRouteValidationQueryParamMatchesUnsupported,
 		},
 		{
 			msg: "we don't support any group except core kubernetes for backendRefs",
@@ -374,7 +428,7 @@ func TestValidateHTTPRoute(t *testing.T) {
 	} {
 		// Passed routesValidator is irrelevant for the above test cases.
 		valid, validMsg, err := ValidateHTTPRoute(
-			context.Background(), mockRoutesValidator{}, parser.FeatureFlags{}, tt.route, tt.gateways...,
+			context.Background(), mockRoutesValidator{}, tt.parserFeatures, tt.route, tt.gateways...,
 		)
 		assert.Equal(t, tt.valid, valid, tt.msg)
 		assert.Equal(t, tt.validationMsg, validMsg, tt.msg)
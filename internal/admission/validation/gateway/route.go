@@ -0,0 +1,194 @@
+package gateway
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/kong/go-kong/kong"
+	"github.com/samber/lo"
+
+	"github.com/kong/kubernetes-ingress-controller/v2/internal/dataplane/kongstate"
+	"github.com/kong/kubernetes-ingress-controller/v2/internal/gatewayapi"
+)
+
+type routeValidator interface {
+	Validate(context.Context, *kong.Route) (bool, string, error)
+}
+
+// -----------------------------------------------------------------------------
+// Validation - Routes - Private Functions
+// -----------------------------------------------------------------------------
+
+// validateRouteGateways performs Gateway validations for a route of the given kind
+// (e.g. listener validation, namespace validation, e.t.c.). Listeners are matched by
+// the provided protocols when a parentRef doesn't specify a sectionName.
+func validateRouteGateways(
+	routeKind string,
+	routeNamespace string,
+	parentRefs []gatewayapi.ParentReference,
+	listenerProtocols []gatewayapi.ProtocolType,
+	attachedGateways []*gatewayapi.Gateway,
+) (string, error) {
+	kindLower := strings.ToLower(routeKind)
+	for _, gateway := range attachedGateways {
+		// TODO: validate that the namespace is supported by the linked Gateway objects
+		//       See: https://github.com/Kong/kubernetes-ingress-controller/issues/2080
+
+		// determine the parentRef for this gateway
+		parentRef, err := getParentRefForRouteGateway(routeNamespace, parentRefs, gateway)
+		if err != nil {
+			return fmt.Sprintf("couldn't determine parentRefs for %s", kindLower), err
+		}
+
+		// gather the relevant gateway listeners for the route
+		listeners, err := getListenersForRouteValidation(parentRef.SectionName, listenerProtocols, gateway)
+		if err != nil {
+			return fmt.Sprintf("couldn't find gateway listeners for %s", kindLower), err
+		}
+
+		// perform validation of this route against it's linked gateway listeners
+		for _, listener := range listeners {
+			if err := validateRouteListener(routeKind, listener); err != nil {
+				return fmt.Sprintf("%s linked gateway listeners did not pass validation", kindLower), err
+			}
+		}
+	}
+	return "", nil
+}
+
+// validateRouteListener verifies that a route of the given kind is configured properly
+// for a given gateway listener which it is linked to.
+func validateRouteListener(routeKind string, listener *gatewayapi.Listener) error {
+	// verify that the listener supports the route kind
+	if listener.AllowedRoutes != nil && // if there are no allowed routes, assume all are allowed
+		len(listener.AllowedRoutes.Kinds) > 0 { // if there are no allowed kinds, assume all are allowed
+		// search each of the allowedRoutes in the listener to verify that the route kind is supported
+		supported := false
+		for _, allowedKind := range listener.AllowedRoutes.Kinds {
+			if string(allowedKind.Kind) == routeKind {
+				supported = true
+			}
+		}
+
+		// verify that we found a supported kind
+		if !supported {
+			return fmt.Errorf("%s not supported by listener %s", routeKind, listener.Name)
+		}
+	}
+
+	return nil
+}
+
+// validateBackendRefsFeatures validates that backendRefs of a route of the given kind
+// don't use features that are not supported by this implementation.
+func validateBackendRefsFeatures(routeKind string, backendRefs []gatewayapi.BackendRef) error {
+	kindLower := strings.ToLower(routeKind)
+	// We don't support any backendRef types except Kubernetes Services.
+	for _, ref := range backendRefs {
+		if ref.Group != nil && *ref.Group != "core" && *ref.Group != "" {
+			return fmt.Errorf("%s is not a supported group for %s backendRefs, only core is supported", *ref.Group, kindLower)
+		}
+		if ref.Kind != nil && *ref.Kind != "Service" {
+			return fmt.Errorf("%s is not a supported kind for %s backendRefs, only Service is supported", *ref.Kind, kindLower)
+		}
+	}
+	return nil
+}
+
+// validateKongRoutes uses provided routesValidator to validate translated routes
+// against Kong Gateway validation endpoint.
+func validateKongRoutes(
+	ctx context.Context, routesValidator routeValidator, routeKind string, routes []kongstate.Route,
+) (bool, string, error) {
+	var errMsgs []string
+	for _, r := range routes {
+		kg := r.Route
+		ok, msg, err := routesValidator.Validate(ctx, &kg)
+		if err != nil {
+			return false, fmt.Sprintf("unable to validate %s schema: %s", routeKind, err.Error()), nil
+		}
+		if !ok {
+			errMsgs = append(errMsgs, msg)
+		}
+	}
+	if len(errMsgs) > 0 {
+		return false, validationMsg(routeKind, errMsgs), nil
+	}
+	return true, "", nil
+}
+
+// -----------------------------------------------------------------------------
+// Validation - Routes - Private Utility Functions
+// -----------------------------------------------------------------------------
+
+// getParentRefForRouteGateway extracts an existing parentRef from parentRefs of a route
+// which links to the provided Gateway if available. If the provided Gateway is not
+// actually referenced by parentRef in the provided route this is considered
+// invalid input and will produce an error.
+func getParentRefForRouteGateway(
+	routeNamespace string, parentRefs []gatewayapi.ParentReference, gateway *gatewayapi.Gateway,
+) (*gatewayapi.ParentReference, error) {
+	// search all the parentRefs on the route to find one that matches the Gateway
+	for _, ref := range parentRefs {
+		// determine the namespace for the gateway reference
+		namespace := routeNamespace
+		if ref.Namespace != nil {
+			namespace = string(*ref.Namespace)
+		}
+
+		// match the gateway with its parentRef
+		if gateway.Namespace == namespace && gateway.Name == string(ref.Name) {
+			copyRef := ref
+			return &copyRef, nil
+		}
+	}
+
+	// if no matches could be found then the input is invalid
+	return nil, fmt.Errorf("no parentRef matched gateway %s/%s", gateway.Namespace, gateway.Name)
+}
+
+// getListenersForRouteValidation determines if ALL listeners with the provided protocols should be
+// used for validation or if only a select listener should be considered.
+func getListenersForRouteValidation(
+	sectionName *gatewayapi.SectionName, protocols []gatewayapi.ProtocolType, gateway *gatewayapi.Gateway,
+) ([]*gatewayapi.Listener, error) {
+	var listenersForValidation []*gatewayapi.Listener
+	if sectionName != nil {
+		// only one specified listener is in use, only need to validate the
+		// route against that listener.
+		for _, listener := range gateway.Spec.Listeners {
+			if string(listener.Name) == string(*sectionName) {
+				listenerCopy := listener
+				listenersForValidation = append(listenersForValidation, &listenerCopy)
+			}
+		}
+
+		// if the sectionName isn't empty, we need to verify that we actually found
+		// a listener which matched it, otherwise the object is invalid.
+		if len(listenersForValidation) == 0 {
+			return nil, fmt.Errorf("sectionname referenced listener %s was not found on gateway %s/%s", *sectionName, gateway.Namespace, gateway.Name)
+		}
+	} else {
+		// no specific listener was chosen, so we'll simply validate against
+		// all listeners with matching protocols on the Gateway.
+		for _, listener := range gateway.Spec.Listeners {
+			if lo.Contains(protocols, listener.Protocol) {
+				listenerCopy := listener
+				listenersForValidation = append(listenersForValidation, &listenerCopy)
+			}
+		}
+	}
+
+	// if for some reason the gateway has no listeners (it may be under active provisioning)
+	// the route fails validation because it has no listeners that can be used.
+	if len(listenersForValidation) == 0 {
+		return nil, fmt.Errorf("no listeners could be found for gateway %s/%s", gateway.Namespace, gateway.Name)
+	}
+
+	return listenersForValidation, nil
+}
+
+func validationMsg(routeKind string, errMsgs []string) string {
+	return fmt.Sprintf("%s failed schema validation: %s", routeKind, strings.Join(errMsgs, ", "))
+}
@@ -0,0 +1,281 @@
+package gateway
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/kong/go-kong/kong"
+	"github.com/samber/lo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/kong/kubernetes-ingress-controller/v2/internal/dataplane/parser"
+	"github.com/kong/kubernetes-ingress-controller/v2/internal/gatewayapi"
+)
+
+func routeValidationTestGateway(listeners ...gatewayapi.Listener) *gatewayapi.Gateway {
+	return &gatewayapi.Gateway{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: corev1.NamespaceDefault,
+			Name:      "testing-gateway",
+		},
+		Spec: gatewayapi.GatewaySpec{
+			Listeners: listeners,
+		},
+	}
+}
+
+func routeValidationTestParentRefs(sectionName string) []gatewayapi.ParentReference {
+	ref := gatewayapi.ParentReference{Name: "testing-gateway"}
+	if sectionName != "" {
+		ref.SectionName = (*gatewayapi.SectionName)(&sectionName)
+	}
+	return []gatewayapi.ParentReference{ref}
+}
+
+func routeValidationTestBackendRefs(kind string) []gatewayapi.BackendRef {
+	return []gatewayapi.BackendRef{{
+		BackendObjectReference: gatewayapi.BackendObjectReference{
+			Kind: (*gatewayapi.Kind)(&kind),
+			Name: "service",
+			Port: lo.ToPtr(gatewayapi.PortNumber(80)),
+		},
+	}}
+}
+
+func TestValidateGRPCRoute(t *testing.T) {
+	grpcRoute := func(backendKind string) *gatewayapi.GRPCRoute {
+		return &gatewayapi.GRPCRoute{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: corev1.NamespaceDefault,
+				Name:      "testing-grpcroute",
+			},
+			Spec: gatewayapi.GRPCRouteSpec{
+				CommonRouteSpec: gatewayapi.CommonRouteSpec{ParentRefs: routeValidationTestParentRefs("")},
+				Hostnames:       []gatewayapi.Hostname{"grpc.example.com"},
+				Rules: []gatewayapi.GRPCRouteRule{{
+					Matches: []gatewayapi.GRPCRouteMatch{{
+						Method: &gatewayapi.GRPCMethodMatch{
+							Service: kong.String("grpcbin.GRPCBin"),
+							Method:  kong.String("DummyUnary"),
+						},
+					}},
+					BackendRefs: []gatewayapi.GRPCBackendRef{{BackendRef: routeValidationTestBackendRefs(backendKind)[0]}},
+				}},
+			},
+		}
+	}
+	httpsListener := gatewayapi.Listener{Name: "https", Port: 443, Protocol: gatewayapi.HTTPSProtocolType}
+
+	for _, tt := range []struct {
+		msg             string
+		route           *gatewayapi.GRPCRoute
+		gateway         *gatewayapi.Gateway
+		routesValidator routeValidator
+		expressions     bool
+		valid           bool
+		validationMsg   string
+		err             error
+	}{
+		{
+			msg:             "valid route attached to an HTTPS listener passes validation",
+			route:           grpcRoute("Service"),
+			gateway:         routeValidationTestGateway(httpsListener),
+			routesValidator: mockRoutesValidator{},
+			valid:           true,
+		},
+		{
+			msg:             "valid route passes validation with expressions router",
+			route:           grpcRoute("Service"),
+			gateway:         routeValidationTestGateway(httpsListener),
+			routesValidator: mockRoutesValidator{},
+			expressions:     true,
+			valid:           true,
+		},
+		{
+			msg:           "unsupported backendRef kind fails validation",
+			route:         grpcRoute("Pod"),
+			gateway:       routeValidationTestGateway(httpsListener),
+			validationMsg: "grpcroute spec did not pass validation",
+			err:           fmt.Errorf("Pod is not a supported kind for grpcroute backendRefs, only Service is supported"),
+		},
+		{
+			msg:   "gateway without HTTP listeners fails validation",
+			route: grpcRoute("Service"),
+			gateway: routeValidationTestGateway(gatewayapi.Listener{
+				Name: "tcp", Port: 8888, Protocol: gatewayapi.TCPProtocolType,
+			}),
+			validationMsg: "couldn't find gateway listeners for grpcroute",
+			err:           fmt.Errorf("no listeners could be found for gateway default/testing-gateway"),
+		},
+		{
+			msg:             "route rejected by Kong Gateway fails validation",
+			route:           grpcRoute("Service"),
+			gateway:         routeValidationTestGateway(httpsListener),
+			routesValidator: rejectingRoutesValidator{msg: "invalid route"},
+			validationMsg:   "GRPCRoute failed schema validation: invalid route",
+		},
+	} {
+		t.Run(tt.msg, func(t *testing.T) {
+			valid, validMsg, err := ValidateGRPCRoute(
+				context.Background(), tt.routesValidator, parser.FeatureFlags{ExpressionRoutes: tt.expressions}, tt.route, tt.gateway,
+			)
+			assert.Equal(t, tt.valid, valid)
+			assert.Equal(t, tt.validationMsg, validMsg)
+			assert.Equal(t, tt.err, err)
+		})
+	}
+}
+
+func TestValidateTCPRoute(t *testing.T) {
+	tcpRoute := func(sectionName string, rules ...gatewayapi.TCPRouteRule) *gatewayapi.TCPRoute {
+		return &gatewayapi.TCPRoute{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: corev1.NamespaceDefault,
+				Name:      "testing-tcproute",
+			},
+			Spec: gatewayapi.TCPRouteSpec{
+				CommonRouteSpec: gatewayapi.CommonRouteSpec{ParentRefs: routeValidationTestParentRefs(sectionName)},
+				Rules:           rules,
+			},
+		}
+	}
+	serviceRule := gatewayapi.TCPRouteRule{BackendRefs: routeValidationTestBackendRefs("Service")}
+	tcpListener := gatewayapi.Listener{
+		Name:     "tcp",
+		Port:     8888,
+		Protocol: gatewayapi.TCPProtocolType,
+		AllowedRoutes: &gatewayapi.AllowedRoutes{
+			Kinds: []gatewayapi.RouteGroupKind{{Kind: "TCPRoute"}},
+		},
+	}
+	httpListener := gatewayapi.Listener{
+		Name:     "http",
+		Port:     80,
+		Protocol: gatewayapi.HTTPProtocolType,
+		AllowedRoutes: &gatewayapi.AllowedRoutes{
+			Kinds: []gatewayapi.RouteGroupKind{{Kind: "HTTPRoute"}},
+		},
+	}
+
+	for _, tt := range []struct {
+		msg           string
+		route         *gatewayapi.TCPRoute
+		gateway       *gatewayapi.Gateway
+		valid         bool
+		validationMsg string
+		err           error
+	}{
+		{
+			msg:     "valid route attached to a TCP listener passes validation",
+			route:   tcpRoute("", serviceRule),
+			gateway: routeValidationTestGateway(httpListener, tcpListener),
+			valid:   true,
+		},
+		{
+			msg:           "route attached to a listener not allowing TCPRoutes fails validation",
+			route:         tcpRoute("http", serviceRule),
+			gateway:       routeValidationTestGateway(httpListener, tcpListener),
+			validationMsg: "tcproute linked gateway listeners did not pass validation",
+			err:           fmt.Errorf("TCPRoute not supported by listener http"),
+		},
+		{
+			msg:           "route not referencing the gateway fails validation",
+			route:         &gatewayapi.TCPRoute{ObjectMeta: metav1.ObjectMeta{Namespace: corev1.NamespaceDefault, Name: "testing-tcproute"}},
+			gateway:       routeValidationTestGateway(tcpListener),
+			validationMsg: "couldn't determine parentRefs for tcproute",
+			err:           fmt.Errorf("no parentRef matched gateway default/testing-gateway"),
+		},
+		{
+			msg:           "route without backendRefs fails translation",
+			route:         tcpRoute("", gatewayapi.TCPRouteRule{}),
+			gateway:       routeValidationTestGateway(tcpListener),
+			validationMsg: "TCPRoute failed schema validation: missing backendRef in rule",
+		},
+	} {
+		t.Run(tt.msg, func(t *testing.T) {
+			valid, validMsg, err := ValidateTCPRoute(
+				context.Background(), mockRoutesValidator{}, parser.FeatureFlags{}, tt.route, tt.gateway,
+			)
+			assert.Equal(t, tt.valid, valid)
+			assert.Equal(t, tt.validationMsg, validMsg)
+			assert.Equal(t, tt.err, err)
+		})
+	}
+}
+
+func TestValidateTLSRoute(t *testing.T) {
+	tlsListener := gatewayapi.Listener{Name: "tls", Port: 8899, Protocol: gatewayapi.TLSProtocolType}
+	route := &gatewayapi.TLSRoute{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: corev1.NamespaceDefault,
+			Name:      "testing-tlsroute",
+		},
+		Spec: gatewayapi.TLSRouteSpec{
+			CommonRouteSpec: gatewayapi.CommonRouteSpec{ParentRefs: routeValidationTestParentRefs("")},
+			Hostnames:       []gatewayapi.Hostname{"tls.example.com"},
+			Rules:           []gatewayapi.TLSRouteRule{{BackendRefs: routeValidationTestBackendRefs("Service")}},
+		},
+	}
+
+	valid, validMsg, err := ValidateTLSRoute(
+		context.Background(), mockRoutesValidator{}, parser.FeatureFlags{}, route, routeValidationTestGateway(tlsListener),
+	)
+	require.NoError(t, err)
+	assert.True(t, valid)
+	assert.Empty(t, validMsg)
+
+	t.Run("route without hostnames fails translation", func(t *testing.T) {
+		route := route.DeepCopy()
+		route.Spec.Hostnames = nil
+		valid, validMsg, err := ValidateTLSRoute(
+			context.Background(), mockRoutesValidator{}, parser.FeatureFlags{}, route, routeValidationTestGateway(tlsListener),
+		)
+		require.NoError(t, err)
+		assert.False(t, valid)
+		assert.Equal(t, "TLSRoute failed schema validation: no hostnames provided", validMsg)
+	})
+}
+
+func TestValidateUDPRoute(t *testing.T) {
+	udpListener := gatewayapi.Listener{Name: "udp", Port: 9999, Protocol: gatewayapi.UDPProtocolType}
+	route := &gatewayapi.UDPRoute{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: corev1.NamespaceDefault,
+			Name:      "testing-udproute",
+		},
+		Spec: gatewayapi.UDPRouteSpec{
+			CommonRouteSpec: gatewayapi.CommonRouteSpec{ParentRefs: routeValidationTestParentRefs("")},
+			Rules:           []gatewayapi.UDPRouteRule{{BackendRefs: routeValidationTestBackendRefs("Service")}},
+		},
+	}
+
+	for _, expressions := range []bool{false, true} {
+		valid, validMsg, err := ValidateUDPRoute(
+			context.Background(), mockRoutesValidator{}, parser.FeatureFlags{ExpressionRoutes: expressions}, route, routeValidationTestGateway(udpListener),
+		)
+		require.NoError(t, err)
+		assert.True(t, valid)
+		assert.Empty(t, validMsg)
+	}
+
+	t.Run("route rejected by Kong Gateway fails validation", func(t *testing.T) {
+		valid, validMsg, err := ValidateUDPRoute(
+			context.Background(), rejectingRoutesValidator{msg: "invalid route"}, parser.FeatureFlags{}, route, routeValidationTestGateway(udpListener),
+		)
+		require.NoError(t, err)
+		assert.False(t, valid)
+		assert.Equal(t, "UDPRoute failed schema validation: invalid route", validMsg)
+	})
+}
+
+type rejectingRoutesValidator struct {
+	msg string
+}
+
+func (v rejectingRoutesValidator) Validate(_ context.Context, _ *kong.Route) (bool, string, error) {
+	return false, v.msg, nil
+}
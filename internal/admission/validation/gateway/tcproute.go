@@ -0,0 +1,67 @@
+package gateway
+
+import (
+	"context"
+
+	"github.com/kong/kubernetes-ingress-controller/v2/internal/dataplane/parser"
+	"github.com/kong/kubernetes-ingress-controller/v2/internal/gatewayapi"
+)
+
+const tcpRouteKind = "TCPRoute"
+
+// -----------------------------------------------------------------------------
+// Validation - TCPRoute - Public Functions
+// -----------------------------------------------------------------------------
+
+// ValidateTCPRoute provides a suite of validation for a given TCPRoute and
+// any number of Gateway resources it's attached to that the caller wants to
+// have it validated against. It checks supported features, linked objects,
+// and uses provided routesValidator to validate the route against Kong Gateway
+// validation endpoint.
+func ValidateTCPRoute(
+	ctx context.Context,
+	routesValidator routeValidator,
+	parserFeatures parser.FeatureFlags,
+	tcproute *gatewayapi.TCPRoute,
+	attachedGateways ...*gatewayapi.Gateway,
+) (bool, string, error) {
+	// validate that no unsupported features are in use
+	if err := validateTCPRouteFeatures(tcproute); err != nil {
+		return false, "tcproute spec did not pass validation", err
+	}
+
+	// perform Gateway validations for the TCPRoute (e.g. listener validation, namespace validation, e.t.c.)
+	if msg, err := validateRouteGateways(
+		tcpRouteKind,
+		tcproute.Namespace,
+		tcproute.Spec.ParentRefs,
+		[]gatewayapi.ProtocolType{gatewayapi.TCPProtocolType},
+		attachedGateways,
+	); err != nil {
+		return false, msg, err
+	}
+
+	// Translate TCPRoute to Kong Route object(s) that can be sent directly to the Admin API for validation.
+	kongRoutes, err := parser.GenerateKongRoutesFromTCPRoute(tcproute, parserFeatures.ExpressionRoutes)
+	if err != nil {
+		return false, validationMsg(tcpRouteKind, []string{err.Error()}), nil
+	}
+	// Validate by using feature of Kong Gateway.
+	return validateKongRoutes(ctx, routesValidator, tcpRouteKind, kongRoutes)
+}
+
+// -----------------------------------------------------------------------------
+// Validation - TCPRoute - Private Functions
+// -----------------------------------------------------------------------------
+
+// validateTCPRouteFeatures checks for features that are not supported by this
+// TCPRoute implementation and validates that the provided object is not using
+// any of those unsupported features.
+func validateTCPRouteFeatures(tcproute *gatewayapi.TCPRoute) error {
+	for _, rule := range tcproute.Spec.Rules {
+		if err := validateBackendRefsFeatures(tcpRouteKind, rule.BackendRefs); err != nil {
+			return err
+		}
+	}
+	return nil
+}
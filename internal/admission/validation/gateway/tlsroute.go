@@ -0,0 +1,68 @@
+package gateway
+
+import (
+	"context"
+
+	"github.com/kong/kubernetes-ingress-controller/v2/internal/dataplane/parser"
+	"github.com/kong/kubernetes-ingress-controller/v2/internal/gatewayapi"
+)
+
+const tlsRouteKind = "TLSRoute"
+
+// -----------------------------------------------------------------------------
+// Validation - TLSRoute - Public Functions
+// -----------------------------------------------------------------------------
+
+// ValidateTLSRoute provides a suite of validation for a given TLSRoute and
+// any number of Gateway resources it's attached to that the caller wants to
+// have it validated against. It checks supported features, linked objects,
+// and uses provided routesValidator to validate the route against Kong Gateway
+// validation endpoint.
+// TLS passthrough configured on the Gateway listeners isn't taken into account.
+func ValidateTLSRoute(
+	ctx context.Context,
+	routesValidator routeValidator,
+	parserFeatures parser.FeatureFlags,
+	tlsroute *gatewayapi.TLSRoute,
+	attachedGateways ...*gatewayapi.Gateway,
+) (bool, string, error) {
+	// validate that no unsupported features are in use
+	if err := validateTLSRouteFeatures(tlsroute); err != nil {
+		return false, "tlsroute spec did not pass validation", err
+	}
+
+	// perform Gateway validations for the TLSRoute (e.g. listener validation, namespace validation, e.t.c.)
+	if msg, err := validateRouteGateways(
+		tlsRouteKind,
+		tlsroute.Namespace,
+		tlsroute.Spec.ParentRefs,
+		[]gatewayapi.ProtocolType{gatewayapi.TLSProtocolType},
+		attachedGateways,
+	); err != nil {
+		return false, msg, err
+	}
+
+	// Translate TLSRoute to Kong Route object(s) that can be sent directly to the Admin API for validation.
+	kongRoutes, err := parser.GenerateKongRoutesFromTLSRoute(tlsroute, parserFeatures.ExpressionRoutes)
+	if err != nil {
+		return false, validationMsg(tlsRouteKind, []string{err.Error()}), nil
+	}
+	// Validate by using feature of Kong Gateway.
+	return validateKongRoutes(ctx, routesValidator, tlsRouteKind, kongRoutes)
+}
+
+// -----------------------------------------------------------------------------
+// Validation - TLSRoute - Private Functions
+// -----------------------------------------------------------------------------
+
+// validateTLSRouteFeatures checks for features that are not supported by this
+// TLSRoute implementation and validates that the provided object is not using
+// any of those unsupported features.
+func validateTLSRouteFeatures(tlsroute *gatewayapi.TLSRoute) error {
+	for _, rule := range tlsroute.Spec.Rules {
+		if err := validateBackendRefsFeatures(tlsRouteKind, rule.BackendRefs); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,67 @@
+package gateway
+
+import (
+	"context"
+
+	"github.com/kong/kubernetes-ingress-controller/v2/internal/dataplane/parser"
+	"github.com/kong/kubernetes-ingress-controller/v2/internal/gatewayapi"
+)
+
+const udpRouteKind = "UDPRoute"
+
+// -----------------------------------------------------------------------------
+// Validation - UDPRoute - Public Functions
+// -----------------------------------------------------------------------------
+
+// ValidateUDPRoute provides a suite of validation for a given UDPRoute and
+// any number of Gateway resources it's attached to that the caller wants to
+// have it validated against. It checks supported features, linked objects,
+// and uses provided routesValidator to validate the route against Kong Gateway
+// validation endpoint.
+func ValidateUDPRoute(
+	ctx context.Context,
+	routesValidator routeValidator,
+	parserFeatures parser.FeatureFlags,
+	udproute *gatewayapi.UDPRoute,
+	attachedGateways ...*gatewayapi.Gateway,
+) (bool, string, error) {
+	// validate that no unsupported features are in use
+	if err := validateUDPRouteFeatures(udproute); err != nil {
+		return false, "udproute spec did not pass validation", err
+	}
+
+	// perform Gateway validations for the UDPRoute (e.g. listener validation, namespace validation, e.t.c.)
+	if msg, err := validateRouteGateways(
+		udpRouteKind,
+		udproute.Namespace,
+		udproute.Spec.ParentRefs,
+		[]gatewayapi.ProtocolType{gatewayapi.UDPProtocolType},
+		attachedGateways,
+	); err != nil {
+		return false, msg, err
+	}
+
+	// Translate UDPRoute to Kong Route object(s) that can be sent directly to the Admin API for validation.
+	kongRoutes, err := parser.GenerateKongRoutesFromUDPRoute(udproute, parserFeatures.ExpressionRoutes)
+	if err != nil {
+		return false, validationMsg(udpRouteKind, []string{err.Error()}), nil
+	}
+	// Validate by using feature of Kong Gateway.
+	return validateKongRoutes(ctx, routesValidator, udpRouteKind, kongRoutes)
+}
+
+// -----------------------------------------------------------------------------
+// Validation - UDPRoute - Private Functions
+// -----------------------------------------------------------------------------
+
+// validateUDPRouteFeatures checks for features that are not supported by this
+// UDPRoute implementation and validates that the provided object is not using
+// any of those unsupported features.
+func validateUDPRouteFeatures(udproute *gatewayapi.UDPRoute) error {
+	for _, rule := range udproute.Spec.Rules {
+		if err := validateBackendRefsFeatures(udpRouteKind, rule.BackendRefs); err != nil {
+			return err
+		}
+	}
+	return nil
+}
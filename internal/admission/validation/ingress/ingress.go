@@ -23,32 +23,70 @@ func ValidateIngress(
 	ingress *netv1.Ingress,
 ) (bool, string, error) {
 	// Validate by using feature of Kong Gateway.
+	errMsgs, err := validateKongRoutes(ctx, routesValidator, ingressToKongRoutesForValidation(
+		parserFeatures, ingress, kongv1alpha1.IngressClassParametersSpec{EnableLegacyRegexDetection: true},
+	))
+	if err != nil {
+		return false, fmt.Sprintf("unable to validate Ingress schema: %s", err.Error()), nil
+	}
+	if len(errMsgs) > 0 {
+		return false, fmt.Sprintf("Ingress failed schema validation: %s", strings.Join(errMsgs, ", ")), nil
+	}
+	return true, "", nil
+}
+
+// ValidateIngressClassParameters validates the given Ingresses of a class as if they were translated
+// with the provided IngressClassParameters to ensure that changing the parameters won't break them.
+func ValidateIngressClassParameters(
+	ctx context.Context,
+	routesValidator routeValidator,
+	parserFeatures parser.FeatureFlags,
+	params *kongv1alpha1.IngressClassParameters,
+	ingresses []*netv1.Ingress,
+) (bool, string, error) {
 	var errMsgs []string
-	for _, kg := range ingressToKongRoutesForValidation(parserFeatures, ingress) {
+	for _, ingress := range ingresses {
+		ingressErrMsgs, err := validateKongRoutes(ctx, routesValidator, ingressToKongRoutesForValidation(
+			parserFeatures, ingress, params.Spec,
+		))
+		if err != nil {
+			return false, fmt.Sprintf("unable to validate Ingress %s/%s schema: %s", ingress.Namespace, ingress.Name, err.Error()), nil
+		}
+		for _, msg := range ingressErrMsgs {
+			errMsgs = append(errMsgs, fmt.Sprintf("Ingress %s/%s: %s", ingress.Namespace, ingress.Name, msg))
+		}
+	}
+	if len(errMsgs) > 0 {
+		return false, fmt.Sprintf("IngressClassParameters would make Ingresses fail schema validation: %s", strings.Join(errMsgs, ", ")), nil
+	}
+	return true, "", nil
+}
+
+// validateKongRoutes validates routes with Kong Gateway and returns messages of the failed validations.
+func validateKongRoutes(ctx context.Context, routesValidator routeValidator, routes []kong.Route) ([]string, error) {
+	var errMsgs []string
+	for _, kg := range routes {
 		kg := kg
 		ok, msg, err := routesValidator.Validate(ctx, &kg)
 		if err != nil {
-			return false, fmt.Sprintf("unable to validate Ingress schema: %s", err.Error()), nil
+			return nil, err
 		}
 		if !ok {
 			errMsgs = append(errMsgs, msg)
 		}
 	}
-	if len(errMsgs) > 0 {
-		return false, fmt.Sprintf("Ingress failed schema validation: %s", strings.Join(errMsgs, ", ")), nil
-	}
-	return true, "", nil
+	return errMsgs, nil
 }
 
 // ingressToKongRoutesForValidation converts Ingress to Kong Routes that can be validated by Kong Gateway,
 // discards everything else that is not needed for validation.
 func ingressToKongRoutesForValidation(
-	parserFeatures parser.FeatureFlags, ingress *netv1.Ingress,
+	parserFeatures parser.FeatureFlags, ingress *netv1.Ingress, icp kongv1alpha1.IngressClassParametersSpec,
 ) []kong.Route {
 	kongServices := parser.IngressesV1ToKongServices(
 		parserFeatures,
 		[]*netv1.Ingress{ingress},
-		kongv1alpha1.IngressClassParametersSpec{EnableLegacyRegexDetection: true},
+		icp,
 		&parser.ObjectsCollector{}, // It's irrelevant for validation.
 	)
 
@@ -0,0 +1,100 @@
+package ingress
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/kong/go-kong/kong"
+
+	"github.com/kong/kubernetes-ingress-controller/v2/internal/dataplane/kongstate"
+	"github.com/kong/kubernetes-ingress-controller/v2/internal/dataplane/parser"
+	kongv1beta1 "github.com/kong/kubernetes-ingress-controller/v2/pkg/apis/configuration/v1beta1"
+)
+
+// ValidateTCPIngress validates a TCPIngress against other TCPIngresses of the same ingress class, that must not
+// accept traffic on the same ports and hosts, and uses provided routesValidator to validate the routes generated
+// for it against Kong Gateway validation endpoint.
+func ValidateTCPIngress(
+	ctx context.Context,
+	routesValidator routeValidator,
+	parserFeatures parser.FeatureFlags,
+	ingress *kongv1beta1.TCPIngress,
+	classIngresses []kongv1beta1.TCPIngress,
+) (bool, string, error) {
+	used := make(map[string]string)
+	for _, other := range classIngresses {
+		if other.Namespace == ingress.Namespace && other.Name == ingress.Name {
+			continue
+		}
+		for _, rule := range other.Spec.Rules {
+			used[tcpIngressRuleKey(rule)] = fmt.Sprintf("TCPIngress %s/%s", other.Namespace, other.Name)
+		}
+	}
+	for i, rule := range ingress.Spec.Rules {
+		key := tcpIngressRuleKey(rule)
+		if user, ok := used[key]; ok {
+			return false, fmt.Sprintf("TCPIngress rule %d: port %d %sis already used by %s", i, rule.Port, hostDescription(rule.Host), user), nil
+		}
+		used[key] = fmt.Sprintf("rule %d", i)
+	}
+
+	return validateL4KongRoutes(ctx, routesValidator, "TCPIngress", parser.GenerateKongRoutesFromTCPIngress(ingress, parserFeatures.ExpressionRoutes))
+}
+
+// ValidateUDPIngress validates a UDPIngress against other UDPIngresses of the same ingress class, that must not
+// accept traffic on the same ports, and uses provided routesValidator to validate the routes generated for it
+// against Kong Gateway validation endpoint.
+func ValidateUDPIngress(
+	ctx context.Context,
+	routesValidator routeValidator,
+	parserFeatures parser.FeatureFlags,
+	ingress *kongv1beta1.UDPIngress,
+	classIngresses []kongv1beta1.UDPIngress,
+) (bool, string, error) {
+	used := make(map[int]string)
+	for _, other := range classIngresses {
+		if other.Namespace == ingress.Namespace && other.Name == ingress.Name {
+			continue
+		}
+		for _, rule := range other.Spec.Rules {
+			used[rule.Port] = fmt.Sprintf("UDPIngress %s/%s", other.Namespace, other.Name)
+		}
+	}
+	for i, rule := range ingress.Spec.Rules {
+		if user, ok := used[rule.Port]; ok {
+			return false, fmt.Sprintf("UDPIngress rule %d: port %d is already used by %s", i, rule.Port, user), nil
+		}
+		used[rule.Port] = fmt.Sprintf("rule %d", i)
+	}
+
+	return validateL4KongRoutes(ctx, routesValidator, "UDPIngress", parser.GenerateKongRoutesFromUDPIngress(ingress, parserFeatures.ExpressionRoutes))
+}
+
+func validateL4KongRoutes(ctx context.Context, routesValidator routeValidator, kind string, routes []kongstate.Route) (bool, string, error) {
+	kongRoutes := make([]kong.Route, 0, len(routes))
+	for _, r := range routes {
+		kongRoutes = append(kongRoutes, r.Route)
+	}
+	errMsgs, err := validateKongRoutes(ctx, routesValidator, kongRoutes)
+	if err != nil {
+		return false, fmt.Sprintf("unable to validate %s schema: %s", kind, err.Error()), nil
+	}
+	if len(errMsgs) > 0 {
+		return false, fmt.Sprintf("%s failed schema validation: %s", kind, strings.Join(errMsgs, ", ")), nil
+	}
+	return true, "", nil
+}
+
+// tcpIngressRuleKey identifies traffic accepted by a TCPIngress rule. Rules without a host accept plain TCP
+// traffic on their port, while rules with a host accept TLS traffic with a matching SNI.
+func tcpIngressRuleKey(rule kongv1beta1.IngressRule) string {
+	return fmt.Sprintf("%d/%s", rule.Port, rule.Host)
+}
+
+func hostDescription(host string) string {
+	if host == "" {
+		return ""
+	}
+	return fmt.Sprintf("with host %s ", host)
+}
@@ -2,13 +2,16 @@ package admission
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"strings"
 
 	"github.com/go-logr/logr"
 	"github.com/kong/go-kong/kong"
+	"github.com/samber/lo"
 	corev1 "k8s.io/api/core/v1"
 	netv1 "k8s.io/api/networking/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -20,9 +23,11 @@ import (
 	gatewaycontroller "github.com/kong/kubernetes-ingress-controller/v2/internal/controllers/gateway"
 	"github.com/kong/kubernetes-ingress-controller/v2/internal/dataplane/kongstate"
 	"github.com/kong/kubernetes-ingress-controller/v2/internal/dataplane/parser"
+	"github.com/kong/kubernetes-ingress-controller/v2/internal/dataplane/parser/translators"
 	"github.com/kong/kubernetes-ingress-controller/v2/internal/gatewayapi"
 	"github.com/kong/kubernetes-ingress-controller/v2/internal/util"
 	kongv1 "github.com/kong/kubernetes-ingress-controller/v2/pkg/apis/configuration/v1"
+	kongv1alpha1 "github.com/kong/kubernetes-ingress-controller/v2/pkg/apis/configuration/v1alpha1"
 	kongv1beta1 "github.com/kong/kubernetes-ingress-controller/v2/pkg/apis/configuration/v1beta1"
 )
 
@@ -34,8 +39,16 @@ type KongValidator interface {
 	ValidateClusterPlugin(ctx context.Context, plugin kongv1.KongClusterPlugin) (bool, string, error)
 	ValidateCredential(ctx context.Context, secret corev1.Secret) (bool, string, error)
 	ValidateGateway(ctx context.Context, gateway gatewayapi.Gateway) (bool, string, error)
-	ValidateHTTPRoute(ctx context.Context, httproute gatewayapi.HTTPRoute) (bool, string, error)
-	ValidateIngress(ctx context.Context, ingress netv1.Ingress) (bool, string, error)
+	ValidateHTTPRoute(ctx context.Context, httproute gatewayapi.HTTPRoute) (bool, string, []string, error)
+	ValidateGRPCRoute(ctx context.Context, grpcroute gatewayapi.GRPCRoute) (bool, string, error)
+	ValidateTCPRoute(ctx context.Context, tcproute gatewayapi.TCPRoute) (bool, string, error)
+	ValidateTLSRoute(ctx context.Context, tlsroute gatewayapi.TLSRoute) (bool, string, error)
+	ValidateUDPRoute(ctx context.Context, udproute gatewayapi.UDPRoute) (bool, string, error)
+	ValidateIngress(ctx context.Context, ingress netv1.Ingress) (bool, string, []string, error)
+	ValidateUpstreamPolicy(ctx context.Context, policy kongv1beta1.KongUpstreamPolicy) (bool, string, error)
+	ValidateTCPIngress(ctx context.Context, ingress kongv1beta1.TCPIngress) (bool, string, error)
+	ValidateUDPIngress(ctx context.Context, ingress kongv1beta1.UDPIngress) (bool, string, error)
+	ValidateIngressClassParameters(ctx context.Context, params kongv1alpha1.IngressClassParameters) (bool, string, error)
 }
 
 // AdminAPIServicesProvider provides KongHTTPValidator with Kong Admin API services that are needed to perform
@@ -62,9 +75,16 @@ type KongHTTPValidator struct {
 	ManagerClient            client.Client
 	AdminAPIServicesProvider AdminAPIServicesProvider
 	ParserFeatures           parser.FeatureFlags
+	RoutesLister             RoutesLister
+	RouteConflictPolicy      RouteConflictPolicy
+	// CredentialRegistry resolves types of credentials. When nil, only the types known to the controller are supported.
+	CredentialRegistry *credsvalidation.Registry
 
+	ingressClassName      string
 	ingressClassMatcher   func(*metav1.ObjectMeta, string, annotations.ClassMatching) bool
 	ingressV1ClassMatcher func(*netv1.Ingress, annotations.ClassMatching) bool
+	// routeMatchesCache caches route matches of objects from RoutesLister for route conflicts detection.
+	routeMatchesCache *routeMatchesCache
 }
 
 // NewKongHTTPValidator provides a new KongHTTPValidator object provided a
@@ -77,6 +97,9 @@ func NewKongHTTPValidator(
 	ingressClass string,
 	servicesProvider AdminAPIServicesProvider,
 	parserFeatures parser.FeatureFlags,
+	routesLister RoutesLister,
+	routeConflictPolicy RouteConflictPolicy,
+	credentialRegistry *credsvalidation.Registry,
 ) KongHTTPValidator {
 	return KongHTTPValidator{
 		Logger:                   logger,
@@ -85,9 +108,14 @@ func NewKongHTTPValidator(
 		ManagerClient:            managerClient,
 		AdminAPIServicesProvider: servicesProvider,
 		ParserFeatures:           parserFeatures,
+		RoutesLister:             routesLister,
+		RouteConflictPolicy:      routeConflictPolicy,
+		CredentialRegistry:       credentialRegistry,
 
+		ingressClassName:      ingressClass,
 		ingressClassMatcher:   annotations.IngressClassValidatorFuncFromObjectMeta(ingressClass),
 		ingressV1ClassMatcher: annotations.IngressClassValidatorFuncFromV1Ingress(ingressClass),
+		routeMatchesCache:     newRouteMatchesCache(),
 	}
 }
 
@@ -142,7 +170,7 @@ func (validator KongHTTPValidator) ValidateConsumer(
 		}
 
 		// do the basic credentials validation
-		if err := credsvalidation.ValidateCredentials(secret); err != nil {
+		if err := validator.credentialRegistry().ValidateCredentials(ctx, secret); err != nil {
 			return false, ErrTextConsumerCredentialValidationFailed, err
 		}
 
@@ -242,7 +270,7 @@ func (validator KongHTTPValidator) ValidateCredential(
 	}
 
 	// If we know it's a credentials secret, we can ensure its base-level validity.
-	if err := credsvalidation.ValidateCredentials(&secret); err != nil {
+	if err := validator.credentialRegistry().ValidateCredentials(ctx, &secret); err != nil {
 		return false, fmt.Sprintf("%s: %s", ErrTextConsumerCredentialValidationFailed, err), nil
 	}
 
@@ -303,12 +331,23 @@ func (validator KongHTTPValidator) ValidatePlugin(
 		if len(plugin.Config) > 0 {
 			return false, ErrTextPluginUsesBothConfigTypes, nil
 		}
+		if len(k8sPlugin.ConfigPatches) > 0 {
+			return false, ErrTextPluginUsesBothConfigFromAndPatches, nil
+		}
 		config, err := kongstate.SecretToConfiguration(validator.SecretGetter, (*k8sPlugin.ConfigFrom).SecretValue, k8sPlugin.Namespace)
 		if err != nil {
 			return false, ErrTextPluginSecretConfigUnretrievable, err
 		}
 		plugin.Config = config
 	}
+	if len(k8sPlugin.ConfigPatches) > 0 {
+		plugin.Config, err = kongstate.ApplyConfigPatches(
+			validator.SecretGetter, plugin.Config, k8sPlugin.ConfigPatches, k8sPlugin.Namespace,
+		)
+		if err != nil {
+			return false, fmt.Sprintf(ErrTextPluginConfigPatchesInvalid, err), nil
+		}
+	}
 	if k8sPlugin.RunOn != "" {
 		plugin.RunOn = kong.String(k8sPlugin.RunOn)
 	}
@@ -354,6 +393,26 @@ func (validator KongHTTPValidator) ValidateClusterPlugin(
 	} else {
 		derived.ObjectMeta.Namespace = "default"
 	}
+	// Patches of a KongClusterPlugin may refer to Secrets in different namespaces, so they can't be
+	// transferred to the derived KongPlugin. They're applied to its Config instead.
+	if len(k8sPlugin.ConfigPatches) > 0 {
+		if k8sPlugin.ConfigFrom != nil {
+			return false, ErrTextPluginUsesBothConfigFromAndPatches, nil
+		}
+		config, err := kongstate.RawConfigToConfiguration(k8sPlugin.Config)
+		if err != nil {
+			return false, ErrTextPluginConfigInvalid, err
+		}
+		config, err = kongstate.ApplyNamespacedConfigPatches(validator.SecretGetter, config, k8sPlugin.ConfigPatches)
+		if err != nil {
+			return false, fmt.Sprintf(ErrTextPluginConfigPatchesInvalid, err), nil
+		}
+		raw, err := json.Marshal(config)
+		if err != nil {
+			return false, ErrTextPluginConfigInvalid, err
+		}
+		derived.Config = apiextensionsv1.JSON{Raw: raw}
+	}
 	return validator.ValidatePlugin(ctx, derived)
 }
 
@@ -385,14 +444,231 @@ func (validator KongHTTPValidator) ValidateGateway(
 
 func (validator KongHTTPValidator) ValidateHTTPRoute(
 	ctx context.Context, httproute gatewayapi.HTTPRoute,
+) (bool, string, []string, error) {
+	managedGateways, msg, err := validator.getManagedGatewaysForRoute(ctx, httproute.Namespace, httproute.Spec.ParentRefs)
+	if err != nil {
+		return false, msg, nil, err
+	}
+
+	// if there are no managed Gateways this is not a supported HTTPRoute
+	if len(managedGateways) == 0 {
+		return true, "", nil, nil
+	}
+
+	// Now that we know whether or not the HTTPRoute is linked to a managed
+	// Gateway we can run it through full validation.
+	ok, msg, err := gatewayvalidation.ValidateHTTPRoute(
+		ctx, validator.routesValidator(), validator.ParserFeatures, &httproute, managedGateways...,
+	)
+	if err != nil || !ok {
+		return ok, msg, nil, err
+	}
+
+	return validator.routeConflicts(
+		routeOwner{kind: "HTTPRoute", namespace: httproute.Namespace, name: httproute.Name},
+		httpRouteParents(&httproute),
+		httpRouteRoutesForConflicts(&httproute),
+	)
+}
+
+func (validator KongHTTPValidator) ValidateGRPCRoute(
+	ctx context.Context, grpcroute gatewayapi.GRPCRoute,
+) (bool, string, error) {
+	managedGateways, msg, err := validator.getManagedGatewaysForRoute(ctx, grpcroute.Namespace, grpcroute.Spec.ParentRefs)
+	if err != nil {
+		return false, msg, err
+	}
+	if len(managedGateways) == 0 {
+		return true, "", nil
+	}
+	return gatewayvalidation.ValidateGRPCRoute(
+		ctx, validator.routesValidator(), validator.ParserFeatures, &grpcroute, managedGateways...,
+	)
+}
+
+func (validator KongHTTPValidator) ValidateTCPRoute(
+	ctx context.Context, tcproute gatewayapi.TCPRoute,
+) (bool, string, error) {
+	managedGateways, msg, err := validator.getManagedGatewaysForRoute(ctx, tcproute.Namespace, tcproute.Spec.ParentRefs)
+	if err != nil {
+		return false, msg, err
+	}
+	if len(managedGateways) == 0 {
+		return true, "", nil
+	}
+	return gatewayvalidation.ValidateTCPRoute(
+		ctx, validator.routesValidator(), validator.ParserFeatures, &tcproute, managedGateways...,
+	)
+}
+
+func (validator KongHTTPValidator) ValidateTLSRoute(
+	ctx context.Context, tlsroute gatewayapi.TLSRoute,
+) (bool, string, error) {
+	managedGateways, msg, err := validator.getManagedGatewaysForRoute(ctx, tlsroute.Namespace, tlsroute.Spec.ParentRefs)
+	if err != nil {
+		return false, msg, err
+	}
+	if len(managedGateways) == 0 {
+		return true, "", nil
+	}
+	return gatewayvalidation.ValidateTLSRoute(
+		ctx, validator.routesValidator(), validator.ParserFeatures, &tlsroute, managedGateways...,
+	)
+}
+
+func (validator KongHTTPValidator) ValidateUDPRoute(
+	ctx context.Context, udproute gatewayapi.UDPRoute,
+) (bool, string, error) {
+	managedGateways, msg, err := validator.getManagedGatewaysForRoute(ctx, udproute.Namespace, udproute.Spec.ParentRefs)
+	if err != nil {
+		return false, msg, err
+	}
+	if len(managedGateways) == 0 {
+		return true, "", nil
+	}
+	return gatewayvalidation.ValidateUDPRoute(
+		ctx, validator.routesValidator(), validator.ParserFeatures, &udproute, managedGateways...,
+	)
+}
+
+// ValidateUpstreamPolicy checks that a KongUpstreamPolicy can be translated to a Kong upstream
+// accepted by Kong, e.g. that its hash settings don't conflict with its algorithm.
+func (validator KongHTTPValidator) ValidateUpstreamPolicy(
+	_ context.Context, policy kongv1beta1.KongUpstreamPolicy,
+) (bool, string, error) {
+	if err := translators.ValidateKongUpstreamPolicy(policy.Spec); err != nil {
+		return false, fmt.Sprintf(ErrTextUpstreamPolicyInvalid, err), nil
+	}
+	return true, "", nil
+}
+
+// ValidateTCPIngress checks that a TCPIngress references existing Services, doesn't use ports and hosts
+// already used by other TCPIngresses of the same class and that its routes are accepted by Kong.
+func (validator KongHTTPValidator) ValidateTCPIngress(
+	ctx context.Context, ingress kongv1beta1.TCPIngress,
+) (bool, string, error) {
+	// Ignore TCPIngresses that are being managed by another controller.
+	if !validator.ingressClassMatcher(&ingress.ObjectMeta, annotations.IngressClassKey, annotations.ExactClassMatch) {
+		return true, "", nil
+	}
+
+	serviceNames := lo.Map(ingress.Spec.Rules, func(rule kongv1beta1.IngressRule, _ int) string {
+		return rule.Backend.ServiceName
+	})
+	if msg, err := validator.ensureServicesExist(ctx, ingress.Namespace, serviceNames); err != nil || msg != "" {
+		return false, msg, err
+	}
+
+	var ingresses kongv1beta1.TCPIngressList
+	if err := validator.ManagerClient.List(ctx, &ingresses); err != nil {
+		return false, ErrTextIngressesUnretrievable, err
+	}
+	classIngresses := lo.Filter(ingresses.Items, func(other kongv1beta1.TCPIngress, _ int) bool {
+		return validator.ingressClassMatcher(&other.ObjectMeta, annotations.IngressClassKey, annotations.ExactClassMatch)
+	})
+
+	return ingressvalidation.ValidateTCPIngress(ctx, validator.routesValidator(), validator.ParserFeatures, &ingress, classIngresses)
+}
+
+// ValidateUDPIngress checks that a UDPIngress references existing Services, doesn't use ports already used
+// by other UDPIngresses of the same class and that its routes are accepted by Kong.
+func (validator KongHTTPValidator) ValidateUDPIngress(
+	ctx context.Context, ingress kongv1beta1.UDPIngress,
+) (bool, string, error) {
+	// Ignore UDPIngresses that are being managed by another controller.
+	if !validator.ingressClassMatcher(&ingress.ObjectMeta, annotations.IngressClassKey, annotations.ExactClassMatch) {
+		return true, "", nil
+	}
+
+	serviceNames := lo.Map(ingress.Spec.Rules, func(rule kongv1beta1.UDPIngressRule, _ int) string {
+		return rule.Backend.ServiceName
+	})
+	if msg, err := validator.ensureServicesExist(ctx, ingress.Namespace, serviceNames); err != nil || msg != "" {
+		return false, msg, err
+	}
+
+	var ingresses kongv1beta1.UDPIngressList
+	if err := validator.ManagerClient.List(ctx, &ingresses); err != nil {
+		return false, ErrTextIngressesUnretrievable, err
+	}
+	classIngresses := lo.Filter(ingresses.Items, func(other kongv1beta1.UDPIngress, _ int) bool {
+		return validator.ingressClassMatcher(&other.ObjectMeta, annotations.IngressClassKey, annotations.ExactClassMatch)
+	})
+
+	return ingressvalidation.ValidateUDPIngress(ctx, validator.routesValidator(), validator.ParserFeatures, &ingress, classIngresses)
+}
+
+// ValidateIngressClassParameters checks that Ingresses of the controller's IngressClass still pass validation
+// when translated with the IngressClassParameters, if the IngressClass references them.
+func (validator KongHTTPValidator) ValidateIngressClassParameters(
+	ctx context.Context, params kongv1alpha1.IngressClassParameters,
 ) (bool, string, error) {
-	// in order to be sure whether or not an HTTPRoute resource is managed by this
-	// controller we disallow references to Gateway resources that do not exist.
+	var ingressClass netv1.IngressClass
+	if err := validator.ManagerClient.Get(ctx, client.ObjectKey{Name: validator.ingressClassName}, &ingressClass); err != nil {
+		if apierrors.IsNotFound(err) {
+			return true, "", nil
+		}
+		return false, ErrTextIngressClassUnretrievable, err
+	}
+	if !ingressClassReferencesParameters(&ingressClass, &params) {
+		return true, "", nil
+	}
+
+	var ingresses netv1.IngressList
+	if err := validator.ManagerClient.List(ctx, &ingresses); err != nil {
+		return false, ErrTextIngressesUnretrievable, err
+	}
+	var classIngresses []*netv1.Ingress
+	for i := range ingresses.Items {
+		ingress := &ingresses.Items[i]
+		if validator.ingressClassMatcher(&ingress.ObjectMeta, annotations.IngressClassKey, annotations.ExactClassMatch) ||
+			validator.ingressV1ClassMatcher(ingress, annotations.ExactClassMatch) {
+			classIngresses = append(classIngresses, ingress)
+		}
+	}
+
+	return ingressvalidation.ValidateIngressClassParameters(
+		ctx, validator.routesValidator(), validator.ParserFeatures, &params, classIngresses,
+	)
+}
+
+// ensureServicesExist returns a message describing the first Service of the given names that doesn't exist
+// in the namespace.
+func (validator KongHTTPValidator) ensureServicesExist(ctx context.Context, namespace string, names []string) (string, error) {
+	for _, name := range lo.Uniq(names) {
+		var svc corev1.Service
+		if err := validator.ManagerClient.Get(ctx, client.ObjectKey{Namespace: namespace, Name: name}, &svc); err != nil {
+			if apierrors.IsNotFound(err) {
+				return fmt.Sprintf(ErrTextServiceNotFound, namespace, name), nil
+			}
+			return ErrTextServiceUnretrievable, err
+		}
+	}
+	return "", nil
+}
+
+// ingressClassReferencesParameters tells whether the IngressClass references the IngressClassParameters
+// the way the controller reads them.
+func ingressClassReferencesParameters(ingressClass *netv1.IngressClass, params *kongv1alpha1.IngressClassParameters) bool {
+	ref := ingressClass.Spec.Parameters
+	return ref != nil &&
+		lo.FromPtr(ref.APIGroup) == kongv1alpha1.GroupVersion.Group &&
+		ref.Kind == kongv1alpha1.IngressClassParametersKind &&
+		ref.Name == params.Name &&
+		lo.FromPtr(ref.Namespace) == params.Namespace
+}
+
+// getManagedGatewaysForRoute returns Gateways referenced by a route's parentRefs that are managed by this controller.
+// In order to be sure whether or not a route is managed by this controller we disallow references to Gateway
+// resources that do not exist.
+func (validator KongHTTPValidator) getManagedGatewaysForRoute(
+	ctx context.Context, routeNamespace string, parentRefs []gatewayapi.ParentReference,
+) ([]*gatewayapi.Gateway, string, error) {
 	var managedGateways []*gatewayapi.Gateway
-	for _, parentRef := range httproute.Spec.ParentRefs {
+	for _, parentRef := range parentRefs {
 		// determine the namespace of the gateway referenced via parentRef. If no
 		// explicit namespace is provided, assume the namespace of the route.
-		namespace := httproute.Namespace
+		namespace := routeNamespace
 		if parentRef.Namespace != nil {
 			namespace = string(*parentRef.Namespace)
 		}
@@ -404,13 +680,13 @@ func (validator KongHTTPValidator) ValidateHTTPRoute(
 			Namespace: namespace,
 			Name:      string(parentRef.Name),
 		}, &gateway); err != nil {
-			return false, fmt.Sprintf("couldn't retrieve referenced gateway %s/%s", namespace, parentRef.Name), err
+			return nil, fmt.Sprintf("couldn't retrieve referenced gateway %s/%s", namespace, parentRef.Name), err
 		}
 
 		// pull the referenced GatewayClass object from the Gateway
 		gatewayClass := gatewayapi.GatewayClass{}
 		if err := validator.ManagerClient.Get(ctx, client.ObjectKey{Name: string(gateway.Spec.GatewayClassName)}, &gatewayClass); err != nil {
-			return false, fmt.Sprintf("couldn't retrieve referenced gatewayclass %s", gateway.Spec.GatewayClassName), err
+			return nil, fmt.Sprintf("couldn't retrieve referenced gatewayclass %s", gateway.Spec.GatewayClassName), err
 		}
 
 		// determine ultimately whether the Gateway is managed by this controller implementation
@@ -418,37 +694,37 @@ func (validator KongHTTPValidator) ValidateHTTPRoute(
 			managedGateways = append(managedGateways, &gateway)
 		}
 	}
+	return managedGateways, "", nil
+}
 
-	// if there are no managed Gateways this is not a supported HTTPRoute
-	if len(managedGateways) == 0 {
-		return true, "", nil
-	}
-
-	// Now that we know whether or not the HTTPRoute is linked to a managed
-	// Gateway we can run it through full validation.
+// routesValidator returns Kong Gateway routes validation endpoint if available.
+func (validator KongHTTPValidator) routesValidator() routeValidator {
 	var routeValidator routeValidator = noOpRoutesValidator{}
 	if routesSvc, ok := validator.AdminAPIServicesProvider.GetRoutesService(); ok {
 		routeValidator = routesSvc
 	}
-	return gatewayvalidation.ValidateHTTPRoute(
-		ctx, routeValidator, validator.ParserFeatures, &httproute, managedGateways...,
-	)
+	return routeValidator
 }
 
 func (validator KongHTTPValidator) ValidateIngress(
 	ctx context.Context, ingress netv1.Ingress,
-) (bool, string, error) {
+) (bool, string, []string, error) {
 	// Ignore Ingresses that are being managed by another controller.
 	if !validator.ingressClassMatcher(&ingress.ObjectMeta, annotations.IngressClassKey, annotations.ExactClassMatch) &&
 		!validator.ingressV1ClassMatcher(&ingress, annotations.ExactClassMatch) {
-		return true, "", nil
+		return true, "", nil, nil
 	}
 
-	var routeValidator routeValidator = noOpRoutesValidator{}
-	if routesSvc, ok := validator.AdminAPIServicesProvider.GetRoutesService(); ok {
-		routeValidator = routesSvc
+	ok, msg, err := ingressvalidation.ValidateIngress(ctx, validator.routesValidator(), validator.ParserFeatures, &ingress)
+	if err != nil || !ok {
+		return ok, msg, nil, err
 	}
-	return ingressvalidation.ValidateIngress(ctx, routeValidator, validator.ParserFeatures, &ingress)
+
+	return validator.routeConflicts(
+		routeOwner{kind: "Ingress", namespace: ingress.Namespace, name: ingress.Name},
+		validator.ingressRouteParents(),
+		validator.ingressRoutesForConflicts(&ingress),
+	)
 }
 
 type routeValidator interface {
@@ -465,6 +741,13 @@ func (noOpRoutesValidator) Validate(_ context.Context, _ *kong.Route) (bool, str
 // KongHTTPValidator - Private Methods
 // -----------------------------------------------------------------------------
 
+func (validator KongHTTPValidator) credentialRegistry() *credsvalidation.Registry {
+	if validator.CredentialRegistry == nil {
+		return credsvalidation.NewRegistry(nil, false)
+	}
+	return validator.CredentialRegistry
+}
+
 func (validator KongHTTPValidator) listManagedConsumers(ctx context.Context) ([]*kongv1.KongConsumer, error) {
 	// Gather a list of all consumers from the cached client.
 	consumers, err := validator.ConsumerGetter.ListAllConsumers(ctx)
@@ -14,12 +14,20 @@ import (
 	"github.com/stretchr/testify/require"
 	"go.uber.org/zap"
 	corev1 "k8s.io/api/core/v1"
+	netv1 "k8s.io/api/networking/v1"
 	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	fakeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
 
 	"github.com/kong/kubernetes-ingress-controller/v2/internal/annotations"
+	gatewaycontroller "github.com/kong/kubernetes-ingress-controller/v2/internal/controllers/gateway"
+	"github.com/kong/kubernetes-ingress-controller/v2/internal/gatewayapi"
 	"github.com/kong/kubernetes-ingress-controller/v2/internal/store"
 	kongv1 "github.com/kong/kubernetes-ingress-controller/v2/pkg/apis/configuration/v1"
+	kongv1alpha1 "github.com/kong/kubernetes-ingress-controller/v2/pkg/apis/configuration/v1alpha1"
 	kongv1beta1 "github.com/kong/kubernetes-ingress-controller/v2/pkg/apis/configuration/v1beta1"
 )
 
@@ -91,7 +99,17 @@ func (f fakeServicesProvider) GetRoutesService() (kong.AbstractRouteService, boo
 }
 
 func TestKongHTTPValidator_ValidatePlugin(t *testing.T) {
-	store, _ := store.NewFakeStore(store.FakeObjects{})
+	store, _ := store.NewFakeStore(store.FakeObjects{
+		Secrets: []*corev1.Secret{
+			{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "conf-secret"},
+				Data: map[string][]byte{
+					"key-names": []byte(`["apikey"]`),
+					"not-json":  []byte(`apikey`),
+				},
+			},
+		},
+	})
 	type args struct {
 		plugin kongv1.KongPlugin
 	}
@@ -187,6 +205,98 @@ func TestKongHTTPValidator_ValidatePlugin(t *testing.T) {
 			wantMessage: ErrTextPluginSecretConfigUnretrievable,
 			wantErr:     true,
 		},
+		{
+			name:      "plugin has valid ConfigPatches",
+			PluginSvc: &fakePluginSvc{valid: true},
+			args: args{
+				plugin: kongv1.KongPlugin{
+					ObjectMeta: metav1.ObjectMeta{Namespace: "default"},
+					PluginName: "key-auth",
+					Config: apiextensionsv1.JSON{
+						Raw: []byte(`{"hide_credentials": true}`),
+					},
+					ConfigPatches: []kongv1.ConfigPatch{
+						{
+							Path: "/key_names",
+							ValueFrom: kongv1.ConfigSource{
+								SecretValue: kongv1.SecretValueFromSource{Secret: "conf-secret", Key: "key-names"},
+							},
+						},
+					},
+				},
+			},
+			wantOK:      true,
+			wantMessage: "",
+			wantErr:     false,
+		},
+		{
+			name:      "plugin has both ConfigFrom and ConfigPatches",
+			PluginSvc: &fakePluginSvc{},
+			args: args{
+				plugin: kongv1.KongPlugin{
+					ObjectMeta: metav1.ObjectMeta{Namespace: "default"},
+					PluginName: "key-auth",
+					ConfigFrom: &kongv1.ConfigSource{
+						SecretValue: kongv1.SecretValueFromSource{Secret: "conf-secret", Key: "key-names"},
+					},
+					ConfigPatches: []kongv1.ConfigPatch{
+						{
+							Path: "/key_names",
+							ValueFrom: kongv1.ConfigSource{
+								SecretValue: kongv1.SecretValueFromSource{Secret: "conf-secret", Key: "key-names"},
+							},
+						},
+					},
+				},
+			},
+			wantOK:      false,
+			wantMessage: ErrTextPluginUsesBothConfigFromAndPatches,
+			wantErr:     false,
+		},
+		{
+			name:      "plugin ConfigPatches references a value that is not JSON",
+			PluginSvc: &fakePluginSvc{},
+			args: args{
+				plugin: kongv1.KongPlugin{
+					ObjectMeta: metav1.ObjectMeta{Namespace: "default"},
+					PluginName: "key-auth",
+					ConfigPatches: []kongv1.ConfigPatch{
+						{
+							Path: "/key_names",
+							ValueFrom: kongv1.ConfigSource{
+								SecretValue: kongv1.SecretValueFromSource{Secret: "conf-secret", Key: "not-json"},
+							},
+						},
+					},
+				},
+			},
+			wantOK: false,
+			wantMessage: fmt.Sprintf(ErrTextPluginConfigPatchesInvalid,
+				"key 'not-json' in secret 'default/conf-secret' does not contain valid JSON"),
+			wantErr: false,
+		},
+		{
+			name:      "plugin ConfigPatches has a path that does not exist",
+			PluginSvc: &fakePluginSvc{},
+			args: args{
+				plugin: kongv1.KongPlugin{
+					ObjectMeta: metav1.ObjectMeta{Namespace: "default"},
+					PluginName: "key-auth",
+					ConfigPatches: []kongv1.ConfigPatch{
+						{
+							Path: "/nested/key_names",
+							ValueFrom: kongv1.ConfigSource{
+								SecretValue: kongv1.SecretValueFromSource{Secret: "conf-secret", Key: "key-names"},
+							},
+						},
+					},
+				},
+			},
+			wantOK: false,
+			wantMessage: fmt.Sprintf(ErrTextPluginConfigPatchesInvalid,
+				`failed to apply configuration patches: add operation does not apply: doc is missing path: "/nested/key_names": missing value`),
+			wantErr: false,
+		},
 		{
 			name:      "failed to retrieve validation info",
 			PluginSvc: &fakePluginSvc{valid: false, err: fmt.Errorf("everything broke")},
@@ -223,7 +333,16 @@ func TestKongHTTPValidator_ValidatePlugin(t *testing.T) {
 }
 
 func TestKongHTTPValidator_ValidateClusterPlugin(t *testing.T) {
-	store, _ := store.NewFakeStore(store.FakeObjects{})
+	store, _ := store.NewFakeStore(store.FakeObjects{
+		Secrets: []*corev1.Secret{
+			{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "kong", Name: "conf-secret"},
+				Data: map[string][]byte{
+					"key-names": []byte(`["apikey"]`),
+				},
+			},
+		},
+	})
 	type args struct {
 		plugin kongv1.KongClusterPlugin
 	}
@@ -321,6 +440,51 @@ func TestKongHTTPValidator_ValidateClusterPlugin(t *testing.T) {
 			wantMessage: ErrTextPluginSecretConfigUnretrievable,
 			wantErr:     true,
 		},
+		{
+			name:      "plugin has valid ConfigPatches",
+			PluginSvc: &fakePluginSvc{valid: true},
+			args: args{
+				plugin: kongv1.KongClusterPlugin{
+					PluginName: "key-auth",
+					ConfigPatches: []kongv1.NamespacedConfigPatch{
+						{
+							Path: "/key_names",
+							ValueFrom: kongv1.NamespacedConfigSource{
+								SecretValue: kongv1.NamespacedSecretValueFromSource{
+									Namespace: "kong", Secret: "conf-secret", Key: "key-names",
+								},
+							},
+						},
+					},
+				},
+			},
+			wantOK:      true,
+			wantMessage: "",
+			wantErr:     false,
+		},
+		{
+			name:      "plugin ConfigPatches references non-existent Secret",
+			PluginSvc: &fakePluginSvc{},
+			args: args{
+				plugin: kongv1.KongClusterPlugin{
+					PluginName: "key-auth",
+					ConfigPatches: []kongv1.NamespacedConfigPatch{
+						{
+							Path: "/key_names",
+							ValueFrom: kongv1.NamespacedConfigSource{
+								SecretValue: kongv1.NamespacedSecretValueFromSource{
+									Namespace: "default", Secret: "conf-secret", Key: "key-names",
+								},
+							},
+						},
+					},
+				},
+			},
+			wantOK: false,
+			wantMessage: fmt.Sprintf(ErrTextPluginConfigPatchesInvalid,
+				"error fetching plugin configuration patch secret 'default/conf-secret': Secret default/conf-secret not found"),
+			wantErr: false,
+		},
 		{
 			name:      "failed to retrieve validation info",
 			PluginSvc: &fakePluginSvc{valid: false, err: fmt.Errorf("everything broke")},
@@ -644,3 +808,459 @@ type fakeConsumerGetter struct {
 func (f fakeConsumerGetter) ListAllConsumers(context.Context) ([]kongv1.KongConsumer, error) {
 	return f.consumers, nil
 }
+
+type fakeRoutesSvc struct {
+	kong.AbstractRouteService
+	valid bool
+	msg   string
+}
+
+func (f fakeRoutesSvc) Validate(context.Context, *kong.Route) (bool, string, error) {
+	return f.valid, f.msg, nil
+}
+
+func TestKongHTTPValidator_ValidateTCPRoute(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, gatewayv1.Install(scheme))
+	managerClient := fakeclient.NewClientBuilder().WithScheme(scheme).WithObjects(
+		&gatewayapi.GatewayClass{
+			ObjectMeta: metav1.ObjectMeta{Name: "kong"},
+			Spec:       gatewayapi.GatewayClassSpec{ControllerName: gatewaycontroller.GetControllerName()},
+		},
+		&gatewayapi.GatewayClass{
+			ObjectMeta: metav1.ObjectMeta{Name: "other"},
+			Spec:       gatewayapi.GatewayClassSpec{ControllerName: "example.com/other"},
+		},
+		&gatewayapi.Gateway{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "kong"},
+			Spec: gatewayapi.GatewaySpec{
+				GatewayClassName: "kong",
+				Listeners:        []gatewayapi.Listener{{Name: "tcp", Port: 8888, Protocol: gatewayapi.TCPProtocolType}},
+			},
+		},
+		&gatewayapi.Gateway{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "other"},
+			Spec:       gatewayapi.GatewaySpec{GatewayClassName: "other"},
+		},
+	).Build()
+
+	tcpRoute := func(gatewayName string) gatewayapi.TCPRoute {
+		return gatewayapi.TCPRoute{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "tcproute"},
+			Spec: gatewayapi.TCPRouteSpec{
+				CommonRouteSpec: gatewayapi.CommonRouteSpec{
+					ParentRefs: []gatewayapi.ParentReference{{Name: gatewayapi.ObjectName(gatewayName)}},
+				},
+				Rules: []gatewayapi.TCPRouteRule{{
+					BackendRefs: []gatewayapi.BackendRef{{
+						BackendObjectReference: gatewayapi.BackendObjectReference{
+							Name: "service",
+							Port: lo.ToPtr(gatewayapi.PortNumber(80)),
+						},
+					}},
+				}},
+			},
+		}
+	}
+
+	testCases := []struct {
+		name        string
+		route       gatewayapi.TCPRoute
+		routesSvc   kong.AbstractRouteService
+		wantOK      bool
+		wantMessage string
+		wantErr     bool
+	}{
+		{
+			name:      "route attached to a managed gateway accepted by Kong",
+			route:     tcpRoute("kong"),
+			routesSvc: fakeRoutesSvc{valid: true},
+			wantOK:    true,
+		},
+		{
+			name:        "route attached to a managed gateway rejected by Kong",
+			route:       tcpRoute("kong"),
+			routesSvc:   fakeRoutesSvc{msg: "schema violation"},
+			wantOK:      false,
+			wantMessage: "TCPRoute failed schema validation: schema violation",
+		},
+		{
+			name:      "route attached to a gateway of another controller is not validated",
+			route:     tcpRoute("other"),
+			routesSvc: fakeRoutesSvc{msg: "schema violation"},
+			wantOK:    true,
+		},
+		{
+			name:        "route attached to a nonexistent gateway",
+			route:       tcpRoute("nonexistent"),
+			wantOK:      false,
+			wantMessage: "couldn't retrieve referenced gateway default/nonexistent",
+			wantErr:     true,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			validator := KongHTTPValidator{
+				ManagerClient:            managerClient,
+				AdminAPIServicesProvider: fakeServicesProvider{routeSvc: tc.routesSvc},
+			}
+			ok, msg, err := validator.ValidateTCPRoute(context.Background(), tc.route)
+			if tc.wantErr {
+				require.Error(t, err)
+			} else {
+				require.NoError(t, err)
+			}
+			assert.Equal(t, tc.wantOK, ok)
+			assert.Equal(t, tc.wantMessage, msg)
+		})
+	}
+}
+
+func TestKongHTTPValidator_ValidateUpstreamPolicy(t *testing.T) {
+	validator := KongHTTPValidator{}
+
+	valid, msg, err := validator.ValidateUpstreamPolicy(context.Background(), kongv1beta1.KongUpstreamPolicy{
+		Spec: kongv1beta1.KongUpstreamPolicySpec{
+			Algorithm: lo.ToPtr("consistent-hashing"),
+			HashOn:    &kongv1beta1.KongUpstreamHash{Header: lo.ToPtr("x-user")},
+		},
+	})
+	require.NoError(t, err)
+	require.True(t, valid)
+	require.Empty(t, msg)
+
+	valid, msg, err = validator.ValidateUpstreamPolicy(context.Background(), kongv1beta1.KongUpstreamPolicy{
+		Spec: kongv1beta1.KongUpstreamPolicySpec{
+			Algorithm: lo.ToPtr("round-robin"),
+			HashOn:    &kongv1beta1.KongUpstreamHash{Header: lo.ToPtr("x-user")},
+		},
+	})
+	require.NoError(t, err)
+	require.False(t, valid)
+	require.Equal(t, "KongUpstreamPolicy is invalid: hashOn and hashOnFallback require the consistent-hashing algorithm", msg)
+}
+
+func TestKongHTTPValidator_ValidateL4Ingresses(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, corev1.AddToScheme(scheme))
+	require.NoError(t, kongv1beta1.AddToScheme(scheme))
+	classAnnotations := map[string]string{annotations.IngressClassKey: "kong"}
+	managerClient := fakeclient.NewClientBuilder().WithScheme(scheme).WithObjects(
+		&corev1.Service{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "svc"}},
+		&kongv1beta1.TCPIngress{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "existing", Annotations: classAnnotations},
+			Spec: kongv1beta1.TCPIngressSpec{Rules: []kongv1beta1.IngressRule{
+				{Port: 9000, Backend: kongv1beta1.IngressBackend{ServiceName: "svc", ServicePort: 80}},
+				{Port: 9443, Host: "example.com", Backend: kongv1beta1.IngressBackend{ServiceName: "svc", ServicePort: 80}},
+			}},
+		},
+		&kongv1beta1.TCPIngress{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: "default", Name: "other-class", Annotations: map[string]string{annotations.IngressClassKey: "other"},
+			},
+			Spec: kongv1beta1.TCPIngressSpec{Rules: []kongv1beta1.IngressRule{
+				{Port: 9001, Backend: kongv1beta1.IngressBackend{ServiceName: "svc", ServicePort: 80}},
+			}},
+		},
+		&kongv1beta1.UDPIngress{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "existing", Annotations: classAnnotations},
+			Spec: kongv1beta1.UDPIngressSpec{Rules: []kongv1beta1.UDPIngressRule{
+				{Port: 9053, Backend: kongv1beta1.IngressBackend{ServiceName: "svc", ServicePort: 53}},
+			}},
+		},
+	).Build()
+	validator := KongHTTPValidator{
+		ManagerClient:            managerClient,
+		AdminAPIServicesProvider: fakeServicesProvider{routeSvc: fakeRoutesSvc{valid: true}},
+		ingressClassMatcher:      annotations.IngressClassValidatorFuncFromObjectMeta("kong"),
+	}
+
+	tcpIngress := func(name string, rules ...kongv1beta1.IngressRule) kongv1beta1.TCPIngress {
+		return kongv1beta1.TCPIngress{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: name, Annotations: classAnnotations},
+			Spec:       kongv1beta1.TCPIngressSpec{Rules: rules},
+		}
+	}
+	tcpRule := func(port int, host, serviceName string) kongv1beta1.IngressRule {
+		return kongv1beta1.IngressRule{Port: port, Host: host, Backend: kongv1beta1.IngressBackend{ServiceName: serviceName, ServicePort: 80}}
+	}
+
+	tcpTestCases := []struct {
+		name        string
+		ingress     kongv1beta1.TCPIngress
+		wantOK      bool
+		wantMessage string
+	}{
+		{
+			name:    "unused port and a port used with another host",
+			ingress: tcpIngress("new", tcpRule(9001, "", "svc"), tcpRule(9443, "other.example.com", "svc")),
+			wantOK:  true,
+		},
+		{
+			name:    "updating an existing TCPIngress doesn't conflict with itself",
+			ingress: tcpIngress("existing", tcpRule(9000, "", "svc")),
+			wantOK:  true,
+		},
+		{
+			name:        "port used by another TCPIngress of the class",
+			ingress:     tcpIngress("new", tcpRule(9000, "", "svc")),
+			wantMessage: "TCPIngress rule 0: port 9000 is already used by TCPIngress default/existing",
+		},
+		{
+			name:        "port and host used by another TCPIngress of the class",
+			ingress:     tcpIngress("new", tcpRule(9443, "example.com", "svc")),
+			wantMessage: "TCPIngress rule 0: port 9443 with host example.com is already used by TCPIngress default/existing",
+		},
+		{
+			name:        "port used twice in the same TCPIngress",
+			ingress:     tcpIngress("new", tcpRule(9002, "", "svc"), tcpRule(9002, "", "svc")),
+			wantMessage: "TCPIngress rule 1: port 9002 is already used by rule 0",
+		},
+		{
+			name:        "missing Service",
+			ingress:     tcpIngress("new", tcpRule(9002, "", "missing")),
+			wantMessage: "referenced service default/missing does not exist",
+		},
+		{
+			name: "TCPIngress of another class",
+			ingress: kongv1beta1.TCPIngress{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "new", Annotations: map[string]string{annotations.IngressClassKey: "other"}},
+				Spec:       kongv1beta1.TCPIngressSpec{Rules: []kongv1beta1.IngressRule{tcpRule(9000, "", "missing")}},
+			},
+			wantOK: true,
+		},
+	}
+	for _, tc := range tcpTestCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			ok, msg, err := validator.ValidateTCPIngress(context.Background(), tc.ingress)
+			require.NoError(t, err)
+			assert.Equal(t, tc.wantOK, ok)
+			assert.Equal(t, tc.wantMessage, msg)
+		})
+	}
+
+	t.Run("UDPIngress", func(t *testing.T) {
+		udpIngress := func(ports ...int) kongv1beta1.UDPIngress {
+			return kongv1beta1.UDPIngress{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "new", Annotations: classAnnotations},
+				Spec: kongv1beta1.UDPIngressSpec{Rules: lo.Map(ports, func(port int, _ int) kongv1beta1.UDPIngressRule {
+					return kongv1beta1.UDPIngressRule{Port: port, Backend: kongv1beta1.IngressBackend{ServiceName: "svc", ServicePort: 53}}
+				})},
+			}
+		}
+
+		ok, msg, err := validator.ValidateUDPIngress(context.Background(), udpIngress(9054))
+		require.NoError(t, err)
+		assert.True(t, ok)
+		assert.Empty(t, msg)
+
+		ok, msg, err = validator.ValidateUDPIngress(context.Background(), udpIngress(9053))
+		require.NoError(t, err)
+		assert.False(t, ok)
+		assert.Equal(t, "UDPIngress rule 0: port 9053 is already used by UDPIngress default/existing", msg)
+	})
+
+	t.Run("routes rejected by Kong", func(t *testing.T) {
+		validator := validator
+		validator.AdminAPIServicesProvider = fakeServicesProvider{routeSvc: fakeRoutesSvc{msg: "schema violation"}}
+		ok, msg, err := validator.ValidateTCPIngress(context.Background(), tcpIngress("new", tcpRule(9001, "", "svc")))
+		require.NoError(t, err)
+		assert.False(t, ok)
+		assert.Equal(t, "TCPIngress failed schema validation: schema violation", msg)
+	})
+}
+
+func TestKongHTTPValidator_ValidateIngressClassParameters(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, netv1.AddToScheme(scheme))
+	params := kongv1alpha1.IngressClassParameters{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "kong", Name: "params"},
+		Spec:       kongv1alpha1.IngressClassParametersSpec{EnableLegacyRegexDetection: true},
+	}
+	ingressClass := &netv1.IngressClass{
+		ObjectMeta: metav1.ObjectMeta{Name: "kong"},
+		Spec: netv1.IngressClassSpec{
+			Controller: "ingress-controllers.konghq.com/kong",
+			Parameters: &netv1.IngressClassParametersReference{
+				APIGroup:  lo.ToPtr(kongv1alpha1.GroupVersion.Group),
+				Kind:      kongv1alpha1.IngressClassParametersKind,
+				Name:      "params",
+				Scope:     lo.ToPtr(netv1.IngressClassParametersReferenceScopeNamespace),
+				Namespace: lo.ToPtr("kong"),
+			},
+		},
+	}
+	ingress := &netv1.Ingress{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "ingress"},
+		Spec: netv1.IngressSpec{
+			IngressClassName: lo.ToPtr("kong"),
+			Rules: []netv1.IngressRule{{
+				IngressRuleValue: netv1.IngressRuleValue{HTTP: &netv1.HTTPIngressRuleValue{
+					Paths: []netv1.HTTPIngressPath{{
+						Path:     "/foo[",
+						PathType: lo.ToPtr(netv1.PathTypeImplementationSpecific),
+						Backend: netv1.IngressBackend{Service: &netv1.IngressServiceBackend{
+							Name: "svc", Port: netv1.ServiceBackendPort{Number: 80},
+						}},
+					}},
+				}},
+			}},
+		},
+	}
+
+	newValidator := func(routesSvc kong.AbstractRouteService, objs ...client.Object) KongHTTPValidator {
+		return KongHTTPValidator{
+			ManagerClient:            fakeclient.NewClientBuilder().WithScheme(scheme).WithObjects(objs...).Build(),
+			AdminAPIServicesProvider: fakeServicesProvider{routeSvc: routesSvc},
+			ingressClassName:         "kong",
+			ingressClassMatcher:      annotations.IngressClassValidatorFuncFromObjectMeta("kong"),
+			ingressV1ClassMatcher:    annotations.IngressClassValidatorFuncFromV1Ingress("kong"),
+		}
+	}
+
+	t.Run("parameters referenced by the IngressClass breaking its Ingresses", func(t *testing.T) {
+		validator := newValidator(fakeRoutesSvc{msg: "invalid regex"}, ingressClass, ingress)
+		ok, msg, err := validator.ValidateIngressClassParameters(context.Background(), params)
+		require.NoError(t, err)
+		assert.False(t, ok)
+		assert.Equal(t, "IngressClassParameters would make Ingresses fail schema validation: Ingress default/ingress: invalid regex", msg)
+	})
+
+	t.Run("parameters referenced by the IngressClass", func(t *testing.T) {
+		validator := newValidator(fakeRoutesSvc{valid: true}, ingressClass, ingress)
+		ok, msg, err := validator.ValidateIngressClassParameters(context.Background(), params)
+		require.NoError(t, err)
+		assert.True(t, ok)
+		assert.Empty(t, msg)
+	})
+
+	t.Run("parameters not referenced by the IngressClass", func(t *testing.T) {
+		validator := newValidator(fakeRoutesSvc{msg: "invalid regex"}, ingressClass, ingress)
+		otherParams := params.DeepCopy()
+		otherParams.Namespace = "other"
+		ok, msg, err := validator.ValidateIngressClassParameters(context.Background(), *otherParams)
+		require.NoError(t, err)
+		assert.True(t, ok)
+		assert.Empty(t, msg)
+	})
+
+	t.Run("no IngressClass", func(t *testing.T) {
+		validator := newValidator(fakeRoutesSvc{msg: "invalid regex"}, ingress)
+		ok, _, err := validator.ValidateIngressClassParameters(context.Background(), params)
+		require.NoError(t, err)
+		assert.True(t, ok)
+	})
+}
+
+func TestKongHTTPValidator_ValidateIngressRouteConflicts(t *testing.T) {
+	newIngress := func(name, host, path string) *netv1.Ingress {
+		return &netv1.Ingress{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: name},
+			Spec: netv1.IngressSpec{
+				IngressClassName: lo.ToPtr("kong"),
+				Rules: []netv1.IngressRule{{
+					Host: host,
+					IngressRuleValue: netv1.IngressRuleValue{HTTP: &netv1.HTTPIngressRuleValue{
+						Paths: []netv1.HTTPIngressPath{{
+							Path:     path,
+							PathType: lo.ToPtr(netv1.PathTypePrefix),
+							Backend: netv1.IngressBackend{Service: &netv1.IngressServiceBackend{
+								Name: "svc", Port: netv1.ServiceBackendPort{Number: 80},
+							}},
+						}},
+					}},
+				}},
+			},
+		}
+	}
+	httproute := &gatewayapi.HTTPRoute{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "httproute"},
+		Spec: gatewayapi.HTTPRouteSpec{
+			Hostnames: []gatewayapi.Hostname{"example.com"},
+			Rules: []gatewayapi.HTTPRouteRule{{
+				Matches: []gatewayapi.HTTPRouteMatch{{
+					Path: &gatewayapi.HTTPPathMatch{
+						Type:  lo.ToPtr(gatewayapi.PathMatchPathPrefix),
+						Value: lo.ToPtr("/api"),
+					},
+				}},
+			}},
+		},
+	}
+	storer, err := store.NewFakeStore(store.FakeObjects{
+		IngressesV1: []*netv1.Ingress{newIngress("existing", "example.com", "/foo"), newIngress("other", "example.com", "/bar")},
+		HTTPRoutes:  []*gatewayapi.HTTPRoute{httproute},
+	})
+	require.NoError(t, err)
+
+	newValidator := func(policy RouteConflictPolicy) KongHTTPValidator {
+		return KongHTTPValidator{
+			AdminAPIServicesProvider: fakeServicesProvider{routeSvc: fakeRoutesSvc{valid: true}},
+			RoutesLister:             storer,
+			RouteConflictPolicy:      policy,
+			ingressClassMatcher:      annotations.IngressClassValidatorFuncFromObjectMeta("kong"),
+			ingressV1ClassMatcher:    annotations.IngressClassValidatorFuncFromV1Ingress("kong"),
+		}
+	}
+
+	testCases := []struct {
+		name             string
+		policy           RouteConflictPolicy
+		ingress          *netv1.Ingress
+		expectedOK       bool
+		expectedMsg      string
+		expectedWarnings []string
+	}{
+		{
+			name:        "conflict with an Ingress is denied",
+			policy:      RouteConflictPolicyDeny,
+			ingress:     newIngress("new", "example.com", "/foo"),
+			expectedOK:  false,
+			expectedMsg: "routes exactly overlap with routes of Ingress default/existing",
+		},
+		{
+			name:             "conflict is warned about",
+			policy:           RouteConflictPolicyWarn,
+			ingress:          newIngress("new", "example.com", "/bar"),
+			expectedOK:       true,
+			expectedWarnings: []string{"routes exactly overlap with routes of Ingress default/other"},
+		},
+		{
+			name:       "routes of HTTPRoutes attached to Gateways don't conflict",
+			policy:     RouteConflictPolicyDeny,
+			ingress:    newIngress("new", "example.com", "/api"),
+			expectedOK: true,
+		},
+		{
+			name:       "conflicts are ignored with the off policy",
+			policy:     RouteConflictPolicyOff,
+			ingress:    newIngress("new", "example.com", "/foo"),
+			expectedOK: true,
+		},
+		{
+			name:       "routes with a different host don't conflict",
+			policy:     RouteConflictPolicyDeny,
+			ingress:    newIngress("new", "other.example.com", "/foo"),
+			expectedOK: true,
+		},
+		{
+			name:       "updated Ingress doesn't conflict with itself",
+			policy:     RouteConflictPolicyDeny,
+			ingress:    newIngress("existing", "example.com", "/foo"),
+			expectedOK: true,
+		},
+	}
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			ok, msg, warnings, err := newValidator(tc.policy).ValidateIngress(context.Background(), *tc.ingress)
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedOK, ok)
+			assert.Equal(t, tc.expectedMsg, msg)
+			assert.Equal(t, tc.expectedWarnings, warnings)
+		})
+	}
+}
@@ -63,6 +63,22 @@ const (
 	UserTagKey           = "/tags"
 	RewriteURIKey        = "/rewrite"
 
+	// SessionPersistenceCookieKey, SessionPersistenceCookiePathKey and SessionPersistenceHeaderKey are annotations
+	// used on an HTTPRoute to request cookie or header based session persistence for the backends of its rules.
+	// SessionPersistenceRulesKey limits it to the rules with the given comma-separated indexes.
+	SessionPersistenceCookieKey     = "/session-persistence-cookie"
+	SessionPersistenceCookiePathKey = "/session-persistence-cookie-path"
+	SessionPersistenceHeaderKey     = "/session-persistence-header"
+	SessionPersistenceRulesKey      = "/session-persistence-rules"
+
+	// EffectiveRouteSettingsKey is an annotation set by the mutating admission webhook on an Ingress to record
+	// settings of Kong routes generated from it, including the defaults applied by the controller.
+	EffectiveRouteSettingsKey = "/effective-route-settings"
+
+	// CredentialRotationGracePeriodKey is an annotation used on a credential Secret to keep the previous value of
+	// the credential valid for the given duration (e.g. 24h) after the Secret is updated with a new one.
+	CredentialRotationGracePeriodKey = "/credential-rotation-grace-period"
+
 	// GatewayClassUnmanagedKey is an annotation used on a Gateway resource to
 	// indicate that the GatewayClass should be reconciled according to unmanaged
 	// mode.
@@ -71,6 +87,10 @@ const (
 	// resources: "unmanaged" mode is the only supported mode at this time.
 	GatewayClassUnmanagedKey = "/gatewayclass-unmanaged"
 
+	// GatewayClassManagedGatewayImageKey is an annotation used on a managed GatewayClass to override the Kong Gateway
+	// image (set with --managed-gateway-image) run by data-planes provisioned for its Gateways.
+	GatewayClassManagedGatewayImageKey = "/managed-gateway-image"
+
 	// GatewayPublishServiceKey is an annotation suffix used to indicate the Service(s) a Gateway's routes are
 	// published to.
 	GatewayPublishServiceKey = "/publish-service"
@@ -381,3 +401,43 @@ func ExtractRewriteURI(anns map[string]string) (string, bool) {
 	s, ok := anns[AnnotationPrefix+RewriteURIKey]
 	return s, ok
 }
+
+// ExtractSessionPersistenceCookie extracts the name of the cookie used for session persistence.
+func ExtractSessionPersistenceCookie(anns map[string]string) (string, bool) {
+	s, ok := anns[AnnotationPrefix+SessionPersistenceCookieKey]
+	return s, ok
+}
+
+// ExtractSessionPersistenceCookiePath extracts the path of the cookie used for session persistence.
+func ExtractSessionPersistenceCookiePath(anns map[string]string) (string, bool) {
+	s, ok := anns[AnnotationPrefix+SessionPersistenceCookiePathKey]
+	return s, ok
+}
+
+// ExtractSessionPersistenceHeader extracts the name of the header used for session persistence.
+func ExtractSessionPersistenceHeader(anns map[string]string) (string, bool) {
+	s, ok := anns[AnnotationPrefix+SessionPersistenceHeaderKey]
+	return s, ok
+}
+
+// ExtractSessionPersistenceRules extracts the indexes of the rules session persistence is requested for.
+func ExtractSessionPersistenceRules(anns map[string]string) ([]string, bool) {
+	val, ok := anns[AnnotationPrefix+SessionPersistenceRulesKey]
+	if !ok {
+		return nil, false
+	}
+	return strings.Split(val, ","), true
+}
+
+// ExtractCredentialRotationGracePeriod extracts the grace period of a credential rotation.
+func ExtractCredentialRotationGracePeriod(anns map[string]string) (string, bool) {
+	s, ok := anns[AnnotationPrefix+CredentialRotationGracePeriodKey]
+	return s, ok
+}
+
+// ExtractManagedGatewayImage extracts the Kong Gateway image of data-planes provisioned for Gateways of
+// a managed GatewayClass.
+func ExtractManagedGatewayImage(anns map[string]string) (string, bool) {
+	s, ok := anns[AnnotationPrefix+GatewayClassManagedGatewayImageKey]
+	return s, ok && s != ""
+}
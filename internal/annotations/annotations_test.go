@@ -961,3 +961,59 @@ func TestExtractRewriteURI(t *testing.T) {
 		})
 	}
 }
+
+func TestExtractSessionPersistence(t *testing.T) {
+	anns := map[string]string{
+		"konghq.com/session-persistence-cookie":      "session",
+		"konghq.com/session-persistence-cookie-path": "/app",
+		"konghq.com/session-persistence-header":      "x-session",
+		"konghq.com/session-persistence-rules":       "0,2",
+	}
+
+	cookie, exist := ExtractSessionPersistenceCookie(anns)
+	require.Equal(t, "session", cookie)
+	require.True(t, exist)
+	cookiePath, exist := ExtractSessionPersistenceCookiePath(anns)
+	require.Equal(t, "/app", cookiePath)
+	require.True(t, exist)
+	header, exist := ExtractSessionPersistenceHeader(anns)
+	require.Equal(t, "x-session", header)
+	require.True(t, exist)
+	rules, exist := ExtractSessionPersistenceRules(anns)
+	require.Equal(t, []string{"0", "2"}, rules)
+	require.True(t, exist)
+
+	_, exist = ExtractSessionPersistenceCookie(map[string]string{})
+	require.False(t, exist)
+	_, exist = ExtractSessionPersistenceRules(map[string]string{})
+	require.False(t, exist)
+}
+
+func TestExtractCredentialRotationGracePeriod(t *testing.T) {
+	got, exist := ExtractCredentialRotationGracePeriod(map[string]string{})
+	require.Empty(t, got)
+	require.False(t, exist)
+
+	got, exist = ExtractCredentialRotationGracePeriod(map[string]string{
+		"konghq.com/credential-rotation-grace-period": "24h",
+	})
+	require.Equal(t, "24h", got)
+	require.True(t, exist)
+}
+
+func TestExtractManagedGatewayImage(t *testing.T) {
+	got, exist := ExtractManagedGatewayImage(map[string]string{})
+	require.Empty(t, got)
+	require.False(t, exist)
+
+	_, exist = ExtractManagedGatewayImage(map[string]string{
+		"konghq.com/managed-gateway-image": "",
+	})
+	require.False(t, exist)
+
+	got, exist = ExtractManagedGatewayImage(map[string]string{
+		"konghq.com/managed-gateway-image": "kong:3.5",
+	})
+	require.Equal(t, "kong:3.5", got)
+	require.True(t, exist)
+}
@@ -9,6 +9,8 @@ import (
 	"github.com/go-logr/logr"
 	"github.com/samber/lo"
 	"golang.org/x/exp/maps"
+	k8stypes "k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
 
 	"github.com/kong/kubernetes-ingress-controller/v2/internal/adminapi"
 	"github.com/kong/kubernetes-ingress-controller/v2/internal/util"
@@ -29,6 +31,7 @@ type ClientFactory interface {
 type AdminAPIClientsProvider interface {
 	KonnectClient() *adminapi.KonnectClient
 	GatewayClients() []*adminapi.Client
+	ManagedGatewayClients() map[k8stypes.NamespacedName][]*adminapi.Client
 }
 
 // Ticker is an interface that allows to control a ticker.
@@ -48,6 +51,21 @@ type AdminAPIClientsManager struct {
 	discoveredAdminAPIsNotifyChan    chan []adminapi.DiscoveredAdminAPI
 	gatewayClientsChangesSubscribers []chan struct{}
 
+	// discoveredAdminAPIs are the Admin API endpoints discovered from the Admin API Service.
+	discoveredAdminAPIs []adminapi.DiscoveredAdminAPI
+	// managedGatewaysAdminAPIs are the Admin API endpoints of Kong data-planes provisioned for managed Gateways.
+	// Gateways whose data-planes have no endpoints yet are kept with empty lists.
+	managedGatewaysAdminAPIs map[k8stypes.NamespacedName][]adminapi.DiscoveredAdminAPI
+	// managedGatewaysAddresses maps addresses of managedGatewaysAdminAPIs to their Gateways. Clients of these
+	// addresses are excluded from GatewayClients, as their data-planes serve only routes of their Gateways.
+	// It's guarded by lock, as it's read along with readyGatewayClients.
+	managedGatewaysAddresses map[string]k8stypes.NamespacedName
+	// managedGateways are the keys of managedGatewaysAdminAPIs, guarded by lock like managedGatewaysAddresses.
+	managedGateways sets.Set[k8stypes.NamespacedName]
+	// notifyLock serializes notifications, so the last one sent to discoveredAdminAPIsNotifyChan
+	// always reflects the latest discoveredAdminAPIs and managedGatewaysAdminAPIs.
+	notifyLock sync.Mutex
+
 	ctx                   context.Context
 	onceNotifyLoopRunning sync.Once
 	runningChan           chan struct{}
@@ -105,6 +123,9 @@ func NewAdminAPIClientsManager(
 		readinessChecker:              readinessChecker,
 		readinessReconciliationTicker: clock.NewTicker(),
 		discoveredAdminAPIsNotifyChan: make(chan []adminapi.DiscoveredAdminAPI),
+		managedGatewaysAdminAPIs:      make(map[k8stypes.NamespacedName][]adminapi.DiscoveredAdminAPI),
+		managedGatewaysAddresses:      make(map[string]k8stypes.NamespacedName),
+		managedGateways:               sets.New[k8stypes.NamespacedName](),
 		ctx:                           ctx,
 		runningChan:                   make(chan struct{}),
 		logger:                        logger,
@@ -137,6 +158,43 @@ func (c *AdminAPIClientsManager) Run() {
 // Notify receives a list of addresses that KongClient should use from now on as
 // a list of Kong Admin API endpoints.
 func (c *AdminAPIClientsManager) Notify(discoveredAPIs []adminapi.DiscoveredAdminAPI) {
+	c.notifyLock.Lock()
+	defer c.notifyLock.Unlock()
+
+	c.discoveredAdminAPIs = discoveredAPIs
+	c.notify()
+}
+
+// NotifyManagedGateway receives a list of Admin API endpoints of Kong data-planes provisioned for a managed
+// Gateway. Their clients are managed along with the ones of the endpoints passed to Notify, but they're returned
+// by ManagedGatewayClients instead of GatewayClients. An empty list removes the Gateway's endpoints, but keeps
+// the Gateway known as managed, so its routes are still kept out of the configuration of the other Gateways.
+// A nil list forgets the Gateway, e.g. when it's deleted or its GatewayClass is no longer managed.
+func (c *AdminAPIClientsManager) NotifyManagedGateway(gateway k8stypes.NamespacedName, discoveredAPIs []adminapi.DiscoveredAdminAPI) {
+	c.notifyLock.Lock()
+	defer c.notifyLock.Unlock()
+
+	if discoveredAPIs == nil {
+		delete(c.managedGatewaysAdminAPIs, gateway)
+	} else {
+		c.managedGatewaysAdminAPIs[gateway] = discoveredAPIs
+	}
+
+	c.lock.Lock()
+	c.managedGateways = sets.KeySet(c.managedGatewaysAdminAPIs)
+	maps.Clear(c.managedGatewaysAddresses)
+	for gateway, apis := range c.managedGatewaysAdminAPIs {
+		for _, api := range apis {
+			c.managedGatewaysAddresses[api.Address] = gateway
+		}
+	}
+	c.lock.Unlock()
+
+	c.notify()
+}
+
+// notify sends all the known Admin API endpoints to the reconciliation loop. It must be called with notifyLock held.
+func (c *AdminAPIClientsManager) notify() {
 	// Ensure here that we're not done.
 	select {
 	case <-c.ctx.Done():
@@ -144,6 +202,14 @@ func (c *AdminAPIClientsManager) Notify(discoveredAPIs []adminapi.DiscoveredAdmi
 	default:
 	}
 
+	discoveredAPIs := c.discoveredAdminAPIs
+	if len(c.managedGatewaysAdminAPIs) > 0 {
+		discoveredAPIs = append([]adminapi.DiscoveredAdminAPI{}, c.discoveredAdminAPIs...)
+		for _, managedGatewayAPIs := range c.managedGatewaysAdminAPIs {
+			discoveredAPIs = append(discoveredAPIs, managedGatewayAPIs...)
+		}
+	}
+
 	// And here also listen on c.ctx.Done() to allow the notification to be interrupted.
 	select {
 	case <-c.ctx.Done():
@@ -165,18 +231,48 @@ func (c *AdminAPIClientsManager) KonnectClient() *adminapi.KonnectClient {
 	return c.konnectClient
 }
 
-// GatewayClients returns a copy of current client's slice. Konnect client won't be included.
+// GatewayClients returns a copy of current client's slice. Konnect client and clients of data-planes provisioned
+// for managed Gateways won't be included.
 // This method can be used when some actions need to be performed only against Kong Gateway clients.
 func (c *AdminAPIClientsManager) GatewayClients() []*adminapi.Client {
 	c.lock.RLock()
 	defer c.lock.RUnlock()
-	return lo.Values(c.readyGatewayClients)
+	return lo.Values(lo.OmitBy(c.readyGatewayClients, func(address string, _ *adminapi.Client) bool {
+		_, managed := c.managedGatewaysAddresses[address]
+		return managed
+	}))
 }
 
-func (c *AdminAPIClientsManager) GatewayClientsCount() int {
+// ManagedGatewayClients returns current clients of data-planes provisioned for managed Gateways, grouped by
+// their Gateways. These data-planes are supposed to be configured only with routes attached to their Gateways.
+// Managed Gateways whose data-planes have no ready clients are included with no clients.
+func (c *AdminAPIClientsManager) ManagedGatewayClients() map[k8stypes.NamespacedName][]*adminapi.Client {
 	c.lock.RLock()
 	defer c.lock.RUnlock()
-	return len(c.readyGatewayClients)
+	clients := make(map[k8stypes.NamespacedName][]*adminapi.Client, c.managedGateways.Len())
+	for gateway := range c.managedGateways {
+		clients[gateway] = nil
+	}
+	for address, cl := range c.readyGatewayClients {
+		if gateway, ok := c.managedGatewaysAddresses[address]; ok {
+			clients[gateway] = append(clients[gateway], cl)
+		}
+	}
+	return clients
+}
+
+// EntitySchema returns a schema of a Kong entity retrieved from any of the Gateways. Schemas of entities are assumed
+// to be the same in all Gateways as they run the same version with the same plugins installed.
+func (c *AdminAPIClientsManager) EntitySchema(ctx context.Context, entityName string) (map[string]interface{}, error) {
+	gwClients := c.GatewayClients()
+	if len(gwClients) == 0 {
+		return nil, errors.New("no ready gateway clients")
+	}
+	return gwClients[0].PluginSchemaStore().EntitySchema(ctx, entityName)
+}
+
+func (c *AdminAPIClientsManager) GatewayClientsCount() int {
+	return len(c.GatewayClients())
 }
 
 // SubscribeToGatewayClientsChanges returns a channel that will receive a notification on every Gateway clients update.
@@ -157,6 +157,65 @@ func TestAdminAPIClientsManager_OnNotifyClientsAreUpdatedAccordingly(t *testing.
 	require.NotPanics(t, func() { manager.Notify([]adminapi.DiscoveredAdminAPI{}) }, "notifying about new clients after manager has been shut down shouldn't panic")
 }
 
+func TestAdminAPIClientsManager_NotifyManagedGateway(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	logger := zapr.NewLogger(zap.NewNop())
+	readinessChecker := &mockReadinessChecker{}
+	initialClient, err := adminapi.NewTestClient("https://localhost:8083")
+	require.NoError(t, err)
+	manager, err := clients.NewAdminAPIClientsManager(
+		ctx,
+		logger,
+		[]*adminapi.Client{initialClient},
+		readinessChecker,
+	)
+	require.NoError(t, err)
+	manager.Run()
+	<-manager.Running()
+
+	gateway := k8stypes.NamespacedName{Namespace: "default", Name: "managed"}
+	clientAddresses := func(clients []*adminapi.Client) []string {
+		addresses := lo.Map(clients, func(cl *adminapi.Client, _ int) string {
+			return cl.BaseRootURL()
+		})
+		slices.Sort(addresses)
+		return addresses
+	}
+	requireClientsMatchEventually := func(t *testing.T, addresses, managedAddresses []string, args ...any) {
+		require.Eventually(t, func() bool {
+			return slices.Equal(addresses, clientAddresses(manager.GatewayClients())) &&
+				slices.Equal(managedAddresses, clientAddresses(manager.ManagedGatewayClients()[gateway]))
+		}, time.Second, time.Millisecond, args...)
+	}
+
+	readinessChecker.LetChecksReturn(clients.ReadinessCheckResult{ClientsTurnedReady: intoTurnedReady(testURL1)})
+	manager.Notify([]adminapi.DiscoveredAdminAPI{testDiscoveredAdminAPI(testURL1)})
+	requireClientsMatchEventually(t, []string{testURL1}, nil,
+		"after notifying about a discovered address we should get 1 client eventually")
+
+	readinessChecker.LetChecksReturn(clients.ReadinessCheckResult{ClientsTurnedReady: intoTurnedReady(testURL2)})
+	manager.NotifyManagedGateway(gateway, []adminapi.DiscoveredAdminAPI{testDiscoveredAdminAPI(testURL2)})
+	requireClientsMatchEventually(t, []string{testURL1}, []string{testURL2},
+		"after notifying about a managed Gateway's address we should get its client separately from the discovered one")
+	require.Equal(t, 1, manager.GatewayClientsCount(), "managed Gateway's client should not be counted")
+
+	readinessChecker.LetChecksReturn(clients.ReadinessCheckResult{})
+	manager.Notify([]adminapi.DiscoveredAdminAPI{testDiscoveredAdminAPI(testURL1)})
+	requireClientsMatchEventually(t, []string{testURL1}, []string{testURL2},
+		"notifying about discovered addresses should keep the managed Gateway's client")
+
+	manager.NotifyManagedGateway(gateway, []adminapi.DiscoveredAdminAPI{})
+	requireClientsMatchEventually(t, []string{testURL1}, nil,
+		"after removing the managed Gateway's addresses only the discovered client should be left")
+	require.Contains(t, manager.ManagedGatewayClients(), gateway,
+		"managed Gateway without addresses should still be known")
+
+	manager.NotifyManagedGateway(gateway, nil)
+	require.NotContains(t, manager.ManagedGatewayClients(), gateway, "forgotten managed Gateway should not be known")
+}
+
 func TestNewAdminAPIClientsManager_NoInitialClientsDisallowed(t *testing.T) {
 	_, err := clients.NewAdminAPIClientsManager(context.Background(), zapr.NewLogger(zap.NewNop()), nil, &mockReadinessChecker{})
 	require.ErrorContains(t, err, "at least one initial client must be provided")
@@ -15,11 +15,12 @@ import (
 // Execute is the entry point to the controller manager.
 func Execute() {
 	var (
-		cfg        manager.Config
-		rootCmd    = GetRootCmd(&cfg)
-		versionCmd = GetVersionCmd()
+		cfg          manager.Config
+		rootCmd      = GetRootCmd(&cfg)
+		versionCmd   = GetVersionCmd()
+		translateCmd = GetTranslateCmd()
 	)
-	rootCmd.AddCommand(versionCmd)
+	rootCmd.AddCommand(versionCmd, translateCmd)
 	cobra.CheckErr(rootCmd.Execute())
 }
 
@@ -42,6 +42,7 @@ func StartDiagnosticsServer(
 		s.ConfigDumps = util.ConfigDumpDiagnostic{
 			DumpsIncludeSensitive: c.DumpSensitiveConfig,
 			Configs:               make(chan util.ConfigDump, DiagnosticConfigBufferDepth),
+			Failures:              make(chan util.FailuresDump, DiagnosticConfigBufferDepth),
 		}
 	}
 	go func() {
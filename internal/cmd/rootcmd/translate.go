@@ -0,0 +1,222 @@
+package rootcmd
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/go-logr/logr"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"k8s.io/apimachinery/pkg/util/sets"
+	utilyaml "k8s.io/apimachinery/pkg/util/yaml"
+	cliflag "k8s.io/component-base/cli/flag"
+	"sigs.k8s.io/yaml"
+
+	"github.com/kong/kubernetes-ingress-controller/v2/internal/annotations"
+	"github.com/kong/kubernetes-ingress-controller/v2/internal/dataplane/deckgen"
+	"github.com/kong/kubernetes-ingress-controller/v2/internal/dataplane/failures"
+	"github.com/kong/kubernetes-ingress-controller/v2/internal/dataplane/parser"
+	"github.com/kong/kubernetes-ingress-controller/v2/internal/dataplane/parser/translators"
+	"github.com/kong/kubernetes-ingress-controller/v2/internal/manager/featuregates"
+	"github.com/kong/kubernetes-ingress-controller/v2/internal/store"
+)
+
+const (
+	translateOutputYAML = "yaml"
+	translateOutputJSON = "json"
+
+	routerFlavorTraditional           = "traditional"
+	routerFlavorTraditionalCompatible = "traditional_compatible"
+	routerFlavorExpressions           = "expressions"
+)
+
+// ErrTranslationFailures is returned by Translate when some of the objects failed to be translated.
+var ErrTranslationFailures = errors.New("translation failed")
+
+// TranslateConfig is the configuration of the translate command.
+type TranslateConfig struct {
+	IngressClassName string
+	RouterFlavor     string
+	FeatureGates     map[string]bool
+	Output           string
+}
+
+// FlagSet binds the translate command configuration to flags.
+func (c *TranslateConfig) FlagSet() *pflag.FlagSet {
+	flagSet := pflag.NewFlagSet("", pflag.ExitOnError)
+	flagSet.StringVar(&c.IngressClassName, "ingress-class", annotations.DefaultIngressClass,
+		`Name of the ingress class of the objects to translate.`)
+	flagSet.StringVar(&c.RouterFlavor, "router-flavor", routerFlavorTraditionalCompatible,
+		fmt.Sprintf(`Router flavor of the Kong Gateway the configuration is generated for. One of: %s.`,
+			strings.Join([]string{routerFlavorTraditional, routerFlavorTraditionalCompatible, routerFlavorExpressions}, ", ")))
+	flagSet.Var(cliflag.NewMapStringBool(&c.FeatureGates), "feature-gates",
+		"A set of key=value pairs that describe feature gates for alpha/beta/experimental features. "+
+			fmt.Sprintf("See the Feature Gates documentation for information and available options: %s.", featuregates.DocsURL))
+	flagSet.StringVarP(&c.Output, "output", "o", translateOutputYAML,
+		fmt.Sprintf(`Format of the printed configuration. One of: %s, %s.`, translateOutputYAML, translateOutputJSON))
+	return flagSet
+}
+
+func GetTranslateCmd() *cobra.Command {
+	var cfg TranslateConfig
+	cmd := &cobra.Command{
+		Use:   "translate [FILE]...",
+		Short: "Translate Kubernetes manifests to Kong declarative configuration without a cluster",
+		Long: "Translate Kubernetes manifests read from files, or from stdin when no files or '-' are given, " +
+			"to the Kong declarative configuration the controller would send to Kong Gateway. " +
+			"Translation failures are printed to stderr and make the command exit with a non-zero code.",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			manifests, err := readManifests(cmd.InOrStdin(), args)
+			if err != nil {
+				return err
+			}
+			return Translate(cmd.Context(), cfg, manifests, cmd.OutOrStdout(), cmd.ErrOrStderr())
+		},
+	}
+	cmd.Flags().AddFlagSet(cfg.FlagSet())
+	return cmd
+}
+
+// Translate translates manifests with YAML or JSON Kubernetes objects to a Kong declarative configuration printed
+// to out. Translation failures are printed to errOut, and ErrTranslationFailures is returned after printing
+// the configuration translated from the other objects.
+func Translate(ctx context.Context, cfg TranslateConfig, manifests [][]byte, out io.Writer, errOut io.Writer) error {
+	switch cfg.RouterFlavor {
+	case routerFlavorTraditional, routerFlavorTraditionalCompatible, routerFlavorExpressions:
+	default:
+		return fmt.Errorf("invalid router flavor %q", cfg.RouterFlavor)
+	}
+	if cfg.Output != translateOutputYAML && cfg.Output != translateOutputJSON {
+		return fmt.Errorf("invalid output format %q", cfg.Output)
+	}
+
+	logger := logr.Discard()
+	featureGates, err := featuregates.New(logger, cfg.FeatureGates)
+	if err != nil {
+		return err
+	}
+
+	objects, err := splitManifests(manifests)
+	if err != nil {
+		return err
+	}
+	cacheStores, err := store.NewCacheStoresFromObjYAML(objects...)
+	if err != nil {
+		return fmt.Errorf("failed to load objects: %w", err)
+	}
+
+	// There's no Kong Gateway to get available plugins from, so the plugins bundled with it are assumed.
+	featureFlags := parser.NewFeatureFlags(logger, featureGates, cfg.RouterFlavor, sets.New(translators.QueryParamsGuardPluginName), false)
+	p, err := parser.NewParser(logger, store.New(cacheStores, cfg.IngressClassName, logger), featureFlags)
+	if err != nil {
+		return fmt.Errorf("failed to create parser: %w", err)
+	}
+	result := p.BuildKongConfig()
+	for _, failure := range result.TranslationFailures {
+		fmt.Fprintln(errOut, formatTranslationFailure(failure))
+	}
+
+	content := deckgen.ToDeckContent(ctx, logger, result.KongState, deckgen.GenerateDeckContentParams{
+		ExpressionRoutes: featureFlags.ExpressionRoutes,
+		PluginSchemas:    emptyPluginSchemaStore{},
+	})
+
+	var b []byte
+	switch cfg.Output {
+	case translateOutputJSON:
+		b, err = json.MarshalIndent(content, "", "  ")
+		b = append(b, '\n')
+	default:
+		b, err = yaml.Marshal(content)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to marshal configuration: %w", err)
+	}
+	if _, err := out.Write(b); err != nil {
+		return err
+	}
+	if len(result.TranslationFailures) > 0 {
+		return fmt.Errorf("%w: %d translation failure(s)", ErrTranslationFailures, len(result.TranslationFailures))
+	}
+	return nil
+}
+
+// readManifests reads the files with the given paths, reading stdin for '-' or when no paths are given.
+func readManifests(stdin io.Reader, paths []string) ([][]byte, error) {
+	if len(paths) == 0 {
+		paths = []string{"-"}
+	}
+	manifests := make([][]byte, 0, len(paths))
+	for _, path := range paths {
+		var (
+			b   []byte
+			err error
+		)
+		if path == "-" {
+			b, err = io.ReadAll(stdin)
+		} else {
+			b, err = os.ReadFile(path)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read manifests from %s: %w", path, err)
+		}
+		manifests = append(manifests, b)
+	}
+	return manifests, nil
+}
+
+// splitManifests splits multi-document manifests into single objects, skipping empty documents.
+func splitManifests(manifests [][]byte) ([][]byte, error) {
+	var objects [][]byte
+	for _, manifest := range manifests {
+		reader := utilyaml.NewYAMLReader(bufio.NewReader(bytes.NewReader(manifest)))
+		for {
+			doc, err := reader.Read()
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			if err != nil {
+				return nil, fmt.Errorf("failed to read manifest: %w", err)
+			}
+			if isEmptyYAMLDocument(doc) {
+				continue
+			}
+			objects = append(objects, doc)
+		}
+	}
+	return objects, nil
+}
+
+func isEmptyYAMLDocument(doc []byte) bool {
+	for _, line := range strings.Split(string(doc), "\n") {
+		line = strings.TrimSpace(line)
+		if line != "" && !strings.HasPrefix(line, "#") {
+			return false
+		}
+	}
+	return true
+}
+
+func formatTranslationFailure(failure failures.ResourceFailure) string {
+	objects := make([]string, 0, len(failure.CausingObjects()))
+	for _, obj := range failure.CausingObjects() {
+		objects = append(objects, fmt.Sprintf("%s %s/%s",
+			obj.GetObjectKind().GroupVersionKind().Kind, obj.GetNamespace(), obj.GetName()))
+	}
+	return fmt.Sprintf("translation failure: %s (%s)", failure.Message(), strings.Join(objects, ", "))
+}
+
+// emptyPluginSchemaStore returns empty schemas for all plugins, as there's no Kong Gateway to get them from.
+// Plugin configurations are therefore not filled with defaults.
+type emptyPluginSchemaStore struct{}
+
+func (emptyPluginSchemaStore) Schema(context.Context, string) (map[string]interface{}, error) {
+	return map[string]interface{}{}, nil
+}
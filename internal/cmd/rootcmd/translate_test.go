@@ -0,0 +1,160 @@
+package rootcmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"sigs.k8s.io/yaml"
+)
+
+const translateTestManifests = `
+apiVersion: v1
+kind: Service
+metadata:
+  name: svc
+  namespace: default
+spec:
+  ports:
+  - port: 80
+---
+# empty document
+---
+apiVersion: networking.k8s.io/v1
+kind: Ingress
+metadata:
+  name: ingress
+  namespace: default
+  annotations:
+    kubernetes.io/ingress.class: kong
+spec:
+  rules:
+  - http:
+      paths:
+      - path: /
+        pathType: Prefix
+        backend:
+          service:
+            name: svc
+            port:
+              number: 80
+`
+
+const translateTestInvalidHTTPRoute = `
+apiVersion: gateway.networking.k8s.io/v1
+kind: HTTPRoute
+metadata:
+  name: route
+  namespace: default
+  annotations:
+    konghq.com/session-persistence-cookie: session
+    konghq.com/session-persistence-header: x-session
+spec:
+  rules:
+  - backendRefs:
+    - name: svc
+      port: 80
+`
+
+func TestTranslate(t *testing.T) {
+	defaultConfig := func() TranslateConfig {
+		return TranslateConfig{
+			IngressClassName: "kong",
+			RouterFlavor:     routerFlavorTraditionalCompatible,
+			Output:           translateOutputYAML,
+		}
+	}
+
+	type deckConfig struct {
+		Services []struct {
+			Name   string `json:"name"`
+			Routes []struct {
+				Name       string `json:"name"`
+				Expression string `json:"expression"`
+			} `json:"routes"`
+		} `json:"services"`
+	}
+
+	t.Run("yaml output", func(t *testing.T) {
+		var out, errOut bytes.Buffer
+		err := Translate(context.Background(), defaultConfig(), [][]byte{[]byte(translateTestManifests)}, &out, &errOut)
+		require.NoError(t, err)
+		assert.Empty(t, errOut.String())
+
+		var config deckConfig
+		require.NoError(t, yaml.Unmarshal(out.Bytes(), &config))
+		require.Len(t, config.Services, 1)
+		assert.Equal(t, "default.svc.80", config.Services[0].Name)
+		require.Len(t, config.Services[0].Routes, 1)
+		assert.Equal(t, "default.ingress.svc..80", config.Services[0].Routes[0].Name)
+	})
+
+	t.Run("json output with expressions router", func(t *testing.T) {
+		cfg := defaultConfig()
+		cfg.Output = translateOutputJSON
+		cfg.RouterFlavor = routerFlavorExpressions
+		var out, errOut bytes.Buffer
+		err := Translate(context.Background(), cfg, [][]byte{[]byte(translateTestManifests)}, &out, &errOut)
+		require.NoError(t, err)
+
+		var config deckConfig
+		require.NoError(t, json.Unmarshal(out.Bytes(), &config))
+		require.Len(t, config.Services, 1)
+		require.Len(t, config.Services[0].Routes, 1)
+		assert.NotEmpty(t, config.Services[0].Routes[0].Expression)
+	})
+
+	t.Run("translation failures are printed separately", func(t *testing.T) {
+		var out, errOut bytes.Buffer
+		err := Translate(context.Background(), defaultConfig(),
+			[][]byte{[]byte(translateTestManifests), []byte(translateTestInvalidHTTPRoute)}, &out, &errOut)
+		require.ErrorIs(t, err, ErrTranslationFailures)
+		assert.Contains(t, errOut.String(), "translation failure: ")
+		assert.Contains(t, errOut.String(), "(HTTPRoute default/route)")
+		assert.NotContains(t, out.String(), "translation failure")
+		assert.Contains(t, out.String(), "services:", "configuration translated from other objects is printed")
+	})
+
+	t.Run("invalid options", func(t *testing.T) {
+		for _, cfg := range []TranslateConfig{
+			{IngressClassName: "kong", RouterFlavor: "unknown", Output: translateOutputYAML},
+			{IngressClassName: "kong", RouterFlavor: routerFlavorTraditional, Output: "xml"},
+			{IngressClassName: "kong", RouterFlavor: routerFlavorTraditional, Output: translateOutputYAML, FeatureGates: map[string]bool{"Unknown": true}},
+		} {
+			err := Translate(context.Background(), cfg, [][]byte{[]byte(translateTestManifests)}, &bytes.Buffer{}, &bytes.Buffer{})
+			require.Error(t, err)
+		}
+	})
+}
+
+func TestTranslateCmd(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "manifests.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(translateTestManifests), 0o600))
+
+	var fromFile, fromStdin bytes.Buffer
+	cmd := GetTranslateCmd()
+	cmd.SetArgs([]string{"--feature-gates", "FillIDs=false", path})
+	cmd.SetOut(&fromFile)
+	require.NoError(t, cmd.Execute())
+
+	cmd = GetTranslateCmd()
+	cmd.SetArgs([]string{"--feature-gates", "FillIDs=false", "-"})
+	cmd.SetIn(bytes.NewBufferString(translateTestManifests))
+	cmd.SetOut(&fromStdin)
+	require.NoError(t, cmd.Execute())
+
+	assert.Equal(t, fromFile.String(), fromStdin.String())
+	assert.NotContains(t, fromFile.String(), "id:", "IDs shouldn't be filled with FillIDs disabled")
+
+	cmd = GetTranslateCmd()
+	cmd.SetArgs([]string{"-"})
+	cmd.SetIn(bytes.NewBufferString(translateTestManifests + "---\n" + translateTestInvalidHTTPRoute))
+	cmd.SetOut(&bytes.Buffer{})
+	cmd.SetErr(&bytes.Buffer{})
+	require.ErrorIs(t, cmd.Execute(), ErrTranslationFailures, "translation failures should make the command fail")
+}
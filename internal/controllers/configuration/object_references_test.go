@@ -167,6 +167,31 @@ func TestListKongPluginReferredSecrets(t *testing.T) {
 				Name:      "secret1",
 			},
 		},
+		{
+			name: "kong_plugin_refer_secrets_in_config_patches",
+			plugin: &kongv1.KongPlugin{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: "ns",
+					Name:      "plugin1",
+				},
+				ConfigPatches: []kongv1.ConfigPatch{
+					{
+						Path: "/key",
+						ValueFrom: kongv1.ConfigSource{
+							SecretValue: kongv1.SecretValueFromSource{
+								Secret: "secret2",
+								Key:    "k",
+							},
+						},
+					},
+				},
+			},
+			secretNum: 1,
+			refSecretName: k8stypes.NamespacedName{
+				Namespace: "ns",
+				Name:      "secret2",
+			},
+		},
 	}
 
 	for _, tc := range testCases {
@@ -216,6 +241,31 @@ func TestListKongClusterPluginReferredSecrets(t *testing.T) {
 				Name:      "secret1",
 			},
 		},
+		{
+			name: "kong_cluster_plugin_refer_secrets_in_config_patches",
+			plugin: &kongv1.KongClusterPlugin{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "plugin1",
+				},
+				ConfigPatches: []kongv1.NamespacedConfigPatch{
+					{
+						Path: "/key",
+						ValueFrom: kongv1.NamespacedConfigSource{
+							SecretValue: kongv1.NamespacedSecretValueFromSource{
+								Namespace: "ns2",
+								Secret:    "secret2",
+								Key:       "k",
+							},
+						},
+					},
+				},
+			},
+			secretNum: 1,
+			refSecretName: k8stypes.NamespacedName{
+				Namespace: "ns2",
+				Name:      "secret2",
+			},
+		},
 	}
 
 	for _, tc := range testCases {
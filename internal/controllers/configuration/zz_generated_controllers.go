@@ -1047,6 +1047,12 @@ func (r *KongV1KongConsumerReconciler) Reconcile(ctx context.Context, req ctrl.R
 		log.V(util.DebugLevel).Info("updating programmed condition status", "namespace", req.Namespace, "name", req.Name)
 		configurationStatus := r.DataplaneClient.KubernetesObjectConfigurationStatus(obj)
 		conditions, updateNeeded := ctrlutils.EnsureProgrammedCondition(configurationStatus, obj.Generation, obj.Status.Conditions)
+		fallbackStatus := r.DataplaneClient.KubernetesObjectFallbackStatus(obj)
+		conditions, excludedUpdateNeeded := ctrlutils.EnsureExcludedCondition(fallbackStatus, obj.Generation, conditions)
+		updateNeeded = updateNeeded || excludedUpdateNeeded
+		credentialRotations := r.DataplaneClient.KubernetesObjectCredentialRotations(obj)
+		conditions, credentialRotationUpdateNeeded := ctrlutils.EnsureCredentialRotationCondition(credentialRotations, obj.Generation, conditions)
+		updateNeeded = updateNeeded || credentialRotationUpdateNeeded
 		obj.Status.Conditions = conditions
 		if updateNeeded {
 			return ctrl.Result{}, r.Status().Update(ctx, obj)
@@ -1234,6 +1240,9 @@ func (r *KongV1Beta1KongConsumerGroupReconciler) Reconcile(ctx context.Context,
 		log.V(util.DebugLevel).Info("updating programmed condition status", "namespace", req.Namespace, "name", req.Name)
 		configurationStatus := r.DataplaneClient.KubernetesObjectConfigurationStatus(obj)
 		conditions, updateNeeded := ctrlutils.EnsureProgrammedCondition(configurationStatus, obj.Generation, obj.Status.Conditions)
+		fallbackStatus := r.DataplaneClient.KubernetesObjectFallbackStatus(obj)
+		conditions, excludedUpdateNeeded := ctrlutils.EnsureExcludedCondition(fallbackStatus, obj.Generation, conditions)
+		updateNeeded = updateNeeded || excludedUpdateNeeded
 		obj.Status.Conditions = conditions
 		if updateNeeded {
 			return ctrl.Result{}, r.Status().Update(ctx, obj)
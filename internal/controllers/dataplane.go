@@ -17,6 +17,8 @@ type DataPlane interface {
 	Listeners(ctx context.Context) ([]kong.ProxyListener, []kong.StreamListener, error)
 	AreKubernetesObjectReportsEnabled() bool
 	KubernetesObjectConfigurationStatus(obj client.Object) k8sobj.ConfigurationStatus
+	KubernetesObjectFallbackStatus(obj client.Object) k8sobj.FallbackStatus
+	KubernetesObjectCredentialRotations(obj client.Object) []k8sobj.CredentialRotation
 	KubernetesObjectIsConfigured(obj client.Object) bool
 }
 
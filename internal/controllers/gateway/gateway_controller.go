@@ -59,6 +59,9 @@ type GatewayReconciler struct { //nolint:revive
 	PublishServiceRef    k8stypes.NamespacedName
 	PublishServiceUDPRef mo.Option[k8stypes.NamespacedName]
 
+	// ManagedGateways configures provisioning of Kong data-planes for Gateways of managed GatewayClasses.
+	ManagedGateways ManagedGatewaysConfig
+
 	// If enableReferenceGrant is true, controller will watch ReferenceGrants
 	// to invalidate or allow cross-namespace TLSConfigs in gateways.
 	// It's resolved on SetupWithManager call.
@@ -135,6 +138,12 @@ func (r *GatewayReconciler) SetupWithManager(mgr ctrl.Manager) error {
 		return err
 	}
 
+	if r.ManagedGateways.Enabled {
+		if err := r.watchManagedGatewaysObjects(mgr, c); err != nil {
+			return err
+		}
+	}
+
 	// watch ReferenceGrants, which may invalidate or allow cross-namespace TLSConfigs
 	if r.enableReferenceGrant {
 		if err := c.Watch(
@@ -152,6 +161,8 @@ func (r *GatewayReconciler) SetupWithManager(mgr ctrl.Manager) error {
 		Log:              r.Log.WithName("V1Beta1GatewayClass"),
 		Scheme:           r.Scheme,
 		CacheSyncTimeout: r.CacheSyncTimeout,
+
+		ManagedGatewaysEnabled: r.ManagedGateways.Enabled,
 	}
 
 	return gwcCTRL.SetupWithManager(mgr)
@@ -167,7 +178,8 @@ func (r *GatewayReconciler) SetLogger(l logr.Logger) {
 // -----------------------------------------------------------------------------
 
 // gatewayHasMatchingGatewayClass is a watch predicate which filters out reconciliation events for
-// gateway objects which aren't supported by this controller or not using an unmanaged GatewayClass.
+// gateway objects which aren't supported by this controller or not using an unmanaged GatewayClass
+// (or a managed one, when managed Gateways are enabled).
 func (r *GatewayReconciler) gatewayHasMatchingGatewayClass(obj client.Object) bool {
 	gateway, ok := obj.(*gatewayapi.Gateway)
 	if !ok {
@@ -183,11 +195,12 @@ func (r *GatewayReconciler) gatewayHasMatchingGatewayClass(obj client.Object) bo
 		r.Log.Error(err, "could not retrieve gatewayclass", "gatewayclass", gateway.Spec.GatewayClassName)
 		return false
 	}
-	return isGatewayClassControlledAndUnmanaged(gatewayClass)
+	return r.isGatewayClassSupported(gatewayClass)
 }
 
 // gatewayClassMatchesController is a watch predicate which filters out events for gatewayclasses which
-// aren't configured with the required ControllerName or not annotated as unmanaged.
+// aren't configured with the required ControllerName or not annotated as unmanaged (unless managed
+// Gateways are enabled).
 func (r *GatewayReconciler) gatewayClassMatchesController(obj client.Object) bool {
 	gatewayClass, ok := obj.(*gatewayapi.GatewayClass)
 	if !ok {
@@ -198,7 +211,12 @@ func (r *GatewayReconciler) gatewayClassMatchesController(obj client.Object) boo
 		)
 		return false
 	}
-	return isGatewayClassControlledAndUnmanaged(gatewayClass)
+	return r.isGatewayClassSupported(gatewayClass)
+}
+
+// isGatewayClassSupported returns boolean if Gateways of the GatewayClass are reconciled by this controller.
+func (r *GatewayReconciler) isGatewayClassSupported(gatewayClass *gatewayapi.GatewayClass) bool {
+	return isGatewayClassSupported(gatewayClass, r.ManagedGateways.Enabled)
 }
 
 // listGatewaysForGatewayClass is a watch predicate which finds all the gateway objects reference
@@ -344,6 +362,7 @@ func (r *GatewayReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ct
 			if err != nil {
 				return ctrl.Result{}, err
 			}
+			r.forgetManagedGateway(req.NamespacedName)
 			debug(log, gateway, "reconciliation triggered but gateway does not exist, deleting it in dataplane")
 			return ctrl.Result{}, r.DataplaneClient.DeleteObject(gateway)
 		}
@@ -365,6 +384,7 @@ func (r *GatewayReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ct
 		if err != nil {
 			return ctrl.Result{}, err
 		}
+		r.forgetManagedGateway(req.NamespacedName)
 		if err := r.DataplaneClient.DeleteObject(gateway); err != nil {
 			debug(log, gateway, "failed to delete object from data-plane, requeuing")
 			return ctrl.Result{}, err
@@ -379,6 +399,7 @@ func (r *GatewayReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ct
 		if err != nil {
 			return ctrl.Result{}, err
 		}
+		r.forgetManagedGateway(req.NamespacedName)
 		if err := r.DataplaneClient.DeleteObject(gateway); err != nil {
 			debug(log, gateway, "failed to delete object from data-plane, requeuing")
 			return ctrl.Result{}, err
@@ -387,28 +408,36 @@ func (r *GatewayReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ct
 		return ctrl.Result{}, nil
 	}
 
-	// if there's any deletion timestamp on the object, we can simply ignore it. At this point
-	// with unmanaged mode being the only option supported there are no finalizers and
-	// the object should be cleaned up by GC promptly.
+	// if there's any deletion timestamp on the object, we can simply ignore it. There are no
+	// finalizers in neither of the supported modes and the object (as well as the objects
+	// provisioned for it in managed mode) should be cleaned up by GC promptly.
 	debug(log, gateway, "checking deletion timestamp")
 	if gateway.DeletionTimestamp != nil {
 		debug(log, gateway, "gateway is being deleted, ignoring")
 		return ctrl.Result{Requeue: false}, nil
 	}
 
-	// ensure that the GatewayClass matches the ControllerName and is unmanaged.
+	// ensure that the GatewayClass matches the ControllerName and its mode is supported.
 	// This check has already been performed by predicates, but we need to ensure this condition
 	// as the reconciliation loop may be triggered by objects in which predicates we
-	// cannot check the ControllerName and the unmanaged mode (e.g., ReferenceGrants).
-	if !isGatewayClassControlledAndUnmanaged(gwc) {
+	// cannot check the ControllerName and the mode (e.g., ReferenceGrants).
+	if !r.isGatewayClassSupported(gwc) {
+		r.forgetManagedGateway(req.NamespacedName)
 		return reconcile.Result{}, nil
 	}
 
-	// reconciliation assumes unmanaged mode, in the future we may have a slot here for
-	// other gateway management modes.
-	result, err := r.reconcileUnmanagedGateway(ctx, log, gateway)
-	// reconcileUnmanagedGateway has side effects and modifies the referenced gateway object. dataplane updates must
-	// happen afterwards
+	var (
+		result ctrl.Result
+		err    error
+	)
+	if isGatewayClassControlledAndUnmanaged(gwc) {
+		r.forgetManagedGateway(req.NamespacedName)
+		result, err = r.reconcileUnmanagedGateway(ctx, log, gateway)
+	} else {
+		result, err = r.reconcileManagedGateway(ctx, log, gateway, gwc)
+	}
+	// reconcileUnmanagedGateway and reconcileManagedGateway have side effects and modify the referenced gateway
+	// object. dataplane updates must happen afterwards
 	if err == nil {
 		if err := r.DataplaneClient.UpdateObject(gateway); err != nil {
 			debug(log, gateway, "failed to update object in data-plane, requeueing")
@@ -311,9 +311,10 @@ func TestIsGatewayControlledAndUnmanagedMode(t *testing.T) {
 	var testControllerName gatewayapi.GatewayController = "acme.io/gateway-controller"
 
 	testCases := []struct {
-		name           string
-		GatewayClass   *gatewayapi.GatewayClass
-		expectedResult bool
+		name            string
+		GatewayClass    *gatewayapi.GatewayClass
+		expectedResult  bool
+		expectedManaged bool
 	}{
 		{
 			name: "uncontrolled managed GatewayClass",
@@ -352,7 +353,8 @@ func TestIsGatewayControlledAndUnmanagedMode(t *testing.T) {
 					ControllerName: GetControllerName(),
 				},
 			},
-			expectedResult: false,
+			expectedResult:  false,
+			expectedManaged: true,
 		},
 		{
 			name: "controlled unmanaged GatewayClass",
@@ -376,6 +378,9 @@ func TestIsGatewayControlledAndUnmanagedMode(t *testing.T) {
 		t.Run(tc.name, func(t *testing.T) {
 			t.Parallel()
 			assert.Equal(t, tc.expectedResult, isGatewayClassControlledAndUnmanaged(tc.GatewayClass))
+			assert.Equal(t, tc.expectedManaged, isGatewayClassControlledAndManaged(tc.GatewayClass))
+			assert.Equal(t, tc.expectedResult, isGatewayClassSupported(tc.GatewayClass, false))
+			assert.Equal(t, tc.expectedResult || tc.expectedManaged, isGatewayClassSupported(tc.GatewayClass, true))
 		})
 	}
 }
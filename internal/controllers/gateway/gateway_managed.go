@@ -0,0 +1,722 @@
+package gateway
+
+import (
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/go-logr/logr"
+	"github.com/samber/lo"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8stypes "k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/apimachinery/pkg/util/sets"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+
+	"github.com/kong/kubernetes-ingress-controller/v2/internal/adminapi"
+	"github.com/kong/kubernetes-ingress-controller/v2/internal/annotations"
+	"github.com/kong/kubernetes-ingress-controller/v2/internal/gatewayapi"
+)
+
+// -----------------------------------------------------------------------------
+// Managed Gateways - Vars & Consts
+// -----------------------------------------------------------------------------
+
+const (
+	// DefaultManagedGatewayImage is the default Kong Gateway image run by data-planes
+	// provisioned for managed Gateways.
+	DefaultManagedGatewayImage = "kong:3.4"
+
+	// ManagedGatewayLabel is the label set on objects provisioned for a managed Gateway.
+	// Its value is the UID of the Gateway, as names of Gateways can exceed the length
+	// allowed for label values.
+	ManagedGatewayLabel = annotations.AnnotationPrefix + "/managed-gateway"
+
+	// managedGatewayAdminPortName is the name of the Admin API port of provisioned data-planes.
+	// It's one of the default --kong-admin-svc-port-names, so the Admin API endpoints are discovered.
+	managedGatewayAdminPortName = "admin"
+	// managedGatewayAdminPort is the port of the Admin API of provisioned data-planes.
+	managedGatewayAdminPort = 8444
+	// managedGatewayStatusPort is the port of the status API of provisioned data-planes.
+	managedGatewayStatusPort = 8100
+
+	// managedGatewayAdminClientCAKey is the key of the ConfigMap provisioned for a managed Gateway holding
+	// the CA certificate the Admin API verifies client certificates with.
+	managedGatewayAdminClientCAKey = "ca.crt"
+	// managedGatewayAdminClientCADir is the directory the ConfigMap with the Admin API client CA certificate
+	// is mounted in.
+	managedGatewayAdminClientCADir = "/etc/kong/admin-client-ca"
+	// managedGatewayAdminClientCAHashAnnotation is the annotation of the Pod template holding the hash of the
+	// Admin API client CA certificate, so Pods get replaced when it changes.
+	managedGatewayAdminClientCAHashAnnotation = annotations.AnnotationPrefix + "/admin-client-ca-hash"
+
+	// managedGatewayPrivilegedPortOffset is added to privileged ports of listeners to get the ports
+	// Kong listens on, as Kong doesn't run as root and can't bind to them.
+	managedGatewayPrivilegedPortOffset = 8000
+)
+
+// AdminAPIsDiscoverer discovers Admin API endpoints of Kong Gateways backing a Service.
+type AdminAPIsDiscoverer interface {
+	GetAdminAPIsForService(
+		ctx context.Context,
+		kubeClient client.Client,
+		service k8stypes.NamespacedName,
+	) (sets.Set[adminapi.DiscoveredAdminAPI], error)
+}
+
+// ManagedGatewayAdminAPIsNotifier is notified about Admin API endpoints of data-planes provisioned for managed Gateways.
+type ManagedGatewayAdminAPIsNotifier interface {
+	NotifyManagedGateway(gateway k8stypes.NamespacedName, adminAPIs []adminapi.DiscoveredAdminAPI)
+}
+
+// ManagedGatewaysConfig configures provisioning of Kong data-planes for Gateways of managed GatewayClasses,
+// i.e. GatewayClasses controlled by this controller that aren't annotated as unmanaged.
+type ManagedGatewaysConfig struct {
+	// Enabled enables the managed mode. When disabled, Gateways of managed GatewayClasses are ignored.
+	Enabled bool
+
+	// Image is the Kong Gateway image run by the provisioned data-planes. GatewayClasses can override it
+	// with the konghq.com/managed-gateway-image annotation.
+	Image string
+
+	// RouterFlavor is the router flavor of the provisioned data-planes. As all Kong Gateways configured by
+	// the controller get the same configuration, it has to match the router flavor of the other Kong Gateways.
+	RouterFlavor string
+
+	// AdminClientCACert is the PEM-encoded CA certificate the Admin APIs of the provisioned data-planes verify
+	// client certificates with, so that only the controller (presenting its Admin API client certificate)
+	// can access them.
+	AdminClientCACert string
+
+	// AdminAPIsDiscoverer discovers Admin API endpoints of the provisioned data-planes.
+	AdminAPIsDiscoverer AdminAPIsDiscoverer
+
+	// AdminAPIsNotifier is notified about Admin API endpoints of the provisioned data-planes, so they get
+	// configured with routes attached to their Gateways.
+	AdminAPIsNotifier ManagedGatewayAdminAPIsNotifier
+}
+
+// -----------------------------------------------------------------------------
+// Managed Gateways - Listeners
+// -----------------------------------------------------------------------------
+
+// managedGatewayListener is a Gateway listener served by a data-plane provisioned for a managed Gateway.
+type managedGatewayListener struct {
+	gatewayapi.Listener
+
+	// containerPort is the port Kong listens on for the listener.
+	containerPort int32
+}
+
+// portName returns the name of ports of the provisioned Deployment and Service for the listener.
+func (l managedGatewayListener) portName() string {
+	return fmt.Sprintf("%s-%d", strings.ToLower(string(l.Protocol)), l.Port)
+}
+
+// serviceProtocol returns the protocol of ports of the provisioned Deployment and Service for the listener.
+func (l managedGatewayListener) serviceProtocol() corev1.Protocol {
+	if l.Protocol == gatewayapi.UDPProtocolType {
+		return corev1.ProtocolUDP
+	}
+	return corev1.ProtocolTCP
+}
+
+// managedGatewayListenersFromGateway returns listeners of the Gateway which data-planes provisioned for it serve,
+// along with names of the listeners which can't be served because they conflict with each other. Listeners of the
+// same port and protocol differ only by hostnames and are served by a single Kong listen, so only the first of them
+// is returned. UDP listeners are served by separate sockets, so they can share ports with listeners of other
+// protocols. Other listeners sharing a port Kong listens on (also after offsetting privileged ports) conflict,
+// as a single Kong listen can't serve them, so none of them is served.
+func managedGatewayListenersFromGateway(gateway *gatewayapi.Gateway) ([]managedGatewayListener, sets.Set[gatewayapi.SectionName], error) {
+	type socket struct {
+		containerPort int32
+		udp           bool
+	}
+	var (
+		sockets          []socket
+		socketsListeners = make(map[socket][]gatewayapi.Listener)
+		listeners        []managedGatewayListener
+		conflictedNames  = sets.New[gatewayapi.SectionName]()
+	)
+	for _, listener := range gateway.Spec.Listeners {
+		switch listener.Protocol {
+		case gatewayapi.HTTPProtocolType, gatewayapi.HTTPSProtocolType,
+			gatewayapi.TCPProtocolType, gatewayapi.TLSProtocolType, gatewayapi.UDPProtocolType:
+		default:
+			continue
+		}
+
+		containerPort := int32(listener.Port)
+		if containerPort < 1024 {
+			containerPort += managedGatewayPrivilegedPortOffset
+		}
+		udp := listener.Protocol == gatewayapi.UDPProtocolType
+		if !udp && (containerPort == managedGatewayAdminPort || containerPort == managedGatewayStatusPort) {
+			return nil, nil, fmt.Errorf("listener %s can't be served by Kong on port %d reserved for its Admin or status API", listener.Name, containerPort)
+		}
+
+		key := socket{containerPort: containerPort, udp: udp}
+		if _, ok := socketsListeners[key]; !ok {
+			sockets = append(sockets, key)
+		}
+		socketsListeners[key] = append(socketsListeners[key], listener)
+	}
+
+	for _, key := range sockets {
+		socketListeners := socketsListeners[key]
+		first := socketListeners[0]
+		if lo.EveryBy(socketListeners, func(l gatewayapi.Listener) bool {
+			return l.Port == first.Port && l.Protocol == first.Protocol
+		}) {
+			listeners = append(listeners, managedGatewayListener{
+				Listener:      first,
+				containerPort: key.containerPort,
+			})
+			continue
+		}
+		for _, l := range socketListeners {
+			conflictedNames.Insert(l.Name)
+		}
+	}
+	return listeners, conflictedNames, nil
+}
+
+// setManagedGatewayListenersConflicted marks statuses of the listeners conflicting with each other in a way that
+// prevents data-planes provisioned for managed Gateways from serving them as Conflicted and not Programmed.
+func setManagedGatewayListenersConflicted(
+	statuses []gatewayapi.ListenerStatus,
+	conflictedNames sets.Set[gatewayapi.SectionName],
+	generation int64,
+) {
+	for i, status := range statuses {
+		if !conflictedNames.Has(status.Name) {
+			continue
+		}
+		conditions := lo.Reject(status.Conditions, func(c metav1.Condition, _ int) bool {
+			return c.Type == string(gatewayapi.ListenerConditionConflicted) ||
+				c.Type == string(gatewayapi.ListenerConditionProgrammed)
+		})
+		conditions = append(conditions,
+			metav1.Condition{
+				Type:               string(gatewayapi.ListenerConditionConflicted),
+				Status:             metav1.ConditionTrue,
+				ObservedGeneration: generation,
+				LastTransitionTime: metav1.Now(),
+				Reason:             string(gatewayapi.ListenerReasonProtocolConflict),
+				Message:            "listeners sharing this port can't be served by a single Kong listen",
+			},
+			metav1.Condition{
+				Type:               string(gatewayapi.ListenerConditionProgrammed),
+				Status:             metav1.ConditionFalse,
+				ObservedGeneration: generation,
+				LastTransitionTime: metav1.Now(),
+				Reason:             string(gatewayapi.ListenerReasonInvalid),
+			},
+		)
+		// consistent sort statuses to allow equality comparisons, like getListenerStatus does.
+		sort.Slice(conditions, func(i, j int) bool {
+			a, b := conditions[i], conditions[j]
+			return fmt.Sprintf("%s%s%s%s", a.Type, a.Status, a.Reason, a.Message) <
+				fmt.Sprintf("%s%s%s%s", b.Type, b.Status, b.Reason, b.Message)
+		})
+		statuses[i].Conditions = conditions
+	}
+}
+
+// kongListensFromManagedGatewayListeners returns values of Kong's proxy_listen, stream_listen and port_maps
+// configuration properties that make Kong serve the listeners.
+func kongListensFromManagedGatewayListeners(listeners []managedGatewayListener) (proxyListen, streamListen, portMaps string) {
+	var proxyListens, streamListens, ports []string
+	for _, l := range listeners {
+		listen := fmt.Sprintf("0.0.0.0:%d", l.containerPort)
+		switch l.Protocol {
+		case gatewayapi.HTTPProtocolType:
+			proxyListens = append(proxyListens, listen)
+		case gatewayapi.HTTPSProtocolType:
+			proxyListens = append(proxyListens, listen+" http2 ssl")
+		case gatewayapi.TCPProtocolType:
+			streamListens = append(streamListens, listen)
+		case gatewayapi.TLSProtocolType:
+			streamListens = append(streamListens, listen+" ssl")
+		case gatewayapi.UDPProtocolType:
+			streamListens = append(streamListens, listen+" udp")
+		default:
+			// other protocols are dropped by managedGatewayListenersFromGateway.
+			continue
+		}
+		// UDP listeners can share ports with listeners of other protocols, which are mapped only once.
+		if portMap := fmt.Sprintf("%d:%d", l.Port, l.containerPort); !lo.Contains(ports, portMap) {
+			ports = append(ports, portMap)
+		}
+	}
+
+	proxyListen, streamListen = "off", "off"
+	if len(proxyListens) > 0 {
+		proxyListen = strings.Join(proxyListens, ", ")
+	}
+	if len(streamListens) > 0 {
+		streamListen = strings.Join(streamListens, ", ")
+	}
+	return proxyListen, streamListen, strings.Join(ports, ", ")
+}
+
+// -----------------------------------------------------------------------------
+// Managed Gateways - Provisioned Objects
+// -----------------------------------------------------------------------------
+
+// managedGatewayDeploymentName returns the name of the Deployment provisioned for a managed Gateway.
+func managedGatewayDeploymentName(gateway *gatewayapi.Gateway) string {
+	return gateway.Name + "-kong"
+}
+
+// managedGatewayProxyServiceName returns the name of the Service exposing listeners of a managed Gateway.
+func managedGatewayProxyServiceName(gateway *gatewayapi.Gateway) string {
+	return gateway.Name + "-kong-proxy"
+}
+
+// managedGatewayAdminServiceName returns the name of the headless Service used to discover
+// Admin API endpoints of data-planes provisioned for a managed Gateway.
+func managedGatewayAdminServiceName(gateway *gatewayapi.Gateway) string {
+	return gateway.Name + "-kong-admin"
+}
+
+// managedGatewayAdminClientCAConfigMapName returns the name of the ConfigMap holding the CA certificate
+// the Admin API of the data-plane provisioned for a managed Gateway verifies client certificates with.
+func managedGatewayAdminClientCAConfigMapName(gateway *gatewayapi.Gateway) string {
+	return gateway.Name + "-kong-admin-client-ca"
+}
+
+// managedGatewaySelector returns labels selecting Pods of the data-plane provisioned for a managed Gateway.
+func managedGatewaySelector(gateway *gatewayapi.Gateway) map[string]string {
+	return map[string]string{ManagedGatewayLabel: string(gateway.UID)}
+}
+
+// setManagedGatewayDeploymentSpec sets the spec of the Deployment running the Kong data-plane of a managed Gateway.
+// Only the fields managed by the controller are set, so defaults filled in by the API server are left intact.
+func setManagedGatewayDeploymentSpec(
+	deployment *appsv1.Deployment,
+	gateway *gatewayapi.Gateway,
+	listeners []managedGatewayListener,
+	config ManagedGatewaysConfig,
+) {
+	selector := managedGatewaySelector(gateway)
+	proxyListen, streamListen, portMaps := kongListensFromManagedGatewayListeners(listeners)
+
+	env := []corev1.EnvVar{
+		// managed Gateways are only supported when the controller configures db-less Kong Gateways,
+		// which is verified on start.
+		{Name: "KONG_DATABASE", Value: "off"},
+		{Name: "KONG_PROXY_LISTEN", Value: proxyListen},
+		{Name: "KONG_STREAM_LISTEN", Value: streamListen},
+		{Name: "KONG_PORT_MAPS", Value: portMaps},
+		{Name: "KONG_ADMIN_LISTEN", Value: fmt.Sprintf("0.0.0.0:%d http2 ssl", managedGatewayAdminPort)},
+		// the Admin API is reachable from the network, so clients have to present a certificate signed by
+		// the CA the controller's client certificate is verified with.
+		{Name: "KONG_NGINX_ADMIN_SSL_VERIFY_CLIENT", Value: "on"},
+		{Name: "KONG_NGINX_ADMIN_SSL_CLIENT_CERTIFICATE", Value: managedGatewayAdminClientCADir + "/" + managedGatewayAdminClientCAKey},
+		{Name: "KONG_STATUS_LISTEN", Value: fmt.Sprintf("0.0.0.0:%d", managedGatewayStatusPort)},
+		{Name: "KONG_KIC", Value: "on"},
+		{Name: "KONG_ADMIN_ACCESS_LOG", Value: "/dev/stdout"},
+		{Name: "KONG_ADMIN_ERROR_LOG", Value: "/dev/stderr"},
+		{Name: "KONG_PROXY_ERROR_LOG", Value: "/dev/stderr"},
+	}
+	if config.RouterFlavor != "" {
+		env = append(env, corev1.EnvVar{Name: "KONG_ROUTER_FLAVOR", Value: config.RouterFlavor})
+	}
+
+	ports := make([]corev1.ContainerPort, 0, len(listeners)+1)
+	for _, l := range listeners {
+		ports = append(ports, corev1.ContainerPort{
+			Name:          l.portName(),
+			ContainerPort: l.containerPort,
+			Protocol:      l.serviceProtocol(),
+		})
+	}
+	ports = append(ports, corev1.ContainerPort{
+		Name:          managedGatewayAdminPortName,
+		ContainerPort: managedGatewayAdminPort,
+		Protocol:      corev1.ProtocolTCP,
+	})
+
+	statusProbe := func(path string) *corev1.Probe {
+		return &corev1.Probe{
+			ProbeHandler: corev1.ProbeHandler{
+				HTTPGet: &corev1.HTTPGetAction{
+					Path:   path,
+					Port:   intstr.FromInt(managedGatewayStatusPort),
+					Scheme: corev1.URISchemeHTTP,
+				},
+			},
+			InitialDelaySeconds: 5,
+			TimeoutSeconds:      1,
+			PeriodSeconds:       10,
+			SuccessThreshold:    1,
+			FailureThreshold:    3,
+		}
+	}
+
+	deployment.Labels = selector
+	deployment.Spec.Selector = &metav1.LabelSelector{MatchLabels: selector}
+	deployment.Spec.Template.Labels = selector
+	deployment.Spec.Template.Annotations = map[string]string{
+		managedGatewayAdminClientCAHashAnnotation: fmt.Sprintf("%x", sha256.Sum256([]byte(config.AdminClientCACert))),
+	}
+	deployment.Spec.Template.Spec.Volumes = []corev1.Volume{{
+		Name: "admin-client-ca",
+		VolumeSource: corev1.VolumeSource{
+			ConfigMap: &corev1.ConfigMapVolumeSource{
+				LocalObjectReference: corev1.LocalObjectReference{Name: managedGatewayAdminClientCAConfigMapName(gateway)},
+			},
+		},
+	}}
+	deployment.Spec.Template.Spec.Containers = []corev1.Container{{
+		Name:           "proxy",
+		Image:          config.Image,
+		Env:            env,
+		Ports:          ports,
+		LivenessProbe:  statusProbe("/status"),
+		ReadinessProbe: statusProbe("/status/ready"),
+		VolumeMounts: []corev1.VolumeMount{{
+			Name:      "admin-client-ca",
+			MountPath: managedGatewayAdminClientCADir,
+			ReadOnly:  true,
+		}},
+		Lifecycle: &corev1.Lifecycle{
+			PreStop: &corev1.LifecycleHandler{
+				Exec: &corev1.ExecAction{Command: []string{"/bin/bash", "-c", "kong quit"}},
+			},
+		},
+	}}
+}
+
+// setManagedGatewayAdminClientCAConfigMapData sets the data of the ConfigMap holding the CA certificate the Admin API
+// of a managed Gateway's data-plane verifies client certificates with.
+func setManagedGatewayAdminClientCAConfigMapData(configMap *corev1.ConfigMap, gateway *gatewayapi.Gateway, config ManagedGatewaysConfig) {
+	configMap.Labels = managedGatewaySelector(gateway)
+	configMap.Data = map[string]string{managedGatewayAdminClientCAKey: config.AdminClientCACert}
+}
+
+// setManagedGatewayProxyServiceSpec sets the spec of the Service exposing listeners of a managed Gateway.
+func setManagedGatewayProxyServiceSpec(service *corev1.Service, gateway *gatewayapi.Gateway, listeners []managedGatewayListener) {
+	ports := make([]corev1.ServicePort, 0, len(listeners))
+	for _, l := range listeners {
+		ports = append(ports, corev1.ServicePort{
+			Name:       l.portName(),
+			Port:       int32(l.Port),
+			TargetPort: intstr.FromInt(int(l.containerPort)),
+			Protocol:   l.serviceProtocol(),
+		})
+	}
+	// keep the order stable to not update the Service when the order of listeners changes.
+	sort.Slice(ports, func(i, j int) bool {
+		if ports[i].Port != ports[j].Port {
+			return ports[i].Port < ports[j].Port
+		}
+		return ports[i].Protocol < ports[j].Protocol
+	})
+
+	service.Labels = managedGatewaySelector(gateway)
+	service.Spec.Type = corev1.ServiceTypeLoadBalancer
+	service.Spec.Selector = managedGatewaySelector(gateway)
+	service.Spec.Ports = mergeServicePorts(service.Spec.Ports, ports)
+}
+
+// setManagedGatewayAdminServiceSpec sets the spec of the headless Service used to discover Admin API
+// endpoints of data-planes provisioned for a managed Gateway.
+func setManagedGatewayAdminServiceSpec(service *corev1.Service, gateway *gatewayapi.Gateway) {
+	service.Labels = managedGatewaySelector(gateway)
+	service.Spec.ClusterIP = corev1.ClusterIPNone
+	service.Spec.Selector = managedGatewaySelector(gateway)
+	service.Spec.Ports = mergeServicePorts(service.Spec.Ports, []corev1.ServicePort{{
+		Name:       managedGatewayAdminPortName,
+		Port:       managedGatewayAdminPort,
+		TargetPort: intstr.FromInt(managedGatewayAdminPort),
+		Protocol:   corev1.ProtocolTCP,
+	}})
+}
+
+// mergeServicePorts returns the desired ports of a Service preserving node ports allocated
+// for the existing ones, so they're not reallocated on every update.
+func mergeServicePorts(existing, desired []corev1.ServicePort) []corev1.ServicePort {
+	for i := range desired {
+		for _, e := range existing {
+			if e.Port == desired[i].Port && e.Protocol == desired[i].Protocol {
+				desired[i].NodePort = e.NodePort
+			}
+		}
+	}
+	return desired
+}
+
+// -----------------------------------------------------------------------------
+// Managed Gateways - Watches
+// -----------------------------------------------------------------------------
+
+// watchManagedGatewaysObjects sets up watches of objects provisioned for managed Gateways, so the Gateways
+// get reconciled when their data-planes become ready or their addresses change.
+func (r *GatewayReconciler) watchManagedGatewaysObjects(mgr ctrl.Manager, c controller.Controller) error {
+	if err := c.Watch(
+		source.Kind(mgr.GetCache(), &appsv1.Deployment{}),
+		handler.EnqueueRequestForOwner(mgr.GetScheme(), mgr.GetRESTMapper(), &gatewayapi.Gateway{}, handler.OnlyControllerOwner()),
+	); err != nil {
+		return err
+	}
+	if err := c.Watch(
+		source.Kind(mgr.GetCache(), &corev1.Service{}),
+		handler.EnqueueRequestForOwner(mgr.GetScheme(), mgr.GetRESTMapper(), &gatewayapi.Gateway{}, handler.OnlyControllerOwner()),
+	); err != nil {
+		return err
+	}
+	if err := c.Watch(
+		source.Kind(mgr.GetCache(), &corev1.ConfigMap{}),
+		handler.EnqueueRequestForOwner(mgr.GetScheme(), mgr.GetRESTMapper(), &gatewayapi.Gateway{}, handler.OnlyControllerOwner()),
+	); err != nil {
+		return err
+	}
+	// Admin API endpoints are discovered from EndpointSlices of the admin Service, which don't
+	// reference the Gateway directly.
+	return c.Watch(
+		source.Kind(mgr.GetCache(), &discoveryv1.EndpointSlice{}),
+		handler.EnqueueRequestsFromMapFunc(r.listManagedGatewaysForEndpointSlice),
+	)
+}
+
+// listManagedGatewaysForEndpointSlice finds the managed Gateway owning the Service of the EndpointSlice.
+func (r *GatewayReconciler) listManagedGatewaysForEndpointSlice(ctx context.Context, obj client.Object) []reconcile.Request {
+	serviceName, ok := obj.GetLabels()[discoveryv1.LabelServiceName]
+	if !ok {
+		return nil
+	}
+	svc := &corev1.Service{}
+	if err := r.Client.Get(ctx, k8stypes.NamespacedName{Namespace: obj.GetNamespace(), Name: serviceName}, svc); err != nil {
+		if !apierrors.IsNotFound(err) {
+			r.Log.Error(err, "failed to retrieve service in watch predicates", "service", serviceName)
+		}
+		return nil
+	}
+	owner := metav1.GetControllerOf(svc)
+	if owner == nil || owner.Kind != "Gateway" || !strings.HasPrefix(owner.APIVersion, gatewayv1.GroupName+"/") {
+		return nil
+	}
+	return []reconcile.Request{{
+		NamespacedName: k8stypes.NamespacedName{Namespace: svc.Namespace, Name: owner.Name},
+	}}
+}
+
+// -----------------------------------------------------------------------------
+// Managed Gateways - Reconciliation
+// -----------------------------------------------------------------------------
+
+// forgetManagedGateway makes the Admin API endpoints of the data-plane provisioned for the Gateway
+// (if it was ever managed) not configured anymore.
+func (r *GatewayReconciler) forgetManagedGateway(gateway k8stypes.NamespacedName) {
+	if r.ManagedGateways.Enabled {
+		r.ManagedGateways.AdminAPIsNotifier.NotifyManagedGateway(gateway, nil)
+	}
+}
+
+// managedGatewaysConfigForGatewayClass returns the configuration of data-planes provisioned for Gateways
+// of the GatewayClass, i.e. the controller's configuration with overrides set in the GatewayClass' annotations.
+func managedGatewaysConfigForGatewayClass(config ManagedGatewaysConfig, gatewayClass *gatewayapi.GatewayClass) ManagedGatewaysConfig {
+	if image, ok := annotations.ExtractManagedGatewayImage(gatewayClass.GetAnnotations()); ok {
+		config.Image = image
+	}
+	return config
+}
+
+// reconcileManagedGateway reconciles a Gateway of a managed GatewayClass. This mode provisions a Deployment
+// running Kong Gateway along with Services exposing its listeners and its Admin API for the Gateway. The Admin API
+// endpoints are passed to the clients manager so the data-plane gets configured with routes attached to the Gateway.
+func (r *GatewayReconciler) reconcileManagedGateway(
+	ctx context.Context,
+	log logr.Logger,
+	gateway *gatewayapi.Gateway,
+	gatewayClass *gatewayapi.GatewayClass,
+) (ctrl.Result, error) {
+	gatewayNN := k8stypes.NamespacedName{Namespace: gateway.Namespace, Name: gateway.Name}
+
+	if !isGatewayScheduled(gateway) {
+		// the Gateway is known as managed before its data-plane is provisioned, so routes attached to it
+		// are kept out of the configuration of the other Kong Gateways from the start.
+		r.ManagedGateways.AdminAPIsNotifier.NotifyManagedGateway(gatewayNN, []adminapi.DiscoveredAdminAPI{})
+		info(log, gateway, "marking gateway as accepted")
+		setGatewayCondition(gateway, metav1.Condition{
+			Type:               string(gatewayapi.GatewayConditionAccepted),
+			Status:             metav1.ConditionTrue,
+			ObservedGeneration: gateway.Generation,
+			LastTransitionTime: metav1.Now(),
+			Reason:             string(gatewayapi.GatewayReasonAccepted),
+			Message:            "this managed gateway has been picked up by the controller and will be processed",
+		})
+		setGatewayCondition(gateway, metav1.Condition{
+			Type:               string(gatewayapi.GatewayConditionProgrammed),
+			Status:             metav1.ConditionFalse,
+			ObservedGeneration: gateway.Generation,
+			LastTransitionTime: metav1.Now(),
+			Reason:             string(gatewayapi.GatewayReasonPending),
+		})
+		return ctrl.Result{}, r.Status().Update(ctx, pruneGatewayStatusConds(gateway))
+	}
+
+	listeners, conflictedListeners, err := managedGatewayListenersFromGateway(gateway)
+	if err != nil {
+		info(log, gateway, "gateway listeners can't be served by a managed data-plane", "reason", err.Error())
+		r.ManagedGateways.AdminAPIsNotifier.NotifyManagedGateway(gatewayNN, []adminapi.DiscoveredAdminAPI{})
+		return ctrl.Result{}, r.setManagedGatewayNotProgrammed(ctx, gateway, gatewayapi.GatewayReasonInvalid, err.Error())
+	}
+
+	debug(log, gateway, "ensuring data-plane objects for managed gateway")
+	adminClientCAConfigMap := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Namespace: gateway.Namespace, Name: managedGatewayAdminClientCAConfigMapName(gateway)}}
+	if err := r.ensureManagedGatewayObject(ctx, gateway, adminClientCAConfigMap, func() {
+		setManagedGatewayAdminClientCAConfigMapData(adminClientCAConfigMap, gateway, r.ManagedGateways)
+	}); err != nil {
+		return ctrl.Result{}, err
+	}
+	deployment := &appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Namespace: gateway.Namespace, Name: managedGatewayDeploymentName(gateway)}}
+	if err := r.ensureManagedGatewayObject(ctx, gateway, deployment, func() {
+		setManagedGatewayDeploymentSpec(deployment, gateway, listeners, managedGatewaysConfigForGatewayClass(r.ManagedGateways, gatewayClass))
+	}); err != nil {
+		return ctrl.Result{}, err
+	}
+	proxyService := &corev1.Service{ObjectMeta: metav1.ObjectMeta{Namespace: gateway.Namespace, Name: managedGatewayProxyServiceName(gateway)}}
+	if err := r.ensureManagedGatewayObject(ctx, gateway, proxyService, func() {
+		setManagedGatewayProxyServiceSpec(proxyService, gateway, listeners)
+	}); err != nil {
+		return ctrl.Result{}, err
+	}
+	adminService := &corev1.Service{ObjectMeta: metav1.ObjectMeta{Namespace: gateway.Namespace, Name: managedGatewayAdminServiceName(gateway)}}
+	if err := r.ensureManagedGatewayObject(ctx, gateway, adminService, func() {
+		setManagedGatewayAdminServiceSpec(adminService, gateway)
+	}); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	debug(log, gateway, "discovering admin APIs of managed gateway data-plane")
+	adminAPIs, err := r.ManagedGateways.AdminAPIsDiscoverer.GetAdminAPIsForService(ctx, r.Client, client.ObjectKeyFromObject(adminService))
+	if err != nil {
+		return ctrl.Result{}, fmt.Errorf("failed to discover admin APIs of managed gateway data-plane: %w", err)
+	}
+	r.ManagedGateways.AdminAPIsNotifier.NotifyManagedGateway(gatewayNN, adminAPIs.UnsortedList())
+
+	// the Deployment, Services and their EndpointSlices are watched, so the Gateway gets reconciled
+	// again once its data-plane becomes ready.
+	if deployment.Status.AvailableReplicas == 0 || adminAPIs.Len() == 0 {
+		debug(log, gateway, "waiting for managed gateway data-plane to become ready")
+		return ctrl.Result{}, r.setManagedGatewayNotProgrammed(ctx, gateway, gatewayapi.GatewayReasonPending,
+			"waiting for the Kong data-plane to become ready")
+	}
+
+	addresses, _, err := r.determineL4ListenersFromService(log, proxyService)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	referenceGrantList := &gatewayapi.ReferenceGrantList{}
+	if r.enableReferenceGrant {
+		if err := r.Client.List(ctx, referenceGrantList); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+	servedListeners := make([]gatewayapi.Listener, 0, len(listeners))
+	for _, l := range listeners {
+		servedListeners = append(servedListeners, l.Listener)
+	}
+	listenerStatuses, err := getListenerStatus(ctx, gateway, servedListeners, referenceGrantList.Items, r.Client)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	setManagedGatewayListenersConflicted(listenerStatuses, conflictedListeners, gateway.Generation)
+
+	debug(log, gateway, "updating the gateway status if necessary")
+	statusAddresses := make([]gatewayapi.GatewayStatusAddress, 0, len(addresses))
+	for _, addr := range addresses {
+		statusAddresses = append(statusAddresses, gatewayapi.GatewayStatusAddress(addr))
+	}
+	// the API server transforms a zero-length address slice into a nil one.
+	if len(statusAddresses) == 0 {
+		statusAddresses = nil
+	}
+	if isGatewayProgrammed(gateway) &&
+		reflect.DeepEqual(gateway.Status.Addresses, statusAddresses) &&
+		reflect.DeepEqual(gateway.Status.Listeners, listenerStatuses) {
+		info(log, gateway, "gateway provisioning complete")
+		return ctrl.Result{}, nil
+	}
+	gateway.Status.Addresses = statusAddresses
+	gateway.Status.Listeners = listenerStatuses
+	setGatewayCondition(gateway, metav1.Condition{
+		Type:               string(gatewayapi.GatewayConditionProgrammed),
+		Status:             metav1.ConditionTrue,
+		ObservedGeneration: gateway.Generation,
+		LastTransitionTime: metav1.Now(),
+		Reason:             string(gatewayapi.GatewayReasonProgrammed),
+	})
+	if err := r.Status().Update(ctx, pruneGatewayStatusConds(gateway)); err != nil {
+		if apierrors.IsConflict(err) {
+			// if there's a conflict that's normal just requeue to retry, no need to make noise.
+			return ctrl.Result{Requeue: true}, nil
+		}
+		return ctrl.Result{}, err
+	}
+	return ctrl.Result{}, nil
+}
+
+// ensureManagedGatewayObject creates or updates an object provisioned for a managed Gateway,
+// making the Gateway its controller so it's garbage collected along with the Gateway.
+func (r *GatewayReconciler) ensureManagedGatewayObject(
+	ctx context.Context,
+	gateway *gatewayapi.Gateway,
+	obj client.Object,
+	setSpec func(),
+) error {
+	_, err := controllerutil.CreateOrUpdate(ctx, r.Client, obj, func() error {
+		setSpec()
+		return controllerutil.SetControllerReference(gateway, obj, r.Scheme)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to ensure %T %s/%s for managed gateway: %w", obj, obj.GetNamespace(), obj.GetName(), err)
+	}
+	return nil
+}
+
+// setManagedGatewayNotProgrammed sets the Programmed condition of a managed Gateway to false
+// with the given reason, unless it's already set.
+func (r *GatewayReconciler) setManagedGatewayNotProgrammed(
+	ctx context.Context,
+	gateway *gatewayapi.Gateway,
+	reason gatewayapi.GatewayConditionReason,
+	message string,
+) error {
+	for _, cond := range gateway.Status.Conditions {
+		if cond.Type == string(gatewayapi.GatewayConditionProgrammed) &&
+			cond.Status == metav1.ConditionFalse &&
+			cond.Reason == string(reason) &&
+			cond.Message == message &&
+			cond.ObservedGeneration == gateway.Generation {
+			return nil
+		}
+	}
+	setGatewayCondition(gateway, metav1.Condition{
+		Type:               string(gatewayapi.GatewayConditionProgrammed),
+		Status:             metav1.ConditionFalse,
+		ObservedGeneration: gateway.Generation,
+		LastTransitionTime: metav1.Now(),
+		Reason:             string(reason),
+		Message:            message,
+	})
+	return r.Status().Update(ctx, pruneGatewayStatusConds(gateway))
+}
@@ -0,0 +1,351 @@
+package gateway
+
+import (
+	"context"
+	"testing"
+
+	"github.com/go-logr/logr"
+	"github.com/samber/lo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	k8stypes "k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/apimachinery/pkg/util/sets"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	fakeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+
+	"github.com/kong/kubernetes-ingress-controller/v2/internal/gatewayapi"
+)
+
+func TestManagedGatewayListenersFromGateway(t *testing.T) {
+	testCases := []struct {
+		name               string
+		listeners          []gatewayapi.Listener
+		expectedPorts      map[gatewayapi.SectionName]int32
+		expectedConflicted []gatewayapi.SectionName
+		expectedErrorPart  string
+	}{
+		{
+			name: "privileged ports are offset",
+			listeners: []gatewayapi.Listener{
+				{Name: "http", Port: 80, Protocol: gatewayapi.HTTPProtocolType},
+				{Name: "https", Port: 443, Protocol: gatewayapi.HTTPSProtocolType},
+				{Name: "tcp", Port: 9000, Protocol: gatewayapi.TCPProtocolType},
+			},
+			expectedPorts: map[gatewayapi.SectionName]int32{
+				"http":  8080,
+				"https": 8443,
+				"tcp":   9000,
+			},
+		},
+		{
+			name: "only the first listener of a port and protocol is served",
+			listeners: []gatewayapi.Listener{
+				{Name: "http", Port: 80, Protocol: gatewayapi.HTTPProtocolType},
+				{Name: "http-other-hostname", Port: 80, Protocol: gatewayapi.HTTPProtocolType},
+			},
+			expectedPorts: map[gatewayapi.SectionName]int32{
+				"http": 8080,
+			},
+		},
+		{
+			name: "UDP listeners share ports with listeners of other protocols",
+			listeners: []gatewayapi.Listener{
+				{Name: "tcp", Port: 53, Protocol: gatewayapi.TCPProtocolType},
+				{Name: "udp", Port: 53, Protocol: gatewayapi.UDPProtocolType},
+				{Name: "http", Port: 9000, Protocol: gatewayapi.HTTPProtocolType},
+				{Name: "udp-9000", Port: 9000, Protocol: gatewayapi.UDPProtocolType},
+			},
+			expectedPorts: map[gatewayapi.SectionName]int32{
+				"tcp":      8053,
+				"udp":      8053,
+				"http":     9000,
+				"udp-9000": 9000,
+			},
+		},
+		{
+			name: "listeners of unsupported protocols are dropped",
+			listeners: []gatewayapi.Listener{
+				{Name: "unsupported", Port: 80, Protocol: "SCTP"},
+				{Name: "udp", Port: 53, Protocol: gatewayapi.UDPProtocolType},
+			},
+			expectedPorts: map[gatewayapi.SectionName]int32{
+				"udp": 8053,
+			},
+		},
+		{
+			name: "listeners of different protocols sharing a port conflict",
+			listeners: []gatewayapi.Listener{
+				{Name: "http", Port: 80, Protocol: gatewayapi.HTTPProtocolType},
+				{Name: "tcp", Port: 80, Protocol: gatewayapi.TCPProtocolType},
+				{Name: "https", Port: 443, Protocol: gatewayapi.HTTPSProtocolType},
+				{Name: "tls", Port: 443, Protocol: gatewayapi.TLSProtocolType},
+				{Name: "udp", Port: 80, Protocol: gatewayapi.UDPProtocolType},
+			},
+			expectedPorts: map[gatewayapi.SectionName]int32{
+				"udp": 8080,
+			},
+			expectedConflicted: []gatewayapi.SectionName{"http", "tcp", "https", "tls"},
+		},
+		{
+			name: "listeners colliding after offsetting privileged ports conflict",
+			listeners: []gatewayapi.Listener{
+				{Name: "http", Port: 80, Protocol: gatewayapi.HTTPProtocolType},
+				{Name: "http-alt", Port: 8080, Protocol: gatewayapi.HTTPProtocolType},
+				{Name: "https", Port: 443, Protocol: gatewayapi.HTTPSProtocolType},
+			},
+			expectedPorts: map[gatewayapi.SectionName]int32{
+				"https": 8443,
+			},
+			expectedConflicted: []gatewayapi.SectionName{"http", "http-alt"},
+		},
+		{
+			name: "listener colliding with the admin API",
+			listeners: []gatewayapi.Listener{
+				{Name: "https", Port: 444, Protocol: gatewayapi.HTTPSProtocolType},
+			},
+			expectedErrorPart: "listener https can't be served by Kong on port 8444",
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			gateway := &gatewayapi.Gateway{
+				Spec: gatewayapi.GatewaySpec{Listeners: tc.listeners},
+			}
+			listeners, conflicted, err := managedGatewayListenersFromGateway(gateway)
+			if tc.expectedErrorPart != "" {
+				require.ErrorContains(t, err, tc.expectedErrorPart)
+				return
+			}
+			require.NoError(t, err)
+			ports := make(map[gatewayapi.SectionName]int32, len(listeners))
+			for _, l := range listeners {
+				ports[l.Name] = l.containerPort
+			}
+			assert.Equal(t, tc.expectedPorts, ports)
+			assert.ElementsMatch(t, tc.expectedConflicted, conflicted.UnsortedList())
+		})
+	}
+}
+
+func TestSetManagedGatewayListenersConflicted(t *testing.T) {
+	statuses := []gatewayapi.ListenerStatus{
+		{
+			Name: "http",
+			Conditions: []metav1.Condition{
+				{Type: string(gatewayapi.ListenerConditionAccepted), Status: metav1.ConditionTrue},
+				{Type: string(gatewayapi.ListenerConditionConflicted), Status: metav1.ConditionFalse},
+				{Type: string(gatewayapi.ListenerConditionProgrammed), Status: metav1.ConditionTrue},
+			},
+		},
+		{
+			Name: "udp",
+			Conditions: []metav1.Condition{
+				{Type: string(gatewayapi.ListenerConditionConflicted), Status: metav1.ConditionFalse},
+				{Type: string(gatewayapi.ListenerConditionProgrammed), Status: metav1.ConditionTrue},
+			},
+		},
+	}
+	setManagedGatewayListenersConflicted(statuses, sets.New[gatewayapi.SectionName]("http"), 2)
+
+	conflicted, ok := lo.Find(statuses[0].Conditions, func(c metav1.Condition) bool {
+		return c.Type == string(gatewayapi.ListenerConditionConflicted)
+	})
+	require.True(t, ok)
+	assert.Equal(t, metav1.ConditionTrue, conflicted.Status)
+	assert.Equal(t, string(gatewayapi.ListenerReasonProtocolConflict), conflicted.Reason)
+	assert.Equal(t, int64(2), conflicted.ObservedGeneration)
+	programmed, ok := lo.Find(statuses[0].Conditions, func(c metav1.Condition) bool {
+		return c.Type == string(gatewayapi.ListenerConditionProgrammed)
+	})
+	require.True(t, ok)
+	assert.Equal(t, metav1.ConditionFalse, programmed.Status)
+	assert.Len(t, statuses[0].Conditions, 3)
+
+	assert.Len(t, statuses[1].Conditions, 2, "statuses of listeners which don't conflict are kept")
+	assert.Equal(t, metav1.ConditionFalse, statuses[1].Conditions[0].Status)
+}
+
+func TestKongListensFromManagedGatewayListeners(t *testing.T) {
+	t.Run("all protocols", func(t *testing.T) {
+		proxyListen, streamListen, portMaps := kongListensFromManagedGatewayListeners([]managedGatewayListener{
+			{Listener: gatewayapi.Listener{Port: 80, Protocol: gatewayapi.HTTPProtocolType}, containerPort: 8080},
+			{Listener: gatewayapi.Listener{Port: 443, Protocol: gatewayapi.HTTPSProtocolType}, containerPort: 8443},
+			{Listener: gatewayapi.Listener{Port: 9000, Protocol: gatewayapi.TCPProtocolType}, containerPort: 9000},
+			{Listener: gatewayapi.Listener{Port: 9443, Protocol: gatewayapi.TLSProtocolType}, containerPort: 9443},
+			{Listener: gatewayapi.Listener{Port: 53, Protocol: gatewayapi.UDPProtocolType}, containerPort: 8053},
+		})
+		assert.Equal(t, "0.0.0.0:8080, 0.0.0.0:8443 http2 ssl", proxyListen)
+		assert.Equal(t, "0.0.0.0:9000, 0.0.0.0:9443 ssl, 0.0.0.0:8053 udp", streamListen)
+		assert.Equal(t, "80:8080, 443:8443, 9000:9000, 9443:9443, 53:8053", portMaps)
+	})
+
+	t.Run("UDP and TCP listeners sharing a port are mapped once", func(t *testing.T) {
+		proxyListen, streamListen, portMaps := kongListensFromManagedGatewayListeners([]managedGatewayListener{
+			{Listener: gatewayapi.Listener{Port: 53, Protocol: gatewayapi.TCPProtocolType}, containerPort: 8053},
+			{Listener: gatewayapi.Listener{Port: 53, Protocol: gatewayapi.UDPProtocolType}, containerPort: 8053},
+		})
+		assert.Equal(t, "off", proxyListen)
+		assert.Equal(t, "0.0.0.0:8053, 0.0.0.0:8053 udp", streamListen)
+		assert.Equal(t, "53:8053", portMaps)
+	})
+
+	t.Run("no stream listeners", func(t *testing.T) {
+		proxyListen, streamListen, portMaps := kongListensFromManagedGatewayListeners([]managedGatewayListener{
+			{Listener: gatewayapi.Listener{Port: 80, Protocol: gatewayapi.HTTPProtocolType}, containerPort: 8080},
+		})
+		assert.Equal(t, "0.0.0.0:8080", proxyListen)
+		assert.Equal(t, "off", streamListen)
+		assert.Equal(t, "80:8080", portMaps)
+	})
+}
+
+func TestManagedGatewaysConfigForGatewayClass(t *testing.T) {
+	config := ManagedGatewaysConfig{Image: "kong:3.4", RouterFlavor: "traditional"}
+
+	got := managedGatewaysConfigForGatewayClass(config, &gatewayapi.GatewayClass{})
+	assert.Equal(t, config, got)
+
+	got = managedGatewaysConfigForGatewayClass(config, &gatewayapi.GatewayClass{
+		ObjectMeta: metav1.ObjectMeta{
+			Annotations: map[string]string{"konghq.com/managed-gateway-image": "kong:3.5"},
+		},
+	})
+	assert.Equal(t, "kong:3.5", got.Image)
+	assert.Equal(t, "traditional", got.RouterFlavor)
+	assert.Equal(t, "kong:3.4", config.Image, "the controller's configuration is kept intact")
+}
+
+func TestSetManagedGatewayObjectsSpecs(t *testing.T) {
+	gateway := &gatewayapi.Gateway{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "gateway", UID: "gateway-uid"},
+	}
+	listeners := []managedGatewayListener{
+		{Listener: gatewayapi.Listener{Port: 443, Protocol: gatewayapi.HTTPSProtocolType}, containerPort: 8443},
+		{Listener: gatewayapi.Listener{Port: 53, Protocol: gatewayapi.UDPProtocolType}, containerPort: 8053},
+	}
+	expectedSelector := map[string]string{ManagedGatewayLabel: "gateway-uid"}
+
+	t.Run("deployment", func(t *testing.T) {
+		deployment := &appsv1.Deployment{}
+		setManagedGatewayDeploymentSpec(deployment, gateway, listeners, ManagedGatewaysConfig{
+			Image:        "kong:test",
+			RouterFlavor: "expressions",
+		})
+
+		assert.Equal(t, expectedSelector, deployment.Spec.Selector.MatchLabels)
+		assert.Equal(t, expectedSelector, deployment.Spec.Template.Labels)
+		require.Len(t, deployment.Spec.Template.Spec.Containers, 1)
+		container := deployment.Spec.Template.Spec.Containers[0]
+		assert.Equal(t, "kong:test", container.Image)
+		assert.Contains(t, container.Env, corev1.EnvVar{Name: "KONG_PROXY_LISTEN", Value: "0.0.0.0:8443 http2 ssl"})
+		assert.Contains(t, container.Env, corev1.EnvVar{Name: "KONG_STREAM_LISTEN", Value: "0.0.0.0:8053 udp"})
+		assert.Contains(t, container.Env, corev1.EnvVar{Name: "KONG_PORT_MAPS", Value: "443:8443, 53:8053"})
+		assert.Contains(t, container.Env, corev1.EnvVar{Name: "KONG_ROUTER_FLAVOR", Value: "expressions"})
+		assert.Contains(t, container.Env, corev1.EnvVar{Name: "KONG_NGINX_ADMIN_SSL_VERIFY_CLIENT", Value: "on"})
+		assert.Contains(t, container.Env, corev1.EnvVar{Name: "KONG_NGINX_ADMIN_SSL_CLIENT_CERTIFICATE", Value: "/etc/kong/admin-client-ca/ca.crt"})
+		assert.Equal(t, []corev1.VolumeMount{
+			{Name: "admin-client-ca", MountPath: "/etc/kong/admin-client-ca", ReadOnly: true},
+		}, container.VolumeMounts)
+		require.Len(t, deployment.Spec.Template.Spec.Volumes, 1)
+		assert.Equal(t, "gateway-kong-admin-client-ca", deployment.Spec.Template.Spec.Volumes[0].ConfigMap.Name)
+		assert.Equal(t, []corev1.ContainerPort{
+			{Name: "https-443", ContainerPort: 8443, Protocol: corev1.ProtocolTCP},
+			{Name: "udp-53", ContainerPort: 8053, Protocol: corev1.ProtocolUDP},
+			{Name: "admin", ContainerPort: 8444, Protocol: corev1.ProtocolTCP},
+		}, container.Ports)
+	})
+
+	t.Run("deployment is rolled out when admin client CA changes", func(t *testing.T) {
+		deployment := &appsv1.Deployment{}
+		setManagedGatewayDeploymentSpec(deployment, gateway, listeners, ManagedGatewaysConfig{AdminClientCACert: "ca-1"})
+		hash := deployment.Spec.Template.Annotations[managedGatewayAdminClientCAHashAnnotation]
+		require.NotEmpty(t, hash)
+
+		setManagedGatewayDeploymentSpec(deployment, gateway, listeners, ManagedGatewaysConfig{AdminClientCACert: "ca-2"})
+		assert.NotEqual(t, hash, deployment.Spec.Template.Annotations[managedGatewayAdminClientCAHashAnnotation])
+	})
+
+	t.Run("admin client CA config map", func(t *testing.T) {
+		configMap := &corev1.ConfigMap{}
+		setManagedGatewayAdminClientCAConfigMapData(configMap, gateway, ManagedGatewaysConfig{AdminClientCACert: "ca"})
+
+		assert.Equal(t, expectedSelector, configMap.Labels)
+		assert.Equal(t, map[string]string{"ca.crt": "ca"}, configMap.Data)
+	})
+
+	t.Run("proxy service preserves allocated node ports", func(t *testing.T) {
+		service := &corev1.Service{
+			Spec: corev1.ServiceSpec{
+				Ports: []corev1.ServicePort{
+					{Name: "https-443", Port: 443, Protocol: corev1.ProtocolTCP, NodePort: 30443},
+					{Name: "http-80", Port: 80, Protocol: corev1.ProtocolTCP, NodePort: 30080},
+				},
+			},
+		}
+		setManagedGatewayProxyServiceSpec(service, gateway, listeners)
+
+		assert.Equal(t, corev1.ServiceTypeLoadBalancer, service.Spec.Type)
+		assert.Equal(t, expectedSelector, service.Spec.Selector)
+		assert.Equal(t, []corev1.ServicePort{
+			{Name: "udp-53", Port: 53, TargetPort: intstr.FromInt(8053), Protocol: corev1.ProtocolUDP},
+			{Name: "https-443", Port: 443, TargetPort: intstr.FromInt(8443), Protocol: corev1.ProtocolTCP, NodePort: 30443},
+		}, service.Spec.Ports)
+	})
+
+	t.Run("admin service", func(t *testing.T) {
+		service := &corev1.Service{}
+		setManagedGatewayAdminServiceSpec(service, gateway)
+
+		assert.Equal(t, corev1.ClusterIPNone, service.Spec.ClusterIP)
+		assert.Equal(t, expectedSelector, service.Spec.Selector)
+		assert.Equal(t, []corev1.ServicePort{
+			{Name: "admin", Port: 8444, TargetPort: intstr.FromInt(8444), Protocol: corev1.ProtocolTCP},
+		}, service.Spec.Ports)
+	})
+}
+
+func TestListManagedGatewaysForEndpointSlice(t *testing.T) {
+	s := runtime.NewScheme()
+	require.NoError(t, clientgoscheme.AddToScheme(s))
+	require.NoError(t, gatewayv1.Install(s))
+
+	gateway := &gatewayapi.Gateway{
+		TypeMeta:   metav1.TypeMeta{APIVersion: "gateway.networking.k8s.io/v1", Kind: "Gateway"},
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "gateway", UID: "gateway-uid"},
+	}
+	managedService := &corev1.Service{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "gateway-kong-admin"}}
+	require.NoError(t, controllerutil.SetControllerReference(gateway, managedService, s))
+	otherService := &corev1.Service{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "other"}}
+
+	r := &GatewayReconciler{
+		Client: fakeclient.NewClientBuilder().WithScheme(s).WithObjects(managedService, otherService).Build(),
+		Log:    logr.Discard(),
+	}
+	endpointSliceForService := func(name string) *discoveryv1.EndpointSlice {
+		return &discoveryv1.EndpointSlice{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: "default",
+				Name:      name + "-abcde",
+				Labels:    map[string]string{discoveryv1.LabelServiceName: name},
+			},
+		}
+	}
+
+	assert.Equal(t,
+		[]reconcile.Request{{NamespacedName: k8stypes.NamespacedName{Namespace: "default", Name: "gateway"}}},
+		r.listManagedGatewaysForEndpointSlice(context.Background(), endpointSliceForService("gateway-kong-admin")),
+	)
+	assert.Empty(t, r.listManagedGatewaysForEndpointSlice(context.Background(), endpointSliceForService("other")))
+	assert.Empty(t, r.listManagedGatewaysForEndpointSlice(context.Background(), endpointSliceForService("missing")))
+}
@@ -89,6 +89,20 @@ func isGatewayClassControlledAndUnmanaged(gatewayClass *gatewayapi.GatewayClass)
 	return gatewayClass.Spec.ControllerName == GetControllerName() && isUnmanaged
 }
 
+// isGatewayClassControlledAndManaged returns boolean if the GatewayClass
+// is controlled by this controller and is not configured for unmanaged mode.
+func isGatewayClassControlledAndManaged(gatewayClass *gatewayapi.GatewayClass) bool {
+	isUnmanaged := isObjectUnmanaged(gatewayClass.Annotations)
+	return gatewayClass.Spec.ControllerName == GetControllerName() && !isUnmanaged
+}
+
+// isGatewayClassSupported returns boolean if the GatewayClass is controlled by this controller
+// and is either configured for unmanaged mode or managed mode is enabled.
+func isGatewayClassSupported(gatewayClass *gatewayapi.GatewayClass, managedGatewaysEnabled bool) bool {
+	return isGatewayClassControlledAndUnmanaged(gatewayClass) ||
+		(managedGatewaysEnabled && isGatewayClassControlledAndManaged(gatewayClass))
+}
+
 // pruneGatewayStatusConds cleans out old status conditions if the Gateway currently has more
 // status conditions set than the 8 maximum allowed by the Kubernetes API.
 func pruneGatewayStatusConds(gateway *gatewayapi.Gateway) *gatewayapi.Gateway {
@@ -159,10 +173,21 @@ func extractListenerSpecFromGateway(gateway *gatewayapi.Gateway, listenerName ga
 
 type (
 	protocolPortMap map[gatewayapi.ProtocolType]map[gatewayapi.PortNumber]bool
-	portProtocolMap map[gatewayapi.PortNumber]gatewayapi.ProtocolType
-	portHostnameMap map[gatewayapi.PortNumber]map[gatewayapi.Hostname]bool
+	portProtocolMap map[listenerPort]gatewayapi.ProtocolType
+	portHostnameMap map[listenerPort]map[gatewayapi.Hostname]bool
 )
 
+// listenerPort identifies the port a listener is served on. UDP listeners are served by separate sockets,
+// so they don't share ports with listeners of other protocols.
+type listenerPort struct {
+	port gatewayapi.PortNumber
+	udp  bool
+}
+
+func portOfListener(listener gatewayapi.Listener) listenerPort {
+	return listenerPort{port: listener.Port, udp: listener.Protocol == gatewayapi.UDPProtocolType}
+}
+
 func buildKongPortMap(listens []gatewayapi.Listener) protocolPortMap {
 	p := make(map[gatewayapi.ProtocolType]map[gatewayapi.PortNumber]bool, len(listens))
 	for _, listen := range listens {
@@ -192,7 +217,7 @@ func initializeListenerMaps(gateway *gatewayapi.Gateway) (
 	}
 
 	for _, listener := range gateway.Spec.Listeners {
-		portToHostname[listener.Port] = make(map[gatewayapi.Hostname]bool)
+		portToHostname[portOfListener(listener)] = make(map[gatewayapi.Hostname]bool)
 	}
 	return portToProtocol, portToHostname
 }
@@ -238,12 +263,13 @@ func getListenerStatus(
 	statuses := make(map[gatewayapi.SectionName]gatewayapi.ListenerStatus, len(gateway.Spec.Listeners))
 	portToProtocol, portToHostname := initializeListenerMaps(gateway)
 	kongProtocolsToPort := buildKongPortMap(kongListens)
-	conflictedPorts := make(map[gatewayapi.PortNumber]bool, len(gateway.Spec.Listeners))
-	conflictedHostnames := make(map[gatewayapi.PortNumber]map[gatewayapi.Hostname]bool, len(gateway.Spec.Listeners))
+	conflictedPorts := make(map[listenerPort]bool, len(gateway.Spec.Listeners))
+	conflictedHostnames := make(map[listenerPort]map[gatewayapi.Hostname]bool, len(gateway.Spec.Listeners))
 
 	// TODO we should check transition time rather than always nowing, which we do throughout the below
 	// https://github.com/Kong/kubernetes-ingress-controller/issues/2556
 	for listenerIndex, listener := range gateway.Spec.Listeners {
+		port := portOfListener(listener)
 		var hostname gatewayapi.Hostname
 		if listener.Hostname != nil {
 			hostname = *listener.Hostname
@@ -315,12 +341,12 @@ func getListenerStatus(
 			})
 		}
 
-		if _, ok := portToProtocol[listener.Port]; !ok {
+		if _, ok := portToProtocol[port]; !ok {
 			// unoccupied ports are free game
-			portToProtocol[listener.Port] = listener.Protocol
-			portToHostname[listener.Port][hostname] = true
+			portToProtocol[port] = listener.Protocol
+			portToHostname[port][hostname] = true
 		} else {
-			if !canSharePort(listener.Protocol, portToProtocol[listener.Port]) {
+			if !canSharePort(listener.Protocol, portToProtocol[port]) {
 				status.Conditions = append(status.Conditions, metav1.Condition{
 					Type:               string(gatewayapi.ListenerConditionConflicted),
 					Status:             metav1.ConditionTrue,
@@ -328,7 +354,7 @@ func getListenerStatus(
 					LastTransitionTime: metav1.Now(),
 					Reason:             string(gatewayapi.ListenerReasonProtocolConflict),
 				})
-				conflictedPorts[listener.Port] = true
+				conflictedPorts[port] = true
 			} else {
 				// shareable ports determine conflicts by hostname
 				// Each Listener within the group specifies a Hostname that is unique within the group.
@@ -341,11 +367,11 @@ func getListenerStatus(
 				// example.com on port 8000, and your Kong instance has a proxy_listen with both port 8000 and 8200,
 				// you have also added a phantom Listener for hostname example.com and port 8200, because Kong will
 				// serve the route on both. See https://github.com/Kong/kubernetes-ingress-controller/issues/2606
-				if conflictedHostnames[listener.Port] == nil {
-					conflictedHostnames[listener.Port] = map[gatewayapi.Hostname]bool{}
+				if conflictedHostnames[port] == nil {
+					conflictedHostnames[port] = map[gatewayapi.Hostname]bool{}
 				}
-				if _, exists := portToHostname[listener.Port][hostname]; !exists {
-					portToHostname[listener.Port][hostname] = true
+				if _, exists := portToHostname[port][hostname]; !exists {
+					portToHostname[port][hostname] = true
 				} else {
 					status.Conditions = append(status.Conditions, metav1.Condition{
 						Type:               string(gatewayapi.ListenerConditionConflicted),
@@ -354,7 +380,7 @@ func getListenerStatus(
 						LastTransitionTime: metav1.Now(),
 						Reason:             string(gatewayapi.ListenerReasonHostnameConflict),
 					})
-					conflictedHostnames[listener.Port][hostname] = true
+					conflictedHostnames[port][hostname] = true
 				}
 			}
 		}
@@ -447,17 +473,18 @@ func getListenerStatus(
 	// if we encountered conflicts, we must strip the ready status we originally set
 	for _, listener := range gateway.Spec.Listeners {
 		var conflictReason string
+		port := portOfListener(listener)
 
 		var hostname gatewayapi.Hostname
 		if listener.Hostname != nil {
 			hostname = *listener.Hostname
 		}
 		// there's no filter for protocols that don't use Hostname, but this won't be populated from earlier for those
-		if _, ok := conflictedHostnames[listener.Port][hostname]; ok {
+		if _, ok := conflictedHostnames[port][hostname]; ok {
 			conflictReason = string(gatewayapi.ListenerReasonHostnameConflict)
 		}
 
-		if _, ok := conflictedPorts[listener.Port]; ok {
+		if _, ok := conflictedPorts[port]; ok {
 			conflictReason = string(gatewayapi.ListenerReasonProtocolConflict)
 		}
 
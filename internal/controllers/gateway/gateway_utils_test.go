@@ -142,6 +142,30 @@ func TestGetListenerStatus_no_duplicated_condition(t *testing.T) {
 	assertOnlyOneConditionForType(t, listenerStatus.Conditions)
 }
 
+func TestGetListenerStatus_UDP_and_TCP_listeners_sharing_a_port(t *testing.T) {
+	ctx := context.Background()
+	client := fake.NewClientBuilder().Build()
+
+	statuses, err := getListenerStatus(ctx, &gatewayapi.Gateway{
+		Spec: gatewayapi.GatewaySpec{
+			GatewayClassName: "kong",
+			Listeners: []gatewayapi.Listener{
+				{Name: "tcp", Port: 53, Protocol: gatewayapi.TCPProtocolType},
+				{Name: "udp", Port: 53, Protocol: gatewayapi.UDPProtocolType},
+			},
+		},
+	}, nil, nil, client)
+	require.NoError(t, err)
+	require.Len(t, statuses, 2)
+	for _, status := range statuses {
+		for _, c := range status.Conditions {
+			if c.Type == string(gatewayapi.ListenerConditionConflicted) {
+				assert.Equalf(t, metav1.ConditionFalse, c.Status, "listener %s shouldn't be conflicted", status.Name)
+			}
+		}
+	}
+}
+
 func assertOnlyOneConditionForType(t *testing.T, conditions []metav1.Condition) {
 	conditionsNum := lo.CountValuesBy(conditions, func(c metav1.Condition) string {
 		return c.Type
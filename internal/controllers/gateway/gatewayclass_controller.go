@@ -59,6 +59,10 @@ type GatewayClassReconciler struct { //nolint:revive
 	Log              logr.Logger
 	Scheme           *runtime.Scheme
 	CacheSyncTimeout time.Duration
+
+	// ManagedGatewaysEnabled makes the controller accept managed GatewayClasses,
+	// i.e. the ones that aren't annotated as unmanaged.
+	ManagedGatewaysEnabled bool
 }
 
 // SetupWithManager sets up the controller with the Manager.
@@ -76,7 +80,7 @@ func (r *GatewayClassReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	return c.Watch(
 		source.Kind(mgr.GetCache(), &gatewayapi.GatewayClass{}),
 		&handler.EnqueueRequestForObject{},
-		predicate.NewPredicateFuncs(r.GatewayClassIsUnmanaged),
+		predicate.NewPredicateFuncs(r.GatewayClassIsSupported),
 	)
 }
 
@@ -84,9 +88,9 @@ func (r *GatewayClassReconciler) SetupWithManager(mgr ctrl.Manager) error {
 // GatewayClass Controller - Watch Predicates
 // -----------------------------------------------------------------------------
 
-// GatewayClassIsUnmanaged is a watch predicate which filters out reconciliation events for
-// gateway objects which aren't annotated as unmanaged.
-func (r *GatewayClassReconciler) GatewayClassIsUnmanaged(obj client.Object) bool {
+// GatewayClassIsSupported is a watch predicate which filters out reconciliation events for
+// gateway objects which aren't annotated as unmanaged, unless managed Gateways are enabled.
+func (r *GatewayClassReconciler) GatewayClassIsSupported(obj client.Object) bool {
 	gatewayClass, ok := obj.(*gatewayapi.GatewayClass)
 	if !ok {
 		r.Log.Error(
@@ -97,7 +101,7 @@ func (r *GatewayClassReconciler) GatewayClassIsUnmanaged(obj client.Object) bool
 		return false
 	}
 
-	return isGatewayClassControlledAndUnmanaged(gatewayClass)
+	return isGatewayClassSupported(gatewayClass, r.ManagedGatewaysEnabled)
 }
 
 // -----------------------------------------------------------------------------
@@ -123,7 +127,7 @@ func (r *GatewayClassReconciler) Reconcile(ctx context.Context, req ctrl.Request
 
 	log.V(util.DebugLevel).Info("processing gatewayclass", "name", req.Name)
 
-	if isGatewayClassControlledAndUnmanaged(gwc) {
+	if isGatewayClassSupported(gwc, r.ManagedGatewaysEnabled) {
 		alreadyAccepted := util.CheckCondition(
 			gwc.Status.Conditions,
 			util.ConditionType(gatewayapi.GatewayClassConditionStatusAccepted),
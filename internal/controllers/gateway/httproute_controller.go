@@ -31,6 +31,7 @@ import (
 	"github.com/kong/kubernetes-ingress-controller/v2/internal/util"
 	k8sobj "github.com/kong/kubernetes-ingress-controller/v2/internal/util/kubernetes/object"
 	"github.com/kong/kubernetes-ingress-controller/v2/internal/util/kubernetes/object/status"
+	kongv1 "github.com/kong/kubernetes-ingress-controller/v2/pkg/apis/configuration/v1"
 )
 
 // -----------------------------------------------------------------------------
@@ -47,6 +48,16 @@ type HTTPRouteReconciler struct {
 	CacheSyncTimeout time.Duration
 	StatusQueue      *status.Queue
 
+	// ExpressionRoutes is set when Kong uses the expressions router. Otherwise, query param matches
+	// are enforced by a plugin, which is described by the QueryParamMatches condition.
+	ExpressionRoutes bool
+	// QueryParamsGuardPlugin is set when the plugin enforcing query param matches is available in Kong.
+	// Otherwise, HTTPRoutes with query param matches fail translation when the traditional router is used.
+	QueryParamsGuardPlugin bool
+	// RequestMirroring is set when HTTPRoute RequestMirror filters of rules are translated to Kong configuration.
+	// Otherwise, HTTPRoutes using them are not accepted.
+	RequestMirroring bool
+
 	// If enableReferenceGrant is true, we will check for ReferenceGrant if backend in another
 	// namespace is in backendRefs.
 	// If it is false, referencing backend in different namespace will be rejected.
@@ -116,6 +127,20 @@ func (r *HTTPRouteReconciler) SetupWithManager(mgr ctrl.Manager) error {
 		}
 	}
 
+	// KongPlugins referenced by ExtensionRef filters are checked when resolving the references of HTTPRoutes,
+	// so the HTTPRoutes referencing a KongPlugin need to be enqueued when it's created or deleted to keep
+	// their ResolvedRefs condition up to date.
+	if err := c.Watch(
+		source.Kind(mgr.GetCache(), &kongv1.KongPlugin{}),
+		handler.EnqueueRequestsFromMapFunc(r.listHTTPRoutesForKongPlugin),
+		predicate.Funcs{
+			GenericFunc: func(e event.GenericEvent) bool { return false },
+			UpdateFunc:  func(e event.UpdateEvent) bool { return false }, // plugin updates don't affect references
+		},
+	); err != nil {
+		return err
+	}
+
 	if r.StatusQueue != nil {
 		if err := c.Watch(
 			&source.Channel{Source: r.StatusQueue.Subscribe(schema.GroupVersionKind{
@@ -192,6 +217,49 @@ func referenceGrantHasHTTPRouteFrom(obj client.Object) bool {
 	return false
 }
 
+// listHTTPRoutesForKongPlugin is a watch predicate which finds all HTTPRoutes
+// referencing a KongPlugin in an ExtensionRef filter.
+func (r *HTTPRouteReconciler) listHTTPRoutesForKongPlugin(ctx context.Context, obj client.Object) []reconcile.Request {
+	plugin, ok := obj.(*kongv1.KongPlugin)
+	if !ok {
+		r.Log.Error(
+			fmt.Errorf("unexpected object type"),
+			"kongplugin watch predicate received unexpected object type",
+			"expected", "*kongv1.KongPlugin", "found", reflect.TypeOf(obj),
+		)
+		return nil
+	}
+	httproutes := &gatewayapi.HTTPRouteList{}
+	if err := r.Client.List(ctx, httproutes, client.InNamespace(plugin.Namespace)); err != nil {
+		r.Log.Error(err, "failed to list httproutes in watch", "kongplugin", plugin.Name)
+		return nil
+	}
+	recs := []reconcile.Request{}
+	for _, httproute := range httproutes.Items {
+		if httpRouteReferencesKongPlugin(httproute, plugin.Name) {
+			recs = append(recs, reconcile.Request{
+				NamespacedName: k8stypes.NamespacedName{
+					Namespace: httproute.Namespace,
+					Name:      httproute.Name,
+				},
+			})
+		}
+	}
+	return recs
+}
+
+func httpRouteReferencesKongPlugin(httproute gatewayapi.HTTPRoute, pluginName string) bool {
+	for _, rule := range httproute.Spec.Rules {
+		for _, filter := range rule.Filters {
+			if filter.Type == gatewayapi.HTTPRouteFilterExtensionRef && filter.ExtensionRef != nil &&
+				util.IsKongPluginExtensionRef(*filter.ExtensionRef) && string(filter.ExtensionRef.Name) == pluginName {
+				return true
+			}
+		}
+	}
+	return false
+}
+
 // listHTTPRoutesForGatewayClass is a controller-runtime event.Handler which
 // produces a list of HTTPRoutes which were bound to a Gateway which is or was
 // bound to this GatewayClass. This implementation effectively does a map-reduce
@@ -397,6 +465,9 @@ func (r *HTTPRouteReconciler) Reconcile(ctx context.Context, req ctrl.Request) (
 		return ctrl.Result{}, err
 	}
 
+	// HTTPRoutes using filters which can't be translated to Kong configuration are not accepted.
+	gateways = rejectHTTPRouteWithUnsupportedFilters(httproute, gateways, r.RequestMirroring)
+
 	// the referenced gateway object(s) for the HTTPRoute needs to be ready
 	// before we'll attempt any configurations of it. If it's not we'll
 	// requeue the object and wait until all supported gateways are ready.
@@ -531,6 +602,7 @@ func (r *HTTPRouteReconciler) ensureGatewayReferenceStatusAdded(ctx context.Cont
 				ObservedGeneration: httproute.Generation,
 				LastTransitionTime: metav1.Now(),
 				Reason:             gateway.condition.Reason,
+				Message:            gateway.condition.Message,
 			}},
 		}
 		if gateway.listenerName != "" {
@@ -561,6 +633,7 @@ func (r *HTTPRouteReconciler) ensureGatewayReferenceStatusAdded(ctx context.Cont
 	if err != nil {
 		return false, err
 	}
+	queryParamMatchesChanged := r.setRouteConditionQueryParamMatchesCondition(httproute, parentStatuses)
 
 	// initialize "programmed" condition to Unknown.
 	// do not update the condition If a "Programmed" condition is already present.
@@ -580,7 +653,7 @@ func (r *HTTPRouteReconciler) ensureGatewayReferenceStatusAdded(ctx context.Cont
 	}
 
 	// if we didn't have to actually make any changes, no status update is needed
-	if !statusChangesWereMade && !resolvedRefsChanged && !programmedConditionChanged {
+	if !statusChangesWereMade && !resolvedRefsChanged && !queryParamMatchesChanged && !programmedConditionChanged {
 		return false, nil
 	}
 
@@ -599,6 +672,43 @@ func (r *HTTPRouteReconciler) ensureGatewayReferenceStatusAdded(ctx context.Cont
 	return true, nil
 }
 
+// rejectHTTPRouteWithUnsupportedFilters sets the Accepted condition of an HTTPRoute to False with the UnsupportedValue
+// reason for all the Gateways when any of its rules uses a filter which can't be translated to Kong configuration.
+// RequestMirror is such a filter unless request mirroring is enabled, and it's never supported in filters of backends.
+func rejectHTTPRouteWithUnsupportedFilters(
+	httproute *gatewayapi.HTTPRoute,
+	gateways []supportedGatewayWithCondition,
+	requestMirroring bool,
+) []supportedGatewayWithCondition {
+	isRequestMirror := func(filter gatewayapi.HTTPRouteFilter) bool {
+		return filter.Type == gatewayapi.HTTPRouteFilterRequestMirror
+	}
+	usesRequestMirror := lo.SomeBy(httproute.Spec.Rules, func(rule gatewayapi.HTTPRouteRule) bool {
+		return (!requestMirroring && lo.SomeBy(rule.Filters, isRequestMirror)) ||
+			lo.SomeBy(rule.BackendRefs, func(backendRef gatewayapi.HTTPBackendRef) bool {
+				return lo.SomeBy(backendRef.Filters, isRequestMirror)
+			})
+	})
+	if !usesRequestMirror {
+		return gateways
+	}
+
+	message := "RequestMirror filter is not supported"
+	if requestMirroring {
+		message = "RequestMirror filter is not supported in filters of backendRefs"
+	}
+	for i := range gateways {
+		if gateways[i].condition.Status != metav1.ConditionTrue {
+			// keep the reason the route wasn't accepted for in the first place.
+			continue
+		}
+		gateways[i].condition.Status = metav1.ConditionFalse
+		gateways[i].condition.Reason = string(gatewayapi.RouteReasonUnsupportedValue)
+		gateways[i].condition.Message = message
+	}
+	return gateways
+}
+
 // ensureGatewayReferenceStatusRemoved uses the ControllerName provided by the Gateway
 // implementation to prune status references to Gateways supported by this controller
 // in the provided HTTPRoute object.
@@ -674,9 +784,79 @@ func (r *HTTPRouteReconciler) setRouteConditionResolvedRefsCondition(
 	return parentStatuses, changed, nil
 }
 
+// setRouteConditionQueryParamMatchesCondition sets a condition of type QueryParamMatches on the route status when
+// its query param matches are enforced by a plugin, and removes it otherwise.
+func (r *HTTPRouteReconciler) setRouteConditionQueryParamMatchesCondition(
+	httpRoute *gatewayapi.HTTPRoute,
+	parentStatuses map[string]*gatewayapi.RouteParentStatus,
+) bool {
+	enforcedByPlugin := !r.ExpressionRoutes && r.QueryParamsGuardPlugin && lo.SomeBy(httpRoute.Spec.Rules, func(rule gatewayapi.HTTPRouteRule) bool {
+		return lo.SomeBy(rule.Matches, func(match gatewayapi.HTTPRouteMatch) bool {
+			return len(match.QueryParams) > 0
+		})
+	})
+
+	var changed bool
+	for _, parentStatus := range parentStatuses {
+		if enforcedByPlugin {
+			conditionChanged := setRouteParentStatusCondition(parentStatus, metav1.Condition{
+				Type:               ConditionTypeQueryParamMatches,
+				Status:             metav1.ConditionTrue,
+				ObservedGeneration: httpRoute.Generation,
+				LastTransitionTime: metav1.Now(),
+				Reason:             string(ConditionReasonQueryParamMatchesEnforcedByPlugin),
+				Message:            ConditionMessageQueryParamMatchesEnforcedByPlugin,
+			})
+			changed = changed || conditionChanged
+			continue
+		}
+
+		conditions := lo.Reject(parentStatus.Conditions, func(condition metav1.Condition, _ int) bool {
+			return condition.Type == ConditionTypeQueryParamMatches
+		})
+		if len(conditions) != len(parentStatus.Conditions) {
+			parentStatus.Conditions = conditions
+			changed = true
+		}
+	}
+	return changed
+}
+
 func (r *HTTPRouteReconciler) getHTTPRouteRuleReason(ctx context.Context, httpRoute gatewayapi.HTTPRoute) (gatewayapi.RouteConditionReason, error) {
 	for _, rule := range httpRoute.Spec.Rules {
+		backendRefs := make([]gatewayapi.BackendObjectReference, 0, len(rule.BackendRefs))
 		for _, backendRef := range rule.BackendRefs {
+			backendRefs = append(backendRefs, backendRef.BackendObjectReference)
+		}
+
+		// backends that requests are mirrored to have to be resolvable as well.
+		if r.RequestMirroring {
+			for _, filter := range rule.Filters {
+				if filter.Type == gatewayapi.HTTPRouteFilterRequestMirror && filter.RequestMirror != nil {
+					backendRefs = append(backendRefs, filter.RequestMirror.BackendRef)
+				}
+			}
+		}
+
+		// KongPlugins referenced by ExtensionRef filters have to exist in the namespace of the HTTPRoute.
+		for _, filter := range rule.Filters {
+			if filter.Type != gatewayapi.HTTPRouteFilterExtensionRef || filter.ExtensionRef == nil {
+				continue
+			}
+			if !util.IsKongPluginExtensionRef(*filter.ExtensionRef) {
+				return gatewayapi.RouteReasonInvalidKind, nil
+			}
+			plugin := &kongv1.KongPlugin{}
+			err := r.Client.Get(ctx, k8stypes.NamespacedName{Namespace: httpRoute.Namespace, Name: string(filter.ExtensionRef.Name)}, plugin)
+			if err != nil {
+				if !apierrors.IsNotFound(err) {
+					return "", err
+				}
+				return ConditionReasonKongPluginNotFound, nil
+			}
+		}
+
+		for _, backendRef := range backendRefs {
 			backendNamespace := httpRoute.Namespace
 			if backendRef.Namespace != nil && *backendRef.Namespace != "" {
 				backendNamespace = string(*backendRef.Namespace)
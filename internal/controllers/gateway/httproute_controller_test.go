@@ -0,0 +1,292 @@
+package gateway
+
+import (
+	"context"
+	"testing"
+
+	"github.com/samber/lo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	fakeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+	gatewayv1beta1 "sigs.k8s.io/gateway-api/apis/v1beta1"
+
+	"github.com/kong/kubernetes-ingress-controller/v2/internal/gatewayapi"
+	"github.com/kong/kubernetes-ingress-controller/v2/internal/util/builder"
+)
+
+func TestSetRouteConditionQueryParamMatchesCondition(t *testing.T) {
+	httpRouteWithMatch := func(match gatewayapi.HTTPRouteMatch) *gatewayapi.HTTPRoute {
+		return &gatewayapi.HTTPRoute{
+			ObjectMeta: metav1.ObjectMeta{Generation: 2},
+			Spec: gatewayapi.HTTPRouteSpec{
+				Rules: []gatewayapi.HTTPRouteRule{{
+					Matches: []gatewayapi.HTTPRouteMatch{match},
+				}},
+			},
+		}
+	}
+	queryParamMatchesCondition := metav1.Condition{
+		Type:               ConditionTypeQueryParamMatches,
+		Status:             metav1.ConditionTrue,
+		ObservedGeneration: 2,
+		Reason:             string(ConditionReasonQueryParamMatchesEnforcedByPlugin),
+		Message:            ConditionMessageQueryParamMatchesEnforcedByPlugin,
+	}
+	acceptedCondition := metav1.Condition{
+		Type:   string(gatewayapi.RouteConditionAccepted),
+		Status: metav1.ConditionTrue,
+	}
+
+	testCases := []struct {
+		name               string
+		expressionRoutes   bool
+		noQueryParamsGuard bool
+		httpRoute          *gatewayapi.HTTPRoute
+		existingConditions []metav1.Condition
+		expectedConditions []metav1.Condition
+		expectedChanged    bool
+	}{
+		{
+			name:               "query param matches on traditional router set the condition",
+			httpRoute:          httpRouteWithMatch(builder.NewHTTPRouteMatch().WithQueryParam("foo", "bar").Build()),
+			existingConditions: []metav1.Condition{acceptedCondition},
+			expectedConditions: []metav1.Condition{acceptedCondition, queryParamMatchesCondition},
+			expectedChanged:    true,
+		},
+		{
+			name:               "already set condition is left unchanged",
+			httpRoute:          httpRouteWithMatch(builder.NewHTTPRouteMatch().WithQueryParam("foo", "bar").Build()),
+			existingConditions: []metav1.Condition{acceptedCondition, queryParamMatchesCondition},
+			expectedConditions: []metav1.Condition{acceptedCondition, queryParamMatchesCondition},
+			expectedChanged:    false,
+		},
+		{
+			name:               "query param matches on expressions router remove the condition",
+			expressionRoutes:   true,
+			httpRoute:          httpRouteWithMatch(builder.NewHTTPRouteMatch().WithQueryParam("foo", "bar").Build()),
+			existingConditions: []metav1.Condition{acceptedCondition, queryParamMatchesCondition},
+			expectedConditions: []metav1.Condition{acceptedCondition},
+			expectedChanged:    true,
+		},
+		{
+			name:               "query param matches without query params guard plugin remove the condition",
+			noQueryParamsGuard: true,
+			httpRoute:          httpRouteWithMatch(builder.NewHTTPRouteMatch().WithQueryParam("foo", "bar").Build()),
+			existingConditions: []metav1.Condition{acceptedCondition, queryParamMatchesCondition},
+			expectedConditions: []metav1.Condition{acceptedCondition},
+			expectedChanged:    true,
+		},
+		{
+			name:               "no query param matches on traditional router",
+			httpRoute:          httpRouteWithMatch(builder.NewHTTPRouteMatch().WithPathPrefix("/").Build()),
+			existingConditions: []metav1.Condition{acceptedCondition},
+			expectedConditions: []metav1.Condition{acceptedCondition},
+			expectedChanged:    false,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			r := &HTTPRouteReconciler{ExpressionRoutes: tc.expressionRoutes, QueryParamsGuardPlugin: !tc.noQueryParamsGuard}
+			parentStatuses := map[string]*gatewayapi.RouteParentStatus{
+				"default/gateway/": {Conditions: tc.existingConditions},
+			}
+
+			changed := r.setRouteConditionQueryParamMatchesCondition(tc.httpRoute, parentStatuses)
+			assert.Equal(t, tc.expectedChanged, changed)
+
+			conditions := parentStatuses["default/gateway/"].Conditions
+			require.Len(t, conditions, len(tc.expectedConditions))
+			for i := range conditions {
+				// LastTransitionTime is set to the current time.
+				conditions[i].LastTransitionTime = metav1.Time{}
+			}
+			assert.Equal(t, tc.expectedConditions, conditions)
+		})
+	}
+}
+
+func TestRejectHTTPRouteWithUnsupportedFilters(t *testing.T) {
+	mirrorFilter := gatewayapi.HTTPRouteFilter{
+		Type: gatewayapi.HTTPRouteFilterRequestMirror,
+		RequestMirror: &gatewayapi.HTTPRequestMirrorFilter{
+			BackendRef: gatewayapi.BackendObjectReference{Kind: lo.ToPtr(gatewayapi.Kind("Service")), Name: "mirror"},
+		},
+	}
+	newGateways := func() []supportedGatewayWithCondition {
+		return []supportedGatewayWithCondition{
+			{
+				condition: metav1.Condition{
+					Type:   string(gatewayapi.RouteConditionAccepted),
+					Status: metav1.ConditionTrue,
+					Reason: string(gatewayapi.RouteReasonAccepted),
+				},
+			},
+			{
+				condition: metav1.Condition{
+					Type:   string(gatewayapi.RouteConditionAccepted),
+					Status: metav1.ConditionFalse,
+					Reason: string(gatewayapi.RouteReasonNotAllowedByListeners),
+				},
+			},
+		}
+	}
+
+	testCases := []struct {
+		name             string
+		rule             gatewayapi.HTTPRouteRule
+		requestMirroring bool
+		expectedReasons  []string
+	}{
+		{
+			name: "route without RequestMirror filters is left unchanged",
+			rule: gatewayapi.HTTPRouteRule{
+				BackendRefs: builder.NewHTTPBackendRef("svc").ToSlice(),
+			},
+			expectedReasons: []string{
+				string(gatewayapi.RouteReasonAccepted),
+				string(gatewayapi.RouteReasonNotAllowedByListeners),
+			},
+		},
+		{
+			name: "RequestMirror filter of a rule is not supported",
+			rule: gatewayapi.HTTPRouteRule{
+				Filters:     []gatewayapi.HTTPRouteFilter{mirrorFilter},
+				BackendRefs: builder.NewHTTPBackendRef("svc").ToSlice(),
+			},
+			expectedReasons: []string{
+				string(gatewayapi.RouteReasonUnsupportedValue),
+				string(gatewayapi.RouteReasonNotAllowedByListeners),
+			},
+		},
+		{
+			name: "RequestMirror filter of a rule is supported when request mirroring is enabled",
+			rule: gatewayapi.HTTPRouteRule{
+				Filters:     []gatewayapi.HTTPRouteFilter{mirrorFilter},
+				BackendRefs: builder.NewHTTPBackendRef("svc").ToSlice(),
+			},
+			requestMirroring: true,
+			expectedReasons: []string{
+				string(gatewayapi.RouteReasonAccepted),
+				string(gatewayapi.RouteReasonNotAllowedByListeners),
+			},
+		},
+		{
+			name: "RequestMirror filter of a backend is not supported even when request mirroring is enabled",
+			rule: gatewayapi.HTTPRouteRule{
+				BackendRefs: []gatewayapi.HTTPBackendRef{{
+					BackendRef: builder.NewBackendRef("svc").Build(),
+					Filters:    []gatewayapi.HTTPRouteFilter{mirrorFilter},
+				}},
+			},
+			requestMirroring: true,
+			expectedReasons: []string{
+				string(gatewayapi.RouteReasonUnsupportedValue),
+				string(gatewayapi.RouteReasonNotAllowedByListeners),
+			},
+		},
+		{
+			name: "RequestMirror filter of a backend is not supported",
+			rule: gatewayapi.HTTPRouteRule{
+				BackendRefs: []gatewayapi.HTTPBackendRef{{
+					BackendRef: builder.NewBackendRef("svc").Build(),
+					Filters:    []gatewayapi.HTTPRouteFilter{mirrorFilter},
+				}},
+			},
+			expectedReasons: []string{
+				string(gatewayapi.RouteReasonUnsupportedValue),
+				string(gatewayapi.RouteReasonNotAllowedByListeners),
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			httpRoute := &gatewayapi.HTTPRoute{
+				Spec: gatewayapi.HTTPRouteSpec{Rules: []gatewayapi.HTTPRouteRule{tc.rule}},
+			}
+			gateways := rejectHTTPRouteWithUnsupportedFilters(httpRoute, newGateways(), tc.requestMirroring)
+			assert.Equal(t, tc.expectedReasons, lo.Map(gateways, func(g supportedGatewayWithCondition, _ int) string {
+				return g.condition.Reason
+			}))
+			assert.Equal(t, tc.expectedReasons[0] == string(gatewayapi.RouteReasonAccepted), isRouteAccepted(gateways))
+		})
+	}
+}
+
+func TestGetHTTPRouteRuleReason_RequestMirror(t *testing.T) {
+	s := runtime.NewScheme()
+	require.NoError(t, clientgoscheme.AddToScheme(s))
+	require.NoError(t, gatewayv1beta1.Install(s))
+	cl := fakeclient.NewClientBuilder().WithScheme(s).WithObjects(
+		&corev1.Service{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "svc"}},
+		&corev1.Service{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "mirror"}},
+		&corev1.Service{ObjectMeta: metav1.ObjectMeta{Namespace: "other", Name: "mirror"}},
+	).Build()
+
+	newHTTPRoute := func(mirrorBackendRef gatewayapi.BackendObjectReference) gatewayapi.HTTPRoute {
+		return gatewayapi.HTTPRoute{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "route"},
+			Spec: gatewayapi.HTTPRouteSpec{
+				Rules: []gatewayapi.HTTPRouteRule{{
+					Filters: []gatewayapi.HTTPRouteFilter{{
+						Type:          gatewayapi.HTTPRouteFilterRequestMirror,
+						RequestMirror: &gatewayapi.HTTPRequestMirrorFilter{BackendRef: mirrorBackendRef},
+					}},
+					BackendRefs: builder.NewHTTPBackendRef("svc").ToSlice(),
+				}},
+			},
+		}
+	}
+
+	testCases := []struct {
+		name             string
+		mirrorBackendRef gatewayapi.BackendObjectReference
+		requestMirroring bool
+		expectedReason   gatewayapi.RouteConditionReason
+	}{
+		{
+			name:             "mirrored backend in the same namespace is resolved",
+			mirrorBackendRef: gatewayapi.BackendObjectReference{Kind: lo.ToPtr(gatewayapi.Kind("Service")), Name: "mirror"},
+			requestMirroring: true,
+			expectedReason:   gatewayapi.RouteReasonResolvedRefs,
+		},
+		{
+			name:             "mirrored backend that doesn't exist",
+			mirrorBackendRef: gatewayapi.BackendObjectReference{Kind: lo.ToPtr(gatewayapi.Kind("Service")), Name: "nonexistent"},
+			requestMirroring: true,
+			expectedReason:   gatewayapi.RouteReasonBackendNotFound,
+		},
+		{
+			name: "mirrored backend in another namespace not permitted by a ReferenceGrant",
+			mirrorBackendRef: gatewayapi.BackendObjectReference{
+				Kind:      lo.ToPtr(gatewayapi.Kind("Service")),
+				Name:      "mirror",
+				Namespace: lo.ToPtr(gatewayapi.Namespace("other")),
+			},
+			requestMirroring: true,
+			expectedReason:   gatewayapi.RouteReasonRefNotPermitted,
+		},
+		{
+			name:             "mirrored backends are not checked when request mirroring is disabled",
+			mirrorBackendRef: gatewayapi.BackendObjectReference{Kind: lo.ToPtr(gatewayapi.Kind("Service")), Name: "nonexistent"},
+			expectedReason:   gatewayapi.RouteReasonResolvedRefs,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			r := &HTTPRouteReconciler{Client: cl, RequestMirroring: tc.requestMirroring, enableReferenceGrant: true}
+			reason, err := r.getHTTPRouteRuleReason(context.Background(), newHTTPRoute(tc.mirrorBackendRef))
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedReason, reason)
+		})
+	}
+}
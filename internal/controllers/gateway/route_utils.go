@@ -34,6 +34,22 @@ const (
 	ConditionReasonProgrammedUnknown   gatewayapi.RouteConditionReason = "Unknown"
 	ConditionReasonConfiguredInGateway gatewayapi.RouteConditionReason = "ConfiguredInGateway"
 	ConditionReasonTranslationError    gatewayapi.RouteConditionReason = "TranslationError"
+
+	// ConditionReasonKongPluginNotFound is used in the ResolvedRefs condition of HTTPRoutes with ExtensionRef
+	// filters referencing KongPlugins which don't exist. Gateway API doesn't define a reason for missing filters.
+	ConditionReasonKongPluginNotFound gatewayapi.RouteConditionReason = "KongPluginNotFound"
+)
+
+const (
+	// ConditionTypeQueryParamMatches is set on HTTPRoutes with query param matches when Kong uses the traditional
+	// router, which can't match query params, to describe how such matches are handled.
+	ConditionTypeQueryParamMatches = "QueryParamMatches"
+
+	ConditionReasonQueryParamMatchesEnforcedByPlugin gatewayapi.RouteConditionReason = "EnforcedByPlugin"
+
+	ConditionMessageQueryParamMatchesEnforcedByPlugin = "Kong's traditional router can't match query params. " +
+		"Requests are routed by the other criteria of matches with query params and rejected with 404 by a pre-function plugin " +
+		"when their query params don't match, instead of being matched against other rules."
 )
 
 var ErrNoMatchingListenerHostname = fmt.Errorf("no matching hostnames in listener")
@@ -644,7 +660,7 @@ func isRouteAccepted(gateways []supportedGatewayWithCondition) bool {
 }
 
 // isHTTPReferenceGranted checks that the backendRef referenced by the HTTPRoute is granted by a ReferenceGrant.
-func isHTTPReferenceGranted(grantSpec gatewayapi.ReferenceGrantSpec, backendRef gatewayapi.HTTPBackendRef, fromNamespace string) bool {
+func isHTTPReferenceGranted(grantSpec gatewayapi.ReferenceGrantSpec, backendRef gatewayapi.BackendObjectReference, fromNamespace string) bool {
 	var backendRefGroup gatewayapi.Group
 	var backendRefKind gatewayapi.Kind
 
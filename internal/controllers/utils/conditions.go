@@ -1,6 +1,10 @@
 package utils
 
 import (
+	"fmt"
+	"strings"
+	"time"
+
 	"github.com/samber/lo"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
@@ -18,6 +22,12 @@ const (
 
 	// ProgrammedConditionFalsePendingMessage is the message for the programmed condition when it is False with reason Pending.
 	ProgrammedConditionFalsePendingMessage = "Object is pending configuration in Kong."
+
+	// ExcludedConditionRemovedMessage is the message for the excluded condition with reason Removed.
+	ExcludedConditionRemovedMessage = "Object was left out of the configuration applied to Kong - see its attached Events for more information."
+
+	// ExcludedConditionLastValidVersionUsedMessage is the message for the excluded condition with reason LastValidVersionUsed.
+	ExcludedConditionLastValidVersionUsedMessage = "Last valid version of the object was applied to Kong instead of the current one - see its attached Events for more information."
 )
 
 // EnsureProgrammedCondition ensures that the programmed condition is present in the conditions slice with the
@@ -79,3 +89,111 @@ func EnsureProgrammedCondition(configurationStatus object.ConfigurationStatus, o
 
 	return conditions, true
 }
+
+// EnsureExcludedCondition ensures that the excluded condition is present in the conditions slice when the object
+// has been excluded from the configuration applied to Kong, and that it's absent otherwise.
+// The second return value tells whether the conditions slice has been modified.
+func EnsureExcludedCondition(fallbackStatus object.FallbackStatus, objectGeneration int64, conditions []metav1.Condition) (
+	updatedConditions []metav1.Condition,
+	updateNeeded bool,
+) {
+	_, idx, found := lo.FindIndexOf(conditions, func(c metav1.Condition) bool { return c.Type == string(kongv1.ConditionExcluded) })
+
+	var (
+		reason  kongv1.ConditionReason
+		message string
+	)
+	switch fallbackStatus {
+	case object.FallbackStatusRemoved:
+		reason = kongv1.ReasonRemoved
+		message = ExcludedConditionRemovedMessage
+	case object.FallbackStatusLastValid:
+		reason = kongv1.ReasonLastValidVersionUsed
+		message = ExcludedConditionLastValidVersionUsedMessage
+	default:
+		if !found {
+			return conditions, false
+		}
+		return lo.Filter(conditions, func(c metav1.Condition, _ int) bool {
+			return c.Type != string(kongv1.ConditionExcluded)
+		}), true
+	}
+
+	desiredCondition := metav1.Condition{
+		Type:               string(kongv1.ConditionExcluded),
+		Status:             metav1.ConditionTrue,
+		ObservedGeneration: objectGeneration,
+		LastTransitionTime: metav1.Now(),
+		Reason:             string(reason),
+		Message:            message,
+	}
+
+	hasMatchingCondition := util.CheckCondition(
+		conditions,
+		util.ConditionType(desiredCondition.Type),
+		util.ConditionReason(desiredCondition.Reason),
+		desiredCondition.Status,
+		desiredCondition.ObservedGeneration,
+	)
+	if hasMatchingCondition {
+		return conditions, false
+	}
+
+	if !found {
+		conditions = append(conditions, desiredCondition)
+	} else {
+		conditions[idx] = desiredCondition
+	}
+
+	return conditions, true
+}
+
+// EnsureCredentialRotationCondition ensures that the credential rotation condition is present in the conditions
+// slice when previous values of the object's rotated credentials are kept in the configuration applied to Kong,
+// and that it's absent otherwise. The second return value tells whether the conditions slice has been modified.
+func EnsureCredentialRotationCondition(rotations []object.CredentialRotation, objectGeneration int64, conditions []metav1.Condition) (
+	updatedConditions []metav1.Condition,
+	updateNeeded bool,
+) {
+	existing, idx, found := lo.FindIndexOf(conditions, func(c metav1.Condition) bool {
+		return c.Type == string(kongv1.ConditionCredentialRotationInProgress)
+	})
+
+	if len(rotations) == 0 {
+		if !found {
+			return conditions, false
+		}
+		return lo.Filter(conditions, func(c metav1.Condition, _ int) bool {
+			return c.Type != string(kongv1.ConditionCredentialRotationInProgress)
+		}), true
+	}
+
+	desiredCondition := metav1.Condition{
+		Type:               string(kongv1.ConditionCredentialRotationInProgress),
+		Status:             metav1.ConditionTrue,
+		ObservedGeneration: objectGeneration,
+		LastTransitionTime: metav1.Now(),
+		Reason:             string(kongv1.ReasonPreviousCredentialsKept),
+		Message: "Previous values of rotated credentials are kept until their grace periods end: " +
+			strings.Join(lo.Map(rotations, func(r object.CredentialRotation, _ int) string {
+				return fmt.Sprintf("%s (until %s)", r.Secret, r.ExpiresAt.UTC().Format(time.RFC3339))
+			}), ", ") + ".",
+	}
+
+	// The message is compared as well, as it changes when other credentials are rotated.
+	if found &&
+		existing.Status == desiredCondition.Status &&
+		existing.Reason == desiredCondition.Reason &&
+		existing.ObservedGeneration == desiredCondition.ObservedGeneration &&
+		existing.Message == desiredCondition.Message {
+		return conditions, false
+	}
+
+	if !found {
+		conditions = append(conditions, desiredCondition)
+	} else {
+		conditions[idx] = desiredCondition
+	}
+
+	return conditions, true
+}
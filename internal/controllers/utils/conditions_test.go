@@ -2,6 +2,7 @@ package utils_test
 
 import (
 	"testing"
+	"time"
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/google/go-cmp/cmp/cmpopts"
@@ -114,3 +115,188 @@ func TestEnsureProgrammedCondition(t *testing.T) {
 		})
 	}
 }
+
+func TestEnsureExcludedCondition(t *testing.T) {
+	const testObjectGeneration = 2
+	var (
+		programmedCondition = metav1.Condition{
+			Type:               string(kongv1.ConditionProgrammed),
+			Status:             metav1.ConditionFalse,
+			ObservedGeneration: testObjectGeneration,
+			Reason:             string(kongv1.ReasonInvalid),
+			Message:            utils.ProgrammedConditionFalseInvalidMessage,
+		}
+		expectedExcludedConditionRemoved = metav1.Condition{
+			Type:               string(kongv1.ConditionExcluded),
+			Status:             metav1.ConditionTrue,
+			ObservedGeneration: testObjectGeneration,
+			Reason:             string(kongv1.ReasonRemoved),
+			Message:            utils.ExcludedConditionRemovedMessage,
+		}
+		expectedExcludedConditionLastValid = metav1.Condition{
+			Type:               string(kongv1.ConditionExcluded),
+			Status:             metav1.ConditionTrue,
+			ObservedGeneration: testObjectGeneration,
+			Reason:             string(kongv1.ReasonLastValidVersionUsed),
+			Message:            utils.ExcludedConditionLastValidVersionUsedMessage,
+		}
+	)
+
+	testCases := []struct {
+		name string
+
+		fallbackStatus object.FallbackStatus
+		conditions     []metav1.Condition
+
+		expectedUpdatedConditions []metav1.Condition
+		expectedUpdateNeeded      bool
+	}{
+		{
+			name:                      "not excluded object without the condition",
+			fallbackStatus:            object.FallbackStatusNotExcluded,
+			conditions:                []metav1.Condition{programmedCondition},
+			expectedUpdatedConditions: []metav1.Condition{programmedCondition},
+			expectedUpdateNeeded:      false,
+		},
+		{
+			name:                      "not excluded object with the condition",
+			fallbackStatus:            object.FallbackStatusNotExcluded,
+			conditions:                []metav1.Condition{expectedExcludedConditionRemoved, programmedCondition},
+			expectedUpdatedConditions: []metav1.Condition{programmedCondition},
+			expectedUpdateNeeded:      true,
+		},
+		{
+			name:                      "removed object without the condition",
+			fallbackStatus:            object.FallbackStatusRemoved,
+			conditions:                []metav1.Condition{programmedCondition},
+			expectedUpdatedConditions: []metav1.Condition{programmedCondition, expectedExcludedConditionRemoved},
+			expectedUpdateNeeded:      true,
+		},
+		{
+			name:                      "condition already present with correct reason",
+			fallbackStatus:            object.FallbackStatusLastValid,
+			conditions:                []metav1.Condition{expectedExcludedConditionLastValid},
+			expectedUpdatedConditions: []metav1.Condition{expectedExcludedConditionLastValid},
+			expectedUpdateNeeded:      false,
+		},
+		{
+			name:                      "condition present with different reason",
+			fallbackStatus:            object.FallbackStatusLastValid,
+			conditions:                []metav1.Condition{expectedExcludedConditionRemoved},
+			expectedUpdatedConditions: []metav1.Condition{expectedExcludedConditionLastValid},
+			expectedUpdateNeeded:      true,
+		},
+		{
+			name:           "condition present with older observed generation",
+			fallbackStatus: object.FallbackStatusRemoved,
+			conditions: []metav1.Condition{
+				func() metav1.Condition {
+					cond := expectedExcludedConditionRemoved
+					cond.ObservedGeneration = 1
+					return cond
+				}(),
+			},
+			expectedUpdatedConditions: []metav1.Condition{expectedExcludedConditionRemoved},
+			expectedUpdateNeeded:      true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			conditions, updateNeeded := utils.EnsureExcludedCondition(tc.fallbackStatus, testObjectGeneration, tc.conditions)
+			assert.Equal(t, tc.expectedUpdateNeeded, updateNeeded)
+
+			ignoreLastTransitionTime := cmpopts.IgnoreFields(metav1.Condition{}, "LastTransitionTime")
+			diff := cmp.Diff(conditions, tc.expectedUpdatedConditions, ignoreLastTransitionTime)
+			assert.Empty(t, diff, "conditions mismatch")
+		})
+	}
+}
+
+func TestEnsureCredentialRotationCondition(t *testing.T) {
+	const testObjectGeneration = 2
+	var (
+		expiresAt           = time.Date(2023, time.October, 1, 12, 0, 0, 0, time.UTC)
+		programmedCondition = metav1.Condition{
+			Type:               string(kongv1.ConditionProgrammed),
+			Status:             metav1.ConditionTrue,
+			ObservedGeneration: testObjectGeneration,
+			Reason:             string(kongv1.ReasonProgrammed),
+			Message:            utils.ProgrammedConditionTrueMessage,
+		}
+		expectedCredentialRotationCondition = metav1.Condition{
+			Type:               string(kongv1.ConditionCredentialRotationInProgress),
+			Status:             metav1.ConditionTrue,
+			ObservedGeneration: testObjectGeneration,
+			Reason:             string(kongv1.ReasonPreviousCredentialsKept),
+			Message: "Previous values of rotated credentials are kept until their grace periods end: " +
+				"key-a (until 2023-10-01T12:00:00Z), key-b (until 2023-10-01T13:00:00Z).",
+		}
+		rotations = []object.CredentialRotation{
+			{Secret: "key-a", ExpiresAt: expiresAt},
+			{Secret: "key-b", ExpiresAt: expiresAt.Add(time.Hour)},
+		}
+	)
+
+	testCases := []struct {
+		name string
+
+		rotations  []object.CredentialRotation
+		conditions []metav1.Condition
+
+		expectedUpdatedConditions []metav1.Condition
+		expectedUpdateNeeded      bool
+	}{
+		{
+			name:                      "no rotations without the condition",
+			conditions:                []metav1.Condition{programmedCondition},
+			expectedUpdatedConditions: []metav1.Condition{programmedCondition},
+			expectedUpdateNeeded:      false,
+		},
+		{
+			name:                      "no rotations with the condition",
+			conditions:                []metav1.Condition{expectedCredentialRotationCondition, programmedCondition},
+			expectedUpdatedConditions: []metav1.Condition{programmedCondition},
+			expectedUpdateNeeded:      true,
+		},
+		{
+			name:                      "rotations without the condition",
+			rotations:                 rotations,
+			conditions:                []metav1.Condition{programmedCondition},
+			expectedUpdatedConditions: []metav1.Condition{programmedCondition, expectedCredentialRotationCondition},
+			expectedUpdateNeeded:      true,
+		},
+		{
+			name:                      "condition already present with the same rotations",
+			rotations:                 rotations,
+			conditions:                []metav1.Condition{expectedCredentialRotationCondition},
+			expectedUpdatedConditions: []metav1.Condition{expectedCredentialRotationCondition},
+			expectedUpdateNeeded:      false,
+		},
+		{
+			name:      "condition present with other rotations",
+			rotations: rotations,
+			conditions: []metav1.Condition{
+				func() metav1.Condition {
+					cond := expectedCredentialRotationCondition
+					cond.Message = "Previous values of rotated credentials are kept until their grace periods end: " +
+						"key-a (until 2023-10-01T12:00:00Z)."
+					return cond
+				}(),
+			},
+			expectedUpdatedConditions: []metav1.Condition{expectedCredentialRotationCondition},
+			expectedUpdateNeeded:      true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			conditions, updateNeeded := utils.EnsureCredentialRotationCondition(tc.rotations, testObjectGeneration, tc.conditions)
+			assert.Equal(t, tc.expectedUpdateNeeded, updateNeeded)
+
+			ignoreLastTransitionTime := cmpopts.IgnoreFields(metav1.Condition{}, "LastTransitionTime")
+			diff := cmp.Diff(conditions, tc.expectedUpdatedConditions, ignoreLastTransitionTime)
+			assert.Empty(t, diff, "conditions mismatch")
+		})
+	}
+}
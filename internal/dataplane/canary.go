@@ -0,0 +1,190 @@
+package dataplane
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/kong/kubernetes-ingress-controller/v2/internal/adminapi"
+	"github.com/kong/kubernetes-ingress-controller/v2/internal/dataplane/sendconfig"
+)
+
+const (
+	// DefaultCanarySoakTime is the default time to wait after applying configuration to canary gateways
+	// before checking their health.
+	DefaultCanarySoakTime = 10 * time.Second
+
+	// DefaultCanaryProbeTimeout is the default timeout of each request sent by CanaryProbeHealthCheck.
+	DefaultCanaryProbeTimeout = 5 * time.Second
+
+	// CanaryProbeHostPlaceholder is replaced with the host of a canary gateway in the URL of CanaryProbeHealthCheck.
+	CanaryProbeHostPlaceholder = "{host}"
+
+	// defaultCanaryProbeRequests is the number of requests sent by CanaryProbeHealthCheck.
+	defaultCanaryProbeRequests = 10
+)
+
+// CanaryRolloutConfig configures staged rollouts of configuration, in which configuration is applied
+// to a subset of gateways (canaries) first, and to the rest of them only when the canaries stay healthy.
+type CanaryRolloutConfig struct {
+	// Percentage of gateways to apply configuration to first. At least one gateway is a canary,
+	// and at least one gateway isn't.
+	Percentage int
+
+	// SoakTime is the time to wait after applying configuration to canaries before checking their health.
+	SoakTime time.Duration
+
+	// HealthChecks are run against each of the canaries after SoakTime. All of them have to pass
+	// for the configuration to be applied to the rest of the gateways.
+	HealthChecks []CanaryHealthCheck
+}
+
+// splitCanaries splits gateway clients into canaries and the rest. Canaries are chosen by their URLs
+// for the same gateways to be canaries in subsequent rollouts. No canaries are returned when there are
+// less than two gateways, as there's nothing to protect then.
+func (cfg CanaryRolloutConfig) splitCanaries(clients []*adminapi.Client) (canaries, rest []*adminapi.Client) {
+	if len(clients) < 2 || cfg.Percentage <= 0 {
+		return nil, clients
+	}
+
+	sorted := make([]*adminapi.Client, len(clients))
+	copy(sorted, clients)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].BaseRootURL() < sorted[j].BaseRootURL() })
+
+	count := int(math.Ceil(float64(len(sorted)*cfg.Percentage) / 100))
+	if count >= len(sorted) {
+		count = len(sorted) - 1
+	}
+	return sorted[:count], sorted[count:]
+}
+
+// checkHealth waits for the soak time and runs health checks against each of the canaries.
+func (cfg CanaryRolloutConfig) checkHealth(ctx context.Context, canaries []*adminapi.Client) error {
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-time.After(cfg.SoakTime):
+	}
+
+	var errs error
+	for _, canary := range canaries {
+		for _, check := range cfg.HealthChecks {
+			if err := check.Check(ctx, canary); err != nil {
+				errs = errors.Join(errs, fmt.Errorf("%s: %w", canary.BaseRootURL(), err))
+			}
+		}
+	}
+	return errs
+}
+
+// CanaryRolloutFailedError is returned when the configuration has been rolled back from canary gateways
+// because they were unhealthy after applying it. The rest of the gateways were left untouched.
+type CanaryRolloutFailedError struct {
+	Err error
+}
+
+func (e CanaryRolloutFailedError) Error() string {
+	return fmt.Sprintf("canary gateways are unhealthy after applying configuration: %v", e.Err)
+}
+
+func (e CanaryRolloutFailedError) Unwrap() error {
+	return e.Err
+}
+
+// failedCanaryRollout describes a configuration that made canary gateways unhealthy.
+type failedCanaryRollout struct {
+	// sha is the SHA of the configuration.
+	sha string
+	// err is the error the canaries' health checks failed with.
+	err error
+}
+
+// CanaryHealthCheck checks the health of a canary gateway configuration has been applied to.
+type CanaryHealthCheck interface {
+	Check(ctx context.Context, client *adminapi.Client) error
+}
+
+// CanaryStatusHealthCheck checks that the Admin API /status endpoint of a canary responds, and that
+// the canary still has configuration loaded (e.g. it hasn't crashed and restarted with no configuration).
+type CanaryStatusHealthCheck struct{}
+
+func (CanaryStatusHealthCheck) Check(ctx context.Context, client *adminapi.Client) error {
+	status, err := client.AdminAPIClient().Status(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get status: %w", err)
+	}
+	if status.ConfigurationHash == sendconfig.WellKnownInitialHash {
+		return errors.New("gateway has no configuration loaded")
+	}
+	return nil
+}
+
+// CanaryProbeHealthCheck sends requests to a URL and checks the rate of failed ones. Requests fail when
+// they can't be sent or are responded with a 5xx status code. CanaryProbeHostPlaceholder in the URL is
+// replaced with the host of the canary's Admin API URL, allowing to probe the canary's proxy.
+type CanaryProbeHealthCheck struct {
+	URL          string
+	MaxErrorRate float64
+	// Timeout of each request. DefaultCanaryProbeTimeout is used when it's not set.
+	Timeout    time.Duration
+	HTTPClient *http.Client
+}
+
+func (p CanaryProbeHealthCheck) Check(ctx context.Context, client *adminapi.Client) error {
+	probeURL := p.URL
+	if strings.Contains(probeURL, CanaryProbeHostPlaceholder) {
+		adminURL, err := url.Parse(client.BaseRootURL())
+		if err != nil {
+			return fmt.Errorf("failed to parse Admin API URL: %w", err)
+		}
+		probeURL = strings.ReplaceAll(probeURL, CanaryProbeHostPlaceholder, adminURL.Hostname())
+	}
+
+	httpClient := p.HTTPClient
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+	timeout := p.Timeout
+	if timeout <= 0 {
+		timeout = DefaultCanaryProbeTimeout
+	}
+
+	var (
+		failed  int
+		lastErr error
+	)
+	for i := 0; i < defaultCanaryProbeRequests; i++ {
+		if err := probe(ctx, httpClient, probeURL, timeout); err != nil {
+			failed++
+			lastErr = err
+		}
+	}
+	if errorRate := float64(failed) / defaultCanaryProbeRequests; errorRate > p.MaxErrorRate {
+		return fmt.Errorf("%d of %d probe requests to %s failed, last error: %w", failed, defaultCanaryProbeRequests, probeURL, lastErr)
+	}
+	return nil
+}
+
+func probe(ctx context.Context, httpClient *http.Client, url string, timeout time.Duration) error {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= http.StatusInternalServerError {
+		return fmt.Errorf("unexpected status code %d", resp.StatusCode)
+	}
+	return nil
+}
@@ -0,0 +1,173 @@
+package dataplane
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/samber/lo"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kong/kubernetes-ingress-controller/v2/internal/adminapi"
+	"github.com/kong/kubernetes-ingress-controller/v2/internal/dataplane/sendconfig"
+	"github.com/kong/kubernetes-ingress-controller/v2/test/mocks"
+)
+
+func TestCanaryRolloutConfig_SplitCanaries(t *testing.T) {
+	newClients := func(t *testing.T, urls ...string) []*adminapi.Client {
+		return lo.Map(urls, func(u string, _ int) *adminapi.Client {
+			c, err := adminapi.NewTestClient(u)
+			require.NoError(t, err)
+			return c
+		})
+	}
+	urls := func(clients []*adminapi.Client) []string {
+		return lo.Map(clients, func(c *adminapi.Client, _ int) string { return c.BaseRootURL() })
+	}
+
+	testCases := []struct {
+		name             string
+		percentage       int
+		urls             []string
+		expectedCanaries []string
+		expectedRest     []string
+	}{
+		{
+			name:         "single gateway is never a canary",
+			percentage:   50,
+			urls:         []string{"https://10.0.0.1:8444"},
+			expectedRest: []string{"https://10.0.0.1:8444"},
+		},
+		{
+			name:         "disabled rollout",
+			percentage:   0,
+			urls:         []string{"https://10.0.0.1:8444", "https://10.0.0.2:8444"},
+			expectedRest: []string{"https://10.0.0.1:8444", "https://10.0.0.2:8444"},
+		},
+		{
+			name:             "at least one gateway is a canary",
+			percentage:       1,
+			urls:             []string{"https://10.0.0.3:8444", "https://10.0.0.1:8444", "https://10.0.0.2:8444"},
+			expectedCanaries: []string{"https://10.0.0.1:8444"},
+			expectedRest:     []string{"https://10.0.0.2:8444", "https://10.0.0.3:8444"},
+		},
+		{
+			name:             "percentage is rounded up",
+			percentage:       50,
+			urls:             []string{"https://10.0.0.3:8444", "https://10.0.0.1:8444", "https://10.0.0.2:8444"},
+			expectedCanaries: []string{"https://10.0.0.1:8444", "https://10.0.0.2:8444"},
+			expectedRest:     []string{"https://10.0.0.3:8444"},
+		},
+		{
+			name:             "at least one gateway is not a canary",
+			percentage:       100,
+			urls:             []string{"https://10.0.0.2:8444", "https://10.0.0.1:8444"},
+			expectedCanaries: []string{"https://10.0.0.1:8444"},
+			expectedRest:     []string{"https://10.0.0.2:8444"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			canaries, rest := CanaryRolloutConfig{Percentage: tc.percentage}.splitCanaries(newClients(t, tc.urls...))
+			require.Equal(t, tc.expectedCanaries, lo.Ternary(len(canaries) == 0, nil, urls(canaries)))
+			require.Equal(t, tc.expectedRest, urls(rest))
+		})
+	}
+}
+
+func TestCanaryStatusHealthCheck(t *testing.T) {
+	startAdminAPI := func(t *testing.T, opts ...mocks.AdminAPIHandlerOpt) *adminapi.Client {
+		server := httptest.NewServer(mocks.NewAdminAPIHandler(t, opts...))
+		t.Cleanup(server.Close)
+		c, err := adminapi.NewKongClientForWorkspace(context.Background(), server.URL, "", server.Client())
+		require.NoError(t, err)
+		return c
+	}
+
+	t.Run("gateway with configuration is healthy", func(t *testing.T) {
+		c := startAdminAPI(t, mocks.WithReady(true), mocks.WithConfigurationHash("8f1dd2f83bc2627cc6b71c76d1476592"))
+		require.NoError(t, CanaryStatusHealthCheck{}.Check(context.Background(), c))
+	})
+
+	t.Run("gateway without configuration is unhealthy", func(t *testing.T) {
+		c := startAdminAPI(t, mocks.WithReady(true), mocks.WithConfigurationHash(sendconfig.WellKnownInitialHash))
+		require.ErrorContains(t, CanaryStatusHealthCheck{}.Check(context.Background(), c), "no configuration loaded")
+	})
+}
+
+func TestCanaryProbeHealthCheck(t *testing.T) {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := requests.Add(1)
+		if r.URL.Path == "/slow" {
+			select {
+			case <-time.After(time.Second):
+			case <-r.Context().Done():
+			}
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		if r.URL.Path == "/flaky" && n%5 == 0 {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	t.Cleanup(server.Close)
+	serverURL, err := url.Parse(server.URL)
+	require.NoError(t, err)
+
+	canary, err := adminapi.NewTestClient("https://127.0.0.1:8444")
+	require.NoError(t, err)
+
+	testCases := []struct {
+		name         string
+		url          string
+		maxErrorRate float64
+		timeout      time.Duration
+		expectError  bool
+	}{
+		{
+			name: "non-5xx responses are healthy",
+			url:  "http://" + CanaryProbeHostPlaceholder + ":" + serverURL.Port() + "/healthy",
+		},
+		{
+			name:        "errors above the max rate are unhealthy",
+			url:         server.URL + "/flaky",
+			expectError: true,
+		},
+		{
+			name:         "errors within the max rate are healthy",
+			url:          server.URL + "/flaky",
+			maxErrorRate: 0.2,
+		},
+		{
+			name:        "requests exceeding the timeout are unhealthy",
+			url:         server.URL + "/slow",
+			timeout:     10 * time.Millisecond,
+			expectError: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			requests.Store(0)
+			err := CanaryProbeHealthCheck{
+				URL:          tc.url,
+				MaxErrorRate: tc.maxErrorRate,
+				Timeout:      tc.timeout,
+			}.Check(context.Background(), canary)
+			if tc.expectError {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, int32(defaultCanaryProbeRequests), requests.Load())
+		})
+	}
+}
@@ -30,3 +30,10 @@ type Client interface {
 	// it to the backend API.
 	Update(ctx context.Context) error
 }
+
+// ConfigChangesNotifier is implemented by Clients which notify about changes of objects the
+// configuration is built from, so that it can be updated as soon as it changes.
+type ConfigChangesNotifier interface {
+	// ConfigChanges returns a channel receiving a notification whenever the configuration changes.
+	ConfigChanges() <-chan struct{}
+}
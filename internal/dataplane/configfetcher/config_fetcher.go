@@ -3,6 +3,7 @@ package configfetcher
 import (
 	"context"
 	"errors"
+	"fmt"
 
 	"github.com/go-logr/logr"
 	"github.com/kong/deck/dump"
@@ -23,7 +24,8 @@ type LastValidConfigFetcher interface {
 	LastValidConfig() (*kongstate.KongState, bool)
 
 	// StoreLastValidConfig stores a given configuration as the last valid config. Should be used when the configuration was successfully accepted by a gateway.
-	StoreLastValidConfig(s *kongstate.KongState)
+	// The returned error indicates the configuration couldn't be persisted, it's still stored in memory in such case.
+	StoreLastValidConfig(ctx context.Context, s *kongstate.KongState) error
 }
 
 type DefaultKongLastGoodConfigFetcher struct {
@@ -33,6 +35,9 @@ type DefaultKongLastGoodConfigFetcher struct {
 	// - Services, Routes, and Consumers - based on their names. It ensures that IDs remain
 	// stable across restarts of the controller.
 	fillIDs bool
+	// storage persists the last valid kongState, so that it's available after restarts of the controller.
+	// It's optional, the last valid kongState is kept only in memory when it's not set.
+	storage LastValidConfigStorage
 }
 
 func NewDefaultKongLastGoodConfigFetcher(fillIDs bool) *DefaultKongLastGoodConfigFetcher {
@@ -42,6 +47,28 @@ func NewDefaultKongLastGoodConfigFetcher(fillIDs bool) *DefaultKongLastGoodConfi
 	}
 }
 
+// EnablePersistence makes the fetcher persist the last valid config in a given storage.
+func (cf *DefaultKongLastGoodConfigFetcher) EnablePersistence(storage LastValidConfigStorage) {
+	cf.storage = storage
+}
+
+// LoadPersistedConfig loads the config persisted in the storage as the last valid config.
+// It's a noop when persistence is not enabled or there's no config persisted.
+func (cf *DefaultKongLastGoodConfigFetcher) LoadPersistedConfig(ctx context.Context, logger logr.Logger) error {
+	if cf.storage == nil {
+		return nil
+	}
+	s, found, err := cf.storage.Load(ctx)
+	if err != nil {
+		return err
+	}
+	if found {
+		cf.lastValidState = s
+		logger.V(util.DebugLevel).Info("last good configuration loaded from storage")
+	}
+	return nil
+}
+
 func (cf *DefaultKongLastGoodConfigFetcher) LastValidConfig() (*kongstate.KongState, bool) {
 	if cf.lastValidState != nil {
 		return cf.lastValidState, true
@@ -49,8 +76,15 @@ func (cf *DefaultKongLastGoodConfigFetcher) LastValidConfig() (*kongstate.KongSt
 	return nil, false
 }
 
-func (cf *DefaultKongLastGoodConfigFetcher) StoreLastValidConfig(s *kongstate.KongState) {
+func (cf *DefaultKongLastGoodConfigFetcher) StoreLastValidConfig(ctx context.Context, s *kongstate.KongState) error {
 	cf.lastValidState = s
+	if cf.storage == nil {
+		return nil
+	}
+	if err := cf.storage.Save(ctx, s); err != nil {
+		return fmt.Errorf("failed to persist last valid configuration: %w", err)
+	}
+	return nil
 }
 
 func (cf *DefaultKongLastGoodConfigFetcher) TryFetchingValidConfigFromGateways(
@@ -0,0 +1,235 @@
+package configfetcher
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8stypes "k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/kong/kubernetes-ingress-controller/v2/internal/dataplane/kongstate"
+)
+
+const (
+	// LastValidConfigSecretKey is the key of the Secret's data the last valid configuration is stored under.
+	LastValidConfigSecretKey = "config.json.gz.enc"
+
+	// LastValidConfigEncryptionKeySize is the size of the key the last valid configuration is encrypted with.
+	LastValidConfigEncryptionKeySize = 32
+
+	// maxSecretDataSize is the maximum size of a Secret's data accepted by Kubernetes.
+	maxSecretDataSize = 1024 * 1024
+)
+
+// LastValidConfigStorage persists the last valid configuration, so that it survives restarts of the controller.
+type LastValidConfigStorage interface {
+	// Load returns the persisted configuration and true if there's one. Otherwise, second return value is false.
+	Load(ctx context.Context) (*kongstate.KongState, bool, error)
+
+	// Save persists a given configuration, replacing the previously persisted one.
+	Save(ctx context.Context, s *kongstate.KongState) error
+}
+
+// SecretLastValidConfigStorage persists the last valid configuration as gzipped JSON in a Secret.
+// Credentials, certificates' keys and plugins' configuration are required to apply the configuration,
+// so it's encrypted with AES-256-GCM instead of being redacted. The encryption key should be kept
+// separately from the Secret, so that access to the Secret alone doesn't reveal them.
+type SecretLastValidConfigStorage struct {
+	client client.Client
+	nn     k8stypes.NamespacedName
+	aead   cipher.AEAD
+
+	// lastSavedSum is the checksum of the data saved most recently. It allows skipping updates
+	// of the Secret when the configuration doesn't change.
+	lastSavedSum [sha256.Size]byte
+}
+
+// NewSecretLastValidConfigStorage creates a SecretLastValidConfigStorage persisting the configuration
+// in the Secret of a given namespaced name, encrypted with a given key of LastValidConfigEncryptionKeySize bytes.
+func NewSecretLastValidConfigStorage(
+	c client.Client, nn k8stypes.NamespacedName, encryptionKey []byte,
+) (*SecretLastValidConfigStorage, error) {
+	if len(encryptionKey) != LastValidConfigEncryptionKeySize {
+		return nil, fmt.Errorf("encryption key must be %d bytes long, got %d bytes", LastValidConfigEncryptionKeySize, len(encryptionKey))
+	}
+	block, err := aes.NewCipher(encryptionKey)
+	if err != nil {
+		return nil, err
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	return &SecretLastValidConfigStorage{
+		client: c,
+		nn:     nn,
+		aead:   aead,
+	}, nil
+}
+
+func (s *SecretLastValidConfigStorage) Load(ctx context.Context) (*kongstate.KongState, bool, error) {
+	var secret corev1.Secret
+	if err := s.client.Get(ctx, s.nn, &secret); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, false, nil
+		}
+		return nil, false, fmt.Errorf("failed to get Secret %s: %w", s.nn, err)
+	}
+	data, ok := secret.Data[LastValidConfigSecretKey]
+	if !ok {
+		return nil, false, nil
+	}
+
+	data, err := s.decrypt(data)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to decrypt configuration stored in Secret %s: %w", s.nn, err)
+	}
+	state, err := decodeKongState(data)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to decode configuration stored in Secret %s: %w", s.nn, err)
+	}
+	s.lastSavedSum = sha256.Sum256(data)
+	return state, true, nil
+}
+
+func (s *SecretLastValidConfigStorage) Save(ctx context.Context, state *kongstate.KongState) error {
+	data, err := encodeKongState(state)
+	if err != nil {
+		return fmt.Errorf("failed to encode configuration: %w", err)
+	}
+	// The checksum is calculated before encrypting, as encrypting the same data twice gives different results.
+	sum := sha256.Sum256(data)
+	if sum == s.lastSavedSum {
+		return nil
+	}
+	data, err = s.encrypt(data)
+	if err != nil {
+		return fmt.Errorf("failed to encrypt configuration: %w", err)
+	}
+	if len(data) > maxSecretDataSize {
+		return fmt.Errorf("encoded configuration size (%d bytes) exceeds the maximum size of a Secret (%d bytes)", len(data), maxSecretDataSize)
+	}
+
+	var secret corev1.Secret
+	err = s.client.Get(ctx, s.nn, &secret)
+	switch {
+	case apierrors.IsNotFound(err):
+		secret = corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      s.nn.Name,
+				Namespace: s.nn.Namespace,
+			},
+			Type: corev1.SecretTypeOpaque,
+			Data: map[string][]byte{LastValidConfigSecretKey: data},
+		}
+		if err := s.client.Create(ctx, &secret); err != nil {
+			return fmt.Errorf("failed to create Secret %s: %w", s.nn, err)
+		}
+	case err != nil:
+		return fmt.Errorf("failed to get Secret %s: %w", s.nn, err)
+	default:
+		if secret.Data == nil {
+			secret.Data = map[string][]byte{}
+		}
+		secret.Data[LastValidConfigSecretKey] = data
+		if err := s.client.Update(ctx, &secret); err != nil {
+			return fmt.Errorf("failed to update Secret %s: %w", s.nn, err)
+		}
+	}
+
+	s.lastSavedSum = sum
+	return nil
+}
+
+// encrypt encrypts data with a random nonce prepended to the result. The Secret's name is authenticated
+// along with the data, so that it can't be decrypted from another Secret.
+func (s *SecretLastValidConfigStorage) encrypt(data []byte) ([]byte, error) {
+	nonce := make([]byte, s.aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	return s.aead.Seal(nonce, nonce, data, []byte(s.nn.String())), nil
+}
+
+// decrypt decrypts data encrypted with encrypt.
+func (s *SecretLastValidConfigStorage) decrypt(data []byte) ([]byte, error) {
+	if len(data) < s.aead.NonceSize() {
+		return nil, errors.New("data is too short")
+	}
+	nonce, ciphertext := data[:s.aead.NonceSize()], data[s.aead.NonceSize():]
+	return s.aead.Open(nil, nonce, ciphertext, []byte(s.nn.String()))
+}
+
+// encodeKongState encodes a KongState as gzipped JSON.
+func encodeKongState(s *kongstate.KongState) ([]byte, error) {
+	var buf bytes.Buffer
+	w := gzip.NewWriter(&buf)
+	if err := json.NewEncoder(w).Encode(persistableCopy(s)); err != nil {
+		return nil, err
+	}
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// decodeKongState decodes a KongState encoded with encodeKongState.
+func decodeKongState(data []byte) (*kongstate.KongState, error) {
+	r, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+	decompressed, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	var s kongstate.KongState
+	if err := json.Unmarshal(decompressed, &s); err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
+
+// persistableCopy returns a shallow copy of a KongState without the Kubernetes objects its Services and
+// Plugins were translated from. They're only used during translation, and the ones stored as interfaces
+// can't be decoded.
+func persistableCopy(s *kongstate.KongState) *kongstate.KongState {
+	c := *s
+	if s.Services != nil {
+		c.Services = make([]kongstate.Service, len(s.Services))
+		for i, service := range s.Services {
+			service.Parent = nil
+			service.K8sServices = nil
+			c.Services[i] = service
+		}
+	}
+	if s.Upstreams != nil {
+		c.Upstreams = make([]kongstate.Upstream, len(s.Upstreams))
+		for i, upstream := range s.Upstreams {
+			upstream.Service.Parent = nil
+			upstream.Service.K8sServices = nil
+			c.Upstreams[i] = upstream
+		}
+	}
+	if s.Plugins != nil {
+		c.Plugins = make([]kongstate.Plugin, len(s.Plugins))
+		for i, plugin := range s.Plugins {
+			plugin.K8sParent = nil
+			c.Plugins[i] = plugin
+		}
+	}
+	return &c
+}
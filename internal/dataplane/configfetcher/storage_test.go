@@ -0,0 +1,197 @@
+package configfetcher
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/go-logr/logr"
+	"github.com/kong/go-kong/kong"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	netv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8stypes "k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/kong/kubernetes-ingress-controller/v2/internal/dataplane/deckgen"
+	"github.com/kong/kubernetes-ingress-controller/v2/internal/dataplane/kongstate"
+	kongv1 "github.com/kong/kubernetes-ingress-controller/v2/pkg/apis/configuration/v1"
+)
+
+var testEncryptionKey = bytes.Repeat([]byte{1}, LastValidConfigEncryptionKeySize)
+
+func mustSecretLastValidConfigStorage(
+	t *testing.T, c client.Client, nn k8stypes.NamespacedName, encryptionKey []byte,
+) *SecretLastValidConfigStorage {
+	t.Helper()
+	storage, err := NewSecretLastValidConfigStorage(c, nn, encryptionKey)
+	require.NoError(t, err)
+	return storage
+}
+
+func sampleKongState() *kongstate.KongState {
+	ingress := &netv1.Ingress{ObjectMeta: metav1.ObjectMeta{Name: "ingress", Namespace: "default"}}
+	service := kongstate.Service{
+		Service: kong.Service{
+			Name: kong.String("default.svc.80"),
+			Host: kong.String("svc.default.80.svc"),
+			Port: kong.Int(80),
+		},
+		Namespace: "default",
+		Routes: []kongstate.Route{
+			{
+				Route: kong.Route{
+					Name:  kong.String("default.ingress.svc.example.com.80"),
+					Paths: kong.StringSlice("/"),
+				},
+				Plugins: []kong.Plugin{{Name: kong.String("key-auth")}},
+			},
+		},
+		K8sServices: map[string]*corev1.Service{
+			"default/svc": {ObjectMeta: metav1.ObjectMeta{Name: "svc", Namespace: "default"}},
+		},
+		Parent: ingress,
+	}
+	return &kongstate.KongState{
+		Services: []kongstate.Service{service},
+		Upstreams: []kongstate.Upstream{
+			{
+				Upstream: kong.Upstream{Name: kong.String("svc.default.80.svc")},
+				Targets:  []kongstate.Target{{Target: kong.Target{Target: kong.String("10.0.0.1:80")}}},
+				Service:  service,
+			},
+		},
+		Certificates: []kongstate.Certificate{
+			{Certificate: kong.Certificate{Cert: kong.String("cert"), Key: kong.String("key")}},
+		},
+		Plugins: []kongstate.Plugin{
+			{
+				Plugin: kong.Plugin{
+					Name:   kong.String("rate-limiting"),
+					Config: kong.Configuration{"minute": float64(10)},
+				},
+				K8sParent: &kongv1.KongClusterPlugin{ObjectMeta: metav1.ObjectMeta{Name: "rate-limiting"}},
+			},
+		},
+		Consumers: []kongstate.Consumer{
+			{
+				Consumer: kong.Consumer{Username: kong.String("consumer")},
+				KeyAuths: []*kongstate.KeyAuth{{KeyAuth: kong.KeyAuth{Key: kong.String("secret-key")}}},
+			},
+		},
+	}
+}
+
+func TestSecretLastValidConfigStorage(t *testing.T) {
+	var (
+		ctx      = context.Background()
+		secretNN = k8stypes.NamespacedName{Namespace: "kong", Name: "last-valid-config"}
+	)
+
+	t.Run("nothing is loaded when the Secret doesn't exist", func(t *testing.T) {
+		storage := mustSecretLastValidConfigStorage(t, fake.NewClientBuilder().Build(), secretNN, testEncryptionKey)
+		_, found, err := storage.Load(ctx)
+		require.NoError(t, err)
+		require.False(t, found)
+	})
+
+	t.Run("saved configuration is loaded without changes of its deck content", func(t *testing.T) {
+		kubeClient := fake.NewClientBuilder().Build()
+		state := sampleKongState()
+		require.NoError(t, mustSecretLastValidConfigStorage(t, kubeClient, secretNN, testEncryptionKey).Save(ctx, state))
+
+		loaded, found, err := mustSecretLastValidConfigStorage(t, kubeClient, secretNN, testEncryptionKey).Load(ctx)
+		require.NoError(t, err)
+		require.True(t, found)
+		require.Nil(t, loaded.Services[0].Parent, "objects stored as interfaces are not persisted")
+		require.Nil(t, loaded.Plugins[0].K8sParent, "objects stored as interfaces are not persisted")
+		require.Nil(t, loaded.Services[0].K8sServices, "Kubernetes Services are not persisted")
+		require.Nil(t, loaded.Upstreams[0].Service.K8sServices, "Kubernetes Services are not persisted")
+		require.Equal(t, "secret-key", *loaded.Consumers[0].KeyAuths[0].Key, "credentials should not be redacted")
+
+		params := deckgen.GenerateDeckContentParams{PluginSchemas: pluginsSchemaStoreStub{}}
+		require.Equal(t,
+			deckgen.ToDeckContent(ctx, logr.Discard(), state, params),
+			deckgen.ToDeckContent(ctx, logr.Discard(), loaded, params),
+		)
+		require.NotNil(t, state.Services[0].Parent, "saving should not modify the configuration")
+	})
+
+	t.Run("configuration is encrypted", func(t *testing.T) {
+		kubeClient := fake.NewClientBuilder().Build()
+		require.NoError(t, mustSecretLastValidConfigStorage(t, kubeClient, secretNN, testEncryptionKey).Save(ctx, sampleKongState()))
+
+		var secret corev1.Secret
+		require.NoError(t, kubeClient.Get(ctx, secretNN, &secret))
+		_, err := decodeKongState(secret.Data[LastValidConfigSecretKey])
+		require.Error(t, err, "configuration should not be stored as plain gzipped JSON")
+
+		anotherKey := bytes.Repeat([]byte{2}, LastValidConfigEncryptionKeySize)
+		_, _, err = mustSecretLastValidConfigStorage(t, kubeClient, secretNN, anotherKey).Load(ctx)
+		require.ErrorContains(t, err, "failed to decrypt configuration")
+
+		anotherSecretNN := k8stypes.NamespacedName{Namespace: "kong", Name: "another-secret"}
+		secret.ObjectMeta = metav1.ObjectMeta{Namespace: anotherSecretNN.Namespace, Name: anotherSecretNN.Name}
+		require.NoError(t, kubeClient.Create(ctx, &secret))
+		_, _, err = mustSecretLastValidConfigStorage(t, kubeClient, anotherSecretNN, testEncryptionKey).Load(ctx)
+		require.ErrorContains(t, err, "failed to decrypt configuration", "configuration copied to another Secret should not be decrypted")
+	})
+
+	t.Run("key of invalid size is rejected", func(t *testing.T) {
+		_, err := NewSecretLastValidConfigStorage(fake.NewClientBuilder().Build(), secretNN, []byte("too short"))
+		require.ErrorContains(t, err, "encryption key must be 32 bytes long")
+	})
+
+	t.Run("Secret is updated only when the configuration changes", func(t *testing.T) {
+		kubeClient := fake.NewClientBuilder().Build()
+		storage := mustSecretLastValidConfigStorage(t, kubeClient, secretNN, testEncryptionKey)
+		state := sampleKongState()
+		require.NoError(t, storage.Save(ctx, state))
+
+		getResourceVersion := func() string {
+			var secret corev1.Secret
+			require.NoError(t, kubeClient.Get(ctx, secretNN, &secret))
+			return secret.ResourceVersion
+		}
+		resourceVersion := getResourceVersion()
+
+		require.NoError(t, storage.Save(ctx, sampleKongState()))
+		require.Equal(t, resourceVersion, getResourceVersion(), "Secret should not be updated with the same configuration")
+
+		state.Consumers[0].Username = kong.String("another-consumer")
+		require.NoError(t, storage.Save(ctx, state))
+		require.NotEqual(t, resourceVersion, getResourceVersion(), "Secret should be updated with a changed configuration")
+	})
+}
+
+func TestDefaultKongLastGoodConfigFetcher_Persistence(t *testing.T) {
+	var (
+		ctx        = context.Background()
+		kubeClient = fake.NewClientBuilder().Build()
+		secretNN   = k8stypes.NamespacedName{Namespace: "kong", Name: "last-valid-config"}
+	)
+
+	fetcher := NewDefaultKongLastGoodConfigFetcher(false)
+	fetcher.EnablePersistence(mustSecretLastValidConfigStorage(t, kubeClient, secretNN, testEncryptionKey))
+	require.NoError(t, fetcher.StoreLastValidConfig(ctx, sampleKongState()))
+
+	restartedFetcher := NewDefaultKongLastGoodConfigFetcher(false)
+	restartedFetcher.EnablePersistence(mustSecretLastValidConfigStorage(t, kubeClient, secretNN, testEncryptionKey))
+	_, found := restartedFetcher.LastValidConfig()
+	require.False(t, found, "nothing should be available before loading the persisted configuration")
+
+	require.NoError(t, restartedFetcher.LoadPersistedConfig(ctx, logr.Discard()))
+	s, found := restartedFetcher.LastValidConfig()
+	require.True(t, found)
+	require.Equal(t, "consumer", *s.Consumers[0].Username)
+}
+
+// pluginsSchemaStoreStub is a stub implementation of the deckgen.PluginSchemaStore interface that returns an empty
+// schema for all plugins.
+type pluginsSchemaStoreStub struct{}
+
+func (p pluginsSchemaStoreStub) Schema(context.Context, string) (map[string]interface{}, error) {
+	return map[string]interface{}{}, nil
+}
@@ -0,0 +1,46 @@
+package deckgen
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/kong/kubernetes-ingress-controller/v2/internal/dataplane/kongstate"
+)
+
+// CustomEntities are Kong entities that decK's file format has no place for (e.g. credentials of types
+// provided by plugins unknown to the controller), keyed by the entity name. Only DB-less gateways accept
+// them, as top-level entities of their declarative configuration.
+type CustomEntities map[string][]map[string]interface{}
+
+// ToCustomEntities generates custom entities from `k8sState`.
+func ToCustomEntities(k8sState *kongstate.KongState) CustomEntities {
+	consumers := make([]kongstate.Consumer, 0, len(k8sState.Consumers))
+	for _, c := range k8sState.Consumers {
+		// Consumers with no username are not generated by ToDeckContent, so their credentials can't be either.
+		if c.Username != nil && len(c.CustomCredentials) > 0 {
+			consumers = append(consumers, c)
+		}
+	}
+	if len(consumers) == 0 {
+		return nil
+	}
+	sort.SliceStable(consumers, func(i, j int) bool {
+		return strings.Compare(*consumers[i].Username, *consumers[j].Username) > 0
+	})
+
+	entities := make(CustomEntities)
+	for _, c := range consumers {
+		for _, cred := range c.CustomCredentials {
+			entity := make(map[string]interface{}, len(cred.Config)+2)
+			for k, v := range cred.Config {
+				entity[k] = v
+			}
+			entity["consumer"] = *c.Username
+			if len(cred.Tags) > 0 {
+				entity["tags"] = cred.Tags
+			}
+			entities[cred.Entity] = append(entities[cred.Entity], entity)
+		}
+	}
+	return entities
+}
@@ -10,13 +10,20 @@ import (
 	"github.com/kong/go-kong/kong"
 )
 
-// GenerateSHA generates a SHA256 checksum of targetContent, with the purpose
+// GenerateSHA generates a SHA256 checksum of targetContent and customEntities, with the purpose
 // of change detection.
-func GenerateSHA(targetContent *file.Content) ([]byte, error) {
+func GenerateSHA(targetContent *file.Content, customEntities CustomEntities) ([]byte, error) {
 	jsonConfig, err := gojson.Marshal(targetContent)
 	if err != nil {
 		return nil, fmt.Errorf("marshaling Kong declarative configuration to JSON: %w", err)
 	}
+	if len(customEntities) > 0 {
+		jsonCustomEntities, err := gojson.Marshal(customEntities)
+		if err != nil {
+			return nil, fmt.Errorf("marshaling custom entities to JSON: %w", err)
+		}
+		jsonConfig = append(jsonConfig, jsonCustomEntities...)
+	}
 
 	shaSum := sha256.Sum256(jsonConfig)
 	return shaSum[:], nil
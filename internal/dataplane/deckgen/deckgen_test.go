@@ -19,7 +19,7 @@ func BenchmarkDeckgenGenerateSHA(b *testing.B) {
 	b.ResetTimer()
 
 	for i := 0; i < b.N; i++ {
-		bb, err := deckgen.GenerateSHA(&targetContent)
+		bb, err := deckgen.GenerateSHA(&targetContent, nil)
 		require.NoError(b, err)
 		_ = bb
 	}
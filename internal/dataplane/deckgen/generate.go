@@ -102,6 +102,13 @@ func ToDeckContent(
 
 	for _, cg := range k8sState.ConsumerGroups {
 		consumerGroup := file.FConsumerGroupObject{ConsumerGroup: cg.ConsumerGroup}
+		for _, p := range cg.Plugins {
+			p := p
+			consumerGroup.Plugins = append(consumerGroup.Plugins, &p)
+		}
+		sort.SliceStable(consumerGroup.Plugins, func(i, j int) bool {
+			return strings.Compare(*consumerGroup.Plugins[i].Name, *consumerGroup.Plugins[j].Name) > 0
+		})
 		content.ConsumerGroups = append(content.ConsumerGroups, consumerGroup)
 	}
 	sort.SliceStable(content.ConsumerGroups, func(i, j int) bool {
@@ -48,6 +48,33 @@ func TestToDeckContent(t *testing.T) {
 				},
 			},
 		},
+		{
+			name:   "consumer group with plugins",
+			params: deckgen.GenerateDeckContentParams{},
+			input: &kongstate.KongState{
+				ConsumerGroups: []kongstate.ConsumerGroup{
+					{
+						ConsumerGroup: kong.ConsumerGroup{Name: kong.String("gold")},
+						Plugins: []kong.ConsumerGroupPlugin{
+							{Name: kong.String("proxy-cache-advanced")},
+							{Name: kong.String("rate-limiting-advanced"), Config: kong.Configuration{"limit": []int{1000}}},
+						},
+					},
+				},
+			},
+			expected: &file.Content{
+				FormatVersion: versions.DeckFileFormatVersion,
+				ConsumerGroups: []file.FConsumerGroupObject{
+					{
+						ConsumerGroup: kong.ConsumerGroup{Name: kong.String("gold")},
+						Plugins: []*kong.ConsumerGroupPlugin{
+							{Name: kong.String("rate-limiting-advanced"), Config: kong.Configuration{"limit": []int{1000}}},
+							{Name: kong.String("proxy-cache-advanced")},
+						},
+					},
+				},
+			},
+		},
 	}
 
 	for _, tc := range testCases {
@@ -57,3 +84,42 @@ func TestToDeckContent(t *testing.T) {
 		})
 	}
 }
+
+func TestToCustomEntities(t *testing.T) {
+	customCredential := func(key string) *kongstate.CustomCredential {
+		return &kongstate.CustomCredential{
+			Entity: "keyauth_enc_credentials",
+			Config: map[string]interface{}{"key": key},
+			Tags:   []*string{kong.String("k8s-name:" + key)},
+		}
+	}
+	input := &kongstate.KongState{
+		Consumers: []kongstate.Consumer{
+			{
+				Consumer:          kong.Consumer{Username: kong.String("alice")},
+				CustomCredentials: []*kongstate.CustomCredential{customCredential("alice-key")},
+			},
+			{
+				Consumer: kong.Consumer{Username: kong.String("bob")},
+				KeyAuths: []*kongstate.KeyAuth{{KeyAuth: kong.KeyAuth{Key: kong.String("bob-plain-key")}}},
+				CustomCredentials: []*kongstate.CustomCredential{
+					customCredential("bob-key"),
+					customCredential("bob-other-key"),
+				},
+			},
+			{
+				Consumer:          kong.Consumer{CustomID: kong.String("no-username")},
+				CustomCredentials: []*kongstate.CustomCredential{customCredential("skipped-key")},
+			},
+		},
+	}
+
+	require.Equal(t, deckgen.CustomEntities{
+		"keyauth_enc_credentials": {
+			{"consumer": "bob", "key": "bob-key", "tags": []*string{kong.String("k8s-name:bob-key")}},
+			{"consumer": "bob", "key": "bob-other-key", "tags": []*string{kong.String("k8s-name:bob-other-key")}},
+			{"consumer": "alice", "key": "alice-key", "tags": []*string{kong.String("k8s-name:alice-key")}},
+		},
+	}, deckgen.ToCustomEntities(input))
+	require.Nil(t, deckgen.ToCustomEntities(&kongstate.KongState{}))
+}
@@ -0,0 +1,170 @@
+package fallback
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/samber/mo"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/kong/kubernetes-ingress-controller/v2/internal/store"
+	k8sobj "github.com/kong/kubernetes-ingress-controller/v2/internal/util/kubernetes/object"
+)
+
+// ExcludedObject is an object whose current version has been excluded from the fallback configuration.
+type ExcludedObject struct {
+	// Object is the current version of the object.
+	Object client.Object
+
+	// Status tells how the object is represented in the fallback configuration.
+	Status k8sobj.FallbackStatus
+
+	// CausingObject is the excluded object the object depends on. It's nil when the object
+	// has been rejected by Kong itself.
+	CausingObject client.Object
+}
+
+// objectKey identifies an object regardless of its API version.
+type objectKey struct {
+	groupKind schema.GroupKind
+	namespace string
+	name      string
+}
+
+func objectKeyFor(obj client.Object) objectKey {
+	return objectKey{
+		groupKind: obj.GetObjectKind().GroupVersionKind().GroupKind(),
+		namespace: obj.GetNamespace(),
+		name:      obj.GetName(),
+	}
+}
+
+func (k objectKey) String() string {
+	if k.namespace == "" {
+		return fmt.Sprintf("%s %s", k.groupKind.Kind, k.name)
+	}
+	return fmt.Sprintf("%s %s/%s", k.groupKind.Kind, k.namespace, k.name)
+}
+
+// GenerateCacheStores generates CacheStores to build the fallback configuration from, given the current
+// CacheStores, the CacheStores the last valid configuration was built from (if any) and the objects Kong
+// rejected. The rejected objects are replaced by their last valid versions when available. Otherwise,
+// they are left out of the configuration along with the objects depending on them, which in turn
+// are replaced by their last valid versions or left out.
+//
+// The returned excluded objects are sorted by kind, namespace and name.
+func GenerateCacheStores(
+	current store.CacheStores,
+	lastValid mo.Option[store.CacheStores],
+	brokenObjects []client.Object,
+) (store.CacheStores, []ExcludedObject, error) {
+	fallbackCache, err := current.TakeSnapshot()
+	if err != nil {
+		return store.CacheStores{}, nil, fmt.Errorf("failed to take a snapshot of the cache: %w", err)
+	}
+
+	currentObjects, err := indexObjects(current)
+	if err != nil {
+		return store.CacheStores{}, nil, err
+	}
+	lastValidObjects := map[objectKey]client.Object{}
+	if lastValidCache, ok := lastValid.Get(); ok {
+		if lastValidObjects, err = indexObjects(lastValidCache); err != nil {
+			return store.CacheStores{}, nil, err
+		}
+	}
+	dependents := indexDependents(currentObjects)
+
+	type exclusion struct {
+		key   objectKey
+		cause client.Object
+	}
+	queue := make([]exclusion, 0, len(brokenObjects))
+	for _, obj := range brokenObjects {
+		queue = append(queue, exclusion{key: objectKeyFor(obj)})
+	}
+
+	excluded := map[objectKey]ExcludedObject{}
+	for len(queue) > 0 {
+		next := queue[0]
+		queue = queue[1:]
+		if _, ok := excluded[next.key]; ok {
+			continue
+		}
+		obj, ok := currentObjects[next.key]
+		if !ok {
+			// The object is gone from the cache already, there's nothing to exclude.
+			continue
+		}
+
+		// The last valid version can only help when it differs from the current one.
+		if lastValidObj, ok := lastValidObjects[next.key]; ok && lastValidObj.GetResourceVersion() != obj.GetResourceVersion() {
+			if err := fallbackCache.Add(lastValidObj); err != nil {
+				return store.CacheStores{}, nil, fmt.Errorf("failed to restore the last valid version of %s: %w", next.key, err)
+			}
+			excluded[next.key] = ExcludedObject{Object: obj, Status: k8sobj.FallbackStatusLastValid, CausingObject: next.cause}
+			continue
+		}
+
+		if err := fallbackCache.Delete(obj); err != nil {
+			return store.CacheStores{}, nil, fmt.Errorf("failed to remove %s: %w", next.key, err)
+		}
+		excluded[next.key] = ExcludedObject{Object: obj, Status: k8sobj.FallbackStatusRemoved, CausingObject: next.cause}
+		for _, dependent := range dependents[next.key] {
+			queue = append(queue, exclusion{key: dependent, cause: obj})
+		}
+	}
+
+	excludedObjects := make([]ExcludedObject, 0, len(excluded))
+	for _, e := range excluded {
+		excludedObjects = append(excludedObjects, e)
+	}
+	sort.Slice(excludedObjects, func(i, j int) bool {
+		return lessObjectKeys(objectKeyFor(excludedObjects[i].Object), objectKeyFor(excludedObjects[j].Object))
+	})
+	return fallbackCache, excludedObjects, nil
+}
+
+// indexObjects returns all the objects stored in the CacheStores by their keys.
+func indexObjects(cs store.CacheStores) (map[objectKey]client.Object, error) {
+	objects := map[objectKey]client.Object{}
+	for _, s := range cs.ListAllStores() {
+		for _, item := range s.List() {
+			obj, ok := item.(client.Object)
+			if !ok {
+				return nil, fmt.Errorf("%T is not a client.Object", item)
+			}
+			objects[objectKeyFor(obj)] = obj
+		}
+	}
+	return objects, nil
+}
+
+// indexDependents returns keys of the objects depending on each of the objects, sorted for the result
+// to be deterministic.
+func indexDependents(objects map[objectKey]client.Object) map[objectKey][]objectKey {
+	dependents := map[objectKey][]objectKey{}
+	for key, obj := range objects {
+		for _, ref := range referencedObjects(obj) {
+			dependents[ref] = append(dependents[ref], key)
+		}
+	}
+	for _, keys := range dependents {
+		sort.Slice(keys, func(i, j int) bool { return lessObjectKeys(keys[i], keys[j]) })
+	}
+	return dependents
+}
+
+func lessObjectKeys(a, b objectKey) bool {
+	if a.groupKind.Kind != b.groupKind.Kind {
+		return a.groupKind.Kind < b.groupKind.Kind
+	}
+	if a.groupKind.Group != b.groupKind.Group {
+		return a.groupKind.Group < b.groupKind.Group
+	}
+	if a.namespace != b.namespace {
+		return a.namespace < b.namespace
+	}
+	return a.name < b.name
+}
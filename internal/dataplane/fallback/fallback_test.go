@@ -0,0 +1,358 @@
+package fallback
+
+import (
+	"testing"
+
+	"github.com/samber/lo"
+	"github.com/samber/mo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	netv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/kong/kubernetes-ingress-controller/v2/internal/annotations"
+	"github.com/kong/kubernetes-ingress-controller/v2/internal/gatewayapi"
+	"github.com/kong/kubernetes-ingress-controller/v2/internal/store"
+	k8sobj "github.com/kong/kubernetes-ingress-controller/v2/internal/util/kubernetes/object"
+	kongv1 "github.com/kong/kubernetes-ingress-controller/v2/pkg/apis/configuration/v1"
+	kongv1beta1 "github.com/kong/kubernetes-ingress-controller/v2/pkg/apis/configuration/v1beta1"
+)
+
+func newIngress(name, resourceVersion string, plugins ...string) *netv1.Ingress {
+	ing := &netv1.Ingress{
+		TypeMeta: metav1.TypeMeta{APIVersion: "networking.k8s.io/v1", Kind: "Ingress"},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            name,
+			Namespace:       "default",
+			ResourceVersion: resourceVersion,
+		},
+		Spec: netv1.IngressSpec{
+			DefaultBackend: &netv1.IngressBackend{
+				Service: &netv1.IngressServiceBackend{Name: name},
+			},
+		},
+	}
+	if len(plugins) > 0 {
+		ing.Annotations = map[string]string{
+			annotations.AnnotationPrefix + annotations.PluginsKey: plugins[0],
+		}
+	}
+	return ing
+}
+
+func newService(name, resourceVersion string) *corev1.Service {
+	return &corev1.Service{
+		TypeMeta: metav1.TypeMeta{APIVersion: "v1", Kind: "Service"},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            name,
+			Namespace:       "default",
+			ResourceVersion: resourceVersion,
+		},
+	}
+}
+
+func newKongPlugin(name, resourceVersion string) *kongv1.KongPlugin {
+	return &kongv1.KongPlugin{
+		TypeMeta: metav1.TypeMeta{APIVersion: kongv1.GroupVersion.String(), Kind: "KongPlugin"},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            name,
+			Namespace:       "default",
+			ResourceVersion: resourceVersion,
+		},
+		PluginName: "key-auth",
+	}
+}
+
+func newCacheStores(t *testing.T, objs ...client.Object) store.CacheStores {
+	cs := store.NewCacheStores()
+	for _, obj := range objs {
+		require.NoError(t, cs.Add(obj))
+	}
+	return cs
+}
+
+// brokenObject returns an object identifying obj the way objects rejected by Kong are identified.
+func brokenObject(obj client.Object) client.Object {
+	return &metav1.PartialObjectMetadata{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: obj.GetObjectKind().GroupVersionKind().GroupVersion().String(),
+			Kind:       obj.GetObjectKind().GroupVersionKind().Kind,
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      obj.GetName(),
+			Namespace: obj.GetNamespace(),
+		},
+	}
+}
+
+func TestGenerateCacheStores(t *testing.T) {
+	type expectedExclusion struct {
+		name          string
+		status        k8sobj.FallbackStatus
+		causingObject string
+	}
+
+	testCases := []struct {
+		name               string
+		current            []client.Object
+		lastValid          []client.Object
+		broken             []client.Object
+		expectedExclusions []expectedExclusion
+		expectedIngresses  map[string]string
+		expectedPlugins    map[string]string
+		expectedServices   map[string]string
+	}{
+		{
+			name: "broken object without last valid version is removed",
+			current: []client.Object{
+				newIngress("foo", "2"),
+				newIngress("bar", "1"),
+				newService("foo", "1"),
+			},
+			broken: []client.Object{newIngress("foo", "2")},
+			expectedExclusions: []expectedExclusion{
+				{name: "foo", status: k8sobj.FallbackStatusRemoved},
+			},
+			expectedIngresses: map[string]string{"bar": "1"},
+			expectedServices:  map[string]string{"foo": "1"},
+		},
+		{
+			name: "broken object is replaced by its last valid version",
+			current: []client.Object{
+				newIngress("foo", "2"),
+				newIngress("bar", "2"),
+			},
+			lastValid: []client.Object{
+				newIngress("foo", "1"),
+				newIngress("bar", "1"),
+			},
+			broken: []client.Object{newIngress("foo", "2")},
+			expectedExclusions: []expectedExclusion{
+				{name: "foo", status: k8sobj.FallbackStatusLastValid},
+			},
+			expectedIngresses: map[string]string{"foo": "1", "bar": "2"},
+		},
+		{
+			name: "broken object whose last valid version is the current one is removed",
+			current: []client.Object{
+				newIngress("foo", "1"),
+			},
+			lastValid: []client.Object{
+				newIngress("foo", "1"),
+			},
+			broken: []client.Object{newIngress("foo", "1")},
+			expectedExclusions: []expectedExclusion{
+				{name: "foo", status: k8sobj.FallbackStatusRemoved},
+			},
+			expectedIngresses: map[string]string{},
+		},
+		{
+			name: "objects depending on a removed object are excluded as well",
+			current: []client.Object{
+				newKongPlugin("auth", "1"),
+				newIngress("foo", "2", "auth"),
+				newIngress("bar", "1", "auth"),
+				newIngress("baz", "1"),
+				newService("foo", "1"),
+			},
+			lastValid: []client.Object{
+				newIngress("foo", "1", "auth"),
+			},
+			broken: []client.Object{newKongPlugin("auth", "1")},
+			expectedExclusions: []expectedExclusion{
+				{name: "bar", status: k8sobj.FallbackStatusRemoved, causingObject: "auth"},
+				{name: "foo", status: k8sobj.FallbackStatusLastValid, causingObject: "auth"},
+				{name: "auth", status: k8sobj.FallbackStatusRemoved},
+			},
+			expectedIngresses: map[string]string{"foo": "1", "baz": "1"},
+			expectedPlugins:   map[string]string{},
+			expectedServices:  map[string]string{"foo": "1"},
+		},
+		{
+			name: "broken object restored to its last valid version doesn't affect its dependents",
+			current: []client.Object{
+				newKongPlugin("auth", "2"),
+				newIngress("foo", "1", "auth"),
+			},
+			lastValid: []client.Object{
+				newKongPlugin("auth", "1"),
+			},
+			broken: []client.Object{newKongPlugin("auth", "2")},
+			expectedExclusions: []expectedExclusion{
+				{name: "auth", status: k8sobj.FallbackStatusLastValid},
+			},
+			expectedIngresses: map[string]string{"foo": "1"},
+			expectedPlugins:   map[string]string{"auth": "1"},
+		},
+		{
+			name: "objects depending on a removed service are excluded transitively",
+			current: []client.Object{
+				newService("foo", "1"),
+				newIngress("foo", "1"),
+			},
+			broken: []client.Object{newService("foo", "1"), newService("foo", "1")},
+			expectedExclusions: []expectedExclusion{
+				{name: "foo", status: k8sobj.FallbackStatusRemoved, causingObject: "foo"},
+				{name: "foo", status: k8sobj.FallbackStatusRemoved},
+			},
+			expectedIngresses: map[string]string{},
+			expectedServices:  map[string]string{},
+		},
+		{
+			name: "broken objects missing from the cache are ignored",
+			current: []client.Object{
+				newIngress("foo", "1"),
+			},
+			broken:             []client.Object{newIngress("bar", "1")},
+			expectedExclusions: []expectedExclusion{},
+			expectedIngresses:  map[string]string{"foo": "1"},
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			current := newCacheStores(t, tc.current...)
+			lastValid := mo.None[store.CacheStores]()
+			if tc.lastValid != nil {
+				lastValid = mo.Some(newCacheStores(t, tc.lastValid...))
+			}
+			broken := lo.Map(tc.broken, func(obj client.Object, _ int) client.Object { return brokenObject(obj) })
+
+			fallbackCache, excluded, err := GenerateCacheStores(current, lastValid, broken)
+			require.NoError(t, err)
+
+			exclusions := lo.Map(excluded, func(e ExcludedObject, _ int) expectedExclusion {
+				exclusion := expectedExclusion{name: e.Object.GetName(), status: e.Status}
+				if e.CausingObject != nil {
+					exclusion.causingObject = e.CausingObject.GetName()
+				}
+				return exclusion
+			})
+			assert.Equal(t, tc.expectedExclusions, exclusions)
+
+			resourceVersions := func(s cache.Store) map[string]string {
+				versions := map[string]string{}
+				for _, item := range s.List() {
+					obj := item.(client.Object)
+					versions[obj.GetName()] = obj.GetResourceVersion()
+				}
+				return versions
+			}
+			if tc.expectedIngresses != nil {
+				assert.Equal(t, tc.expectedIngresses, resourceVersions(fallbackCache.IngressV1))
+			}
+			if tc.expectedPlugins != nil {
+				assert.Equal(t, tc.expectedPlugins, resourceVersions(fallbackCache.Plugin))
+			}
+			if tc.expectedServices != nil {
+				assert.Equal(t, tc.expectedServices, resourceVersions(fallbackCache.Service))
+			}
+
+			t.Log("verifying that the current cache is left intact")
+			currentObjects := lo.FlatMap(current.ListAllStores(), func(s cache.Store, _ int) []interface{} { return s.List() })
+			assert.Len(t, currentObjects, len(tc.current))
+		})
+	}
+}
+
+func TestReferencedObjects(t *testing.T) {
+	testCases := []struct {
+		name     string
+		obj      client.Object
+		expected []string
+	}{
+		{
+			name: "HTTPRoute",
+			obj: &gatewayapi.HTTPRoute{
+				ObjectMeta: metav1.ObjectMeta{Name: "route", Namespace: "default"},
+				Spec: gatewayapi.HTTPRouteSpec{
+					Rules: []gatewayapi.HTTPRouteRule{
+						{
+							Filters: []gatewayapi.HTTPRouteFilter{
+								{
+									Type: gatewayapi.HTTPRouteFilterExtensionRef,
+									ExtensionRef: &gatewayapi.LocalObjectReference{
+										Group: gatewayapi.Group(kongv1.GroupVersion.Group),
+										Kind:  "KongPlugin",
+										Name:  "auth",
+									},
+								},
+							},
+							BackendRefs: []gatewayapi.HTTPBackendRef{
+								{
+									BackendRef: gatewayapi.BackendRef{
+										BackendObjectReference: gatewayapi.BackendObjectReference{
+											Name: "foo",
+										},
+									},
+								},
+								{
+									BackendRef: gatewayapi.BackendRef{
+										BackendObjectReference: gatewayapi.BackendObjectReference{
+											Name:      "bar",
+											Namespace: lo.ToPtr(gatewayapi.Namespace("other")),
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			expected: []string{
+				"KongPlugin default/auth",
+				"Service default/foo",
+				"Service other/bar",
+			},
+		},
+		{
+			name: "KongConsumer",
+			obj: &kongv1.KongConsumer{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "consumer",
+					Namespace: "default",
+					Annotations: map[string]string{
+						annotations.AnnotationPrefix + annotations.PluginsKey: "rate-limit",
+					},
+				},
+				Credentials:    []string{"key"},
+				ConsumerGroups: []string{"gold", "other/silver"},
+			},
+			expected: []string{
+				"KongPlugin default/rate-limit",
+				"KongClusterPlugin rate-limit",
+				"Secret default/key",
+				"KongConsumerGroup default/gold",
+				"KongConsumerGroup other/silver",
+			},
+		},
+		{
+			name: "TCPIngress",
+			obj: &kongv1beta1.TCPIngress{
+				ObjectMeta: metav1.ObjectMeta{Name: "tcp", Namespace: "default"},
+				Spec: kongv1beta1.TCPIngressSpec{
+					Rules: []kongv1beta1.IngressRule{
+						{Port: 9000, Backend: kongv1beta1.IngressBackend{ServiceName: "foo", ServicePort: 80}},
+					},
+					TLS: []kongv1beta1.IngressTLS{{SecretName: "cert"}},
+				},
+			},
+			expected: []string{
+				"Service default/foo",
+				"Secret default/cert",
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			refs := lo.Map(referencedObjects(tc.obj), func(k objectKey, _ int) string { return k.String() })
+			assert.Equal(t, tc.expected, refs)
+		})
+	}
+}
@@ -0,0 +1,168 @@
+package fallback
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	netv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/kong/kubernetes-ingress-controller/v2/internal/annotations"
+	"github.com/kong/kubernetes-ingress-controller/v2/internal/dataplane/kongstate"
+	"github.com/kong/kubernetes-ingress-controller/v2/internal/gatewayapi"
+	kongv1 "github.com/kong/kubernetes-ingress-controller/v2/pkg/apis/configuration/v1"
+	kongv1beta1 "github.com/kong/kubernetes-ingress-controller/v2/pkg/apis/configuration/v1beta1"
+)
+
+var (
+	serviceGroupKind           = schema.GroupKind{Group: corev1.GroupName, Kind: "Service"}
+	secretGroupKind            = schema.GroupKind{Group: corev1.GroupName, Kind: "Secret"}
+	kongPluginGroupKind        = schema.GroupKind{Group: kongv1.GroupVersion.Group, Kind: "KongPlugin"}
+	kongClusterPluginGroupKind = schema.GroupKind{Group: kongv1.GroupVersion.Group, Kind: "KongClusterPlugin"}
+	kongIngressGroupKind       = schema.GroupKind{Group: kongv1.GroupVersion.Group, Kind: "KongIngress"}
+	kongConsumerGroupGroupKind = schema.GroupKind{Group: kongv1beta1.GroupVersion.Group, Kind: "KongConsumerGroup"}
+)
+
+// referencedObjects returns keys of the objects the object references, whose absence changes how the object
+// is configured in Kong (e.g. a route without its authentication plugin).
+func referencedObjects(obj client.Object) []objectKey {
+	namespace := obj.GetNamespace()
+	refs := pluginReferences(namespace, annotations.ExtractKongPluginsFromAnnotations(obj.GetAnnotations()))
+
+	switch o := obj.(type) {
+	case *netv1.Ingress:
+		if o.Spec.DefaultBackend != nil && o.Spec.DefaultBackend.Service != nil {
+			refs = append(refs, objectKey{groupKind: serviceGroupKind, namespace: namespace, name: o.Spec.DefaultBackend.Service.Name})
+		}
+		for _, rule := range o.Spec.Rules {
+			if rule.HTTP == nil {
+				continue
+			}
+			for _, path := range rule.HTTP.Paths {
+				if path.Backend.Service != nil {
+					refs = append(refs, objectKey{groupKind: serviceGroupKind, namespace: namespace, name: path.Backend.Service.Name})
+				}
+			}
+		}
+		for _, tls := range o.Spec.TLS {
+			refs = append(refs, objectKey{groupKind: secretGroupKind, namespace: namespace, name: tls.SecretName})
+		}
+	case *kongv1beta1.TCPIngress:
+		for _, rule := range o.Spec.Rules {
+			refs = append(refs, objectKey{groupKind: serviceGroupKind, namespace: namespace, name: rule.Backend.ServiceName})
+		}
+		for _, tls := range o.Spec.TLS {
+			refs = append(refs, objectKey{groupKind: secretGroupKind, namespace: namespace, name: tls.SecretName})
+		}
+	case *kongv1beta1.UDPIngress:
+		for _, rule := range o.Spec.Rules {
+			refs = append(refs, objectKey{groupKind: serviceGroupKind, namespace: namespace, name: rule.Backend.ServiceName})
+		}
+	case *gatewayapi.HTTPRoute:
+		for _, rule := range o.Spec.Rules {
+			refs = append(refs, extensionRefs(namespace, rule.Filters)...)
+			for _, backendRef := range rule.BackendRefs {
+				refs = append(refs, backendRefs(namespace, backendRef.BackendRef)...)
+				refs = append(refs, extensionRefs(namespace, backendRef.Filters)...)
+			}
+		}
+	case *gatewayapi.GRPCRoute:
+		for _, rule := range o.Spec.Rules {
+			for _, backendRef := range rule.BackendRefs {
+				refs = append(refs, backendRefs(namespace, backendRef.BackendRef)...)
+			}
+		}
+	case *gatewayapi.TCPRoute:
+		for _, rule := range o.Spec.Rules {
+			refs = append(refs, backendRefs(namespace, rule.BackendRefs...)...)
+		}
+	case *gatewayapi.UDPRoute:
+		for _, rule := range o.Spec.Rules {
+			refs = append(refs, backendRefs(namespace, rule.BackendRefs...)...)
+		}
+	case *gatewayapi.TLSRoute:
+		for _, rule := range o.Spec.Rules {
+			refs = append(refs, backendRefs(namespace, rule.BackendRefs...)...)
+		}
+	case *corev1.Service:
+		if name := annotations.ExtractConfigurationName(o.Annotations); name != "" {
+			refs = append(refs, objectKey{groupKind: kongIngressGroupKind, namespace: namespace, name: name})
+		}
+	case *kongv1.KongConsumer:
+		for _, credential := range o.Credentials {
+			refs = append(refs, objectKey{groupKind: secretGroupKind, namespace: namespace, name: credential})
+		}
+		for _, ref := range o.ConsumerGroups {
+			group := kongstate.ConsumerGroupRefFromString(namespace, ref)
+			refs = append(refs, objectKey{groupKind: kongConsumerGroupGroupKind, namespace: group.Namespace, name: group.Name})
+		}
+	case *kongv1.KongPlugin:
+		if o.ConfigFrom != nil {
+			refs = append(refs, objectKey{groupKind: secretGroupKind, namespace: namespace, name: o.ConfigFrom.SecretValue.Secret})
+		}
+		for _, patch := range o.ConfigPatches {
+			refs = append(refs, objectKey{groupKind: secretGroupKind, namespace: namespace, name: patch.ValueFrom.SecretValue.Secret})
+		}
+	case *kongv1.KongClusterPlugin:
+		if o.ConfigFrom != nil {
+			refs = append(refs, objectKey{
+				groupKind: secretGroupKind,
+				namespace: o.ConfigFrom.SecretValue.Namespace,
+				name:      o.ConfigFrom.SecretValue.Secret,
+			})
+		}
+		for _, patch := range o.ConfigPatches {
+			refs = append(refs, objectKey{
+				groupKind: secretGroupKind,
+				namespace: patch.ValueFrom.SecretValue.Namespace,
+				name:      patch.ValueFrom.SecretValue.Secret,
+			})
+		}
+	}
+	return refs
+}
+
+// pluginReferences returns keys of the plugins referenced by the konghq.com/plugins annotation. As the annotation
+// may reference either a KongPlugin or a KongClusterPlugin, both are returned for each name.
+func pluginReferences(namespace string, names []string) []objectKey {
+	refs := make([]objectKey, 0, len(names)*2)
+	for _, name := range names {
+		refs = append(refs,
+			objectKey{groupKind: kongPluginGroupKind, namespace: namespace, name: name},
+			objectKey{groupKind: kongClusterPluginGroupKind, name: name},
+		)
+	}
+	return refs
+}
+
+// extensionRefs returns keys of the KongPlugins referenced by ExtensionRef filters.
+func extensionRefs(namespace string, filters []gatewayapi.HTTPRouteFilter) []objectKey {
+	var refs []objectKey
+	for _, filter := range filters {
+		if filter.ExtensionRef == nil ||
+			string(filter.ExtensionRef.Group) != kongPluginGroupKind.Group ||
+			string(filter.ExtensionRef.Kind) != kongPluginGroupKind.Kind {
+			continue
+		}
+		refs = append(refs, objectKey{groupKind: kongPluginGroupKind, namespace: namespace, name: string(filter.ExtensionRef.Name)})
+	}
+	return refs
+}
+
+// backendRefs returns keys of the Services referenced by Gateway API backend references.
+func backendRefs(namespace string, backendRefs ...gatewayapi.BackendRef) []objectKey {
+	var refs []objectKey
+	for _, backendRef := range backendRefs {
+		if backendRef.Group != nil && string(*backendRef.Group) != serviceGroupKind.Group {
+			continue
+		}
+		if backendRef.Kind != nil && string(*backendRef.Kind) != serviceGroupKind.Kind {
+			continue
+		}
+		backendNamespace := namespace
+		if backendRef.Namespace != nil {
+			backendNamespace = string(*backendRef.Namespace)
+		}
+		refs = append(refs, objectKey{groupKind: serviceGroupKind, namespace: backendNamespace, name: string(backendRef.Name)})
+	}
+	return refs
+}
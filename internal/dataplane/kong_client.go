@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"reflect"
 	"sort"
+	"strings"
 	"sync"
 	"time"
 
@@ -20,6 +21,7 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	k8stypes "k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/client-go/tools/record"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
@@ -28,6 +30,7 @@ import (
 	"github.com/kong/kubernetes-ingress-controller/v2/internal/dataplane/configfetcher"
 	"github.com/kong/kubernetes-ingress-controller/v2/internal/dataplane/deckgen"
 	"github.com/kong/kubernetes-ingress-controller/v2/internal/dataplane/failures"
+	"github.com/kong/kubernetes-ingress-controller/v2/internal/dataplane/fallback"
 	"github.com/kong/kubernetes-ingress-controller/v2/internal/dataplane/kongstate"
 	"github.com/kong/kubernetes-ingress-controller/v2/internal/dataplane/parser"
 	"github.com/kong/kubernetes-ingress-controller/v2/internal/dataplane/sendconfig"
@@ -37,6 +40,7 @@ import (
 	dataplaneutil "github.com/kong/kubernetes-ingress-controller/v2/internal/util/dataplane"
 	k8sobj "github.com/kong/kubernetes-ingress-controller/v2/internal/util/kubernetes/object"
 	"github.com/kong/kubernetes-ingress-controller/v2/internal/util/kubernetes/object/status"
+	kongv1 "github.com/kong/kubernetes-ingress-controller/v2/pkg/apis/configuration/v1"
 )
 
 const (
@@ -46,6 +50,18 @@ const (
 	KongConfigurationTranslationFailedEventReason = "KongConfigurationTranslationFailed"
 	// KongConfigurationApplyFailedEventReason defines an event reason used for creating all config apply resource failure events.
 	KongConfigurationApplyFailedEventReason = "KongConfigurationApplyFailed"
+	// KongConfigurationExcludedEventReason defines an event reason used for creating events for objects excluded from
+	// the fallback configuration.
+	KongConfigurationExcludedEventReason = "KongConfigurationExcluded"
+	// KongConfigurationCanaryAppliedEventReason defines an event reason to tell configuration has been applied to canary gateways.
+	KongConfigurationCanaryAppliedEventReason = "KongConfigurationCanaryApplied"
+	// KongConfigurationCanarySucceededEventReason defines an event reason to tell canary gateways are healthy with new configuration.
+	KongConfigurationCanarySucceededEventReason = "KongConfigurationCanarySucceeded"
+	// KongConfigurationCanaryFailedEventReason defines an event reason to tell canary gateways are unhealthy with new configuration.
+	KongConfigurationCanaryFailedEventReason = "KongConfigurationCanaryFailed"
+	// KongConfigurationCanaryRolledBackEventReason defines an event reason to tell canary gateways have been rolled back
+	// to their previous configuration.
+	KongConfigurationCanaryRolledBackEventReason = "KongConfigurationCanaryRolledBack"
 )
 
 // -----------------------------------------------------------------------------
@@ -55,6 +71,7 @@ const (
 // KongConfigBuilder builds a Kong configuration from a Kubernetes object cache.
 type KongConfigBuilder interface {
 	BuildKongConfig() parser.KongConfigBuildingResult
+	BuildKongConfigFromCache(cache store.CacheStores) parser.KongConfigBuildingResult
 }
 
 // KongClient is a threadsafe high level API client for the Kong data-plane(s)
@@ -85,10 +102,22 @@ type KongClient struct {
 	// lock is used to ensure threadsafety of the KongClient object
 	lock sync.RWMutex
 
+	// updateLock serializes Update() calls. Unlike lock, it's held while waiting for canary gateways
+	// to soak, so that no other configuration is applied in the meantime without blocking readers of the client.
+	updateLock sync.Mutex
+
 	// diagnostic is the client and configuration for reporting diagnostic
 	// information during data-plane update runtime.
 	diagnostic util.ConfigDumpDiagnostic
 
+	// kongResourceFailures are failures of Kubernetes objects whose Kong entities were rejected by
+	// the data-plane(s) during the current Update(). They're reported as diagnostic information.
+	kongResourceFailures []failures.ResourceFailure
+
+	// kongResourceFailuresLock is a mutex for thread-safety of kongResourceFailures, as configuration
+	// is sent to multiple data-planes concurrently.
+	kongResourceFailuresLock sync.Mutex
+
 	// prometheusMetrics is the client for shipping metrics information
 	// updates to the prometheus exporter.
 	prometheusMetrics *metrics.CtrlFuncMetrics
@@ -115,6 +144,10 @@ type KongClient struct {
 	// is actively configured (e.g. to know how to set the object status).
 	kubernetesObjectReportsFilter k8sobj.ConfigurationStatusSet
 
+	// credentialRotations are rotations of KongConsumers' credentials included in the configuration
+	// applied in the most recent successful Update(), by KongConsumer.
+	credentialRotations map[k8stypes.NamespacedName][]k8sobj.CredentialRotation
+
 	// eventRecorder is used to record warning events for resource failures.
 	eventRecorder record.EventRecorder
 
@@ -145,6 +178,30 @@ type KongClient struct {
 
 	// currentConfigStatus is the current status of the configuration synchronisation.
 	currentConfigStatus clients.ConfigStatus
+
+	// configChangesCh is notified whenever objects in the cache change, so that the configuration can be
+	// synchronized without waiting for the next periodic update.
+	configChangesCh chan struct{}
+
+	// fallbackConfigurationEnabled indicates whether the objects rejected by the data-plane(s) are excluded
+	// from the configuration instead of the last valid configuration being applied as a whole.
+	fallbackConfigurationEnabled bool
+
+	// lastValidCacheSnapshot is a snapshot of the cache the last valid configuration has been built from.
+	// It's only kept when fallbackConfigurationEnabled is true.
+	lastValidCacheSnapshot mo.Option[store.CacheStores]
+
+	// canaryRollout configures staged rollouts of configuration to gateways. Configuration is applied to
+	// all gateways at once when it's absent.
+	canaryRollout mo.Option[CanaryRolloutConfig]
+
+	// canaryStates are the configurations most recently applied to canary gateways, by their Admin API URLs.
+	// Canaries are rolled back to them when a new configuration makes them unhealthy.
+	canaryStates map[string]*kongstate.KongState
+
+	// failedCanaryRollout is the configuration that most recently made canary gateways unhealthy. Rolling it
+	// out to canaries again is skipped until the configuration changes.
+	failedCanaryRollout mo.Option[failedCanaryRollout]
 }
 
 // NewKongClient provides a new KongClient object after connecting to the
@@ -180,6 +237,7 @@ func NewKongClient(
 		configChangeDetector:   configChangeDetector,
 		kongConfigBuilder:      parser,
 		kongConfigFetcher:      kongConfigFetcher,
+		configChangesCh:        make(chan struct{}, 1),
 	}
 	c.initializeControllerPodReference()
 
@@ -203,9 +261,24 @@ func (c *KongClient) initializeControllerPodReference() {
 // It will be asynchronously converted into the upstream Kong DSL and applied to the Kong Admin API.
 // A status will later be added to the object whether the configuration update succeeds or fails.
 func (c *KongClient) UpdateObject(obj client.Object) error {
+	// objects are updated on every reconciliation, also when they haven't changed since
+	// the previous one, so only changes of their resource version are notified.
+	changed := true
+	if cached, exists, err := c.cache.Get(obj); err == nil && exists {
+		if cachedObj, ok := cached.(client.Object); ok {
+			changed = obj.GetResourceVersion() == "" || cachedObj.GetResourceVersion() != obj.GetResourceVersion()
+		}
+	}
+
 	// we do a deep copy of the object here so that the caller can continue to use
 	// the original object in a threadsafe manner.
-	return c.cache.Add(obj.DeepCopyObject())
+	if err := c.cache.Add(obj.DeepCopyObject()); err != nil {
+		return err
+	}
+	if changed {
+		c.notifyConfigChanged()
+	}
+	return nil
 }
 
 // DeleteObject accepts a Kubernetes controller-runtime client.Object and removes it from the configuration cache.
@@ -215,7 +288,24 @@ func (c *KongClient) UpdateObject(obj client.Object) error {
 // under the hood the cache implementation will ignore deletions on objects
 // that are not present in the cache, so in those cases this is a no-op.
 func (c *KongClient) DeleteObject(obj client.Object) error {
-	return c.cache.Delete(obj)
+	_, exists, err := c.cache.Get(obj)
+	if err != nil {
+		return err
+	}
+	if err := c.cache.Delete(obj); err != nil {
+		return err
+	}
+	if exists {
+		c.notifyConfigChanged()
+	}
+	return nil
+}
+
+// ConfigChanges returns a channel which receives a notification whenever objects that the configuration
+// is built from are added, updated or deleted. Notifications are coalesced: a single notification may
+// stand for several changes.
+func (c *KongClient) ConfigChanges() <-chan struct{} {
+	return c.configChangesCh
 }
 
 // ObjectExists indicates whether or not any version of the provided object is already present in the proxy.
@@ -330,6 +420,24 @@ func (c *KongClient) Listeners(ctx context.Context) ([]kong.ProxyListener, []kon
 	return retListeners, retStreamListeners, nil
 }
 
+// EnableFallbackConfiguration makes Update() exclude the objects rejected by the data-plane(s) (and the objects
+// depending on them) from the configuration when applying it fails, instead of applying the last valid
+// configuration as a whole. Excluded objects are replaced by their versions from the last valid configuration
+// when possible. It requires the data-plane(s) to report which objects caused the failure (DB-less mode).
+func (c *KongClient) EnableFallbackConfiguration() {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.fallbackConfigurationEnabled = true
+}
+
+// EnableCanaryRollout makes configuration be applied to canary gateways first, and to the rest of them
+// only when the canaries stay healthy. Canaries are rolled back to the last valid configuration otherwise.
+func (c *KongClient) EnableCanaryRollout(cfg CanaryRolloutConfig) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.canaryRollout = mo.Some(cfg)
+}
+
 // -----------------------------------------------------------------------------
 // Dataplane Client - Kong - Reporting
 // -----------------------------------------------------------------------------
@@ -370,6 +478,25 @@ func (c *KongClient) KubernetesObjectConfigurationStatus(obj client.Object) k8so
 	return c.kubernetesObjectReportsFilter.Get(obj)
 }
 
+// KubernetesObjectCredentialRotations returns rotations of the provided KongConsumer's credentials whose
+// previous values are kept in the configuration applied to the data-plane.
+func (c *KongClient) KubernetesObjectCredentialRotations(obj client.Object) []k8sobj.CredentialRotation {
+	if _, ok := obj.(*kongv1.KongConsumer); !ok {
+		return nil
+	}
+	c.kubernetesObjectReportLock.RLock()
+	defer c.kubernetesObjectReportLock.RUnlock()
+	return c.credentialRotations[k8stypes.NamespacedName{Namespace: obj.GetNamespace(), Name: obj.GetName()}]
+}
+
+// KubernetesObjectFallbackStatus reports whether the provided object has been excluded from
+// the configuration applied to the data-plane and how it's represented in the fallback configuration.
+func (c *KongClient) KubernetesObjectFallbackStatus(obj client.Object) k8sobj.FallbackStatus {
+	c.kubernetesObjectReportLock.RLock()
+	defer c.kubernetesObjectReportLock.RUnlock()
+	return c.kubernetesObjectReportsFilter.GetFallbackStatus(obj)
+}
+
 // -----------------------------------------------------------------------------
 // Dataplane Client - Kong - Interface Implementation
 // -----------------------------------------------------------------------------
@@ -385,9 +512,14 @@ func (c *KongClient) DBMode() string {
 // Kubernetes state into Kong objects and state, and then ships the
 // resulting configuration to the data-plane (Kong Admin API).
 func (c *KongClient) Update(ctx context.Context) error {
+	c.updateLock.Lock()
+	defer c.updateLock.Unlock()
 	c.lock.Lock()
 	defer c.lock.Unlock()
 
+	syncTimestamp := time.Now()
+	c.resetKongResourceFailures()
+
 	// If Kong is running in dbless mode, we can fetch and store the last good configuration.
 	if dataplaneutil.IsDBLessMode(c.dbmode) {
 		// Fetch the last valid configuration from the proxy only in case there is no valid
@@ -402,6 +534,19 @@ func (c *KongClient) Update(ctx context.Context) error {
 		}
 	}
 
+	// Objects the configuration is built from are kept to build the fallback configuration from in case the
+	// data-plane(s) reject it. Objects changed in the meantime may be built in their newer versions, which is
+	// fine as the snapshot is only used to determine the current and last valid versions of objects.
+	var cacheSnapshot mo.Option[store.CacheStores]
+	if c.fallbackConfigurationEnabled {
+		snapshot, err := c.cache.TakeSnapshot()
+		if err != nil {
+			c.logger.Error(err, "failed to take a snapshot of the cache, fallback configuration will not be available")
+		} else {
+			cacheSnapshot = mo.Some(snapshot)
+		}
+	}
+
 	c.logger.V(util.DebugLevel).Info("parsing kubernetes objects into data-plane configuration")
 	parsingResult := c.kongConfigBuilder.BuildKongConfig()
 	if failuresCount := len(parsingResult.TranslationFailures); failuresCount > 0 {
@@ -414,9 +559,21 @@ func (c *KongClient) Update(ctx context.Context) error {
 		c.prometheusMetrics.RecordTranslationBrokenResources(0)
 		c.logger.V(util.DebugLevel).Info("successfully built data-plane configuration")
 	}
+	// Failures are reported once the outcome of the sync is known, including Kong entities
+	// rejected when pushing the fallback or the last valid configuration.
+	defer c.sendFailuresDiagnostic(syncTimestamp, parsingResult.TranslationFailures)
 
 	shas, gatewaysSyncErr := c.sendOutToGatewayClients(ctx, parsingResult.KongState, c.kongConfig)
+	if gatewaysSyncErr == nil {
+		if cacheSnapshot.IsPresent() {
+			c.lastValidCacheSnapshot = cacheSnapshot
+		}
+		// Rotations only start or end along with the configuration including them being applied.
+		c.commitCredentialRotations(parsingResult.CredentialRotations)
+	}
 	konnectSyncErr := c.maybeSendOutToKonnectClient(ctx, parsingResult.KongState, c.kongConfig)
+	// Errors are logged only, data-planes of managed Gateways don't affect the config status.
+	_ = c.maybeSendOutToManagedGateways(ctx, parsingResult.KongState, c.kongConfig)
 
 	// Taking into account the results of syncing configuration with Gateways and Konnect, and potential translation
 	// failures, calculate the config status and update it.
@@ -430,6 +587,17 @@ func (c *KongClient) Update(ctx context.Context) error {
 
 	// In case of a failure in syncing configuration with Gateways, propagate the error.
 	if gatewaysSyncErr != nil {
+		// Canaries have been rolled back already and the rest of the Gateways haven't been touched.
+		if errors.As(gatewaysSyncErr, &CanaryRolloutFailedError{}) {
+			return gatewaysSyncErr
+		}
+		if current, ok := cacheSnapshot.Get(); ok {
+			fallbackSyncErr := c.sendOutFallbackConfiguration(ctx, current)
+			if fallbackSyncErr == nil {
+				return gatewaysSyncErr
+			}
+			c.logger.Error(fallbackSyncErr, "failed to apply fallback configuration, falling back to the last valid configuration")
+		}
 		if state, found := c.kongConfigFetcher.LastValidConfig(); found {
 			_, fallbackSyncErr := c.sendOutToGatewayClients(ctx, state, c.kongConfig)
 			if fallbackSyncErr != nil {
@@ -447,7 +615,7 @@ func (c *KongClient) Update(ctx context.Context) error {
 		if !slices.Equal(shas, c.SHAs) {
 			c.logger.V(util.DebugLevel).Info("triggering report for configured Kubernetes objects", "count",
 				len(parsingResult.ConfiguredKubernetesObjects))
-			c.triggerKubernetesObjectReport(parsingResult.ConfiguredKubernetesObjects, parsingResult.TranslationFailures)
+			c.triggerKubernetesObjectReport(parsingResult.ConfiguredKubernetesObjects, parsingResult.TranslationFailures, nil)
 		} else {
 			c.logger.V(util.DebugLevel).Info("no configuration change; resource status update not necessary, skipping")
 		}
@@ -462,22 +630,203 @@ func (c *KongClient) sendOutToGatewayClients(
 ) ([]string, error) {
 	gatewayClients := c.clientsProvider.GatewayClients()
 	c.logger.V(util.DebugLevel).Info("sending configuration to gateway clients", "count", len(gatewayClients))
+	s = sharedGatewaysKongState(s, *c.cache, sets.KeySet(c.clientsProvider.ManagedGatewayClients()))
+
+	var canarySHAs []string
+	if rollout, ok := c.canaryRollout.Get(); ok {
+		canaries, rest := rollout.splitCanaries(gatewayClients)
+		if len(canaries) > 0 {
+			var err error
+			if canarySHAs, err = c.sendOutToCanaries(ctx, rollout, canaries, s, config); err != nil {
+				return nil, err
+			}
+			gatewayClients = rest
+		}
+	}
+
 	shas, err := iter.MapErr(gatewayClients, func(client **adminapi.Client) (string, error) {
 		return c.sendToClient(ctx, *client, s, config)
 	})
 	if err != nil {
 		return nil, err
 	}
+	shas = append(shas, canarySHAs...)
 	previousSHAs := c.SHAs
 
 	sort.Strings(shas)
 	c.SHAs = shas
 
-	c.kongConfigFetcher.StoreLastValidConfig(s)
+	if err := c.kongConfigFetcher.StoreLastValidConfig(ctx, s); err != nil {
+		c.logger.Error(err, "failed to store last valid configuration")
+	}
 
 	return previousSHAs, nil
 }
 
+// sendOutToCanaries sends out the configuration to canary gateways and checks their health when it changed
+// their configuration. Canaries are rolled back to their previous configuration when they turn out unhealthy.
+// The configuration isn't sent out to canaries again until it changes then.
+func (c *KongClient) sendOutToCanaries(
+	ctx context.Context,
+	rollout CanaryRolloutConfig,
+	canaries []*adminapi.Client,
+	s *kongstate.KongState,
+	config sendconfig.Config,
+) ([]string, error) {
+	if failed, ok := c.failedCanaryRollout.Get(); ok {
+		sha, err := c.configSHA(ctx, canaries[0], s, config)
+		if err != nil {
+			return nil, err
+		}
+		if sha == failed.sha {
+			c.logger.V(util.DebugLevel).Info("configuration has already made canary gateways unhealthy, skipping its rollout")
+			return nil, CanaryRolloutFailedError{Err: failed.err}
+		}
+	}
+
+	previousSHAs := lo.Map(canaries, func(client *adminapi.Client, _ int) string {
+		return string(client.LastConfigSHA())
+	})
+	previousStates := lo.Map(canaries, func(client *adminapi.Client, _ int) *kongstate.KongState {
+		return c.canaryStates[client.BaseRootURL()]
+	})
+	shas, err := iter.MapErr(canaries, func(client **adminapi.Client) (string, error) {
+		return c.sendToClient(ctx, *client, s, config)
+	})
+	if err != nil {
+		return nil, err
+	}
+	if slices.Equal(previousSHAs, shas) {
+		// Configuration of canaries hasn't changed, there's nothing to check.
+		c.storeCanaryStates(canaries, s)
+		return shas, nil
+	}
+
+	urls := strings.Join(lo.Map(canaries, func(client *adminapi.Client, _ int) string {
+		return client.BaseRootURL()
+	}), ", ")
+	c.recordControllerPodEvent(corev1.EventTypeNormal, KongConfigurationCanaryAppliedEventReason,
+		fmt.Sprintf("applied Kong configuration to canary gateways %s, checking their health in %s", urls, rollout.SoakTime))
+	c.logger.Info("applied configuration to canary gateways, checking their health", "canaries", urls, "soak_time", rollout.SoakTime)
+
+	if err := c.checkCanariesHealth(ctx, rollout, canaries); err != nil {
+		c.recordControllerPodEvent(corev1.EventTypeWarning, KongConfigurationCanaryFailedEventReason,
+			fmt.Sprintf("canary gateways %s are unhealthy with Kong configuration, rolling it back: %v", urls, err))
+		c.failedCanaryRollout = mo.Some(failedCanaryRollout{sha: shas[0], err: err})
+		if rollbackErr := c.rollBackCanaries(ctx, canaries, previousStates, config); rollbackErr != nil {
+			c.logger.Error(rollbackErr, "failed to roll back canary gateways", "canaries", urls)
+			c.recordControllerPodEvent(corev1.EventTypeWarning, KongConfigurationCanaryFailedEventReason,
+				fmt.Sprintf("failed to roll back canary gateways %s: %v", urls, rollbackErr))
+		} else {
+			c.recordControllerPodEvent(corev1.EventTypeNormal, KongConfigurationCanaryRolledBackEventReason,
+				fmt.Sprintf("rolled back canary gateways %s to their previous Kong configuration", urls))
+		}
+		return nil, CanaryRolloutFailedError{Err: err}
+	}
+
+	c.recordControllerPodEvent(corev1.EventTypeNormal, KongConfigurationCanarySucceededEventReason,
+		fmt.Sprintf("canary gateways %s are healthy with Kong configuration, applying it to the remaining gateways", urls))
+	c.storeCanaryStates(canaries, s)
+	return shas, nil
+}
+
+// checkCanariesHealth waits for the soak time and checks the health of canaries with lock released, for the
+// client's readers (e.g. Listeners()) not to be blocked in the meantime. It has to be called with lock held,
+// which is acquired again before returning. Other Update() calls are kept waiting by updateLock.
+func (c *KongClient) checkCanariesHealth(ctx context.Context, rollout CanaryRolloutConfig, canaries []*adminapi.Client) error {
+	c.lock.Unlock()
+	defer c.lock.Lock()
+	return rollout.checkHealth(ctx, canaries)
+}
+
+// rollBackCanaries sends out to each of the canary gateways the configuration applied to it before the current
+// rollout. It may differ from the last valid configuration, which is only stored once all the gateways accept
+// a configuration, e.g. when the rest of the gateways rejected the configuration canaries were healthy with. The last
+// valid configuration is sent out to canaries whose previous configuration is unknown, e.g. because they have just
+// been discovered or the controller has restarted.
+func (c *KongClient) rollBackCanaries(
+	ctx context.Context,
+	canaries []*adminapi.Client,
+	previousStates []*kongstate.KongState,
+	config sendconfig.Config,
+) error {
+	lastValidState, lastValidFound := c.kongConfigFetcher.LastValidConfig()
+	_, err := iter.MapErr(lo.Range(len(canaries)), func(i *int) (string, error) {
+		client, state := canaries[*i], previousStates[*i]
+		if state == nil {
+			if !lastValidFound {
+				return "", fmt.Errorf("no configuration to roll back %s to", client.BaseRootURL())
+			}
+			state = lastValidState
+		}
+		return c.sendToClient(ctx, client, state, config)
+	})
+	return err
+}
+
+// storeCanaryStates stores the configuration applied to canary gateways to roll them back to later on, and
+// forgets the configuration that made canaries unhealthy most recently.
+func (c *KongClient) storeCanaryStates(canaries []*adminapi.Client, s *kongstate.KongState) {
+	c.canaryStates = lo.SliceToMap(canaries, func(client *adminapi.Client) (string, *kongstate.KongState) {
+		return client.BaseRootURL(), s
+	})
+	c.failedCanaryRollout = mo.None[failedCanaryRollout]()
+}
+
+// configSHA returns the SHA of the configuration that sendToClient would send to a given client.
+func (c *KongClient) configSHA(
+	ctx context.Context,
+	client sendconfig.AdminAPIClient,
+	s *kongstate.KongState,
+	config sendconfig.Config,
+) (string, error) {
+	logger := c.logger.WithValues("url", client.AdminAPIClient().BaseRootURL())
+	targetContent := deckgen.ToDeckContent(ctx, logger, s, deckGenParamsForClient(client, config))
+	sha, err := deckgen.GenerateSHA(targetContent, deckgen.ToCustomEntities(s))
+	if err != nil {
+		return "", err
+	}
+	return string(sha), nil
+}
+
+// sendOutFallbackConfiguration builds the configuration from the current cache snapshot without the objects
+// rejected by the data-plane(s) in the current Update() and the objects depending on them, and sends it out to
+// each of the configured gateway clients.
+func (c *KongClient) sendOutFallbackConfiguration(ctx context.Context, current store.CacheStores) error {
+	c.kongResourceFailuresLock.Lock()
+	brokenObjects := lo.FlatMap(c.kongResourceFailures, func(f failures.ResourceFailure, _ int) []client.Object {
+		return f.CausingObjects()
+	})
+	c.kongResourceFailuresLock.Unlock()
+	if len(brokenObjects) == 0 {
+		return errors.New("no objects rejected by gateways were identified")
+	}
+
+	fallbackCache, excludedObjects, err := fallback.GenerateCacheStores(current, c.lastValidCacheSnapshot, brokenObjects)
+	if err != nil {
+		return fmt.Errorf("failed to generate fallback cache: %w", err)
+	}
+	if len(excludedObjects) == 0 {
+		return errors.New("objects rejected by gateways were not found in the cache")
+	}
+
+	c.logger.V(util.DebugLevel).Info("parsing kubernetes objects into fallback data-plane configuration", "excluded", len(excludedObjects))
+	fallbackResult := c.kongConfigBuilder.BuildKongConfigFromCache(fallbackCache)
+	shas, err := c.sendOutToGatewayClients(ctx, fallbackResult.KongState, c.kongConfig)
+	if err != nil {
+		return err
+	}
+	c.lastValidCacheSnapshot = mo.Some(fallbackCache)
+	c.recordExcludedObjectEvents(excludedObjects)
+	c.logger.Info("due to errors in the current config, the fallback config has been pushed to Gateways",
+		"excluded", len(excludedObjects))
+
+	if c.AreKubernetesObjectReportsEnabled() && !slices.Equal(shas, c.SHAs) {
+		c.triggerKubernetesObjectReport(fallbackResult.ConfiguredKubernetesObjects, fallbackResult.TranslationFailures, excludedObjects)
+	}
+	return nil
+}
+
 // maybeSendOutToKonnectClient sends out the configuration to Konnect when KonnectClient is provided.
 // It's a noop when Konnect integration is not enabled.
 func (c *KongClient) maybeSendOutToKonnectClient(ctx context.Context, s *kongstate.KongState, config sendconfig.Config) error {
@@ -510,12 +859,7 @@ func (c *KongClient) sendToClient(
 ) (string, error) {
 	logger := c.logger.WithValues("url", client.AdminAPIClient().BaseRootURL())
 
-	deckGenParams := deckgen.GenerateDeckContentParams{
-		SelectorTags:                    config.FilterTags,
-		ExpressionRoutes:                config.ExpressionRoutes,
-		PluginSchemas:                   client.PluginSchemaStore(),
-		AppendStubEntityWhenConfigEmpty: !client.IsKonnect() && config.InMemory,
-	}
+	deckGenParams := deckGenParamsForClient(client, config)
 	targetContent := deckgen.ToDeckContent(ctx, logger, s, deckGenParams)
 	sendDiagnostic := prepareSendDiagnosticFn(ctx, logger, c.diagnostic, s, targetContent, deckGenParams)
 
@@ -528,12 +872,14 @@ func (c *KongClient) sendToClient(
 		client,
 		config,
 		targetContent,
+		deckgen.ToCustomEntities(s),
 		c.prometheusMetrics,
 		c.updateStrategyResolver,
 		c.configChangeDetector,
 	)
 
 	c.recordResourceFailureEvents(entityErrors, KongConfigurationApplyFailedEventReason)
+	c.collectKongResourceFailures(entityErrors)
 	// Only record events on applying configuration to Kong gateway here.
 	if !client.IsKonnect() {
 		c.recordApplyConfigurationEvents(err, client.BaseRootURL())
@@ -553,6 +899,16 @@ func (c *KongClient) sendToClient(
 	return string(newConfigSHA), nil
 }
 
+// deckGenParamsForClient returns the parameters of generating the deck content sent to a given client.
+func deckGenParamsForClient(client sendconfig.AdminAPIClient, config sendconfig.Config) deckgen.GenerateDeckContentParams {
+	return deckgen.GenerateDeckContentParams{
+		SelectorTags:                    config.FilterTags,
+		ExpressionRoutes:                config.ExpressionRoutes,
+		PluginSchemas:                   client.PluginSchemaStore(),
+		AppendStubEntityWhenConfigEmpty: !client.IsKonnect() && config.InMemory,
+	}
+}
+
 // SetConfigStatusNotifier sets a notifier which notifies subscribers about configuration sending results.
 // Currently it is used for uploading the node status to konnect control plane.
 func (c *KongClient) SetConfigStatusNotifier(n clients.ConfigStatusNotifier) {
@@ -611,11 +967,54 @@ func prepareSendDiagnosticFn(
 	}
 }
 
+// resetKongResourceFailures clears failures of Kong entities collected during the previous Update().
+func (c *KongClient) resetKongResourceFailures() {
+	c.kongResourceFailuresLock.Lock()
+	defer c.kongResourceFailuresLock.Unlock()
+	c.kongResourceFailures = nil
+}
+
+// collectKongResourceFailures stores failures of Kong entities rejected by a data-plane to report them
+// as diagnostic information at the end of Update().
+func (c *KongClient) collectKongResourceFailures(resourceFailures []failures.ResourceFailure) {
+	c.kongResourceFailuresLock.Lock()
+	defer c.kongResourceFailuresLock.Unlock()
+	c.kongResourceFailures = append(c.kongResourceFailures, resourceFailures...)
+}
+
+// sendFailuresDiagnostic ships translation failures and failures of Kong entities rejected by the data-plane(s)
+// during the sync started at syncTimestamp to the diagnostic server, if enabled (--dump-config).
+func (c *KongClient) sendFailuresDiagnostic(syncTimestamp time.Time, translationFailures []failures.ResourceFailure) {
+	if c.diagnostic.Failures == nil {
+		return
+	}
+
+	c.kongResourceFailuresLock.Lock()
+	kongFailures := slices.Clone(c.kongResourceFailures)
+	c.kongResourceFailuresLock.Unlock()
+
+	select {
+	case c.diagnostic.Failures <- util.FailuresDump{
+		SyncTimestamp:       syncTimestamp,
+		TranslationFailures: translationFailures,
+		KongFailures:        kongFailures,
+	}:
+		c.logger.V(util.DebugLevel).Info("shipping resource failures to diagnostic server")
+	default:
+		c.logger.Error(nil, "resource failures diagnostic buffer full, dropping diagnostic resource failures")
+	}
+}
+
 // triggerKubernetesObjectReport will update the KongClient with a set which
 // enables filtering for which objects are currently applied to the data-plane,
 // as well as updating the c.kubernetesObjectStatusQueue to queue those objects
 // for reconciliation so their statuses can be properly updated.
-func (c *KongClient) triggerKubernetesObjectReport(reportedObjects []client.Object, translationFailures []failures.ResourceFailure) {
+// Objects excluded from the fallback configuration are reported as failed.
+func (c *KongClient) triggerKubernetesObjectReport(
+	reportedObjects []client.Object,
+	translationFailures []failures.ResourceFailure,
+	excludedObjects []fallback.ExcludedObject,
+) {
 	// first a new set of the included objects for the most recent configuration
 	// needs to be generated.
 	set := k8sobj.ConfigurationStatusSet{}
@@ -632,13 +1031,21 @@ func (c *KongClient) triggerKubernetesObjectReport(reportedObjects []client.Obje
 		}
 	}
 
+	// objects excluded from the fallback configuration may be reported in their last valid versions,
+	// so their current versions are overridden as failed.
+	excluded := make([]client.Object, 0, len(excludedObjects))
+	for _, e := range excludedObjects {
+		set.InsertExcluded(e.Object, e.Status)
+		excluded = append(excluded, e.Object)
+	}
+
 	c.updateKubernetesObjectReportFilter(set)
 
 	// after the filter has been updated we signal the status queue so that the
 	// control-plane can update the Kubernetes object statuses for affected objs.
 	// this has to be done in a separate loop so that the filter is in place
 	// before the objects are enqueued, as the filter is used by the control-plane
-	for _, obj := range UniqueObjects(reportedObjects, translationFailures) {
+	for _, obj := range UniqueObjects(append(excluded, reportedObjects...), translationFailures) {
 		c.kubernetesObjectStatusQueue.Publish(obj)
 	}
 }
@@ -662,6 +1069,27 @@ func (c *KongClient) updateKubernetesObjectReportFilter(set k8sobj.Configuration
 	c.kubernetesObjectReportsFilter = set
 }
 
+// notifyConfigChanged notifies about a change of objects in the cache without blocking when a
+// notification is already pending.
+func (c *KongClient) notifyConfigChanged() {
+	select {
+	case c.configChangesCh <- struct{}{}:
+	default:
+	}
+}
+
+// commitCredentialRotations commits changes of credential rotations included in the applied configuration
+// and records normal Events on KongConsumers whose credentials' rotations started or completed.
+func (c *KongClient) commitCredentialRotations(update *kongstate.CredentialRotationsUpdate) {
+	events := update.Commit()
+	c.kubernetesObjectReportLock.Lock()
+	c.credentialRotations = update.InProgress()
+	c.kubernetesObjectReportLock.Unlock()
+	for _, e := range events {
+		c.eventRecorder.Event(e.Consumer, corev1.EventTypeNormal, e.Reason, e.Message)
+	}
+}
+
 // recordResourceFailureEvents records warning Events for each causing object in each input resource failure, with the
 // provided reason.
 func (c *KongClient) recordResourceFailureEvents(resourceFailures []failures.ResourceFailure, reason string) {
@@ -672,14 +1100,28 @@ func (c *KongClient) recordResourceFailureEvents(resourceFailures []failures.Res
 	}
 }
 
-// recordApplyConfigurationEvents records event attached to KIC pod after KIC applied Kong configuration.
-func (c *KongClient) recordApplyConfigurationEvents(err error, rootURL string) {
-	podNN, ok := c.controllerPodReference.Get()
-	if !ok {
-		// Can't record an event without a controller pod reference to attach to.
-		return
+// recordExcludedObjectEvents records warning Events for objects excluded from the fallback configuration.
+func (c *KongClient) recordExcludedObjectEvents(excludedObjects []fallback.ExcludedObject) {
+	for _, e := range excludedObjects {
+		reason := "Kong rejected the configuration generated from the object"
+		if cause := e.CausingObject; cause != nil {
+			name := cause.GetName()
+			if cause.GetNamespace() != "" {
+				name = cause.GetNamespace() + "/" + name
+			}
+			reason = fmt.Sprintf("the object depends on %s %s excluded from the configuration",
+				cause.GetObjectKind().GroupVersionKind().Kind, name)
+		}
+		outcome := "it has been left out of the configuration"
+		if e.Status == k8sobj.FallbackStatusLastValid {
+			outcome = "its last valid version has been applied instead"
+		}
+		c.eventRecorder.Event(e.Object, corev1.EventTypeWarning, KongConfigurationExcludedEventReason, reason+", "+outcome)
 	}
+}
 
+// recordApplyConfigurationEvents records event attached to KIC pod after KIC applied Kong configuration.
+func (c *KongClient) recordApplyConfigurationEvents(err error, rootURL string) {
 	eventType := corev1.EventTypeNormal
 	reason := KongConfigurationApplySucceededEventReason
 	message := fmt.Sprintf("successfully applied Kong configuration to %s", rootURL)
@@ -689,6 +1131,16 @@ func (c *KongClient) recordApplyConfigurationEvents(err error, rootURL string) {
 		reason = KongConfigurationApplyFailedEventReason
 		message = fmt.Sprintf("failed to apply Kong configuration to %s: %v", rootURL, err)
 	}
+	c.recordControllerPodEvent(eventType, reason, message)
+}
+
+// recordControllerPodEvent records an event on the controller pod. It's a noop when the controller pod is unknown.
+func (c *KongClient) recordControllerPodEvent(eventType, reason, message string) {
+	podNN, ok := c.controllerPodReference.Get()
+	if !ok {
+		// Can't record an event without a controller pod reference to attach to.
+		return
+	}
 
 	pod := &corev1.Pod{
 		ObjectMeta: metav1.ObjectMeta{
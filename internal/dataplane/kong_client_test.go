@@ -24,10 +24,14 @@ import (
 	netv1 "k8s.io/api/networking/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	k8stypes "k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/tools/record"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
 
 	"github.com/kong/kubernetes-ingress-controller/v2/internal/adminapi"
+	"github.com/kong/kubernetes-ingress-controller/v2/internal/admission/validation/consumers/credentials"
+	"github.com/kong/kubernetes-ingress-controller/v2/internal/annotations"
 	"github.com/kong/kubernetes-ingress-controller/v2/internal/clients"
 	"github.com/kong/kubernetes-ingress-controller/v2/internal/dataplane/configfetcher"
 	"github.com/kong/kubernetes-ingress-controller/v2/internal/dataplane/deckgen"
@@ -35,10 +39,13 @@ import (
 	"github.com/kong/kubernetes-ingress-controller/v2/internal/dataplane/kongstate"
 	"github.com/kong/kubernetes-ingress-controller/v2/internal/dataplane/parser"
 	"github.com/kong/kubernetes-ingress-controller/v2/internal/dataplane/sendconfig"
+	"github.com/kong/kubernetes-ingress-controller/v2/internal/gatewayapi"
+	"github.com/kong/kubernetes-ingress-controller/v2/internal/labels"
 	"github.com/kong/kubernetes-ingress-controller/v2/internal/metrics"
 	"github.com/kong/kubernetes-ingress-controller/v2/internal/store"
 	"github.com/kong/kubernetes-ingress-controller/v2/internal/util"
 	"github.com/kong/kubernetes-ingress-controller/v2/internal/versions"
+	kongv1 "github.com/kong/kubernetes-ingress-controller/v2/pkg/apis/configuration/v1"
 	"github.com/kong/kubernetes-ingress-controller/v2/test/mocks"
 )
 
@@ -141,8 +148,9 @@ var (
 
 // mockGatewayClientsProvider is a mock implementation of dataplane.AdminAPIClientsProvider.
 type mockGatewayClientsProvider struct {
-	gatewayClients []*adminapi.Client
-	konnectClient  *adminapi.KonnectClient
+	gatewayClients        []*adminapi.Client
+	managedGatewayClients map[k8stypes.NamespacedName][]*adminapi.Client
+	konnectClient         *adminapi.KonnectClient
 }
 
 func (f mockGatewayClientsProvider) KonnectClient() *adminapi.KonnectClient {
@@ -153,11 +161,16 @@ func (f mockGatewayClientsProvider) GatewayClients() []*adminapi.Client {
 	return f.gatewayClients
 }
 
+func (f mockGatewayClientsProvider) ManagedGatewayClients() map[k8stypes.NamespacedName][]*adminapi.Client {
+	return f.managedGatewayClients
+}
+
 // mockUpdateStrategy is a mock implementation of sendconfig.UpdateStrategyResolver.
 type mockUpdateStrategyResolver struct {
 	updateCalledForURLs       []string
 	lastUpdatedContentForURLs map[string]sendconfig.ContentWithHash
 	shouldReturnErrorOnUpdate map[string]struct{}
+	resourceErrorsOnUpdate    []sendconfig.ResourceError
 	t                         *testing.T
 	lock                      sync.RWMutex
 	singleError               bool
@@ -176,7 +189,19 @@ func (f *mockUpdateStrategyResolver) ResolveUpdateStrategy(c sendconfig.UpdateCl
 	defer f.lock.Unlock()
 
 	url := c.AdminAPIClient().BaseRootURL()
-	return &mockUpdateStrategy{onUpdate: f.updateCalledForURLCallback(url, f.singleError)}
+	return &mockUpdateStrategy{
+		onUpdate:       f.updateCalledForURLCallback(url, f.singleError),
+		resourceErrors: f.resourceErrorsOnUpdate,
+	}
+}
+
+// returnResourceErrorsOnUpdate will cause the mockUpdateStrategy to return the given resource errors along with
+// errors returned on Update().
+func (f *mockUpdateStrategyResolver) returnResourceErrorsOnUpdate(resourceErrors []sendconfig.ResourceError) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	f.resourceErrorsOnUpdate = resourceErrors
 }
 
 // returnErrorOnUpdate will cause the mockUpdateStrategy with a given Admin API URL to return an error on Update().
@@ -235,7 +260,8 @@ func (f *mockUpdateStrategyResolver) lastUpdatedContentForURL(url string) (sendc
 
 // mockUpdateStrategy is a mock implementation of sendconfig.UpdateStrategy.
 type mockUpdateStrategy struct {
-	onUpdate func(content sendconfig.ContentWithHash) error
+	onUpdate       func(content sendconfig.ContentWithHash) error
+	resourceErrors []sendconfig.ResourceError
 }
 
 func (m *mockUpdateStrategy) Update(_ context.Context, content sendconfig.ContentWithHash) (
@@ -244,6 +270,9 @@ func (m *mockUpdateStrategy) Update(_ context.Context, content sendconfig.Conten
 	resourceErrorsParseErr error,
 ) {
 	err = m.onUpdate(content)
+	if err != nil {
+		return err, m.resourceErrors, nil
+	}
 	return err, nil, nil
 }
 
@@ -414,6 +443,8 @@ func (m *mockConfigStatusQueue) Notifications() []clients.ConfigStatus {
 type mockKongConfigBuilder struct {
 	translationFailuresToReturn []failures.ResourceFailure
 	kongState                   *kongstate.KongState
+	credentialRotations         *kongstate.CredentialRotationsUpdate
+	cachesBuiltFrom             []store.CacheStores
 }
 
 func newMockKongConfigBuilder() *mockKongConfigBuilder {
@@ -426,9 +457,15 @@ func (p *mockKongConfigBuilder) BuildKongConfig() parser.KongConfigBuildingResul
 	return parser.KongConfigBuildingResult{
 		KongState:           p.kongState,
 		TranslationFailures: p.translationFailuresToReturn,
+		CredentialRotations: p.credentialRotations,
 	}
 }
 
+func (p *mockKongConfigBuilder) BuildKongConfigFromCache(cache store.CacheStores) parser.KongConfigBuildingResult {
+	p.cachesBuiltFrom = append(p.cachesBuiltFrom, cache)
+	return p.BuildKongConfig()
+}
+
 func (p *mockKongConfigBuilder) returnTranslationFailures(enabled bool) {
 	if enabled {
 		// Return some mocked translation failures.
@@ -541,6 +578,143 @@ func TestKongClientUpdate_ConfigStatusIsNotified(t *testing.T) {
 	}
 }
 
+func TestKongClientUpdate_FailuresDiagnostic(t *testing.T) {
+	var (
+		ctx               = context.Background()
+		testGatewayClient = mustSampleGatewayClient(t)
+
+		clientsProvider = mockGatewayClientsProvider{
+			gatewayClients: []*adminapi.Client{testGatewayClient},
+		}
+
+		updateStrategyResolver = newMockUpdateStrategyResolver(t)
+		configChangeDetector   = mockConfigurationChangeDetector{hasConfigurationChanged: true}
+		configBuilder          = newMockKongConfigBuilder()
+		kongRawStateGetter     = &mockKongLastValidConfigFetcher{}
+		kongClient             = setupTestKongClient(t, updateStrategyResolver, clientsProvider, configChangeDetector, configBuilder, nil, kongRawStateGetter)
+		failuresDumps          = make(chan util.FailuresDump, 1)
+	)
+	kongClient.diagnostic = util.ConfigDumpDiagnostic{Failures: failuresDumps}
+
+	t.Run("translation and kong failures are reported", func(t *testing.T) {
+		configBuilder.returnTranslationFailures(true)
+		updateStrategyResolver.returnErrorOnUpdate(testGatewayClient.BaseRootURL(), true)
+		updateStrategyResolver.returnResourceErrorsOnUpdate([]sendconfig.ResourceError{{
+			Name:       "service",
+			Namespace:  "namespace",
+			Kind:       "Service",
+			APIVersion: "v1",
+			Problems:   map[string]string{"service:service": "invalid port"},
+		}})
+
+		beforeUpdate := time.Now()
+		require.Error(t, kongClient.Update(ctx))
+
+		require.Len(t, failuresDumps, 1)
+		dump := <-failuresDumps
+		require.False(t, dump.SyncTimestamp.Before(beforeUpdate))
+		require.Len(t, dump.TranslationFailures, 1)
+		require.Equal(t, "some reason", dump.TranslationFailures[0].Message())
+		require.Len(t, dump.KongFailures, 1)
+		require.Equal(t, "invalid service:service: invalid port", dump.KongFailures[0].Message())
+		require.Equal(t, "service", dump.KongFailures[0].CausingObjects()[0].GetName())
+	})
+
+	t.Run("failures of the last valid configuration push are reported", func(t *testing.T) {
+		kongRawStateGetter.lastKongState = &kongstate.KongState{}
+		defer func() { kongRawStateGetter.lastKongState = nil }()
+		configBuilder.returnTranslationFailures(false)
+		updateStrategyResolver.returnErrorOnUpdate(testGatewayClient.BaseRootURL(), true)
+
+		require.Error(t, kongClient.Update(ctx))
+
+		require.Len(t, failuresDumps, 1)
+		dump := <-failuresDumps
+		require.Len(t, dump.KongFailures, 2, "failures of both the current and the last valid configuration should be reported")
+	})
+
+	t.Run("failures from previous syncs are not reported", func(t *testing.T) {
+		configBuilder.returnTranslationFailures(false)
+		updateStrategyResolver.returnErrorOnUpdate(testGatewayClient.BaseRootURL(), false)
+
+		require.NoError(t, kongClient.Update(ctx))
+
+		require.Len(t, failuresDumps, 1)
+		dump := <-failuresDumps
+		require.Empty(t, dump.TranslationFailures)
+		require.Empty(t, dump.KongFailures)
+	})
+}
+
+func TestKongClientUpdate_CredentialRotations(t *testing.T) {
+	var (
+		ctx               = context.Background()
+		logger            = zapr.NewLogger(zap.NewNop())
+		testGatewayClient = mustSampleGatewayClient(t)
+
+		clientsProvider = mockGatewayClientsProvider{
+			gatewayClients: []*adminapi.Client{testGatewayClient},
+		}
+
+		updateStrategyResolver = newMockUpdateStrategyResolver(t)
+		configChangeDetector   = mockConfigurationChangeDetector{hasConfigurationChanged: true}
+		configBuilder          = newMockKongConfigBuilder()
+		eventRecorder          = mocks.NewEventRecorder()
+		kongClient             = setupTestKongClient(t, updateStrategyResolver, clientsProvider, configChangeDetector, configBuilder, eventRecorder, &mockKongLastValidConfigFetcher{})
+		rotations              = kongstate.NewCredentialRotations()
+	)
+	consumer := &kongv1.KongConsumer{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "consumer",
+			Namespace:   "default",
+			Annotations: map[string]string{annotations.IngressClassKey: annotations.DefaultIngressClass},
+		},
+		Username:    "consumer",
+		Credentials: []string{"consumer-key"},
+	}
+	// observeKey builds consumers with a new update of credential rotations with the given key of the consumer's credential.
+	observeKey := func(key string) *kongstate.CredentialRotationsUpdate {
+		s, err := store.NewFakeStore(store.FakeObjects{
+			KongConsumers: []*kongv1.KongConsumer{consumer},
+			Secrets: []*corev1.Secret{{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        "consumer-key",
+					Namespace:   "default",
+					Labels:      map[string]string{labels.LabelPrefix + labels.CredentialKey: "key-auth"},
+					Annotations: map[string]string{annotations.AnnotationPrefix + annotations.CredentialRotationGracePeriodKey: "1h"},
+				},
+				Data: map[string][]byte{"key": []byte(key)},
+			}},
+		})
+		require.NoError(t, err)
+		update := rotations.NewUpdate()
+		state := kongstate.KongState{}
+		state.FillConsumersAndCredentials(logger, s, failures.NewResourceFailuresCollector(logger), credentials.NewRegistry(nil, false), update)
+		return update
+	}
+	rotationStartedEvents := func() []string {
+		return lo.Filter(eventRecorder.Events(), func(e string, _ int) bool {
+			return strings.Contains(e, kongstate.CredentialRotationStartedEventReason)
+		})
+	}
+	observeKey("old").Commit()
+
+	t.Log("rotation doesn't start when applying the configuration fails")
+	configBuilder.credentialRotations = observeKey("new")
+	updateStrategyResolver.returnErrorOnUpdate(testGatewayClient.BaseRootURL(), true)
+	require.Error(t, kongClient.Update(ctx))
+	require.Empty(t, rotationStartedEvents())
+	require.Empty(t, kongClient.KubernetesObjectCredentialRotations(consumer))
+
+	t.Log("rotation starts when the configuration is applied")
+	configBuilder.credentialRotations = observeKey("new")
+	updateStrategyResolver.returnErrorOnUpdate(testGatewayClient.BaseRootURL(), false)
+	require.NoError(t, kongClient.Update(ctx))
+	require.Len(t, rotationStartedEvents(), 1)
+	require.Len(t, kongClient.KubernetesObjectCredentialRotations(consumer), 1)
+	require.Equal(t, "consumer-key", kongClient.KubernetesObjectCredentialRotations(consumer)[0].Secret)
+}
+
 func TestKongClient_ApplyConfigurationEvents(t *testing.T) {
 	testGatewayClient := mustSampleGatewayClient(t)
 	clientsProvider := mockGatewayClientsProvider{
@@ -735,8 +909,9 @@ func (cf *mockKongLastValidConfigFetcher) LastValidConfig() (*kongstate.KongStat
 	return nil, false
 }
 
-func (cf *mockKongLastValidConfigFetcher) StoreLastValidConfig(s *kongstate.KongState) {
+func (cf *mockKongLastValidConfigFetcher) StoreLastValidConfig(_ context.Context, s *kongstate.KongState) error {
 	cf.lastKongState = s
+	return nil
 }
 
 func (cf *mockKongLastValidConfigFetcher) TryFetchingValidConfigFromGateways(context.Context, logr.Logger, []*adminapi.Client) error {
@@ -875,3 +1050,519 @@ func TestKongClientUpdate_FetchStoreAndPushLastValidConfig(t *testing.T) {
 		})
 	}
 }
+
+func TestKongClientUpdate_FallbackConfiguration(t *testing.T) {
+	var (
+		ctx             = context.Background()
+		gatewayClient   = mustSampleGatewayClient(t)
+		clientsProvider = mockGatewayClientsProvider{
+			gatewayClients: []*adminapi.Client{gatewayClient},
+		}
+		brokenPlugin = &kongv1.KongPlugin{
+			TypeMeta: metav1.TypeMeta{
+				Kind:       "KongPlugin",
+				APIVersion: kongv1.GroupVersion.String(),
+			},
+			ObjectMeta: metav1.ObjectMeta{
+				Name:            "plugin",
+				Namespace:       "default",
+				ResourceVersion: "1",
+			},
+			PluginName: "key-auth",
+		}
+		dependentIngress = &netv1.Ingress{
+			TypeMeta: metav1.TypeMeta{
+				Kind:       "Ingress",
+				APIVersion: netv1.SchemeGroupVersion.String(),
+			},
+			ObjectMeta: metav1.ObjectMeta{
+				Name:            "ingress",
+				Namespace:       "default",
+				ResourceVersion: "1",
+				Annotations: map[string]string{
+					annotations.AnnotationPrefix + annotations.PluginsKey: "plugin",
+				},
+			},
+		}
+		unrelatedService = &corev1.Service{
+			TypeMeta: metav1.TypeMeta{
+				Kind:       "Service",
+				APIVersion: "v1",
+			},
+			ObjectMeta: metav1.ObjectMeta{
+				Name:            "service",
+				Namespace:       "default",
+				ResourceVersion: "1",
+			},
+		}
+	)
+
+	testCases := []struct {
+		name                     string
+		fallbackEnabled          bool
+		expectFallbackCache      bool
+		expectExcludedEventCount int
+	}{
+		{
+			name:                     "fallback configuration enabled, broken objects excluded",
+			fallbackEnabled:          true,
+			expectFallbackCache:      true,
+			expectExcludedEventCount: 2,
+		},
+		{
+			name: "fallback configuration disabled, no fallback cache built",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			updateStrategyResolver := newMockUpdateStrategyResolver(t)
+			updateStrategyResolver.singleError = true
+			updateStrategyResolver.returnErrorOnUpdate(gatewayClient.BaseRootURL(), true)
+			updateStrategyResolver.returnResourceErrorsOnUpdate([]sendconfig.ResourceError{
+				{
+					Name:       brokenPlugin.Name,
+					Namespace:  brokenPlugin.Namespace,
+					Kind:       brokenPlugin.Kind,
+					APIVersion: brokenPlugin.APIVersion,
+					Problems:   map[string]string{"config": "invalid"},
+				},
+			})
+			configBuilder := newMockKongConfigBuilder()
+			eventRecorder := mocks.NewEventRecorder()
+			kongClient := setupTestKongClient(
+				t,
+				updateStrategyResolver,
+				clientsProvider,
+				mockConfigurationChangeDetector{hasConfigurationChanged: true},
+				configBuilder,
+				eventRecorder,
+				&mockKongLastValidConfigFetcher{},
+			)
+			for _, obj := range []client.Object{brokenPlugin, dependentIngress, unrelatedService} {
+				require.NoError(t, kongClient.cache.Add(obj))
+			}
+			if tc.fallbackEnabled {
+				kongClient.EnableFallbackConfiguration()
+			}
+
+			err := kongClient.Update(ctx)
+			require.Error(t, err, "the original error should be propagated even if fallback configuration succeeds")
+
+			excludedEvents := lo.Filter(eventRecorder.Events(), func(e string, _ int) bool {
+				return strings.Contains(e, KongConfigurationExcludedEventReason)
+			})
+			require.Len(t, excludedEvents, tc.expectExcludedEventCount)
+
+			if !tc.expectFallbackCache {
+				require.Empty(t, configBuilder.cachesBuiltFrom)
+				require.False(t, kongClient.lastValidCacheSnapshot.IsPresent())
+				return
+			}
+
+			require.Len(t, configBuilder.cachesBuiltFrom, 1)
+			fallbackCache := configBuilder.cachesBuiltFrom[0]
+			_, exists, err := fallbackCache.Get(brokenPlugin)
+			require.NoError(t, err)
+			require.False(t, exists, "broken plugin should be excluded")
+			_, exists, err = fallbackCache.Get(dependentIngress)
+			require.NoError(t, err)
+			require.False(t, exists, "ingress depending on the broken plugin should be excluded")
+			_, exists, err = fallbackCache.Get(unrelatedService)
+			require.NoError(t, err)
+			require.True(t, exists, "unrelated service should be kept")
+
+			lastValidCache, ok := kongClient.lastValidCacheSnapshot.Get()
+			require.True(t, ok, "fallback cache should be stored as the last valid one")
+			require.Equal(t, fallbackCache, lastValidCache)
+		})
+	}
+}
+
+// canaryHealthCheckFunc is a CanaryHealthCheck implemented by a function.
+type canaryHealthCheckFunc func(ctx context.Context, client *adminapi.Client) error
+
+func (f canaryHealthCheckFunc) Check(ctx context.Context, client *adminapi.Client) error {
+	return f(ctx, client)
+}
+
+func TestKongClientUpdate_CanaryRollout(t *testing.T) {
+	t.Setenv("POD_NAMESPACE", "kong")
+	t.Setenv("POD_NAME", "controller")
+
+	var (
+		ctx             = context.Background()
+		clientsProvider = mockGatewayClientsProvider{
+			gatewayClients: []*adminapi.Client{
+				lo.Must(adminapi.NewTestClient("https://10.0.0.1:8444")),
+				lo.Must(adminapi.NewTestClient("https://10.0.0.2:8444")),
+				lo.Must(adminapi.NewTestClient("https://10.0.0.3:8444")),
+			},
+		}
+		canaryURL = "https://10.0.0.1:8444"
+		restURLs  = []string{"https://10.0.0.2:8444", "https://10.0.0.3:8444"}
+	)
+
+	testCases := []struct {
+		name                 string
+		canaryHealthy        bool
+		expectedUpdatedURLs  []string
+		expectedEventReasons []string
+	}{
+		{
+			name:                "healthy canary, configuration applied to the rest of gateways",
+			canaryHealthy:       true,
+			expectedUpdatedURLs: append([]string{canaryURL}, restURLs...),
+			expectedEventReasons: []string{
+				KongConfigurationCanaryAppliedEventReason,
+				KongConfigurationCanarySucceededEventReason,
+			},
+		},
+		{
+			name:                "unhealthy canary, configuration rolled back and not applied to the rest of gateways",
+			expectedUpdatedURLs: []string{canaryURL, canaryURL},
+			expectedEventReasons: []string{
+				KongConfigurationCanaryAppliedEventReason,
+				KongConfigurationCanaryFailedEventReason,
+				KongConfigurationCanaryRolledBackEventReason,
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			for _, c := range clientsProvider.gatewayClients {
+				c.SetLastConfigSHA(nil)
+			}
+			updateStrategyResolver := newMockUpdateStrategyResolver(t)
+			eventRecorder := mocks.NewEventRecorder()
+			configBuilder := newMockKongConfigBuilder()
+			configBuilder.kongState = &kongstate.KongState{
+				Services: []kongstate.Service{{Service: kong.Service{Name: kong.String("new")}}},
+			}
+			kongClient := setupTestKongClient(
+				t,
+				updateStrategyResolver,
+				clientsProvider,
+				mockConfigurationChangeDetector{hasConfigurationChanged: true},
+				configBuilder,
+				eventRecorder,
+				&mockKongLastValidConfigFetcher{lastKongState: &kongstate.KongState{}},
+			)
+			kongClient.EnableCanaryRollout(CanaryRolloutConfig{
+				Percentage: 1,
+				HealthChecks: []CanaryHealthCheck{
+					canaryHealthCheckFunc(func(_ context.Context, client *adminapi.Client) error {
+						require.Equal(t, canaryURL, client.BaseRootURL(), "only canaries should be checked")
+						if !tc.canaryHealthy {
+							return errors.New("unhealthy")
+						}
+						return nil
+					}),
+				},
+			})
+
+			err := kongClient.Update(ctx)
+			if tc.canaryHealthy {
+				require.NoError(t, err)
+			} else {
+				require.ErrorAs(t, err, &CanaryRolloutFailedError{})
+			}
+			updateStrategyResolver.assertUpdateCalledForURLs(tc.expectedUpdatedURLs)
+
+			canaryEventReasons := func() []string {
+				var reasons []string
+				for _, e := range eventRecorder.Events() {
+					for _, reason := range []string{
+						KongConfigurationCanaryAppliedEventReason,
+						KongConfigurationCanarySucceededEventReason,
+						KongConfigurationCanaryFailedEventReason,
+						KongConfigurationCanaryRolledBackEventReason,
+					} {
+						if strings.Contains(e, " "+reason+" ") {
+							reasons = append(reasons, reason)
+						}
+					}
+				}
+				return reasons
+			}
+			require.Equal(t, tc.expectedEventReasons, canaryEventReasons())
+
+			if tc.canaryHealthy {
+				require.NoError(t, kongClient.Update(ctx))
+				require.Equal(t, tc.expectedEventReasons, canaryEventReasons(),
+					"canaries' health should not be checked when their configuration doesn't change")
+			}
+		})
+	}
+}
+
+func TestKongClientUpdate_CanaryRolloutDoesNotBlockReaders(t *testing.T) {
+	t.Setenv("POD_NAMESPACE", "kong")
+	t.Setenv("POD_NAME", "controller")
+
+	clientsProvider := mockGatewayClientsProvider{
+		gatewayClients: []*adminapi.Client{
+			lo.Must(adminapi.NewTestClient("https://10.0.0.1:8444")),
+			lo.Must(adminapi.NewTestClient("https://10.0.0.2:8444")),
+		},
+	}
+	configBuilder := newMockKongConfigBuilder()
+	configBuilder.kongState = &kongstate.KongState{
+		Services: []kongstate.Service{{Service: kong.Service{Name: kong.String("new")}}},
+	}
+	kongClient := setupTestKongClient(
+		t,
+		newMockUpdateStrategyResolver(t),
+		clientsProvider,
+		mockConfigurationChangeDetector{hasConfigurationChanged: true},
+		configBuilder,
+		mocks.NewEventRecorder(),
+		&mockKongLastValidConfigFetcher{lastKongState: &kongstate.KongState{}},
+	)
+	kongClient.EnableCanaryRollout(CanaryRolloutConfig{
+		Percentage: 1,
+		HealthChecks: []CanaryHealthCheck{
+			canaryHealthCheckFunc(func(context.Context, *adminapi.Client) error {
+				read := make(chan struct{})
+				go func() {
+					_ = kongClient.DBMode()
+					close(read)
+				}()
+				select {
+				case <-read:
+					return nil
+				case <-time.After(time.Second):
+					return errors.New("reading the client is blocked during the canary soak")
+				}
+			}),
+		},
+	})
+
+	require.NoError(t, kongClient.Update(context.Background()))
+}
+
+func TestKongClientUpdate_CanaryRolloutFailure(t *testing.T) {
+	t.Setenv("POD_NAMESPACE", "kong")
+	t.Setenv("POD_NAME", "controller")
+
+	var (
+		ctx             = context.Background()
+		clientsProvider = mockGatewayClientsProvider{
+			gatewayClients: []*adminapi.Client{
+				lo.Must(adminapi.NewTestClient("https://10.0.0.1:8444")),
+				lo.Must(adminapi.NewTestClient("https://10.0.0.2:8444")),
+			},
+		}
+		canaryURL = "https://10.0.0.1:8444"
+		restURL   = "https://10.0.0.2:8444"
+	)
+
+	stateWithService := func(name string) *kongstate.KongState {
+		return &kongstate.KongState{
+			Services: []kongstate.Service{{Service: kong.Service{Name: kong.String(name)}}},
+		}
+	}
+	canaryServiceNames := func(updateStrategyResolver *mockUpdateStrategyResolver) []string {
+		content, ok := updateStrategyResolver.lastUpdatedContentForURL(canaryURL)
+		require.True(t, ok)
+		return lo.Map(content.Content.Services, func(s file.FService, _ int) string {
+			return *s.Name
+		})
+	}
+	resetUpdateCalls := func(updateStrategyResolver *mockUpdateStrategyResolver) {
+		updateStrategyResolver.lock.Lock()
+		defer updateStrategyResolver.lock.Unlock()
+		updateStrategyResolver.updateCalledForURLs = nil
+	}
+
+	updateStrategyResolver := newMockUpdateStrategyResolver(t)
+	configBuilder := newMockKongConfigBuilder()
+	kongClient := setupTestKongClient(
+		t,
+		updateStrategyResolver,
+		clientsProvider,
+		mockConfigurationChangeDetector{hasConfigurationChanged: true},
+		configBuilder,
+		mocks.NewEventRecorder(),
+		&mockKongLastValidConfigFetcher{},
+	)
+	canaryHealthy := true
+	kongClient.EnableCanaryRollout(CanaryRolloutConfig{
+		Percentage: 1,
+		HealthChecks: []CanaryHealthCheck{
+			canaryHealthCheckFunc(func(context.Context, *adminapi.Client) error {
+				if !canaryHealthy {
+					return errors.New("unhealthy")
+				}
+				return nil
+			}),
+		},
+	})
+
+	t.Log("applying configuration healthy on canaries, but rejected by the rest of gateways, so that there's no last valid configuration")
+	configBuilder.kongState = stateWithService("previous")
+	updateStrategyResolver.returnErrorOnUpdate(restURL, true)
+	require.Error(t, kongClient.Update(ctx))
+	require.Equal(t, []string{"previous"}, canaryServiceNames(updateStrategyResolver))
+	updateStrategyResolver.returnErrorOnUpdate(restURL, false)
+
+	t.Log("applying configuration making canaries unhealthy")
+	resetUpdateCalls(updateStrategyResolver)
+	canaryHealthy = false
+	configBuilder.kongState = stateWithService("broken")
+	require.ErrorAs(t, kongClient.Update(ctx), &CanaryRolloutFailedError{})
+	updateStrategyResolver.assertUpdateCalledForURLs([]string{canaryURL, canaryURL})
+	require.Equal(t, []string{"previous"}, canaryServiceNames(updateStrategyResolver),
+		"canaries should be rolled back to their previous configuration even though there's no last valid one")
+
+	t.Log("applying the same configuration again, its rollout should be skipped")
+	resetUpdateCalls(updateStrategyResolver)
+	canaryHealthy = true
+	require.ErrorAs(t, kongClient.Update(ctx), &CanaryRolloutFailedError{})
+	updateStrategyResolver.assertNoUpdateCalled()
+
+	t.Log("applying changed configuration, it should be rolled out")
+	configBuilder.kongState = stateWithService("fixed")
+	require.NoError(t, kongClient.Update(ctx))
+	updateStrategyResolver.assertUpdateCalledForURLs([]string{canaryURL, restURL})
+	require.Equal(t, []string{"fixed"}, canaryServiceNames(updateStrategyResolver))
+}
+
+func TestKongClientUpdate_ManagedGateways(t *testing.T) {
+	var (
+		ctx             = context.Background()
+		managedGateway  = k8stypes.NamespacedName{Namespace: "default", Name: "managed"}
+		pendingGateway  = k8stypes.NamespacedName{Namespace: "default", Name: "pending"}
+		gatewayURL      = "https://10.0.0.1:8444"
+		managedURL      = "https://10.0.0.2:8444"
+		clientsProvider = mockGatewayClientsProvider{
+			gatewayClients: []*adminapi.Client{lo.Must(adminapi.NewTestClient(gatewayURL))},
+			managedGatewayClients: map[k8stypes.NamespacedName][]*adminapi.Client{
+				managedGateway: {lo.Must(adminapi.NewTestClient(managedURL))},
+				// data-plane of this managed Gateway has no ready clients yet.
+				pendingGateway: nil,
+			},
+		}
+	)
+
+	serviceForHTTPRoute := func(name string) kongstate.Service {
+		return kongstate.Service{
+			Service: kong.Service{Name: kong.String(name), Host: kong.String(name)},
+			Routes: []kongstate.Route{{
+				Route: kong.Route{Name: kong.String(name), Paths: kong.StringSlice("/" + name)},
+				Ingress: util.K8sObjectInfo{
+					Name:             name,
+					Namespace:        "default",
+					GroupVersionKind: gatewayv1.SchemeGroupVersion.WithKind("HTTPRoute"),
+				},
+			}},
+		}
+	}
+	configBuilder := newMockKongConfigBuilder()
+	configBuilder.kongState = &kongstate.KongState{
+		Services: []kongstate.Service{
+			serviceForHTTPRoute("attached"),
+			serviceForHTTPRoute("attached-to-pending"),
+			serviceForHTTPRoute("attached-to-both"),
+			serviceForHTTPRoute("other"),
+		},
+	}
+	updateStrategyResolver := newMockUpdateStrategyResolver(t)
+	kongClient := setupTestKongClient(
+		t,
+		updateStrategyResolver,
+		clientsProvider,
+		mockConfigurationChangeDetector{hasConfigurationChanged: true},
+		configBuilder,
+		nil,
+		&mockKongLastValidConfigFetcher{},
+	)
+	for name, gateways := range map[string][]string{
+		"attached":            {managedGateway.Name},
+		"attached-to-pending": {pendingGateway.Name},
+		"attached-to-both":    {managedGateway.Name, "other"},
+		"other":               {"other"},
+	} {
+		require.NoError(t, kongClient.UpdateObject(&gatewayapi.HTTPRoute{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: name},
+			Spec: gatewayapi.HTTPRouteSpec{
+				CommonRouteSpec: gatewayapi.CommonRouteSpec{
+					ParentRefs: lo.Map(gateways, func(gateway string, _ int) gatewayapi.ParentReference {
+						return gatewayapi.ParentReference{Name: gatewayapi.ObjectName(gateway)}
+					}),
+				},
+			},
+		}))
+	}
+
+	require.NoError(t, kongClient.Update(ctx))
+	updateStrategyResolver.assertUpdateCalledForURLs([]string{gatewayURL, managedURL})
+
+	serviceNames := func(url string) []string {
+		content, ok := updateStrategyResolver.lastUpdatedContentForURL(url)
+		require.True(t, ok)
+		return lo.Map(content.Content.Services, func(s file.FService, _ int) string { return *s.Name })
+	}
+	require.ElementsMatch(t, []string{"attached-to-both", "other"}, serviceNames(gatewayURL),
+		"gateways should not get the routes attached only to managed gateways")
+	require.ElementsMatch(t, []string{"attached", "attached-to-both"}, serviceNames(managedURL),
+		"managed gateway should get only the routes attached to it")
+}
+
+func TestKongClient_ConfigChanges(t *testing.T) {
+	kongClient := setupTestKongClient(
+		t,
+		newMockUpdateStrategyResolver(t),
+		mockGatewayClientsProvider{},
+		mockConfigurationChangeDetector{},
+		newMockKongConfigBuilder(),
+		nil,
+		&mockKongLastValidConfigFetcher{},
+	)
+	changeNotified := func() bool {
+		select {
+		case <-kongClient.ConfigChanges():
+			return true
+		default:
+			return false
+		}
+	}
+
+	svc := &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            "svc",
+			Namespace:       "default",
+			ResourceVersion: "1",
+		},
+	}
+
+	t.Log("adding an object notifies about a change")
+	require.NoError(t, kongClient.UpdateObject(svc))
+	require.True(t, changeNotified())
+
+	t.Log("updating an object with the same resource version doesn't notify about a change")
+	require.NoError(t, kongClient.UpdateObject(svc))
+	require.False(t, changeNotified())
+
+	t.Log("updating an object with a new resource version notifies about a change")
+	svc.ResourceVersion = "2"
+	require.NoError(t, kongClient.UpdateObject(svc))
+	require.True(t, changeNotified())
+
+	t.Log("multiple changes are coalesced into a single notification")
+	svc.ResourceVersion = "3"
+	require.NoError(t, kongClient.UpdateObject(svc))
+	svc.ResourceVersion = "4"
+	require.NoError(t, kongClient.UpdateObject(svc))
+	require.True(t, changeNotified())
+	require.False(t, changeNotified())
+
+	t.Log("deleting an object notifies about a change")
+	require.NoError(t, kongClient.DeleteObject(svc))
+	require.True(t, changeNotified())
+
+	t.Log("deleting an object that isn't cached doesn't notify about a change")
+	require.NoError(t, kongClient.DeleteObject(svc))
+	require.False(t, changeNotified())
+}
@@ -23,6 +23,9 @@ type Consumer struct {
 	Oauth2Creds []*Oauth2Credential
 	MTLSAuths   []*MTLSAuth
 
+	// CustomCredentials are credentials of types unknown to the controller, provided by plugins installed in Kong.
+	CustomCredentials []*CustomCredential
+
 	K8sKongConsumer kongv1.KongConsumer
 }
 
@@ -61,8 +64,14 @@ func (c *Consumer) SanitizedCopy() *Consumer {
 			}
 			return
 		}(),
-		ACLGroups:       c.ACLGroups,
-		MTLSAuths:       c.MTLSAuths,
+		ACLGroups: c.ACLGroups,
+		MTLSAuths: c.MTLSAuths,
+		CustomCredentials: func() (res []*CustomCredential) {
+			for _, v := range c.CustomCredentials {
+				res = append(res, v.SanitizedCopy())
+			}
+			return
+		}(),
 		K8sKongConsumer: c.K8sKongConsumer,
 	}
 }
@@ -123,3 +132,12 @@ func (c *Consumer) SetCredential(credType string, credConfig interface{}, tags [
 	}
 	return nil
 }
+
+// SetCustomCredential adds a credential stored as the given Kong entity, whose type is unknown to the controller.
+func (c *Consumer) SetCustomCredential(entity string, credConfig map[string]interface{}, tags []*string) {
+	c.CustomCredentials = append(c.CustomCredentials, &CustomCredential{
+		Entity: entity,
+		Config: credConfig,
+		Tags:   tags,
+	})
+}
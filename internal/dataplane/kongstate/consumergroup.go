@@ -1,14 +1,67 @@
 package kongstate
 
 import (
+	"strings"
+
 	"github.com/kong/go-kong/kong"
+	k8stypes "k8s.io/apimachinery/pkg/types"
 
+	"github.com/kong/kubernetes-ingress-controller/v2/internal/gatewayapi"
+	kongv1 "github.com/kong/kubernetes-ingress-controller/v2/pkg/apis/configuration/v1"
 	kongv1beta1 "github.com/kong/kubernetes-ingress-controller/v2/pkg/apis/configuration/v1beta1"
 )
 
 // ConsumerGroup holds a Kong Consumer.
 type ConsumerGroup struct {
 	kong.ConsumerGroup
+	// Plugins are overrides of plugins' configuration for consumers in the group.
+	Plugins []kong.ConsumerGroupPlugin
 
 	K8sKongConsumerGroup kongv1beta1.KongConsumerGroup
 }
+
+// ConsumerGroupRefFromString parses a consumer group reference of a KongConsumer.
+// A reference is either a name of a group in the consumer's namespace or <namespace>/<name>.
+func ConsumerGroupRefFromString(consumerNamespace, ref string) k8stypes.NamespacedName {
+	if namespace, name, ok := strings.Cut(ref, "/"); ok {
+		return k8stypes.NamespacedName{Namespace: namespace, Name: name}
+	}
+	return k8stypes.NamespacedName{Namespace: consumerNamespace, Name: ref}
+}
+
+// isConsumerGroupRefGranted checks whether any of the grants permits KongConsumers from consumerNamespace
+// to reference the consumer group. ReferenceGrants are only honored in the namespace of the consumer group.
+func isConsumerGroupRefGranted(
+	grants []*gatewayapi.ReferenceGrant,
+	consumerNamespace string,
+	consumerGroup k8stypes.NamespacedName,
+) bool {
+	for _, grant := range grants {
+		if grant.Namespace != consumerGroup.Namespace {
+			continue
+		}
+		if !isConsumerNamespaceInGrantFrom(grant.Spec.From, consumerNamespace) {
+			continue
+		}
+		for _, to := range grant.Spec.To {
+			if string(to.Group) != kongv1beta1.GroupVersion.Group || string(to.Kind) != "KongConsumerGroup" {
+				continue
+			}
+			// if no referent name specified, matching group/kind is sufficient
+			if to.Name == nil || string(*to.Name) == consumerGroup.Name {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func isConsumerNamespaceInGrantFrom(from []gatewayapi.ReferenceGrantFrom, consumerNamespace string) bool {
+	for _, f := range from {
+		if string(f.Group) == kongv1.GroupVersion.Group && string(f.Kind) == "KongConsumer" &&
+			string(f.Namespace) == consumerNamespace {
+			return true
+		}
+	}
+	return false
+}
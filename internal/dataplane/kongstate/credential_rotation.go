@@ -0,0 +1,293 @@
+package kongstate
+
+import (
+	"fmt"
+	"maps"
+	"reflect"
+	"sort"
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	k8stypes "k8s.io/apimachinery/pkg/types"
+
+	"github.com/kong/kubernetes-ingress-controller/v2/internal/admission/validation/consumers/credentials"
+	"github.com/kong/kubernetes-ingress-controller/v2/internal/annotations"
+	k8sobj "github.com/kong/kubernetes-ingress-controller/v2/internal/util/kubernetes/object"
+	kongv1 "github.com/kong/kubernetes-ingress-controller/v2/pkg/apis/configuration/v1"
+)
+
+const (
+	// CredentialRotationStartedEventReason is the reason of events recorded on a KongConsumer when one of
+	// its credentials is rotated and its previous value is kept for the grace period.
+	CredentialRotationStartedEventReason = "KongCredentialRotationStarted"
+	// CredentialRotationCompletedEventReason is the reason of events recorded on a KongConsumer when the grace
+	// period of a rotated credential ends and its previous value is removed.
+	CredentialRotationCompletedEventReason = "KongCredentialRotationCompleted"
+)
+
+// rotatableCredentialTypes are the credential types whose previous values can be kept alongside the new ones.
+var rotatableCredentialTypes = map[string]struct{}{
+	"key-auth":  {},
+	"jwt":       {},
+	"hmac-auth": {},
+}
+
+// CredentialRotationEvent describes a change of a credential rotation that should be reported on the
+// KongConsumer the credential belongs to.
+type CredentialRotationEvent struct {
+	Consumer *kongv1.KongConsumer
+	Reason   string
+	Message  string
+}
+
+// CredentialRotations tracks credentials of KongConsumers across translations. When a credential Secret
+// annotated with konghq.com/credential-rotation-grace-period changes, the previous value of the credential
+// is kept in the configuration until the grace period ends.
+//
+// Changes observed while building a configuration are staged in a CredentialRotationsUpdate and only take
+// effect when it's committed, once the configuration has been applied. That way rotations neither start nor
+// end (and their grace periods don't run) while the configuration including them is rejected.
+//
+// The state is kept in memory only: rotations in progress end when the controller restarts.
+type CredentialRotations struct {
+	lock     sync.Mutex
+	now      func() time.Time
+	revision uint64
+	records  map[credentialRotationKey]credentialRotationRecord
+}
+
+type credentialRotationKey struct {
+	consumer k8stypes.NamespacedName
+	secret   string
+}
+
+type credentialRotationRecord struct {
+	credType string
+	config   map[string]interface{}
+	previous *rotatedCredential
+}
+
+type rotatedCredential struct {
+	config    map[string]interface{}
+	expiresAt time.Time
+}
+
+// NewCredentialRotations creates a CredentialRotations with no credentials tracked.
+func NewCredentialRotations() *CredentialRotations {
+	return &CredentialRotations{
+		now:     time.Now,
+		records: make(map[credentialRotationKey]credentialRotationRecord),
+	}
+}
+
+// NewUpdate starts staging changes of credential rotations observed while building a configuration.
+func (r *CredentialRotations) NewUpdate() *CredentialRotationsUpdate {
+	return r.newUpdate(false)
+}
+
+// NewReadOnlyUpdate returns an update which only provides previous values of credentials rotated in the
+// committed state. It neither starts nor ends rotations and can't be committed. It's used for configurations
+// that aren't built from the current state of objects, e.g. the fallback configuration.
+func (r *CredentialRotations) NewReadOnlyUpdate() *CredentialRotationsUpdate {
+	return r.newUpdate(true)
+}
+
+func (r *CredentialRotations) newUpdate(readOnly bool) *CredentialRotationsUpdate {
+	if r == nil {
+		return nil
+	}
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	return &CredentialRotationsUpdate{
+		rotations: r,
+		readOnly:  readOnly,
+		revision:  r.revision,
+		committed: maps.Clone(r.records),
+		records:   make(map[credentialRotationKey]credentialRotationRecord),
+	}
+}
+
+// CredentialRotationsUpdate holds changes of credential rotations observed while building a configuration.
+// A nil update tracks no rotations.
+type CredentialRotationsUpdate struct {
+	rotations *CredentialRotations
+	readOnly  bool
+	revision  uint64
+	committed map[credentialRotationKey]credentialRotationRecord
+	records   map[credentialRotationKey]credentialRotationRecord
+	events    []CredentialRotationEvent
+}
+
+// Commit makes the observed changes the current state of credential rotations and returns the events
+// describing rotations that started or completed with them. Credentials that haven't been observed, e.g.
+// because their KongConsumers were deleted or no longer reference them, are forgotten.
+//
+// Updates started before another one got committed are outdated and can't be committed.
+func (u *CredentialRotationsUpdate) Commit() []CredentialRotationEvent {
+	if u == nil || u.readOnly {
+		return nil
+	}
+	u.rotations.lock.Lock()
+	defer u.rotations.lock.Unlock()
+
+	if u.revision != u.rotations.revision {
+		return nil
+	}
+	u.rotations.records = u.records
+	u.rotations.revision++
+	return u.events
+}
+
+// InProgress returns the rotations whose previous credential values are included in the configuration
+// built with the update, by KongConsumer.
+func (u *CredentialRotationsUpdate) InProgress() map[k8stypes.NamespacedName][]k8sobj.CredentialRotation {
+	if u == nil {
+		return nil
+	}
+	records := u.records
+	if u.readOnly {
+		records = u.committed
+	}
+	inProgress := make(map[k8stypes.NamespacedName][]k8sobj.CredentialRotation)
+	for key, record := range records {
+		if record.previous == nil {
+			continue
+		}
+		inProgress[key.consumer] = append(inProgress[key.consumer], k8sobj.CredentialRotation{
+			Secret:    key.secret,
+			ExpiresAt: record.previous.expiresAt,
+		})
+	}
+	for _, rotations := range inProgress {
+		sort.Slice(rotations, func(i, j int) bool { return rotations[i].Secret < rotations[j].Secret })
+	}
+	return inProgress
+}
+
+// observe records the current configuration of a credential and returns the configuration of its
+// previous value when the credential is being rotated. An error is returned when the grace period
+// declared by the Secret is invalid, in which case the credential is not rotated.
+func (u *CredentialRotationsUpdate) observe(
+	consumer *kongv1.KongConsumer,
+	secret *corev1.Secret,
+	credType string,
+	config map[string]interface{},
+) (map[string]interface{}, error) {
+	if u == nil {
+		return nil, nil
+	}
+
+	// An invalid grace period is reported, but the credential is still tracked as if it declared none.
+	gracePeriod, err := credentialRotationGracePeriod(secret)
+
+	key := credentialRotationKey{
+		consumer: k8stypes.NamespacedName{Namespace: consumer.Namespace, Name: consumer.Name},
+		secret:   secret.Name,
+	}
+	now := u.rotations.now()
+	record, ok := u.committed[key]
+	if u.readOnly {
+		// Only rotations of the committed value of the credential are kept, as long as they're valid.
+		if !ok || record.previous == nil || !now.Before(record.previous.expiresAt) ||
+			record.credType != credType || !reflect.DeepEqual(record.config, config) {
+			return nil, err
+		}
+		return record.previous.config, err
+	}
+	if !ok {
+		u.records[key] = credentialRotationRecord{
+			credType: credType,
+			config:   config,
+		}
+		return nil, err
+	}
+
+	if record.previous != nil && !now.Before(record.previous.expiresAt) {
+		record.previous = nil
+		u.pushEvent(consumer, CredentialRotationCompletedEventReason,
+			fmt.Sprintf("grace period of credential %q ended, its previous value has been removed", secret.Name))
+	}
+
+	if record.credType != credType || !reflect.DeepEqual(record.config, config) {
+		if gracePeriod > 0 && record.credType == credType && canCredentialsCoexist(credType, record.config, config) {
+			record.previous = &rotatedCredential{
+				config:    previousCredentialConfig(credType, record.config, gracePeriod),
+				expiresAt: now.Add(gracePeriod),
+			}
+			u.pushEvent(consumer, CredentialRotationStartedEventReason,
+				fmt.Sprintf("credential %q has been rotated, its previous value remains valid until %s",
+					secret.Name, record.previous.expiresAt.UTC().Format(time.RFC3339)))
+		}
+		record.credType = credType
+		record.config = config
+	}
+
+	// The previous value can't be kept when it clashes with the current one, e.g. after rolling back.
+	if record.previous != nil && !canCredentialsCoexist(credType, record.previous.config, config) {
+		record.previous = nil
+		u.pushEvent(consumer, CredentialRotationCompletedEventReason,
+			fmt.Sprintf("previous value of credential %q has been removed as it conflicts with the current one", secret.Name))
+	}
+
+	u.records[key] = record
+	if record.previous == nil {
+		return nil, err
+	}
+	return record.previous.config, err
+}
+
+func (u *CredentialRotationsUpdate) pushEvent(consumer *kongv1.KongConsumer, reason, message string) {
+	u.events = append(u.events, CredentialRotationEvent{
+		Consumer: consumer,
+		Reason:   reason,
+		Message:  message,
+	})
+}
+
+// credentialRotationGracePeriod returns the grace period declared by a credential Secret, or 0 when it declares none.
+func credentialRotationGracePeriod(secret *corev1.Secret) (time.Duration, error) {
+	value, ok := annotations.ExtractCredentialRotationGracePeriod(secret.Annotations)
+	if !ok {
+		return 0, nil
+	}
+	gracePeriod, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s annotation: %w", annotations.AnnotationPrefix+annotations.CredentialRotationGracePeriodKey, err)
+	}
+	if gracePeriod < 0 {
+		return 0, fmt.Errorf("invalid %s annotation: grace period must not be negative",
+			annotations.AnnotationPrefix+annotations.CredentialRotationGracePeriodKey)
+	}
+	return gracePeriod, nil
+}
+
+// canCredentialsCoexist tells whether two credentials of the given type can be configured for a consumer
+// at the same time, which requires all their uniquely constrained fields (e.g. key) to differ.
+func canCredentialsCoexist(credType string, a, b map[string]interface{}) bool {
+	if _, ok := rotatableCredentialTypes[credType]; !ok {
+		return false
+	}
+	for field, value := range a {
+		if credentials.IsKeyUniqueConstrained(credType, field) && reflect.DeepEqual(value, b[field]) {
+			return false
+		}
+	}
+	return true
+}
+
+// previousCredentialConfig returns the configuration of a rotated credential. Credentials supporting
+// expiration in Kong expire on their own after the grace period, even if the controller is not running.
+func previousCredentialConfig(credType string, config map[string]interface{}, gracePeriod time.Duration) map[string]interface{} {
+	if credType != "key-auth" {
+		return config
+	}
+	previous := make(map[string]interface{}, len(config)+1)
+	for k, v := range config {
+		previous[k] = v
+	}
+	// Kong expects a whole number of seconds, rounding up never expires the credential before the grace period ends.
+	previous["ttl"] = int((gracePeriod + time.Second - 1) / time.Second)
+	return previous
+}
@@ -0,0 +1,199 @@
+package kongstate
+
+import (
+	"testing"
+	"time"
+
+	"github.com/go-logr/zapr"
+	"github.com/samber/lo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8stypes "k8s.io/apimachinery/pkg/types"
+
+	"github.com/kong/kubernetes-ingress-controller/v2/internal/admission/validation/consumers/credentials"
+	"github.com/kong/kubernetes-ingress-controller/v2/internal/annotations"
+	"github.com/kong/kubernetes-ingress-controller/v2/internal/dataplane/failures"
+	"github.com/kong/kubernetes-ingress-controller/v2/internal/labels"
+	"github.com/kong/kubernetes-ingress-controller/v2/internal/store"
+	k8sobj "github.com/kong/kubernetes-ingress-controller/v2/internal/util/kubernetes/object"
+	kongv1 "github.com/kong/kubernetes-ingress-controller/v2/pkg/apis/configuration/v1"
+)
+
+func TestFillConsumersAndCredentials_CredentialRotation(t *testing.T) {
+	now := time.Date(2023, time.October, 1, 12, 0, 0, 0, time.UTC)
+	rotations := NewCredentialRotations()
+	rotations.now = func() time.Time { return now }
+
+	consumer := &kongv1.KongConsumer{
+		TypeMeta: kongConsumerTypeMeta,
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "foo",
+			Namespace:   "default",
+			Annotations: map[string]string{annotations.IngressClassKey: annotations.DefaultIngressClass},
+		},
+		Username:    "foo",
+		Credentials: []string{"foo-key"},
+	}
+	newSecret := func(key, gracePeriod string) *corev1.Secret {
+		secret := &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "foo-key",
+				Namespace: "default",
+				Labels: map[string]string{
+					labels.LabelPrefix + labels.CredentialKey: "key-auth",
+				},
+			},
+			Data: map[string][]byte{"key": []byte(key)},
+		}
+		if gracePeriod != "" {
+			secret.Annotations = map[string]string{
+				annotations.AnnotationPrefix + annotations.CredentialRotationGracePeriodKey: gracePeriod,
+			}
+		}
+		return secret
+	}
+	logger := zapr.NewLogger(zap.NewNop())
+	fill := func(t *testing.T, update *CredentialRotationsUpdate, secret *corev1.Secret) (Consumer, []failures.ResourceFailure) {
+		s, err := store.NewFakeStore(store.FakeObjects{
+			Secrets:       []*corev1.Secret{secret},
+			KongConsumers: []*kongv1.KongConsumer{consumer},
+		})
+		require.NoError(t, err)
+		failuresCollector := failures.NewResourceFailuresCollector(logger)
+		state := KongState{}
+		state.FillConsumersAndCredentials(logger, s, failuresCollector, credentials.NewRegistry(nil, false), update)
+		require.Len(t, state.Consumers, 1)
+		return state.Consumers[0], failuresCollector.PopResourceFailures()
+	}
+	eventReasons := func(events []CredentialRotationEvent) []string {
+		return lo.Map(events, func(e CredentialRotationEvent, _ int) string { return e.Reason })
+	}
+	// fillAndCommit fills consumers with a new update and commits it, as done when the configuration gets applied.
+	fillAndCommit := func(t *testing.T, secret *corev1.Secret) (Consumer, []failures.ResourceFailure, []string) {
+		update := rotations.NewUpdate()
+		c, resourceFailures := fill(t, update, secret)
+		return c, resourceFailures, eventReasons(update.Commit())
+	}
+	keys := func(c Consumer) []string {
+		return lo.Map(c.KeyAuths, func(k *KeyAuth, _ int) string { return *k.Key })
+	}
+
+	t.Log("initial credential")
+	c, resourceFailures, events := fillAndCommit(t, newSecret("old", "1h"))
+	require.Empty(t, resourceFailures)
+	assert.Equal(t, []string{"old"}, keys(c))
+	assert.Empty(t, events)
+
+	t.Log("rotation doesn't start until the configuration including it is committed")
+	update := rotations.NewUpdate()
+	c, _ = fill(t, update, newSecret("new", "1h"))
+	assert.Equal(t, []string{"new", "old"}, keys(c))
+	now = now.Add(10 * time.Minute)
+	c, _ = fill(t, rotations.NewReadOnlyUpdate(), newSecret("new", "1h"))
+	assert.Equal(t, []string{"new"}, keys(c), "read-only update shouldn't start rotations")
+
+	t.Log("rotated credential keeps its previous value with ttl during the grace period")
+	c, resourceFailures, events = fillAndCommit(t, newSecret("new", "1h"))
+	require.Empty(t, resourceFailures)
+	assert.Equal(t, []string{"new", "old"}, keys(c))
+	require.NotNil(t, c.KeyAuths[1].TTL)
+	assert.Equal(t, 3600, *c.KeyAuths[1].TTL)
+	assert.Nil(t, c.KeyAuths[0].TTL)
+	assert.Equal(t, []string{CredentialRotationStartedEventReason}, events)
+	assert.Empty(t, update.Commit(), "outdated update shouldn't be committed")
+
+	readOnlyUpdate := rotations.NewReadOnlyUpdate()
+	c, _ = fill(t, readOnlyUpdate, newSecret("new", "1h"))
+	assert.Equal(t, []string{"new", "old"}, keys(c), "read-only update should keep previous values of committed rotations")
+	assert.Equal(t, map[k8stypes.NamespacedName][]k8sobj.CredentialRotation{
+		{Namespace: "default", Name: "foo"}: {{Secret: "foo-key", ExpiresAt: now.Add(time.Hour)}},
+	}, readOnlyUpdate.InProgress())
+	c, _ = fill(t, rotations.NewReadOnlyUpdate(), newSecret("newer", "1h"))
+	assert.Equal(t, []string{"newer"}, keys(c), "read-only update should keep previous values of committed values only")
+	assert.Empty(t, readOnlyUpdate.Commit(), "read-only update can't be committed")
+
+	now = now.Add(30 * time.Minute)
+	c, _, events = fillAndCommit(t, newSecret("new", "1h"))
+	assert.Equal(t, []string{"new", "old"}, keys(c))
+	assert.Empty(t, events, "rotation should be reported only once")
+
+	t.Log("previous value is kept until the configuration removing it is committed")
+	now = now.Add(30 * time.Minute)
+	update = rotations.NewUpdate()
+	c, _ = fill(t, update, newSecret("new", "1h"))
+	assert.Equal(t, []string{"new"}, keys(c))
+	assert.Len(t, update.InProgress(), 0)
+	assert.Len(t, rotations.NewReadOnlyUpdate().InProgress(), 1)
+	assert.Equal(t, []string{CredentialRotationCompletedEventReason}, eventReasons(update.Commit()))
+	assert.Len(t, rotations.NewReadOnlyUpdate().InProgress(), 0)
+
+	t.Log("credential without a grace period is replaced immediately")
+	c, _, events = fillAndCommit(t, newSecret("newer", ""))
+	assert.Equal(t, []string{"newer"}, keys(c))
+	assert.Empty(t, events)
+
+	t.Log("previous value conflicting with the current one is removed")
+	_, _, events = fillAndCommit(t, newSecret("newest", "1h"))
+	assert.Equal(t, []string{CredentialRotationStartedEventReason}, events)
+	c, _, events = fillAndCommit(t, newSecret("newer", ""))
+	assert.Equal(t, []string{"newer"}, keys(c))
+	assert.Equal(t, []string{CredentialRotationCompletedEventReason}, events)
+
+	t.Log("invalid grace period is reported and the credential is replaced")
+	c, resourceFailures, events = fillAndCommit(t, newSecret("latest", "a day"))
+	assert.Equal(t, []string{"latest"}, keys(c))
+	require.Len(t, resourceFailures, 1)
+	assert.Equal(t,
+		`credential "foo-key" failure: invalid konghq.com/credential-rotation-grace-period annotation: time: invalid duration "a day"`,
+		resourceFailures[0].Message(),
+	)
+	assert.Empty(t, events)
+}
+
+func TestCanCredentialsCoexist(t *testing.T) {
+	testCases := []struct {
+		name     string
+		credType string
+		a, b     map[string]interface{}
+		expected bool
+	}{
+		{
+			name:     "key-auth with different keys",
+			credType: "key-auth",
+			a:        map[string]interface{}{"key": "old"},
+			b:        map[string]interface{}{"key": "new"},
+			expected: true,
+		},
+		{
+			name:     "hmac-auth with the same username",
+			credType: "hmac-auth",
+			a:        map[string]interface{}{"username": "foo", "secret": "old"},
+			b:        map[string]interface{}{"username": "foo", "secret": "new"},
+			expected: false,
+		},
+		{
+			name:     "jwt with different keys",
+			credType: "jwt",
+			a:        map[string]interface{}{"key": "old", "secret": "old"},
+			b:        map[string]interface{}{"key": "new", "secret": "new"},
+			expected: true,
+		},
+		{
+			name:     "basic-auth is not rotatable",
+			credType: "basic-auth",
+			a:        map[string]interface{}{"username": "old"},
+			b:        map[string]interface{}{"username": "new"},
+			expected: false,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, canCredentialsCoexist(tc.credType, tc.a, tc.b))
+		})
+	}
+}
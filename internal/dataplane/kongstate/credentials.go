@@ -45,6 +45,14 @@ type MTLSAuth struct {
 	kong.MTLSAuth
 }
 
+// CustomCredential represents a credential of a type provided by a plugin and unknown to the controller.
+type CustomCredential struct {
+	// Entity is the name of the Kong entity the credential is stored as (e.g. keyauth_enc_credentials).
+	Entity string
+	Config map[string]interface{}
+	Tags   []*string
+}
+
 func NewKeyAuth(config interface{}) (*KeyAuth, error) {
 	var res KeyAuth
 	err := decodeCredential(config, &res.KeyAuth)
@@ -221,6 +229,20 @@ func (c *Oauth2Credential) SanitizedCopy() *Oauth2Credential {
 	}
 }
 
+// SanitizedCopy returns a shallow copy with sensitive values redacted best-effort.
+// As the fields of custom credentials are unknown, all of them are redacted.
+func (c *CustomCredential) SanitizedCopy() *CustomCredential {
+	config := make(map[string]interface{}, len(c.Config))
+	for k := range c.Config {
+		config[k] = *redactedString
+	}
+	return &CustomCredential{
+		Entity: c.Entity,
+		Config: config,
+		Tags:   c.Tags,
+	}
+}
+
 func decodeCredential(credConfig interface{},
 	credStructPointer interface{},
 ) error {
@@ -3,16 +3,17 @@ package kongstate
 import (
 	"crypto/sha256"
 	"fmt"
-	"strconv"
 	"strings"
 
 	"github.com/go-logr/logr"
 	"github.com/kong/go-kong/kong"
+	"github.com/samber/lo"
 	"k8s.io/apimachinery/pkg/util/sets"
 
 	"github.com/kong/kubernetes-ingress-controller/v2/internal/admission/validation/consumers/credentials"
 	"github.com/kong/kubernetes-ingress-controller/v2/internal/annotations"
 	"github.com/kong/kubernetes-ingress-controller/v2/internal/dataplane/failures"
+	"github.com/kong/kubernetes-ingress-controller/v2/internal/gatewayapi"
 	"github.com/kong/kubernetes-ingress-controller/v2/internal/store"
 	"github.com/kong/kubernetes-ingress-controller/v2/internal/util"
 )
@@ -58,13 +59,131 @@ func (ks *KongState) SanitizedCopy() *KongState {
 	}
 }
 
+// WithoutRoutes returns a shallow copy without the routes for which dropRoute returns true. Services left without
+// routes this way are dropped along with the upstreams only they use and the plugins of the dropped services and
+// routes. Services which had no routes in the first place are kept, as is everything else.
+func (ks *KongState) WithoutRoutes(dropRoute func(Route) bool) *KongState {
+	var (
+		services             []Service
+		droppedServiceNames  = sets.New[string]()
+		droppedRouteNames    = sets.New[string]()
+		keptUpstreamNames    = sets.New[string]()
+		droppedUpstreamNames = sets.New[string]()
+	)
+	for _, svc := range ks.Services {
+		routes := make([]Route, 0, len(svc.Routes))
+		for _, r := range svc.Routes {
+			if !dropRoute(r) {
+				routes = append(routes, r)
+			} else if r.Name != nil {
+				droppedRouteNames.Insert(*r.Name)
+			}
+		}
+		if len(svc.Routes) > 0 && len(routes) == 0 {
+			droppedServiceNames.Insert(*svc.Name)
+			if svc.Host != nil {
+				droppedUpstreamNames.Insert(*svc.Host)
+			}
+			continue
+		}
+		if len(routes) < len(svc.Routes) {
+			svc.Routes = routes
+		}
+		services = append(services, svc)
+		if svc.Host != nil {
+			keptUpstreamNames.Insert(*svc.Host)
+		}
+	}
+	droppedUpstreamNames = droppedUpstreamNames.Difference(keptUpstreamNames)
+
+	filtered := *ks
+	filtered.Services = services
+	filtered.Upstreams = lo.Reject(ks.Upstreams, func(u Upstream, _ int) bool {
+		return u.Name != nil && droppedUpstreamNames.Has(*u.Name)
+	})
+	filtered.Plugins = lo.Reject(ks.Plugins, func(p Plugin, _ int) bool {
+		// plugins reference services and routes by their names.
+		return (p.Service != nil && p.Service.ID != nil && droppedServiceNames.Has(*p.Service.ID)) ||
+			(p.Route != nil && p.Route.ID != nil && droppedRouteNames.Has(*p.Route.ID))
+	})
+	return &filtered
+}
+
+// FilteredByRoutes returns a shallow copy with only the routes for which keepRoute returns true, along with the
+// services, upstreams and plugins they use, and only the certificates for which keepCertificate returns true.
+// Consumers, consumer groups, CA certificates and licenses aren't tied to routes, so they're kept as they are.
+func (ks *KongState) FilteredByRoutes(keepRoute func(Route) bool, keepCertificate func(Certificate) bool) *KongState {
+	var (
+		services      []Service
+		serviceNames  = sets.New[string]()
+		upstreamNames = sets.New[string]()
+		routeNames    = sets.New[string]()
+	)
+	for _, svc := range ks.Services {
+		routes := lo.Filter(svc.Routes, func(r Route, _ int) bool { return keepRoute(r) })
+		if len(routes) == 0 {
+			continue
+		}
+		svc.Routes = routes
+		services = append(services, svc)
+		serviceNames.Insert(*svc.Name)
+		if svc.Host != nil {
+			upstreamNames.Insert(*svc.Host)
+		}
+		for _, r := range routes {
+			routeNames.Insert(*r.Name)
+		}
+	}
+
+	return &KongState{
+		Services: services,
+		Upstreams: lo.Filter(ks.Upstreams, func(u Upstream, _ int) bool {
+			return u.Name != nil && upstreamNames.Has(*u.Name)
+		}),
+		Certificates:   lo.Filter(ks.Certificates, func(c Certificate, _ int) bool { return keepCertificate(c) }),
+		CACertificates: ks.CACertificates,
+		Licenses:       ks.Licenses,
+		Plugins: lo.Filter(ks.Plugins, func(p Plugin, _ int) bool {
+			// plugins reference services and routes by their names.
+			if p.Service != nil && (p.Service.ID == nil || !serviceNames.Has(*p.Service.ID)) {
+				return false
+			}
+			if p.Route != nil && (p.Route.ID == nil || !routeNames.Has(*p.Route.ID)) {
+				return false
+			}
+			return true
+		}),
+		Consumers:      ks.Consumers,
+		ConsumerGroups: ks.ConsumerGroups,
+	}
+}
+
 func (ks *KongState) FillConsumersAndCredentials(
 	logger logr.Logger,
 	s store.Storer,
 	failuresCollector *failures.ResourceFailuresCollector,
+	credentialRegistry *credentials.Registry,
+	credentialRotations *CredentialRotationsUpdate,
 ) {
 	consumerIndex := make(map[string]Consumer)
 
+	// ReferenceGrants are listed lazily, only when a consumer references a consumer group from another namespace.
+	var (
+		referenceGrants       []*gatewayapi.ReferenceGrant
+		referenceGrantsListed bool
+	)
+	listReferenceGrants := func() ([]*gatewayapi.ReferenceGrant, error) {
+		if referenceGrantsListed {
+			return referenceGrants, nil
+		}
+		grants, err := s.ListReferenceGrants()
+		if err != nil {
+			return nil, err
+		}
+		referenceGrants, referenceGrantsListed = grants, true
+		return referenceGrants, nil
+	}
+
 	// build consumer index
 	for _, consumer := range s.ListKongConsumers() {
 		var c Consumer
@@ -82,8 +201,25 @@ func (ks *KongState) FillConsumersAndCredentials(
 		c.Tags = util.GenerateTagsForObject(consumer)
 
 		// Get consumer groups
-		for _, cgName := range consumer.ConsumerGroups {
-			cg, err := s.GetKongConsumerGroup(consumer.Namespace, cgName)
+		for _, cgRef := range consumer.ConsumerGroups {
+			cgNN := ConsumerGroupRefFromString(consumer.Namespace, cgRef)
+			if cgNN.Namespace != consumer.Namespace {
+				grants, err := listReferenceGrants()
+				if err != nil {
+					failuresCollector.PushResourceFailure(
+						fmt.Sprintf("failed to list ReferenceGrants for consumer group %q: %v", cgRef, err), consumer,
+					)
+					continue
+				}
+				if !isConsumerGroupRefGranted(grants, consumer.Namespace, cgNN) {
+					failuresCollector.PushResourceFailure(
+						fmt.Sprintf("consumer group %q is not permitted by any ReferenceGrant in namespace %q", cgRef, cgNN.Namespace),
+						consumer,
+					)
+					continue
+				}
+			}
+			cg, err := s.GetKongConsumerGroup(cgNN.Namespace, cgNN.Name)
 			if err != nil {
 				failuresCollector.PushResourceFailure(fmt.Sprintf("nonexistent consumer group: %q", err), consumer)
 				continue
@@ -102,7 +238,6 @@ func (ks *KongState) FillConsumersAndCredentials(
 				pushCredentialResourceFailures(fmt.Sprintf("failed to fetch secret: %v", err))
 				continue
 			}
-			credConfig := map[string]interface{}{}
 			// try the label first. if it's present, no need to check the field
 			credType, credTypeSource := util.ExtractKongCredentialType(secret)
 			if credTypeSource == util.CredentialTypeFromField {
@@ -110,58 +245,53 @@ func (ks *KongState) FillConsumersAndCredentials(
 					fmt.Sprintf("Secret uses deprecated kongCredType field, needs konghq.com/credential=%s label", credType),
 					"namesapce", secret.Namespace, "name", secret.Name)
 			}
-			if !credentials.SupportedTypes.Has(credType) {
-				pushCredentialResourceFailures(
-					fmt.Sprintf("failed to provision credential: unsupported credential type: %q", credType),
-				)
+			t, err := credentialRegistry.LookupCached(credType)
+			if err != nil {
+				pushCredentialResourceFailures(fmt.Sprintf("failed to provision credential: %v", err))
 				continue
 			}
-			for k, v := range secret.Data {
-				// TODO populate these based on schema from Kong
-				// and remove this workaround
-				if k == "redirect_uris" {
-					credConfig[k] = strings.Split(string(v), ",")
-					continue
-				}
-				// TODO this is a kongCredType-agnostic mutation that should only apply to Oauth2 credentials.
-				// However, the credential-specific code after deals only in interface{}s, and we can't fix individual
-				// keys. To handle this properly we'd need to refactor the types used in all following code.
-				if k == "hash_secret" {
-					boolVal, err := strconv.ParseBool(string(v))
-					if err != nil {
-						// add a translation error here to tell that parsing hash_secret failed.
-						pushCredentialResourceFailures(
-							fmt.Sprintf("failed to parse hash_secret to bool: %v. defaulting to false", err),
-						)
-						credConfig[k] = false
-					} else {
-						credConfig[k] = boolVal
-					}
+			credConfig, coerceErrs := t.Coerce(secret.Data)
+			for _, err := range coerceErrs {
+				pushCredentialResourceFailures(err.Error())
+			}
+			credTags := util.GenerateTagsForObject(secret)
+			if !t.Builtin {
+				if missing := lo.Filter(t.RequiredFields, func(f string, _ int) bool {
+					_, ok := credConfig[f]
+					return !ok
+				}); len(missing) > 0 {
+					pushCredentialResourceFailures(
+						fmt.Sprintf("failed to provision credential: missing required field(s): %s", strings.Join(missing, ", ")),
+					)
 					continue
 				}
-				// ttl is a field that only appears in keyAuth credentials and has int type.
-				// Same as above, we cannot fix individual keys after translated to credConfig.
-				if k == "ttl" {
-					intVal, err := strconv.Atoi(string(v))
-					if err != nil {
-						// add a translation error here to tell that parsing TTL failed.
-						pushCredentialResourceFailures(
-							fmt.Sprintf("failed to parse ttl to int: %v, skipfilling the field", err),
-						)
-					} else {
-						credConfig[k] = intVal
-					}
+				// Custom credentials refer to their consumers by username.
+				if consumer.Username == "" {
+					pushCredentialResourceFailures(
+						fmt.Sprintf("failed to provision credential: credentials of type %q require the consumer to have a username", t.Name),
+					)
 					continue
 				}
-				credConfig[k] = string(v)
+				c.SetCustomCredential(t.Entity, credConfig, credTags)
+				continue
 			}
-			credTags := util.GenerateTagsForObject(secret)
-			if err := c.SetCredential(credType, credConfig, credTags); err != nil {
+			if err := c.SetCredential(t.Name, credConfig, credTags); err != nil {
 				pushCredentialResourceFailures(
 					fmt.Sprintf("failed to provision credential: %v", err),
 				)
 				continue
 			}
+			previousCredConfig, err := credentialRotations.observe(consumer, secret, t.Name, credConfig)
+			if err != nil {
+				pushCredentialResourceFailures(err.Error())
+			}
+			if previousCredConfig != nil {
+				if err := c.SetCredential(t.Name, previousCredConfig, credTags); err != nil {
+					pushCredentialResourceFailures(
+						fmt.Sprintf("failed to provision previous value of rotated credential: %v", err),
+					)
+				}
+			}
 		}
 
 		consumerIndex[consumer.Namespace+"/"+consumer.Name] = c
@@ -173,15 +303,33 @@ func (ks *KongState) FillConsumersAndCredentials(
 	}
 }
 
-func (ks *KongState) FillConsumerGroups(_ logr.Logger, s store.Storer) {
+func (ks *KongState) FillConsumerGroups(
+	_ logr.Logger,
+	s store.Storer,
+	failuresCollector *failures.ResourceFailuresCollector,
+) {
 	for _, cg := range s.ListKongConsumerGroups() {
-		ks.ConsumerGroups = append(ks.ConsumerGroups, ConsumerGroup{
+		consumerGroup := ConsumerGroup{
 			ConsumerGroup: kong.ConsumerGroup{
 				Name: kong.String(cg.Name),
 				Tags: util.GenerateTagsForObject(cg),
 			},
 			K8sKongConsumerGroup: *cg,
-		})
+		}
+		for _, p := range cg.Spec.Plugins {
+			config, err := RawConfigToConfiguration(p.Config)
+			if err != nil {
+				failuresCollector.PushResourceFailure(
+					fmt.Sprintf("could not parse configuration of plugin %q override: %v", p.PluginName, err), cg,
+				)
+				continue
+			}
+			consumerGroup.Plugins = append(consumerGroup.Plugins, kong.ConsumerGroupPlugin{
+				Name:   kong.String(p.PluginName),
+				Config: config,
+			})
+		}
+		ks.ConsumerGroups = append(ks.ConsumerGroups, consumerGroup)
 	}
 }
 
@@ -263,6 +411,8 @@ func (ks *KongState) getPluginRelations() map[string]util.ForeignRelations {
 		for j := range ks.Services[i].Routes {
 			ingress := ks.Services[i].Routes[j].Ingress
 			pluginList := annotations.ExtractKongPluginsFromAnnotations(ingress.Annotations)
+			// a plugin can be attached to a route only once, hence duplicates are dropped.
+			pluginList = lo.Uniq(append(pluginList, ks.Services[i].Routes[j].ExtensionRefPlugins...))
 			for _, pluginName := range pluginList {
 				addRouteRelation(ingress.Namespace, pluginName, *ks.Services[i].Routes[j].Name)
 			}
@@ -421,6 +571,71 @@ func (ks *KongState) FillPlugins(
 	failuresCollector *failures.ResourceFailuresCollector,
 ) {
 	ks.Plugins = buildPlugins(log, s, failuresCollector, ks.getPluginRelations())
+	ks.mergeGeneratedPreFunctionPlugins(failuresCollector)
+}
+
+// preFunctionPluginName is the name of the plugin generated for routes to run code the controller
+// generates, e.g. enforcing HTTPRoute query param matches with Kong's traditional router.
+const preFunctionPluginName = "pre-function"
+
+// mergeGeneratedPreFunctionPlugins merges pre-function plugins generated for routes into pre-function
+// KongPlugins attached to the same routes, as Kong allows a single plugin of each name per route. Functions
+// of the generated plugin are run first, for requests they terminate (e.g. not matching query params of the
+// route) not to reach the functions of the KongPlugin. Disabled KongPlugins can't be merged with, so they're
+// not attached to such routes and reported as translation failures.
+func (ks *KongState) mergeGeneratedPreFunctionPlugins(failuresCollector *failures.ResourceFailuresCollector) {
+	routesByName := make(map[string]*Route)
+	for i := range ks.Services {
+		for j := range ks.Services[i].Routes {
+			if route := &ks.Services[i].Routes[j]; route.Name != nil {
+				routesByName[*route.Name] = route
+			}
+		}
+	}
+
+	plugins := make([]Plugin, 0, len(ks.Plugins))
+	for _, plugin := range ks.Plugins {
+		if plugin.Name == nil || *plugin.Name != preFunctionPluginName || plugin.Route == nil || plugin.Route.ID == nil {
+			plugins = append(plugins, plugin)
+			continue
+		}
+		route, ok := routesByName[*plugin.Route.ID]
+		if !ok {
+			plugins = append(plugins, plugin)
+			continue
+		}
+		_, i, found := lo.FindIndexOf(route.Plugins, func(p kong.Plugin) bool {
+			return p.Name != nil && *p.Name == preFunctionPluginName
+		})
+		if !found {
+			plugins = append(plugins, plugin)
+			continue
+		}
+		if plugin.Enabled != nil && !*plugin.Enabled {
+			failuresCollector.PushResourceFailure(
+				fmt.Sprintf("disabled %s plugin can't be attached to route %s, which requires the plugin enabled",
+					preFunctionPluginName, *route.Name),
+				plugin.K8sParent,
+			)
+			continue
+		}
+
+		generated, _ := route.Plugins[i].Config["access"].([]string)
+		access := lo.Map(generated, func(f string, _ int) interface{} { return f })
+		switch existing := plugin.Config["access"].(type) {
+		case []interface{}:
+			access = append(access, existing...)
+		case []string:
+			access = append(access, lo.Map(existing, func(f string, _ int) interface{} { return f })...)
+		}
+		if plugin.Config == nil {
+			plugin.Config = kong.Configuration{}
+		}
+		plugin.Config["access"] = access
+		route.Plugins = append(route.Plugins[:i:i], route.Plugins[i+1:]...)
+		plugins = append(plugins, plugin)
+	}
+	ks.Plugins = plugins
 }
 
 // FillIDs iterates over the KongState and fills in the ID field for each entity
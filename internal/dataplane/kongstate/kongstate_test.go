@@ -1,6 +1,7 @@
 package kongstate
 
 import (
+	"context"
 	"fmt"
 	"reflect"
 	"strconv"
@@ -14,12 +15,15 @@ import (
 	"github.com/stretchr/testify/require"
 	"go.uber.org/zap"
 	corev1 "k8s.io/api/core/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	k8stypes "k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/sets"
 
+	"github.com/kong/kubernetes-ingress-controller/v2/internal/admission/validation/consumers/credentials"
 	"github.com/kong/kubernetes-ingress-controller/v2/internal/annotations"
 	"github.com/kong/kubernetes-ingress-controller/v2/internal/dataplane/failures"
+	"github.com/kong/kubernetes-ingress-controller/v2/internal/gatewayapi"
 	"github.com/kong/kubernetes-ingress-controller/v2/internal/labels"
 	"github.com/kong/kubernetes-ingress-controller/v2/internal/store"
 	"github.com/kong/kubernetes-ingress-controller/v2/internal/util"
@@ -81,6 +85,112 @@ func TestKongState_SanitizedCopy(t *testing.T) {
 	ensureAllKongStateFieldsAreCoveredInTest(t, testedFields.UnsortedList())
 }
 
+func TestKongState_FilteredByRoutes(t *testing.T) {
+	service := func(name string, routeNames ...string) Service {
+		return Service{
+			Service: kong.Service{Name: kong.String(name), Host: kong.String(name + ".upstream")},
+			Routes: lo.Map(routeNames, func(routeName string, _ int) Route {
+				return Route{Route: kong.Route{Name: kong.String(routeName)}}
+			}),
+		}
+	}
+	in := KongState{
+		Services: []Service{
+			service("kept", "kept-route", "dropped-route"),
+			service("dropped", "other-dropped-route"),
+		},
+		Upstreams: []Upstream{
+			{Upstream: kong.Upstream{Name: kong.String("kept.upstream")}},
+			{Upstream: kong.Upstream{Name: kong.String("dropped.upstream")}},
+		},
+		Certificates: []Certificate{
+			{Certificate: kong.Certificate{ID: kong.String("kept")}},
+			{Certificate: kong.Certificate{ID: kong.String("dropped")}},
+		},
+		CACertificates: []kong.CACertificate{{ID: kong.String("1")}},
+		Plugins: []Plugin{
+			{Plugin: kong.Plugin{Name: kong.String("global")}},
+			{Plugin: kong.Plugin{Name: kong.String("kept-service"), Service: &kong.Service{ID: kong.String("kept")}}},
+			{Plugin: kong.Plugin{Name: kong.String("kept-route"), Route: &kong.Route{ID: kong.String("kept-route")}}},
+			{Plugin: kong.Plugin{Name: kong.String("dropped-service"), Service: &kong.Service{ID: kong.String("dropped")}}},
+			{Plugin: kong.Plugin{Name: kong.String("dropped-route"), Route: &kong.Route{ID: kong.String("dropped-route")}}},
+		},
+		Consumers:      []Consumer{{Consumer: kong.Consumer{Username: kong.String("consumer")}}},
+		Licenses:       []License{{kong.License{ID: kong.String("1")}}},
+		ConsumerGroups: []ConsumerGroup{{ConsumerGroup: kong.ConsumerGroup{Name: kong.String("consumer-group")}}},
+	}
+	ensureAllKongStateFieldsAreCoveredInTest(t, extractNotEmptyFieldNames(in))
+
+	got := in.FilteredByRoutes(
+		func(r Route) bool { return *r.Name == "kept-route" },
+		func(c Certificate) bool { return *c.ID == "kept" },
+	)
+	require.Equal(t, &KongState{
+		Services:       []Service{service("kept", "kept-route")},
+		Upstreams:      []Upstream{{Upstream: kong.Upstream{Name: kong.String("kept.upstream")}}},
+		Certificates:   []Certificate{{Certificate: kong.Certificate{ID: kong.String("kept")}}},
+		CACertificates: in.CACertificates,
+		Plugins:        in.Plugins[:3],
+		Consumers:      in.Consumers,
+		Licenses:       in.Licenses,
+		ConsumerGroups: in.ConsumerGroups,
+	}, got)
+	require.Len(t, in.Services[0].Routes, 2, "routes of the original state should be left intact")
+}
+
+func TestKongState_WithoutRoutes(t *testing.T) {
+	service := func(name, host string, routeNames ...string) Service {
+		return Service{
+			Service: kong.Service{Name: kong.String(name), Host: kong.String(host)},
+			Routes: lo.Map(routeNames, func(routeName string, _ int) Route {
+				return Route{Route: kong.Route{Name: kong.String(routeName)}}
+			}),
+		}
+	}
+	in := KongState{
+		Services: []Service{
+			service("kept", "kept.upstream", "kept-route", "dropped-route"),
+			service("dropped", "dropped.upstream", "other-dropped-route"),
+			service("dropped-sharing-upstream", "kept.upstream", "another-dropped-route"),
+			service("without-routes", "without-routes.upstream"),
+		},
+		Upstreams: []Upstream{
+			{Upstream: kong.Upstream{Name: kong.String("kept.upstream")}},
+			{Upstream: kong.Upstream{Name: kong.String("dropped.upstream")}},
+			{Upstream: kong.Upstream{Name: kong.String("without-routes.upstream")}},
+		},
+		Certificates:   []Certificate{{Certificate: kong.Certificate{ID: kong.String("1")}}},
+		CACertificates: []kong.CACertificate{{ID: kong.String("1")}},
+		Plugins: []Plugin{
+			{Plugin: kong.Plugin{Name: kong.String("global")}},
+			{Plugin: kong.Plugin{Name: kong.String("kept-service"), Service: &kong.Service{ID: kong.String("kept")}}},
+			{Plugin: kong.Plugin{Name: kong.String("kept-route"), Route: &kong.Route{ID: kong.String("kept-route")}}},
+			{Plugin: kong.Plugin{Name: kong.String("dropped-service"), Service: &kong.Service{ID: kong.String("dropped")}}},
+			{Plugin: kong.Plugin{Name: kong.String("dropped-route"), Route: &kong.Route{ID: kong.String("dropped-route")}}},
+		},
+		Consumers:      []Consumer{{Consumer: kong.Consumer{Username: kong.String("consumer")}}},
+		Licenses:       []License{{kong.License{ID: kong.String("1")}}},
+		ConsumerGroups: []ConsumerGroup{{ConsumerGroup: kong.ConsumerGroup{Name: kong.String("consumer-group")}}},
+	}
+	ensureAllKongStateFieldsAreCoveredInTest(t, extractNotEmptyFieldNames(in))
+
+	got := in.WithoutRoutes(func(r Route) bool { return *r.Name != "kept-route" })
+	require.Equal(t, &KongState{
+		Services: []Service{
+			service("kept", "kept.upstream", "kept-route"),
+			service("without-routes", "without-routes.upstream"),
+		},
+		Upstreams:      []Upstream{in.Upstreams[0], in.Upstreams[2]},
+		Certificates:   in.Certificates,
+		CACertificates: in.CACertificates,
+		Plugins:        in.Plugins[:3],
+		Consumers:      in.Consumers,
+		Licenses:       in.Licenses,
+		ConsumerGroups: in.ConsumerGroups,
+	}, got)
+	require.Len(t, in.Services[0].Routes, 2, "routes of the original state should be left intact")
+}
+
 // extractNotEmptyFieldNames returns the names of all non-empty fields in the given KongState.
 // This is to programmatically find out what fields are used in a test case.
 func extractNotEmptyFieldNames(s KongState) []string {
@@ -283,6 +393,51 @@ func TestGetPluginRelations(t *testing.T) {
 				"ns2:baz": {Route: []string{"bar-route"}},
 			},
 		},
+		{
+			name: "routes with ExtensionRef plugins",
+			args: args{
+				state: KongState{
+					Services: []Service{
+						{
+							Service: kong.Service{
+								Name: kong.String("foo-service"),
+							},
+							Routes: []Route{
+								{
+									Route: kong.Route{
+										Name: kong.String("foo-route"),
+									},
+									Ingress: util.K8sObjectInfo{
+										Name:      "some-httproute",
+										Namespace: "ns2",
+										Annotations: map[string]string{
+											annotations.AnnotationPrefix + annotations.PluginsKey: "foo",
+										},
+									},
+									ExtensionRefPlugins: []string{"foo", "bar"},
+								},
+								{
+									Route: kong.Route{
+										Name: kong.String("bar-route"),
+									},
+									Ingress: util.K8sObjectInfo{
+										Name:      "some-httproute",
+										Namespace: "ns2",
+										Annotations: map[string]string{
+											annotations.AnnotationPrefix + annotations.PluginsKey: "foo",
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			want: map[string]util.ForeignRelations{
+				"ns2:foo": {Route: []string{"foo-route", "bar-route"}},
+				"ns2:bar": {Route: []string{"foo-route"}},
+			},
+		},
 		{
 			name: "multiple consumers, consumer groups, routes and services",
 			args: args{
@@ -765,7 +920,7 @@ func TestFillConsumersAndCredentials(t *testing.T) {
 			failureCollector := failures.NewResourceFailuresCollector(logger)
 
 			state := KongState{}
-			state.FillConsumersAndCredentials(logger, store, failureCollector)
+			state.FillConsumersAndCredentials(logger, store, failureCollector, credentials.NewRegistry(nil, false), nil)
 			// compare translated consumers.
 			require.Len(t, state.Consumers, len(tc.expectedKongStateConsumers))
 			// compare fields. Since we only test for translating a single consumer, we only compare the first one if exists.
@@ -800,6 +955,336 @@ func TestFillConsumersAndCredentials(t *testing.T) {
 	}
 }
 
+func TestFillConsumerGroups(t *testing.T) {
+	cgTypeMeta := metav1.TypeMeta{
+		APIVersion: kongv1beta1.GroupVersion.String(),
+		Kind:       "KongConsumerGroup",
+	}
+	store, _ := store.NewFakeStore(store.FakeObjects{
+		KongConsumerGroups: []*kongv1beta1.KongConsumerGroup{
+			{
+				TypeMeta: cgTypeMeta,
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        "gold",
+					Namespace:   "default",
+					Annotations: map[string]string{annotations.IngressClassKey: annotations.DefaultIngressClass},
+				},
+				Spec: kongv1beta1.KongConsumerGroupSpec{
+					Plugins: []kongv1beta1.KongConsumerGroupPlugin{
+						{
+							PluginName: "rate-limiting-advanced",
+							Config: apiextensionsv1.JSON{
+								Raw: []byte(`{"limit":[1000],"window_size":[60]}`),
+							},
+						},
+					},
+				},
+			},
+			{
+				TypeMeta: cgTypeMeta,
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        "broken",
+					Namespace:   "default",
+					Annotations: map[string]string{annotations.IngressClassKey: annotations.DefaultIngressClass},
+				},
+				Spec: kongv1beta1.KongConsumerGroupSpec{
+					Plugins: []kongv1beta1.KongConsumerGroupPlugin{
+						{
+							PluginName: "rate-limiting-advanced",
+							Config: apiextensionsv1.JSON{
+								Raw: []byte(`{{}`),
+							},
+						},
+					},
+				},
+			},
+		},
+	})
+	logger := zapr.NewLogger(zap.NewNop())
+	failuresCollector := failures.NewResourceFailuresCollector(logger)
+
+	state := KongState{}
+	state.FillConsumerGroups(logger, store, failuresCollector)
+
+	require.Len(t, state.ConsumerGroups, 2)
+	groups := lo.SliceToMap(state.ConsumerGroups, func(cg ConsumerGroup) (string, ConsumerGroup) {
+		return *cg.Name, cg
+	})
+	assert.Equal(t, []kong.ConsumerGroupPlugin{
+		{
+			Name: kong.String("rate-limiting-advanced"),
+			Config: kong.Configuration{
+				"limit":       []any{float64(1000)},
+				"window_size": []any{float64(60)},
+			},
+		},
+	}, groups["gold"].Plugins)
+	assert.Empty(t, groups["broken"].Plugins, "override with invalid configuration should be skipped")
+
+	translationFailures := failuresCollector.PopResourceFailures()
+	require.Len(t, translationFailures, 1)
+	assert.Contains(t, translationFailures[0].Message(), `could not parse configuration of plugin "rate-limiting-advanced" override`)
+	assert.Equal(t, "broken", translationFailures[0].CausingObjects()[0].GetName())
+}
+
+func TestFillConsumersAndCredentials_CrossNamespaceConsumerGroups(t *testing.T) {
+	cgTypeMeta := metav1.TypeMeta{
+		APIVersion: kongv1beta1.GroupVersion.String(),
+		Kind:       "KongConsumerGroup",
+	}
+	consumerGroups := []*kongv1beta1.KongConsumerGroup{
+		{
+			TypeMeta: cgTypeMeta,
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        "gold",
+				Namespace:   "platform",
+				Annotations: map[string]string{annotations.IngressClassKey: annotations.DefaultIngressClass},
+			},
+		},
+		{
+			TypeMeta: cgTypeMeta,
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        "silver",
+				Namespace:   "platform",
+				Annotations: map[string]string{annotations.IngressClassKey: annotations.DefaultIngressClass},
+			},
+		},
+		{
+			TypeMeta: cgTypeMeta,
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        "local",
+				Namespace:   "team-a",
+				Annotations: map[string]string{annotations.IngressClassKey: annotations.DefaultIngressClass},
+			},
+		},
+	}
+	grantFromTeamA := gatewayapi.ReferenceGrantFrom{
+		Group:     gatewayapi.Group(kongv1.GroupVersion.Group),
+		Kind:      gatewayapi.Kind("KongConsumer"),
+		Namespace: gatewayapi.Namespace("team-a"),
+	}
+
+	testCases := []struct {
+		name                       string
+		referenceGrants            []*gatewayapi.ReferenceGrant
+		consumerGroupRefs          []string
+		expectedConsumerGroups     []string
+		expectedTranslationFailure string
+	}{
+		{
+			name:                   "consumer group in consumer's namespace does not need a ReferenceGrant",
+			consumerGroupRefs:      []string{"local"},
+			expectedConsumerGroups: []string{"local"},
+		},
+		{
+			name:                       "consumer group in another namespace without ReferenceGrant is rejected",
+			consumerGroupRefs:          []string{"local", "platform/gold"},
+			expectedConsumerGroups:     []string{"local"},
+			expectedTranslationFailure: `consumer group "platform/gold" is not permitted by any ReferenceGrant in namespace "platform"`,
+		},
+		{
+			name: "consumer group in another namespace permitted by ReferenceGrant for all consumer groups",
+			referenceGrants: []*gatewayapi.ReferenceGrant{
+				{
+					ObjectMeta: metav1.ObjectMeta{Name: "team-a-consumers", Namespace: "platform"},
+					Spec: gatewayapi.ReferenceGrantSpec{
+						From: []gatewayapi.ReferenceGrantFrom{grantFromTeamA},
+						To: []gatewayapi.ReferenceGrantTo{
+							{Group: gatewayapi.Group(kongv1beta1.GroupVersion.Group), Kind: gatewayapi.Kind("KongConsumerGroup")},
+						},
+					},
+				},
+			},
+			consumerGroupRefs:      []string{"platform/gold", "platform/silver"},
+			expectedConsumerGroups: []string{"gold", "silver"},
+		},
+		{
+			name: "ReferenceGrant with referent name permits only the named consumer group",
+			referenceGrants: []*gatewayapi.ReferenceGrant{
+				{
+					ObjectMeta: metav1.ObjectMeta{Name: "team-a-consumers", Namespace: "platform"},
+					Spec: gatewayapi.ReferenceGrantSpec{
+						From: []gatewayapi.ReferenceGrantFrom{grantFromTeamA},
+						To: []gatewayapi.ReferenceGrantTo{
+							{
+								Group: gatewayapi.Group(kongv1beta1.GroupVersion.Group),
+								Kind:  gatewayapi.Kind("KongConsumerGroup"),
+								Name:  lo.ToPtr(gatewayapi.ObjectName("gold")),
+							},
+						},
+					},
+				},
+			},
+			consumerGroupRefs:          []string{"platform/gold", "platform/silver"},
+			expectedConsumerGroups:     []string{"gold"},
+			expectedTranslationFailure: `consumer group "platform/silver" is not permitted by any ReferenceGrant in namespace "platform"`,
+		},
+		{
+			name: "ReferenceGrant outside of consumer group's namespace is ignored",
+			referenceGrants: []*gatewayapi.ReferenceGrant{
+				{
+					ObjectMeta: metav1.ObjectMeta{Name: "team-a-consumers", Namespace: "team-a"},
+					Spec: gatewayapi.ReferenceGrantSpec{
+						From: []gatewayapi.ReferenceGrantFrom{grantFromTeamA},
+						To: []gatewayapi.ReferenceGrantTo{
+							{Group: gatewayapi.Group(kongv1beta1.GroupVersion.Group), Kind: gatewayapi.Kind("KongConsumerGroup")},
+						},
+					},
+				},
+			},
+			consumerGroupRefs:          []string{"platform/gold"},
+			expectedTranslationFailure: `consumer group "platform/gold" is not permitted by any ReferenceGrant in namespace "platform"`,
+		},
+		{
+			name: "ReferenceGrant from another namespace is ignored",
+			referenceGrants: []*gatewayapi.ReferenceGrant{
+				{
+					ObjectMeta: metav1.ObjectMeta{Name: "team-b-consumers", Namespace: "platform"},
+					Spec: gatewayapi.ReferenceGrantSpec{
+						From: []gatewayapi.ReferenceGrantFrom{
+							{
+								Group:     gatewayapi.Group(kongv1.GroupVersion.Group),
+								Kind:      gatewayapi.Kind("KongConsumer"),
+								Namespace: gatewayapi.Namespace("team-b"),
+							},
+						},
+						To: []gatewayapi.ReferenceGrantTo{
+							{Group: gatewayapi.Group(kongv1beta1.GroupVersion.Group), Kind: gatewayapi.Kind("KongConsumerGroup")},
+						},
+					},
+				},
+			},
+			consumerGroupRefs:          []string{"platform/gold"},
+			expectedTranslationFailure: `consumer group "platform/gold" is not permitted by any ReferenceGrant in namespace "platform"`,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			consumer := &kongv1.KongConsumer{
+				TypeMeta: kongConsumerTypeMeta,
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        "consumer",
+					Namespace:   "team-a",
+					Annotations: map[string]string{annotations.IngressClassKey: annotations.DefaultIngressClass},
+				},
+				Username:       "consumer",
+				ConsumerGroups: tc.consumerGroupRefs,
+			}
+			store, err := store.NewFakeStore(store.FakeObjects{
+				KongConsumers:      []*kongv1.KongConsumer{consumer},
+				KongConsumerGroups: consumerGroups,
+				ReferenceGrants:    tc.referenceGrants,
+			})
+			require.NoError(t, err)
+			logger := zapr.NewLogger(zap.NewNop())
+			failuresCollector := failures.NewResourceFailuresCollector(logger)
+
+			state := KongState{}
+			state.FillConsumersAndCredentials(logger, store, failuresCollector, credentials.NewRegistry(nil, false), nil)
+
+			require.Len(t, state.Consumers, 1, "consumer should be translated even if some of its groups are rejected")
+			consumerGroupNames := lo.Map(state.Consumers[0].ConsumerGroups, func(cg kong.ConsumerGroup, _ int) string {
+				return *cg.Name
+			})
+			assert.ElementsMatch(t, tc.expectedConsumerGroups, consumerGroupNames)
+
+			translationFailures := failuresCollector.PopResourceFailures()
+			if tc.expectedTranslationFailure == "" {
+				require.Empty(t, translationFailures)
+				return
+			}
+			require.Len(t, translationFailures, 1)
+			assert.Equal(t, tc.expectedTranslationFailure, translationFailures[0].Message())
+			assert.Equal(t, "consumer", translationFailures[0].CausingObjects()[0].GetName())
+		})
+	}
+}
+
+type fakeCredentialSchemaGetter map[string]map[string]interface{}
+
+func (f fakeCredentialSchemaGetter) EntitySchema(_ context.Context, entityName string) (map[string]interface{}, error) {
+	schema, ok := f[entityName]
+	if !ok {
+		return nil, fmt.Errorf("schema of %s not found", entityName)
+	}
+	return schema, nil
+}
+
+func TestFillConsumersAndCredentials_CustomCredentialTypes(t *testing.T) {
+	registry := credentials.NewRegistry(fakeCredentialSchemaGetter{
+		"keyauth_enc_credentials": {
+			"fields": []interface{}{
+				map[string]interface{}{"consumer": map[string]interface{}{"type": "foreign", "reference": "consumers", "required": true}},
+				map[string]interface{}{"key": map[string]interface{}{"type": "string", "required": true}},
+				map[string]interface{}{"ttl": map[string]interface{}{"type": "integer"}},
+			},
+		},
+	}, true)
+	// Translation only uses cached types, so resolve the type upfront as the admission webhook would.
+	_, err := registry.Lookup(context.Background(), "keyauth_enc_credentials")
+	require.NoError(t, err)
+	newCredentialSecret := func(name string, data map[string][]byte) *corev1.Secret {
+		return &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: "default",
+				Labels: map[string]string{
+					labels.LabelPrefix + labels.CredentialKey: "keyauth_enc_credentials",
+				},
+			},
+			Data: data,
+		}
+	}
+	newConsumer := func(name, username string, credentials ...string) *kongv1.KongConsumer {
+		return &kongv1.KongConsumer{
+			TypeMeta: kongConsumerTypeMeta,
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        name,
+				Namespace:   "default",
+				Annotations: map[string]string{annotations.IngressClassKey: annotations.DefaultIngressClass},
+			},
+			Username:    username,
+			CustomID:    name,
+			Credentials: credentials,
+		}
+	}
+	store, err := store.NewFakeStore(store.FakeObjects{
+		Secrets: []*corev1.Secret{
+			newCredentialSecret("valid", map[string][]byte{"key": []byte("little-rabbits-be-good"), "ttl": []byte("60")}),
+			newCredentialSecret("missing-key", map[string][]byte{"ttl": []byte("60")}),
+		},
+		KongConsumers: []*kongv1.KongConsumer{
+			newConsumer("foo", "foo", "valid", "missing-key"),
+			newConsumer("no-username", "", "valid"),
+		},
+	})
+	require.NoError(t, err)
+	logger := zapr.NewLogger(zap.NewNop())
+	failuresCollector := failures.NewResourceFailuresCollector(logger)
+
+	state := KongState{}
+	state.FillConsumersAndCredentials(logger, store, failuresCollector, registry, nil)
+
+	consumers := lo.SliceToMap(state.Consumers, func(c Consumer) (string, Consumer) {
+		return c.K8sKongConsumer.Name, c
+	})
+	require.Len(t, consumers, 2)
+	require.Len(t, consumers["foo"].CustomCredentials, 1)
+	assert.Equal(t, "keyauth_enc_credentials", consumers["foo"].CustomCredentials[0].Entity)
+	assert.Equal(t, map[string]interface{}{"key": "little-rabbits-be-good", "ttl": 60}, consumers["foo"].CustomCredentials[0].Config)
+	assert.Empty(t, consumers["no-username"].CustomCredentials)
+
+	failureMessages := lo.Map(failuresCollector.PopResourceFailures(), func(f failures.ResourceFailure, _ int) string {
+		return f.Message()
+	})
+	assert.ElementsMatch(t, []string{
+		`credential "missing-key" failure: failed to provision credential: missing required field(s): key`,
+		`credential "valid" failure: failed to provision credential: credentials of type "keyauth_enc_credentials" require the consumer to have a username`,
+	}, failureMessages)
+}
+
 func TestKongState_FillIDs(t *testing.T) {
 	testCases := []struct {
 		name   string
@@ -959,3 +1444,84 @@ func TestKongState_BuildPluginsCollisions(t *testing.T) {
 		})
 	}
 }
+
+func TestKongState_FillPlugins_GeneratedPreFunctionPlugins(t *testing.T) {
+	kongPluginTypeMeta := metav1.TypeMeta{APIVersion: kongv1.GroupVersion.String(), Kind: "KongPlugin"}
+	userPreFunction := &kongv1.KongPlugin{
+		TypeMeta:   kongPluginTypeMeta,
+		ObjectMeta: metav1.ObjectMeta{Name: "user-pre-function", Namespace: "default"},
+		PluginName: "pre-function",
+		Config:     apiextensionsv1.JSON{Raw: []byte(`{"access":["kong.log.info('user')"],"log":["kong.log.info('log')"]}`)},
+	}
+	disabledPreFunction := &kongv1.KongPlugin{
+		TypeMeta:   kongPluginTypeMeta,
+		ObjectMeta: metav1.ObjectMeta{Name: "disabled-pre-function", Namespace: "default"},
+		PluginName: "pre-function",
+		Disabled:   true,
+		Config:     apiextensionsv1.JSON{Raw: []byte(`{"access":["kong.log.info('disabled')"]}`)},
+	}
+	s, err := store.NewFakeStore(store.FakeObjects{KongPlugins: []*kongv1.KongPlugin{userPreFunction, disabledPreFunction}})
+	require.NoError(t, err)
+
+	generatedPreFunction := func() kong.Plugin {
+		return kong.Plugin{
+			Name:   kong.String("pre-function"),
+			Config: kong.Configuration{"access": []string{"kong.log.info('generated')"}},
+		}
+	}
+	route := func(name string, annotatedPlugins string, extensionRefPlugins []string, plugins ...kong.Plugin) Route {
+		return Route{
+			Route: kong.Route{Name: kong.String(name)},
+			Ingress: util.K8sObjectInfo{
+				Namespace:   "default",
+				Annotations: map[string]string{annotations.AnnotationPrefix + annotations.PluginsKey: annotatedPlugins},
+			},
+			ExtensionRefPlugins: extensionRefPlugins,
+			Plugins:             plugins,
+		}
+	}
+	state := KongState{
+		Services: []Service{{
+			Service: kong.Service{Name: kong.String("service")},
+			Routes: []Route{
+				route("annotation", "user-pre-function", nil, generatedPreFunction()),
+				route("extension-ref", "", []string{"user-pre-function"}, generatedPreFunction()),
+				route("user-only", "user-pre-function", nil),
+				route("disabled", "disabled-pre-function", nil, generatedPreFunction()),
+			},
+		}},
+	}
+	logger := zapr.NewLogger(zap.NewNop())
+	failuresCollector := failures.NewResourceFailuresCollector(logger)
+
+	state.FillPlugins(logger, s, failuresCollector)
+
+	pluginsByRoute := lo.SliceToMap(state.Plugins, func(p Plugin) (string, Plugin) {
+		return *p.Route.ID, p
+	})
+	require.Len(t, pluginsByRoute, 3)
+	for _, name := range []string{"annotation", "extension-ref"} {
+		require.Equal(t,
+			[]interface{}{"kong.log.info('generated')", "kong.log.info('user')"},
+			pluginsByRoute[name].Config["access"],
+			"functions of the generated plugin should run first on route %s", name,
+		)
+		require.Equal(t, []interface{}{"kong.log.info('log')"}, pluginsByRoute[name].Config["log"])
+	}
+	require.Equal(t, []interface{}{"kong.log.info('user')"}, pluginsByRoute["user-only"].Config["access"])
+
+	routePlugins := lo.SliceToMap(state.Services[0].Routes, func(r Route) (string, []kong.Plugin) {
+		return *r.Name, r.Plugins
+	})
+	require.Empty(t, routePlugins["annotation"], "generated plugin should be merged into the KongPlugin")
+	require.Empty(t, routePlugins["extension-ref"], "generated plugin should be merged into the KongPlugin")
+	require.Equal(t, []kong.Plugin{generatedPreFunction()}, routePlugins["disabled"],
+		"generated plugin should be kept on the route the disabled KongPlugin can't be attached to")
+
+	failureMessages := lo.Map(failuresCollector.PopResourceFailures(), func(f failures.ResourceFailure, _ int) string {
+		return f.Message()
+	})
+	require.Equal(t, []string{
+		"disabled pre-function plugin can't be attached to route disabled, which requires the plugin enabled",
+	}, failureMessages)
+}
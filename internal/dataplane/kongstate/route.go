@@ -20,6 +20,12 @@ type Route struct {
 	Ingress          util.K8sObjectInfo
 	Plugins          []kong.Plugin
 	ExpressionRoutes bool
+
+	// ExtensionRefPlugins holds names of KongPlugins (in the namespace of the Ingress object)
+	// referenced by the object's rule this route was generated for, e.g. by ExtensionRef filters
+	// of an HTTPRoute rule. They're attached to this route only, in addition to the ones
+	// attached to all routes of the object with the konghq.com/plugins annotation.
+	ExtensionRefPlugins []string
 }
 
 var (
@@ -33,6 +39,19 @@ var (
 )
 
 // normalizeProtocols prevents users from mismatching grpc/http.
+// DeepCopy returns a deep copy of the Route. Annotations of the Kubernetes object it was translated
+// from are shared with the original, as they're never modified.
+func (r Route) DeepCopy() Route {
+	c := r
+	c.Route = *r.Route.DeepCopy()
+	c.Plugins = deepCopyKongPlugins(r.Plugins)
+	if r.ExtensionRefPlugins != nil {
+		c.ExtensionRefPlugins = make([]string, len(r.ExtensionRefPlugins))
+		copy(c.ExtensionRefPlugins, r.ExtensionRefPlugins)
+	}
+	return c
+}
+
 func (r *Route) normalizeProtocols() {
 	// skip updating protocols if expression routes enabled.
 	if r.ExpressionRoutes {
@@ -257,6 +276,11 @@ func (r *Route) override(logger logr.Logger) {
 	r.normalizeProtocols()
 }
 
+// OverrideRoute sets the route's fields the same way FillOverrides does before the route is sent to Kong.
+func OverrideRoute(logger logr.Logger, r *Route) {
+	r.override(logger)
+}
+
 // overrideRequestBuffering ensures defaults for the request_buffering option.
 func (r *Route) overrideRequestBuffering(logger logr.Logger, anns map[string]string) {
 	annotationValue, ok := annotations.ExtractRequestBuffering(anns)
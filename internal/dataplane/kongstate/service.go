@@ -12,6 +12,7 @@ import (
 
 	"github.com/kong/kubernetes-ingress-controller/v2/internal/annotations"
 	"github.com/kong/kubernetes-ingress-controller/v2/internal/util"
+	kongv1beta1 "github.com/kong/kubernetes-ingress-controller/v2/pkg/apis/configuration/v1beta1"
 )
 
 // Services is a list of kongstate.Service objects with sorting enabled based
@@ -52,6 +53,14 @@ type Service struct {
 	Backends    []ServiceBackend
 	K8sServices map[string]*corev1.Service
 
+	// TimeoutsFromParent indicates that the connect, read and write timeouts of this Service are set by
+	// its Parent (e.g. HTTPRoute rule timeouts). They take precedence over Kubernetes Service annotations.
+	TimeoutsFromParent bool
+
+	// SessionPersistence is the hash the upstream of this Service has to use to implement session persistence
+	// requested by its Parent (e.g. HTTPRoute rules), or nil if none is requested.
+	SessionPersistence *kongv1beta1.KongUpstreamHash
+
 	// Parent is the parent object of this Service.
 	// It is expected to be a Kubernetes object which translation resulted in creating this Kong Service.
 	// For example, if this Service was created as a result of translating a Kubernetes Ingress, then
@@ -59,6 +68,49 @@ type Service struct {
 	Parent client.Object
 }
 
+// DeepCopy returns a deep copy of the Service. Kubernetes objects it refers to (its Parent and K8sServices)
+// are shared with the original, as they're never modified.
+func (s Service) DeepCopy() Service {
+	c := s
+	c.Service = *s.Service.DeepCopy()
+	if s.Routes != nil {
+		c.Routes = make([]Route, len(s.Routes))
+		for i, route := range s.Routes {
+			c.Routes[i] = route.DeepCopy()
+		}
+	}
+	c.Plugins = deepCopyKongPlugins(s.Plugins)
+	c.SessionPersistence = s.SessionPersistence.DeepCopy()
+	if s.Backends != nil {
+		c.Backends = make([]ServiceBackend, len(s.Backends))
+		for i, backend := range s.Backends {
+			if backend.Weight != nil {
+				backend.Weight = lo.ToPtr(*backend.Weight)
+			}
+			c.Backends[i] = backend
+		}
+	}
+	if s.K8sServices != nil {
+		c.K8sServices = make(map[string]*corev1.Service, len(s.K8sServices))
+		for k, v := range s.K8sServices {
+			c.K8sServices[k] = v
+		}
+	}
+	return c
+}
+
+// deepCopyKongPlugins returns a deep copy of a slice of Kong plugins.
+func deepCopyKongPlugins(plugins []kong.Plugin) []kong.Plugin {
+	if plugins == nil {
+		return nil
+	}
+	c := make([]kong.Plugin, len(plugins))
+	for i := range plugins {
+		c[i] = *plugins[i].DeepCopy()
+	}
+	return c
+}
+
 func (s *Service) overridePath(anns map[string]string) {
 	if s == nil {
 		return
@@ -153,9 +205,11 @@ func (s *Service) overrideByAnnotation(anns map[string]string) {
 	}
 	s.overrideProtocol(anns)
 	s.overridePath(anns)
-	s.overrideConnectTimeout(anns)
-	s.overrideWriteTimeout(anns)
-	s.overrideReadTimeout(anns)
+	if !s.TimeoutsFromParent {
+		s.overrideConnectTimeout(anns)
+		s.overrideWriteTimeout(anns)
+		s.overrideReadTimeout(anns)
+	}
 	s.overrideRetries(anns)
 }
 
@@ -5,11 +5,13 @@ import (
 	"testing"
 
 	"github.com/kong/go-kong/kong"
+	"github.com/samber/lo"
 	"github.com/stretchr/testify/require"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	"github.com/kong/kubernetes-ingress-controller/v2/internal/annotations"
+	kongv1beta1 "github.com/kong/kubernetes-ingress-controller/v2/pkg/apis/configuration/v1beta1"
 )
 
 func TestOverrideService(t *testing.T) {
@@ -530,3 +532,69 @@ func TestServiceOverride_DeterministicOrderWhenMoreThan1KubernetesService(t *tes
 	service.override()
 	require.Equal(t, expectedRetries, *service.Service.Retries)
 }
+
+func TestServiceOverride_TimeoutsFromParentTakePrecedence(t *testing.T) {
+	service := Service{
+		Service: kong.Service{
+			ConnectTimeout: kong.Int(5000),
+			ReadTimeout:    kong.Int(5000),
+			WriteTimeout:   kong.Int(5000),
+		},
+		TimeoutsFromParent: true,
+		K8sServices: map[string]*corev1.Service{
+			"default/service-1": {
+				ObjectMeta: metav1.ObjectMeta{
+					Annotations: map[string]string{
+						annotations.AnnotationPrefix + annotations.ConnectTimeoutKey: "1000",
+						annotations.AnnotationPrefix + annotations.ReadTimeoutKey:    "1000",
+						annotations.AnnotationPrefix + annotations.WriteTimeoutKey:   "1000",
+						annotations.AnnotationPrefix + annotations.RetriesKey:        "1",
+					},
+				},
+			},
+		},
+	}
+
+	service.override()
+	require.Equal(t, 5000, *service.Service.ConnectTimeout)
+	require.Equal(t, 5000, *service.Service.ReadTimeout)
+	require.Equal(t, 5000, *service.Service.WriteTimeout)
+	require.Equal(t, 1, *service.Service.Retries)
+}
+
+func TestServiceDeepCopy(t *testing.T) {
+	service := Service{
+		Service: kong.Service{Name: kong.String("svc"), Port: kong.Int(80)},
+		Routes: []Route{
+			{
+				Route:               kong.Route{Name: kong.String("route"), Paths: kong.StringSlice("/foo")},
+				Plugins:             []kong.Plugin{{Name: kong.String("key-auth")}},
+				ExtensionRefPlugins: []string{"plugin"},
+			},
+		},
+		Plugins:            []kong.Plugin{{Name: kong.String("request-termination")}},
+		Backends:           []ServiceBackend{{Name: "backend", Weight: lo.ToPtr(int32(10))}},
+		K8sServices:        map[string]*corev1.Service{"default/backend": {}},
+		SessionPersistence: &kongv1beta1.KongUpstreamHash{Cookie: kong.String("session")},
+	}
+	c := service.DeepCopy()
+	require.Equal(t, service, c)
+
+	*c.Name = "changed"
+	c.Routes[0].Paths[0] = kong.String("/bar")
+	*c.Routes[0].Plugins[0].Name = "changed"
+	c.Routes[0].ExtensionRefPlugins[0] = "changed"
+	*c.Plugins[0].Name = "changed"
+	*c.Backends[0].Weight = 20
+	c.K8sServices["default/another"] = &corev1.Service{}
+	*c.SessionPersistence.Cookie = "changed"
+
+	require.Equal(t, "svc", *service.Name)
+	require.Equal(t, "/foo", *service.Routes[0].Paths[0])
+	require.Equal(t, "key-auth", *service.Routes[0].Plugins[0].Name)
+	require.Equal(t, "plugin", service.Routes[0].ExtensionRefPlugins[0])
+	require.Equal(t, "request-termination", *service.Plugins[0].Name)
+	require.Equal(t, int32(10), *service.Backends[0].Weight)
+	require.Len(t, service.K8sServices, 1)
+	require.Equal(t, "session", *service.SessionPersistence.Cookie)
+}
@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 
+	jsonpatch "github.com/evanphx/json-patch/v5"
 	"github.com/kong/go-kong/kong"
 	corev1 "k8s.io/api/core/v1"
 	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
@@ -123,6 +124,11 @@ func kongPluginFromK8SClusterPlugin(
 			fmt.Errorf("KongClusterPlugin '/%v' has both "+
 				"Config and ConfigFrom set", k8sPlugin.Name)
 	}
+	if k8sPlugin.ConfigFrom != nil && len(k8sPlugin.ConfigPatches) > 0 {
+		return Plugin{},
+			fmt.Errorf("KongClusterPlugin '/%v' has both "+
+				"ConfigFrom and ConfigPatches set", k8sPlugin.Name)
+	}
 	if k8sPlugin.ConfigFrom != nil {
 		var err error
 		config, err = namespacedSecretToConfiguration(
@@ -134,6 +140,14 @@ func kongPluginFromK8SClusterPlugin(
 					k8sPlugin.Name, err)
 		}
 	}
+	if len(k8sPlugin.ConfigPatches) > 0 {
+		config, err = ApplyNamespacedConfigPatches(s, config, k8sPlugin.ConfigPatches)
+		if err != nil {
+			return Plugin{},
+				fmt.Errorf("error applying config patches for KongClusterPlugin %s: %w",
+					k8sPlugin.Name, err)
+		}
+	}
 
 	return Plugin{
 		Plugin: plugin{
@@ -173,6 +187,11 @@ func kongPluginFromK8SPlugin(
 			fmt.Errorf("KongPlugin '%s/%s' has both Config and ConfigFrom set",
 				k8sPlugin.Namespace, k8sPlugin.Name)
 	}
+	if k8sPlugin.ConfigFrom != nil && len(k8sPlugin.ConfigPatches) > 0 {
+		return Plugin{},
+			fmt.Errorf("KongPlugin '%s/%s' has both ConfigFrom and ConfigPatches set",
+				k8sPlugin.Namespace, k8sPlugin.Name)
+	}
 	if k8sPlugin.ConfigFrom != nil {
 		var err error
 		config, err = SecretToConfiguration(s,
@@ -183,6 +202,14 @@ func kongPluginFromK8SPlugin(
 					k8sPlugin.Name, k8sPlugin.Namespace, err)
 		}
 	}
+	if len(k8sPlugin.ConfigPatches) > 0 {
+		config, err = ApplyConfigPatches(s, config, k8sPlugin.ConfigPatches, k8sPlugin.Namespace)
+		if err != nil {
+			return Plugin{},
+				fmt.Errorf("error applying config patches for KongPlugin '%s/%s': %w",
+					k8sPlugin.Namespace, k8sPlugin.Name, err)
+		}
+	}
 
 	return Plugin{
 		Plugin: plugin{
@@ -257,6 +284,88 @@ func SecretToConfiguration(
 	return config, nil
 }
 
+// ApplyConfigPatches applies configPatches of a KongPlugin to its configuration.
+// Values of the patches are read from Secrets in the given namespace.
+func ApplyConfigPatches(
+	s SecretGetter,
+	config kong.Configuration,
+	patches []kongv1.ConfigPatch, namespace string,
+) (kong.Configuration, error) {
+	namespacedPatches := make([]kongv1.NamespacedConfigPatch, 0, len(patches))
+	for _, patch := range patches {
+		namespacedPatches = append(namespacedPatches, kongv1.NamespacedConfigPatch{
+			Path: patch.Path,
+			ValueFrom: kongv1.NamespacedConfigSource{
+				SecretValue: kongv1.NamespacedSecretValueFromSource{
+					Namespace: namespace,
+					Secret:    patch.ValueFrom.SecretValue.Secret,
+					Key:       patch.ValueFrom.SecretValue.Key,
+				},
+			},
+		})
+	}
+	return ApplyNamespacedConfigPatches(s, config, namespacedPatches)
+}
+
+// ApplyNamespacedConfigPatches applies configPatches of a KongClusterPlugin to its configuration.
+// Each patch is applied as a JSON patch "add" operation, in order. Patches are applied to an empty
+// object when the configuration is empty.
+func ApplyNamespacedConfigPatches(
+	s SecretGetter,
+	config kong.Configuration,
+	patches []kongv1.NamespacedConfigPatch,
+) (kong.Configuration, error) {
+	type jsonPatchOperation struct {
+		Op    string          `json:"op"`
+		Path  string          `json:"path"`
+		Value json.RawMessage `json:"value"`
+	}
+	operations := make([]jsonPatchOperation, 0, len(patches))
+	for _, patch := range patches {
+		ref := patch.ValueFrom.SecretValue
+		secret, err := s.GetSecret(ref.Namespace, ref.Secret)
+		if err != nil {
+			return nil, fmt.Errorf("error fetching plugin configuration patch secret '%v/%v': %w",
+				ref.Namespace, ref.Secret, err)
+		}
+		value, ok := secret.Data[ref.Key]
+		if !ok {
+			return nil, fmt.Errorf("no key '%v' in secret '%v/%v'", ref.Key, ref.Namespace, ref.Secret)
+		}
+		if !json.Valid(value) {
+			return nil, fmt.Errorf("key '%v' in secret '%v/%v' does not contain valid JSON",
+				ref.Key, ref.Namespace, ref.Secret)
+		}
+		operations = append(operations, jsonPatchOperation{Op: "add", Path: patch.Path, Value: value})
+	}
+
+	if config == nil {
+		config = kong.Configuration{}
+	}
+	rawConfig, err := json.Marshal(config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal plugin configuration: %w", err)
+	}
+	rawPatch, err := json.Marshal(operations)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal configuration patches: %w", err)
+	}
+	jsonPatch, err := jsonpatch.DecodePatch(rawPatch)
+	if err != nil {
+		return nil, fmt.Errorf("invalid configuration patches: %w", err)
+	}
+	patchedConfig, err := jsonPatch.Apply(rawConfig)
+	if err != nil {
+		return nil, fmt.Errorf("failed to apply configuration patches: %w", err)
+	}
+
+	var result kong.Configuration
+	if err := json.Unmarshal(patchedConfig, &result); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal patched plugin configuration: %w", err)
+	}
+	return result, nil
+}
+
 // PrettyPrintServiceList makes a clean printable list of a map of Kubernetes
 // services for the purpose of logging (errors, info, e.t.c.).
 func PrettyPrintServiceList(services map[string]*corev1.Service) string {
@@ -0,0 +1,175 @@
+package dataplane
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/samber/lo"
+	corev1 "k8s.io/api/core/v1"
+	k8stypes "k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/tools/cache"
+	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
+
+	"github.com/kong/kubernetes-ingress-controller/v2/internal/dataplane/kongstate"
+	"github.com/kong/kubernetes-ingress-controller/v2/internal/dataplane/sendconfig"
+	"github.com/kong/kubernetes-ingress-controller/v2/internal/gatewayapi"
+	"github.com/kong/kubernetes-ingress-controller/v2/internal/store"
+	"github.com/kong/kubernetes-ingress-controller/v2/internal/util"
+)
+
+// maybeSendOutToManagedGateways sends out the configuration to data-planes provisioned for managed Gateways.
+// Each of them gets only the part of the configuration serving its Gateway (see managedGatewayKongState).
+// Failures are only logged, as they must not affect configuring the other Kong Gateways.
+func (c *KongClient) maybeSendOutToManagedGateways(ctx context.Context, s *kongstate.KongState, config sendconfig.Config) error {
+	var errs []error
+	for gateway, clients := range c.clientsProvider.ManagedGatewayClients() {
+		if len(clients) == 0 {
+			continue
+		}
+		gatewayState := managedGatewayKongState(s, *c.cache, gateway)
+		for _, client := range clients {
+			if _, err := c.sendToClient(ctx, client, gatewayState, config); err != nil {
+				c.logger.Error(err, "Failed pushing configuration to managed gateway", "gateway", gateway.String())
+				errs = append(errs, fmt.Errorf("managed gateway %s: %w", gateway, err))
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// managedGatewayKongState returns the configuration of data-planes provisioned for a managed Gateway. It consists of
+// routes generated for Gateway API routes attached to the Gateway and certificates of the Gateway's listeners.
+func managedGatewayKongState(s *kongstate.KongState, cacheStores store.CacheStores, gateway k8stypes.NamespacedName) *kongstate.KongState {
+	listenerCerts := managedGatewayListenerCerts(cacheStores, gateway)
+	return s.FilteredByRoutes(
+		func(r kongstate.Route) bool {
+			return isRouteAttachedToGateway(routeParentRefs(cacheStores, r.Ingress), r.Ingress.Namespace, gateway)
+		},
+		func(c kongstate.Certificate) bool {
+			return c.Cert != nil && listenerCerts.Has(*c.Cert)
+		},
+	)
+}
+
+// sharedGatewaysKongState returns the configuration of Kong Gateways other than the data-planes provisioned for
+// managed Gateways. Routes attached only to managed Gateways are served by their data-planes alone, so they're
+// excluded from it.
+func sharedGatewaysKongState(
+	s *kongstate.KongState,
+	cacheStores store.CacheStores,
+	managedGateways sets.Set[k8stypes.NamespacedName],
+) *kongstate.KongState {
+	if managedGateways.Len() == 0 {
+		return s
+	}
+	return s.WithoutRoutes(func(r kongstate.Route) bool {
+		gateways := routeParentGateways(routeParentRefs(cacheStores, r.Ingress), r.Ingress.Namespace)
+		return len(gateways) > 0 && lo.EveryBy(gateways, managedGateways.Has)
+	})
+}
+
+// routeParentRefs returns parent references of the Gateway API route a Kong route was generated for.
+// Nil is returned for objects of other kinds.
+func routeParentRefs(cacheStores store.CacheStores, obj util.K8sObjectInfo) []gatewayapi.ParentReference {
+	if obj.GroupVersionKind.Group != gatewayv1.GroupVersion.Group {
+		return nil
+	}
+
+	var routeStore cache.Store
+	switch obj.GroupVersionKind.Kind {
+	case "HTTPRoute":
+		routeStore = cacheStores.HTTPRoute
+	case "GRPCRoute":
+		routeStore = cacheStores.GRPCRoute
+	case "TCPRoute":
+		routeStore = cacheStores.TCPRoute
+	case "UDPRoute":
+		routeStore = cacheStores.UDPRoute
+	case "TLSRoute":
+		routeStore = cacheStores.TLSRoute
+	default:
+		return nil
+	}
+	item, exists, err := routeStore.GetByKey(obj.Namespace + "/" + obj.Name)
+	if err != nil || !exists {
+		return nil
+	}
+
+	switch route := item.(type) {
+	case *gatewayapi.HTTPRoute:
+		return route.Spec.ParentRefs
+	case *gatewayapi.GRPCRoute:
+		return route.Spec.ParentRefs
+	case *gatewayapi.TCPRoute:
+		return route.Spec.ParentRefs
+	case *gatewayapi.UDPRoute:
+		return route.Spec.ParentRefs
+	case *gatewayapi.TLSRoute:
+		return route.Spec.ParentRefs
+	}
+	return nil
+}
+
+// isRouteAttachedToGateway tells whether any of the parent references of a route in the given namespace
+// references the Gateway.
+func isRouteAttachedToGateway(parentRefs []gatewayapi.ParentReference, routeNamespace string, gateway k8stypes.NamespacedName) bool {
+	return lo.Contains(routeParentGateways(parentRefs, routeNamespace), gateway)
+}
+
+// routeParentGateways returns the Gateways referenced by parent references of a route in the given namespace.
+func routeParentGateways(parentRefs []gatewayapi.ParentReference, routeNamespace string) []k8stypes.NamespacedName {
+	var gateways []k8stypes.NamespacedName
+	for _, ref := range parentRefs {
+		if ref.Group != nil && string(*ref.Group) != gatewayv1.GroupVersion.Group {
+			continue
+		}
+		if ref.Kind != nil && *ref.Kind != "Gateway" {
+			continue
+		}
+		namespace := routeNamespace
+		if ref.Namespace != nil {
+			namespace = string(*ref.Namespace)
+		}
+		gateways = append(gateways, k8stypes.NamespacedName{Namespace: namespace, Name: string(ref.Name)})
+	}
+	return gateways
+}
+
+// managedGatewayListenerCerts returns PEM-encoded certificates referenced by TLS configs of the Gateway's listeners.
+// They're compared by contents with the certificates in Kong configuration, as certificates of Secrets with
+// the same contents are merged into one.
+func managedGatewayListenerCerts(cacheStores store.CacheStores, gateway k8stypes.NamespacedName) sets.Set[string] {
+	certs := sets.New[string]()
+	item, exists, err := cacheStores.Gateway.GetByKey(gateway.String())
+	if err != nil || !exists {
+		return certs
+	}
+	gw, ok := item.(*gatewayapi.Gateway)
+	if !ok {
+		return certs
+	}
+
+	for _, listener := range gw.Spec.Listeners {
+		if listener.TLS == nil {
+			continue
+		}
+		for _, ref := range listener.TLS.CertificateRefs {
+			namespace := gw.Namespace
+			if ref.Namespace != nil {
+				namespace = string(*ref.Namespace)
+			}
+			item, exists, err := cacheStores.Secret.GetByKey(namespace + "/" + string(ref.Name))
+			if err != nil || !exists {
+				continue
+			}
+			if secret, ok := item.(*corev1.Secret); ok {
+				// certificates in Kong configuration are trimmed the same way.
+				certs.Insert(strings.TrimSpace(string(secret.Data[corev1.TLSCertKey])))
+			}
+		}
+	}
+	return certs
+}
@@ -48,6 +48,16 @@ func mergeIngressRules(objs ...ingressRules) ingressRules {
 	return result
 }
 
+// mergeServicesCopies merges deep copies of the Kong Services of other into ir.
+func (ir *ingressRules) mergeServicesCopies(other ingressRules) {
+	for k, v := range other.ServiceNameToServices {
+		ir.ServiceNameToServices[k] = v.DeepCopy()
+	}
+	for k, v := range other.ServiceNameToParent {
+		ir.ServiceNameToParent[k] = v
+	}
+}
+
 // populateServices populates the ServiceNameToServices map with additional information
 // and returns a map of services to be skipped.
 func (ir *ingressRules) populateServices(logger logr.Logger, s store.Storer, failuresCollector *failures.ResourceFailuresCollector) map[string]interface{} {
@@ -3,6 +3,7 @@ package parser
 import (
 	"bytes"
 	"crypto/tls"
+	"errors"
 	"fmt"
 	"net"
 	"sort"
@@ -12,16 +13,20 @@ import (
 	"github.com/kong/go-kong/kong"
 	"github.com/samber/lo"
 	"github.com/samber/mo"
+	"golang.org/x/exp/slices"
 	corev1 "k8s.io/api/core/v1"
 	discoveryv1 "k8s.io/api/discovery/v1"
 	netv1 "k8s.io/api/networking/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/apimachinery/pkg/util/sets"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
+	"github.com/kong/kubernetes-ingress-controller/v2/internal/admission/validation/consumers/credentials"
 	"github.com/kong/kubernetes-ingress-controller/v2/internal/annotations"
 	"github.com/kong/kubernetes-ingress-controller/v2/internal/dataplane/failures"
 	"github.com/kong/kubernetes-ingress-controller/v2/internal/dataplane/kongstate"
+	"github.com/kong/kubernetes-ingress-controller/v2/internal/dataplane/parser/translators"
 	"github.com/kong/kubernetes-ingress-controller/v2/internal/gatewayapi"
 	"github.com/kong/kubernetes-ingress-controller/v2/internal/manager/featuregates"
 	"github.com/kong/kubernetes-ingress-controller/v2/internal/store"
@@ -62,12 +67,21 @@ type FeatureFlags struct {
 
 	// RewriteURIs enables the parser to translate the konghq.com/rewrite annotation to the proper set of Kong plugins.
 	RewriteURIs bool
+
+	// QueryParamsGuardPlugin indicates whether the plugin enforcing HTTPRoute query param matches on routes
+	// for the traditional router is available in Kong. Without it, such HTTPRoutes can't be translated.
+	QueryParamsGuardPlugin bool
+
+	// RequestMirroring enables the parser to translate HTTPRoute RequestMirror filters to Kong upstreams
+	// and the plugin mirroring requests to them. It requires the plugin to be available in Kong.
+	RequestMirroring bool
 }
 
 func NewFeatureFlags(
 	logger logr.Logger,
 	featureGates featuregates.FeatureGates,
 	routerFlavor string,
+	availablePlugins sets.Set[string],
 	updateStatusFlag bool,
 ) FeatureFlags {
 	return FeatureFlags{
@@ -75,6 +89,9 @@ func NewFeatureFlags(
 		ExpressionRoutes:                  shouldEnableParserExpressionRoutes(logger, routerFlavor),
 		FillIDs:                           featureGates.Enabled(featuregates.FillIDsFeature),
 		RewriteURIs:                       featureGates.Enabled(featuregates.RewriteURIsFeature),
+		QueryParamsGuardPlugin:            availablePlugins.Has(translators.QueryParamsGuardPluginName),
+		RequestMirroring: featureGates.Enabled(featuregates.RequestMirroringFeature) &&
+			availablePlugins.Has(translators.RequestMirrorPluginName),
 	}
 }
 
@@ -105,8 +122,20 @@ type Parser struct {
 	licenseGetter LicenseGetter
 	featureFlags  FeatureFlags
 
+	credentialRegistry  *credentials.Registry
+	credentialRotations *kongstate.CredentialRotations
+
 	failuresCollector      *failures.ResourceFailuresCollector
 	parsedObjectsCollector *ObjectsCollector
+
+	// ingressTranslationCache and httpRouteTranslationCache hold results of translating Ingresses and HTTPRoutes
+	// in the previous BuildKongConfig calls, so that only changed objects are translated again.
+	ingressTranslationCache   *translationCache[KongServicesCache]
+	httpRouteTranslationCache *translationCache[httpRouteTranslation]
+	// serviceTargetsCache holds targets of Kubernetes Services' ports by port names, and secretCertificatesCache
+	// holds TLS key-pairs extracted from Secrets, for the same reason.
+	serviceTargetsCache     *translationCache[map[string][]kongstate.Target]
+	secretCertificatesCache *translationCache[secretCertificate]
 }
 
 // NewParser produces a new Parser object provided a logging mechanism
@@ -125,11 +154,17 @@ func NewParser(
 	}
 
 	return &Parser{
-		logger:                 logger,
-		storer:                 storer,
-		featureFlags:           featureFlags,
-		failuresCollector:      failuresCollector,
-		parsedObjectsCollector: parsedObjectsCollector,
+		logger:                    logger,
+		storer:                    storer,
+		featureFlags:              featureFlags,
+		credentialRegistry:        credentials.NewRegistry(nil, false),
+		credentialRotations:       kongstate.NewCredentialRotations(),
+		failuresCollector:         failuresCollector,
+		parsedObjectsCollector:    parsedObjectsCollector,
+		ingressTranslationCache:   newTranslationCache[KongServicesCache](),
+		httpRouteTranslationCache: newTranslationCache[httpRouteTranslation](),
+		serviceTargetsCache:       newTranslationCache[map[string][]kongstate.Target](),
+		secretCertificatesCache:   newTranslationCache[secretCertificate](),
 	}, nil
 }
 
@@ -148,11 +183,19 @@ type KongConfigBuildingResult struct {
 
 	// ConfiguredKubernetesObjects is a list of Kubernetes objects that were successfully parsed.
 	ConfiguredKubernetesObjects []client.Object
+
+	// CredentialRotations holds changes of credential rotations observed during parsing. They should be
+	// committed once the configuration has been successfully applied.
+	CredentialRotations *kongstate.CredentialRotationsUpdate
 }
 
 // BuildKongConfig creates a Kong configuration from Ingress and Custom resources
 // defined in Kubernetes.
 func (p *Parser) BuildKongConfig() KongConfigBuildingResult {
+	return p.buildKongConfig(p.credentialRotations.NewUpdate())
+}
+
+func (p *Parser) buildKongConfig(credentialRotations *kongstate.CredentialRotationsUpdate) KongConfigBuildingResult {
 	// parse and merge all rules together from all Kubernetes API sources
 	ingressRules := mergeIngressRules(
 		p.ingressRulesFromIngressV1(),
@@ -188,14 +231,17 @@ func (p *Parser) BuildKongConfig() KongConfigBuildingResult {
 	// merge KongIngress with Routes, Services and Upstream
 	result.FillOverrides(p.logger, p.storer)
 
+	// apply session persistence requested by routes to Upstreams
+	p.applySessionPersistence(result.Upstreams)
+
 	// generate consumers and credentials
-	result.FillConsumersAndCredentials(p.logger, p.storer, p.failuresCollector)
+	result.FillConsumersAndCredentials(p.logger, p.storer, p.failuresCollector, p.credentialRegistry, credentialRotations)
 	for i := range result.Consumers {
 		p.registerSuccessfullyParsedObject(&result.Consumers[i].K8sKongConsumer)
 	}
 
 	// process consumer groups
-	result.FillConsumerGroups(p.logger, p.storer)
+	result.FillConsumerGroups(p.logger, p.storer, p.failuresCollector)
 	for i := range result.ConsumerGroups {
 		p.registerSuccessfullyParsedObject(&result.ConsumerGroups[i].K8sKongConsumerGroup)
 	}
@@ -227,13 +273,41 @@ func (p *Parser) BuildKongConfig() KongConfigBuildingResult {
 		result.FillIDs(p.logger)
 	}
 
+	// drop cached translations of objects which are gone from the store
+	p.ingressTranslationCache.evictUnused()
+	p.httpRouteTranslationCache.evictUnused()
+	p.serviceTargetsCache.evictUnused()
+	p.secretCertificatesCache.evictUnused()
+
 	return KongConfigBuildingResult{
 		KongState:                   &result,
 		TranslationFailures:         p.popTranslationFailures(),
 		ConfiguredKubernetesObjects: p.popConfiguredKubernetesObjects(),
+		CredentialRotations:         credentialRotations,
 	}
 }
 
+// BuildKongConfigFromCache creates a Kong configuration from the objects in the provided cache instead of the
+// store the Parser has been created with. It's used to build the fallback configuration when Kong rejects
+// the one built by BuildKongConfig.
+//
+// Credential rotations aren't started nor ended by configurations built this way, but previous values of
+// credentials being rotated are included in them.
+func (p *Parser) BuildKongConfigFromCache(cache store.CacheStores) KongConfigBuildingResult {
+	fallbackParser := *p
+	fallbackParser.storer = store.New(cache, p.storer.GetIngressClassName(), p.logger)
+	// translations of the objects in the cache aren't cached to not evict the ones of the objects excluded from it
+	fallbackParser.ingressTranslationCache = nil
+	fallbackParser.httpRouteTranslationCache = nil
+	fallbackParser.serviceTargetsCache = nil
+	fallbackParser.secretCertificatesCache = nil
+	fallbackParser.failuresCollector = failures.NewResourceFailuresCollector(p.logger)
+	if p.parsedObjectsCollector != nil {
+		fallbackParser.parsedObjectsCollector = NewObjectsCollector()
+	}
+	return fallbackParser.buildKongConfig(p.credentialRotations.NewReadOnlyUpdate())
+}
+
 // -----------------------------------------------------------------------------
 // Parser - Public Methods - Other Optional Features
 // -----------------------------------------------------------------------------
@@ -243,6 +317,12 @@ func (p *Parser) InjectLicenseGetter(licenseGetter LicenseGetter) {
 	p.licenseGetter = licenseGetter
 }
 
+// InjectCredentialRegistry sets a registry of credential types to be used by the parser.
+// By default, only the credential types known to the controller are supported.
+func (p *Parser) InjectCredentialRegistry(credentialRegistry *credentials.Registry) {
+	p.credentialRegistry = credentialRegistry
+}
+
 // -----------------------------------------------------------------------------
 // Parser - Private Methods
 // -----------------------------------------------------------------------------
@@ -374,7 +454,7 @@ func (p *Parser) getUpstreams(serviceMap map[string]kongstate.Service) ([]kongst
 				serviceMap[serviceName] = service
 
 				// get the new targets for this backend service
-				newTargets := getServiceEndpoints(p.logger, p.storer, k8sService, port)
+				newTargets := p.getServiceTargets(k8sService, port)
 
 				if len(newTargets) == 0 {
 					p.logger.V(util.InfoLevel).Info("no targets could be found for kubernetes service",
@@ -453,6 +533,24 @@ func getCertFromSecret(secret *corev1.Secret) (string, string, error) {
 	return string(cert), string(key), nil
 }
 
+// secretCertificate is a result of extracting a TLS key-pair from a Secret with getCertFromSecret.
+type secretCertificate struct {
+	cert string
+	key  string
+	err  error
+}
+
+// getCertFromSecret extracts a TLS key-pair from a Secret like getCertFromSecret does, reusing the result cached
+// for the Secret if it hasn't changed since.
+func (p *Parser) getCertFromSecret(secret *corev1.Secret) (string, string, error) {
+	c, ok := p.secretCertificatesCache.get(secret, "")
+	if !ok {
+		c.cert, c.key, c.err = getCertFromSecret(secret)
+		p.secretCertificatesCache.set(secret, "", c)
+	}
+	return c.cert, c.key, c.err
+}
+
 type certWrapper struct {
 	identifier        string
 	cert              kong.Certificate
@@ -525,7 +623,7 @@ func (p *Parser) getGatewayCerts() []certWrapper {
 						)
 						continue
 					}
-					cert, key, err := getCertFromSecret(secret)
+					cert, key, err := p.getCertFromSecret(secret)
 					if err != nil {
 						p.registerTranslationFailure("failed to construct certificate from secret", secret, gateway)
 						continue
@@ -566,7 +664,7 @@ func (p *Parser) getCerts(secretsToSNIs SecretNameToSNIs) []certWrapper {
 			p.registerTranslationFailure(fmt.Sprintf("failed to fetch the secret (%s)", secretKey), SNIs.Parents()...)
 			continue
 		}
-		cert, key, err := getCertFromSecret(secret)
+		cert, key, err := p.getCertFromSecret(secret)
 		if err != nil {
 			causingObjects := append(SNIs.Parents(), secret)
 			p.registerTranslationFailure("failed to construct certificate from secret", causingObjects...)
@@ -642,6 +740,35 @@ func mergeCerts(logger logr.Logger, certLists ...[]certWrapper) []kongstate.Cert
 	return res
 }
 
+// getServiceTargets returns targets of a Kubernetes Service's port like getServiceEndpoints does, reusing the ones
+// cached for the Service if neither it, its EndpointSlices nor IngressClassParameters have changed since.
+func (p *Parser) getServiceTargets(svc *corev1.Service, servicePort *corev1.ServicePort) []kongstate.Target {
+	// EndpointSlices and IngressClassParameters are the only objects other than a Service its targets depend on.
+	translationCache := p.serviceTargetsCache
+	endpointSlices, err := p.storer.GetEndpointSlicesForService(svc.Namespace, svc.Name)
+	if err != nil && !errors.As(err, &store.NotFoundError{}) {
+		translationCache = nil
+	}
+	// getServiceEndpoints treats the Service as a regular one when IngressClassParameters can't be retrieved too.
+	ingressClassParameters, _ := getIngressClassParametersOrDefault(p.storer)
+	dependenciesVersion := fmt.Sprintf("%s/%t", objectsVersion(endpointSlices), ingressClassParameters.ServiceUpstream)
+
+	targetsByPort, ok := translationCache.get(svc, dependenciesVersion)
+	if !ok {
+		targetsByPort = make(map[string][]kongstate.Target)
+		translationCache.set(svc, dependenciesVersion, targetsByPort)
+	}
+	// Names of ports are unique within a Service, and other fields of a port can't change without changing
+	// the Service's resourceVersion.
+	targets, ok := targetsByPort[servicePort.Name]
+	if !ok {
+		targets = getServiceEndpoints(p.logger, p.storer, svc, servicePort)
+		targetsByPort[servicePort.Name] = targets
+	}
+	// Targets are copied, as their weights are set in further translation steps while the cached ones must not be.
+	return slices.Clone(targets)
+}
+
 func getServiceEndpoints(
 	logger logr.Logger,
 	s store.Storer,
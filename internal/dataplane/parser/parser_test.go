@@ -23,6 +23,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	k8stypes "k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/apimachinery/pkg/util/sets"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	"github.com/kong/kubernetes-ingress-controller/v2/internal/annotations"
@@ -4506,6 +4507,7 @@ func TestNewFeatureFlags(t *testing.T) {
 
 		featureGates     map[string]bool
 		routerFlavor     string
+		availablePlugins sets.Set[string]
 		updateStatusFlag bool
 
 		expectedFeatureFlags FeatureFlags
@@ -4528,13 +4530,21 @@ func TestNewFeatureFlags(t *testing.T) {
 			},
 			expectInfoLog: "expression routes mode enabled",
 		},
+		{
+			name:             "query params guard plugin available",
+			routerFlavor:     "traditional",
+			availablePlugins: sets.New("pre-function", "cors"),
+			expectedFeatureFlags: FeatureFlags{
+				QueryParamsGuardPlugin: true,
+			},
+		},
 	}
 
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
 			core, logs := observer.New(zap.InfoLevel)
 			logger := zapr.NewLogger(zap.New(core))
-			actualFlags := NewFeatureFlags(logger, tc.featureGates, tc.routerFlavor, tc.updateStatusFlag)
+			actualFlags := NewFeatureFlags(logger, tc.featureGates, tc.routerFlavor, tc.availablePlugins, tc.updateStatusFlag)
 
 			require.Equal(t, tc.expectedFeatureFlags, actualFlags)
 
@@ -4587,6 +4597,38 @@ func TestParser_License(t *testing.T) {
 	})
 }
 
+func TestParser_BuildKongConfigFromCache(t *testing.T) {
+	newConsumer := func(name string) *kongv1.KongConsumer {
+		return &kongv1.KongConsumer{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        name,
+				Namespace:   "default",
+				Annotations: map[string]string{annotations.IngressClassKey: annotations.DefaultIngressClass},
+			},
+			Username: name,
+		}
+	}
+	s, err := store.NewFakeStore(store.FakeObjects{
+		KongConsumers: []*kongv1.KongConsumer{newConsumer("current")},
+	})
+	require.NoError(t, err)
+	p := mustNewParser(t, s)
+
+	cache := store.NewCacheStores()
+	require.NoError(t, cache.Add(newConsumer("fallback")))
+
+	result := p.BuildKongConfigFromCache(cache)
+	require.Len(t, result.KongState.Consumers, 1)
+	require.Equal(t, "fallback", *result.KongState.Consumers[0].Username)
+	require.Len(t, result.ConfiguredKubernetesObjects, 1)
+	require.Equal(t, "fallback", result.ConfiguredKubernetesObjects[0].GetName())
+
+	t.Log("verifying that the parser still builds the configuration from its store")
+	result = p.BuildKongConfig()
+	require.Len(t, result.KongState.Consumers, 1)
+	require.Equal(t, "current", *result.KongState.Consumers[0].Username)
+}
+
 func TestParser_ConfiguredKubernetesObjects(t *testing.T) {
 	testCases := []struct {
 		name                          string
@@ -4755,6 +4797,7 @@ func mustNewParser(t *testing.T, storer store.Storer) *Parser {
 			// We'll assume these are true for all tests.
 			FillIDs:                           true,
 			ReportConfiguredKubernetesObjects: true,
+			QueryParamsGuardPlugin:            true,
 		},
 	)
 	require.NoError(t, err)
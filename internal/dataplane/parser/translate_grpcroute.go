@@ -152,6 +152,25 @@ func (p *Parser) ingressRulesFromGRPCRouteWithPriority(
 	rules.ServiceNameToParent[serviceName] = grpcRoute
 }
 
+// GenerateKongRoutesFromGRPCRoute generates Kong routes from all rules of a GRPCRoute.
+// It's used to validate GRPCRoutes against Kong Gateway before they're translated, so expression based routes
+// don't have priorities assigned relatively to other GRPCRoutes.
+func GenerateKongRoutesFromGRPCRoute(grpcroute *gatewayapi.GRPCRoute, expressionRoutes bool) ([]kongstate.Route, error) {
+	if err := validateGRPCRoute(grpcroute); err != nil {
+		return nil, err
+	}
+
+	var routes []kongstate.Route
+	for ruleNumber := range grpcroute.Spec.Rules {
+		if expressionRoutes {
+			routes = append(routes, translators.GenerateKongExpressionRoutesFromGRPCRouteRule(grpcroute, ruleNumber)...)
+		} else {
+			routes = append(routes, translators.GenerateKongRoutesFromGRPCRouteRule(grpcroute, ruleNumber)...)
+		}
+	}
+	return routes, nil
+}
+
 func grpcBackendRefsToBackendRefs(grpcBackendRef []gatewayapi.GRPCBackendRef) []gatewayapi.BackendRef {
 	backendRefs := make([]gatewayapi.BackendRef, 0, len(grpcBackendRef))
 
@@ -3,6 +3,7 @@ package parser
 import (
 	"errors"
 	"fmt"
+	"regexp"
 	"strings"
 
 	"github.com/kong/go-kong/kong"
@@ -45,8 +46,26 @@ func (p *Parser) ingressRulesFromHTTPRoutes() ingressRules {
 		return result
 	}
 
+	// ReferenceGrants are the only objects other than an HTTPRoute its translation depends on.
+	translationCache := p.httpRouteTranslationCache
+	grants, err := p.storer.ListReferenceGrants()
+	if err != nil {
+		translationCache = nil
+	}
+	dependenciesVersion := objectsVersion(grants)
+
 	for _, httproute := range httpRoutesToTranslate {
-		if err := p.ingressRulesFromHTTPRoute(&result, httproute); err != nil {
+		translation, ok := translationCache.get(httproute, dependenciesVersion)
+		if !ok {
+			translation = httpRouteTranslation{rules: newIngressRules()}
+			translation.err = p.ingressRulesFromHTTPRoute(&translation.rules, httproute)
+			translationCache.set(httproute, dependenciesVersion, translation)
+		}
+		// Services are copied, as they're modified in further translation steps while the cached ones must not be.
+		// Services translated before an error are kept in the result like it's done for not cached translations.
+		result.mergeServicesCopies(translation.rules)
+
+		if err := translation.err; err != nil {
 			p.registerTranslationFailure(fmt.Sprintf("HTTPRoute can't be routed: %s", err), httproute)
 		} else {
 			// at this point the object has been configured and can be
@@ -58,6 +77,12 @@ func (p *Parser) ingressRulesFromHTTPRoutes() ingressRules {
 	return result
 }
 
+// httpRouteTranslation is a result of translating a single HTTPRoute with ingressRulesFromHTTPRoute.
+type httpRouteTranslation struct {
+	rules ingressRules
+	err   error
+}
+
 // ingressRulesFromHTTPRoute validates and generates a set of proto-Kong routes (ingress rules) from an HTTPRoute.
 // If multiple rules in the HTTPRoute use the same Service, it combines them into a single Kong route.
 func (p *Parser) ingressRulesFromHTTPRoute(result *ingressRules, httproute *gatewayapi.HTTPRoute) error {
@@ -72,6 +97,10 @@ func (p *Parser) ingressRulesFromHTTPRoute(result *ingressRules, httproute *gate
 		if err != nil {
 			return err
 		}
+		if err := applyHTTPRouteTimeoutsToKongService(&service, kongServiceTranslation.Timeouts); err != nil {
+			return err
+		}
+		service.SessionPersistence = kongServiceTranslation.SessionPersistence
 
 		// generate the routes for the service and attach them to the service
 		for _, kongRouteTranslation := range kongServiceTranslation.KongRoutes {
@@ -79,6 +108,17 @@ func (p *Parser) ingressRulesFromHTTPRoute(result *ingressRules, httproute *gate
 			if err != nil {
 				return err
 			}
+			mirrorPlugin, err := p.generateRequestMirrorPlugin(result, httproute, kongRouteTranslation.Filters)
+			if err != nil {
+				return err
+			}
+			extensionRefPlugins := translators.KongPluginNamesFromHTTPRouteFilters(kongRouteTranslation.Filters)
+			for i := range routes {
+				if mirrorPlugin != nil {
+					routes[i].Plugins = translators.AddPreFunctionPlugin(routes[i].Plugins, *mirrorPlugin)
+				}
+				routes[i].ExtensionRefPlugins = extensionRefPlugins
+			}
 			service.Routes = append(service.Routes, routes...)
 		}
 
@@ -89,6 +129,73 @@ func (p *Parser) ingressRulesFromHTTPRoute(result *ingressRules, httproute *gate
 	return nil
 }
 
+// generateRequestMirrorPlugin generates Kong services (and hence upstreams) for the backends referenced by
+// RequestMirror filters and returns the plugin mirroring requests to them. Backends which cannot be routed to,
+// e.g. because no ReferenceGrant permits the reference, are skipped. They are reported in the ResolvedRefs
+// condition of the HTTPRoute.
+func (p *Parser) generateRequestMirrorPlugin(
+	result *ingressRules,
+	httproute *gatewayapi.HTTPRoute,
+	filters []gatewayapi.HTTPRouteFilter,
+) (*kong.Plugin, error) {
+	if !p.featureFlags.RequestMirroring {
+		return nil, nil
+	}
+
+	var upstreams []string
+	for _, filter := range filters {
+		if filter.Type != gatewayapi.HTTPRouteFilterRequestMirror || filter.RequestMirror == nil {
+			continue
+		}
+
+		serviceName := translators.KongServiceNameForRequestMirror(httproute, filter.RequestMirror.BackendRef)
+		service, err := generateKongServiceFromBackendRefWithName(
+			p.logger,
+			p.storer,
+			result,
+			serviceName,
+			httproute,
+			"http",
+			gatewayapi.BackendRef{BackendObjectReference: filter.RequestMirror.BackendRef},
+		)
+		if err != nil {
+			return nil, err
+		}
+		if len(service.Backends) == 0 {
+			continue
+		}
+
+		result.ServiceNameToServices[serviceName] = service
+		result.ServiceNameToParent[serviceName] = httproute
+		upstreams = append(upstreams, serviceName)
+	}
+
+	if len(upstreams) == 0 {
+		return nil, nil
+	}
+	plugin := translators.GenerateRequestMirrorPlugin(lo.Uniq(upstreams), util.GenerateTagsForObject(httproute))
+	return &plugin, nil
+}
+
+// applyHTTPRouteTimeoutsToKongService sets the connect, read and write timeouts of a Kong service
+// translated from HTTPRoute rules with the given timeouts. Timeouts set this way take precedence
+// over the ones set by annotations of the backend Kubernetes Services.
+func applyHTTPRouteTimeoutsToKongService(service *kongstate.Service, timeouts *gatewayapi.HTTPRouteTimeouts) error {
+	timeout, err := translators.KongServiceTimeoutFromHTTPRouteTimeouts(timeouts)
+	if err != nil {
+		return err
+	}
+	if timeout == nil {
+		return nil
+	}
+
+	service.ConnectTimeout = kong.Int(*timeout)
+	service.ReadTimeout = kong.Int(*timeout)
+	service.WriteTimeout = kong.Int(*timeout)
+	service.TimeoutsFromParent = true
+	return nil
+}
+
 func validateHTTPRoute(httproute *gatewayapi.HTTPRoute, featureFlags FeatureFlags) error {
 	spec := httproute.Spec
 
@@ -100,21 +207,28 @@ func validateHTTPRoute(httproute *gatewayapi.HTTPRoute, featureFlags FeatureFlag
 		return translators.ErrRouteValidationNoRules
 	}
 
-	// Kong supports query parameter match only with expression router,
-	// so we return error when query param match is specified and expression router is not enabled in the parser.
-	if !featureFlags.ExpressionRoutes {
-		for _, rule := range spec.Rules {
-			for _, match := range rule.Matches {
-				if len(match.QueryParams) > 0 {
-					return translators.ErrRouteValidationQueryParamMatchesUnsupported
-				}
-			}
-		}
+	// Kong's traditional router can't match query params, so they're enforced by a plugin,
+	// which has to be available in Kong.
+	if !featureFlags.ExpressionRoutes && !featureFlags.QueryParamsGuardPlugin && hasQueryParamMatches(httproute) {
+		return translators.ErrRouteValidationQueryParamMatchesUnsupported
+	}
+
+	if _, err := translators.SessionPersistenceForHTTPRouteRules(httproute); err != nil {
+		return err
 	}
 
 	return nil
 }
 
+// hasQueryParamMatches tells whether any of the HTTPRoute's matches matches query params.
+func hasQueryParamMatches(httproute *gatewayapi.HTTPRoute) bool {
+	return lo.SomeBy(httproute.Spec.Rules, func(rule gatewayapi.HTTPRouteRule) bool {
+		return lo.SomeBy(rule.Matches, func(match gatewayapi.HTTPRouteMatch) bool {
+			return len(match.QueryParams) > 0
+		})
+	})
+}
+
 // ingressRulesFromHTTPRoutesUsingExpressionRoutes translates HTTPRoutes to expression based routes
 // when ExpressionRoutes feature flag is enabled.
 // Because we need to assign different priorities based on the hostname and match in the specification of HTTPRoutes,
@@ -227,6 +341,10 @@ func generateKongRoutesFromHTTPRouteMatches(
 	hostnames []*string,
 	tags []*string,
 ) ([]kongstate.Route, error) {
+	if err := translators.ValidateHTTPRouteRuleFilters(matches, filters); err != nil {
+		return []kongstate.Route{}, err
+	}
+
 	if len(matches) == 0 {
 		// it's acceptable for an HTTPRoute to have no matches in the rulesets,
 		// but only backends as long as there are hostnames. In this case, we
@@ -262,15 +380,31 @@ func generateKongRoutesFromHTTPRouteMatches(
 	// stripPath needs to be disabled by default to be conformant with the Gateway API
 	r.StripPath = kong.Bool(false)
 
+	if regexPriority := regexPriorityFromHTTPRouteMatches(matches); regexPriority > 0 {
+		r.RegexPriority = kong.Int(regexPriority)
+	}
+
+	// Kong's traditional router can't match query params, so they're enforced by a plugin
+	// run once the route has been selected by the other criteria of the matches.
+	if len(matches[0].QueryParams) > 0 {
+		r.Plugins = append(r.Plugins, translators.GenerateQueryParamsGuardPlugin(matches[0].QueryParams, tags))
+	}
+
 	_, hasRedirectFilter := lo.Find(filters, func(filter gatewayapi.HTTPRouteFilter) bool {
 		return filter.Type == gatewayapi.HTTPRouteFilterRequestRedirect
 	})
 
-	routes := getRoutesFromMatches(matches, &r, filters, tags, hasRedirectFilter)
+	routes, err := getRoutesFromMatches(matches, &r, filters, tags, hasRedirectFilter)
+	if err != nil {
+		return []kongstate.Route{}, err
+	}
 
 	// if the redirect filter has not been set, we still need to set the route plugins
 	if !hasRedirectFilter {
-		plugins := translators.GeneratePluginsFromHTTPRouteFilters(filters, "", tags)
+		plugins, err := translators.GeneratePluginsFromHTTPRouteFilters(filters, "", tags)
+		if err != nil {
+			return []kongstate.Route{}, err
+		}
 		r.Plugins = append(r.Plugins, plugins...)
 		routes = []kongstate.Route{r}
 	}
@@ -285,9 +419,10 @@ func getRoutesFromMatches(
 	filters []gatewayapi.HTTPRouteFilter,
 	tags []*string,
 	hasRedirectFilter bool,
-) []kongstate.Route {
+) ([]kongstate.Route, error) {
 	seenMethods := make(map[string]struct{})
 	routes := make([]kongstate.Route, 0)
+	capturePrefixRemainder := translators.HasURLRewriteReplacePrefixMatchFilter(filters)
 
 	for _, match := range matches {
 		// if the rule specifies the redirectFilter, we cannot put all the paths under the same route,
@@ -299,7 +434,7 @@ func getRoutesFromMatches(
 			// default if it is not. For those types, we use the path value as-is and let Kong determine the type.
 			// For exact matches, we transform the path into a regular expression that terminates after the value
 			if match.Path != nil {
-				paths := generateKongRoutePathFromHTTPRouteMatch(match)
+				paths := generateKongRoutePathFromHTTPRouteMatch(match, false)
 				for _, p := range paths {
 					matchRoute.Route.Paths = append(matchRoute.Route.Paths, kong.String(p))
 				}
@@ -320,7 +455,10 @@ func getRoutesFromMatches(
 			}
 
 			// generate kong plugins from rule.filters
-			plugins := translators.GeneratePluginsFromHTTPRouteFilters(filters, path, tags)
+			plugins, err := translators.GeneratePluginsFromHTTPRouteFilters(filters, path, tags)
+			if err != nil {
+				return nil, err
+			}
 			matchRoute.Plugins = append(matchRoute.Plugins, plugins...)
 
 			routes = append(routes, *route)
@@ -330,7 +468,7 @@ func getRoutesFromMatches(
 			// default if it is not. For those types, we use the path value as-is and let Kong determine the type.
 			// For exact matches, we transform the path into a regular expression that terminates after the value.
 			if match.Path != nil {
-				for _, path := range generateKongRoutePathFromHTTPRouteMatch(match) {
+				for _, path := range generateKongRoutePathFromHTTPRouteMatch(match, capturePrefixRemainder) {
 					route.Route.Paths = append(route.Route.Paths, kong.String(path))
 				}
 			}
@@ -344,15 +482,26 @@ func getRoutesFromMatches(
 			}
 		}
 	}
-	return routes
+	return routes, nil
 }
 
-func generateKongRoutePathFromHTTPRouteMatch(match gatewayapi.HTTPRouteMatch) []string {
+// generateKongRoutePathFromHTTPRouteMatch generates Kong route paths for the path of an HTTPRoute match.
+// When capturePrefixRemainder is set, paths generated for a PathPrefix match capture the remainder
+// of the path following the prefix, so it can be used by the plugin implementing the URLRewrite filter.
+func generateKongRoutePathFromHTTPRouteMatch(match gatewayapi.HTTPRouteMatch, capturePrefixRemainder bool) []string {
 	switch *match.Path.Type {
 	case gatewayapi.PathMatchExact:
 		return []string{translators.KongPathRegexPrefix + *match.Path.Value + "$"}
 
 	case gatewayapi.PathMatchPathPrefix:
+		if capturePrefixRemainder {
+			prefix := strings.TrimSuffix(*match.Path.Value, "/")
+			captureRemainder := translators.KongPathRegexPrefix + translators.KongPathRegexCapturingPrefixRemainder(prefix)
+			if prefix == "" {
+				return []string{captureRemainder}
+			}
+			return []string{translators.KongPathRegexPrefix + regexp.QuoteMeta(prefix) + "$", captureRemainder}
+		}
 		paths := make([]string, 0, 2)
 		path := *match.Path.Value
 		paths = append(paths, fmt.Sprintf("%s%s$", translators.KongPathRegexPrefix, path))
@@ -368,6 +517,29 @@ func generateKongRoutePathFromHTTPRouteMatch(match gatewayapi.HTTPRouteMatch) []
 	return []string{""} // unreachable code
 }
 
+// regexPriorityFromHTTPRouteMatches returns the regex_priority of a Kong route generated from the given matches.
+// Kong evaluates routes with regex paths (which all the routes with exact and prefix matches are) by their
+// regex_priority before the length of their plain paths. It's derived from the lengths of the matched paths for
+// exact matches to take precedence over prefix matches of the same path and longer prefixes over shorter ones,
+// as required by the Gateway API. Prefix matches translated into regex paths only (to capture the remainder of
+// the path for URLRewrite filters) wouldn't take the length of their prefix into account otherwise. Like Kong
+// does with the lengths of plain paths, the highest priority of the matches consolidated into a route is used.
+func regexPriorityFromHTTPRouteMatches(matches []gatewayapi.HTTPRouteMatch) int {
+	priority := 0
+	for _, match := range matches {
+		if match.Path == nil || match.Path.Type == nil || match.Path.Value == nil {
+			continue
+		}
+		switch *match.Path.Type {
+		case gatewayapi.PathMatchExact:
+			priority = max(priority, 2*len(*match.Path.Value)+1)
+		case gatewayapi.PathMatchPathPrefix:
+			priority = max(priority, 2*len(strings.TrimSuffix(*match.Path.Value, "/")))
+		}
+	}
+	return priority
+}
+
 func generateKongstateHTTPRoute(routeName string, ingressObjectInfo util.K8sObjectInfo, hostnames []*string) kongstate.Route {
 	// build the route object using the method and pathing information
 	r := kongstate.Route{
@@ -427,11 +599,27 @@ func (p *Parser) ingressRulesFromSplitHTTPRouteMatchWithPriority(
 	if err != nil {
 		return err
 	}
+	if err := applyHTTPRouteTimeoutsToKongService(&kongService, rule.Timeouts); err != nil {
+		return err
+	}
+	// invalid session persistence annotations are reported when validating the HTTPRoute.
+	if sessionPersistence, _ := translators.SessionPersistenceForHTTPRouteRules(httpRoute); sessionPersistence != nil {
+		kongService.SessionPersistence = sessionPersistence[match.RuleIndex]
+	}
 
-	kongService.Routes = append(
-		kongService.Routes,
-		translators.KongExpressionRouteFromHTTPRouteMatchWithPriority(httpRouteMatchWithPriority),
-	)
+	route, err := translators.KongExpressionRouteFromHTTPRouteMatchWithPriority(httpRouteMatchWithPriority)
+	if err != nil {
+		return err
+	}
+	mirrorPlugin, err := p.generateRequestMirrorPlugin(rules, httpRoute, rule.Filters)
+	if err != nil {
+		return err
+	}
+	if mirrorPlugin != nil {
+		route.Plugins = translators.AddPreFunctionPlugin(route.Plugins, *mirrorPlugin)
+	}
+	route.ExtensionRefPlugins = translators.KongPluginNamesFromHTTPRouteFilters(rule.Filters)
+	kongService.Routes = append(kongService.Routes, route)
 	// cache the service to avoid duplicates in further loop iterations
 	rules.ServiceNameToServices[serviceName] = kongService
 	rules.ServiceNameToParent[serviceName] = httpRoute
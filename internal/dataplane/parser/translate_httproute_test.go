@@ -1,6 +1,8 @@
 package parser
 
 import (
+	"fmt"
+	"strings"
 	"testing"
 
 	"github.com/go-logr/zapr"
@@ -22,6 +24,8 @@ import (
 	"github.com/kong/kubernetes-ingress-controller/v2/internal/store"
 	"github.com/kong/kubernetes-ingress-controller/v2/internal/util"
 	"github.com/kong/kubernetes-ingress-controller/v2/internal/util/builder"
+	kongv1 "github.com/kong/kubernetes-ingress-controller/v2/pkg/apis/configuration/v1"
+	kongv1beta1 "github.com/kong/kubernetes-ingress-controller/v2/pkg/apis/configuration/v1beta1"
 )
 
 // httprouteGVK is the GVK for HTTPRoutes, needed in unit tests because
@@ -42,10 +46,10 @@ type testCaseIngressRulesFromHTTPRoutes struct {
 
 func TestValidateHTTPRoute(t *testing.T) {
 	testCases := []struct {
-		name             string
-		httpRoute        *gatewayapi.HTTPRoute
-		expressionRoutes bool
-		expectedError    error
+		name          string
+		httpRoute     *gatewayapi.HTTPRoute
+		featureFlags  FeatureFlags
+		expectedError error
 	}{
 		{
 			name: "valid HTTPRoute should pass the validation",
@@ -67,8 +71,7 @@ func TestValidateHTTPRoute(t *testing.T) {
 					}},
 				},
 			},
-			expressionRoutes: false,
-			expectedError:    nil,
+			expectedError: nil,
 		},
 		{
 			name: "HTTPRoute with no rules should not pass the validation",
@@ -85,8 +88,7 @@ func TestValidateHTTPRoute(t *testing.T) {
 					},
 				},
 			},
-			expressionRoutes: false,
-			expectedError:    translators.ErrRouteValidationNoRules,
+			expectedError: translators.ErrRouteValidationNoRules,
 		},
 		{
 			name: "HTTPRoute with query param match should pass validation with expression routes",
@@ -109,11 +111,35 @@ func TestValidateHTTPRoute(t *testing.T) {
 					}},
 				},
 			},
-			expressionRoutes: true,
-			expectedError:    nil,
+			featureFlags:  FeatureFlags{ExpressionRoutes: true},
+			expectedError: nil,
+		},
+		{
+			name: "HTTPRoute with query param match should pass validation when query params guard plugin is available",
+			httpRoute: &gatewayapi.HTTPRoute{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "httproute-query-param-match",
+					Namespace: corev1.NamespaceDefault,
+				},
+				Spec: gatewayapi.HTTPRouteSpec{
+					CommonRouteSpec: commonRouteSpecMock("fake-gateway-1"),
+					Hostnames: []gatewayapi.Hostname{
+						"konghq.com",
+						"www.konghq.com",
+					},
+					Rules: []gatewayapi.HTTPRouteRule{{
+						BackendRefs: []gatewayapi.HTTPBackendRef{
+							builder.NewHTTPBackendRef("fake-service").WithPort(80).Build(),
+						},
+						Matches: builder.NewHTTPRouteMatch().WithQueryParam("foo", "bar").ToSlice(),
+					}},
+				},
+			},
+			featureFlags:  FeatureFlags{QueryParamsGuardPlugin: true},
+			expectedError: nil,
 		},
 		{
-			name: "HTTPRoute with query param match should not pass validation when expression routes disabled",
+			name: "HTTPRoute with query param match should not pass validation when query params guard plugin is not available",
 			httpRoute: &gatewayapi.HTTPRoute{
 				ObjectMeta: metav1.ObjectMeta{
 					Name:      "httproute-query-param-match",
@@ -133,18 +159,15 @@ func TestValidateHTTPRoute(t *testing.T) {
 					}},
 				},
 			},
-			expressionRoutes: false,
-			expectedError:    translators.ErrRouteValidationQueryParamMatchesUnsupported,
+			featureFlags:  FeatureFlags{},
+			expectedError: translators.ErrRouteValidationQueryParamMatchesUnsupported,
 		},
 	}
 
 	for _, tc := range testCases {
 		tc := tc
 		t.Run(tc.name, func(t *testing.T) {
-			featureFlags := FeatureFlags{
-				ExpressionRoutes: tc.expressionRoutes,
-			}
-			err := validateHTTPRoute(tc.httpRoute, featureFlags)
+			err := validateHTTPRoute(tc.httpRoute, tc.featureFlags)
 			if tc.expectedError == nil {
 				require.NoError(t, err, "should pass the validation")
 			} else {
@@ -361,6 +384,7 @@ func TestIngressRulesFromHTTPRoutes(t *testing.T) {
 										kong.String("k8s-group:gateway.networking.k8s.io"),
 										kong.String("k8s-version:v1beta1"),
 									},
+									RegexPriority: kong.Int(16),
 								},
 								Ingress: k8sObjectInfoOfHTTPRoute(routes[0]),
 							}},
@@ -370,6 +394,126 @@ func TestIngressRulesFromHTTPRoutes(t *testing.T) {
 				}
 			},
 		},
+		{
+			msg: "an HTTPRoute with URLRewrite filter replacing prefix match captures the path following the prefix",
+			routes: []*gatewayapi.HTTPRoute{{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "basic-httproute",
+					Namespace: corev1.NamespaceDefault,
+				},
+				Spec: gatewayapi.HTTPRouteSpec{
+					CommonRouteSpec: commonRouteSpecMock("fake-gateway"),
+					Rules: []gatewayapi.HTTPRouteRule{{
+						Matches: []gatewayapi.HTTPRouteMatch{
+							builder.NewHTTPRouteMatch().WithPathPrefix("/httpbin").Build(),
+						},
+						Filters: []gatewayapi.HTTPRouteFilter{
+							builder.NewHTTPRouteURLRewriteFilter().
+								WithURLRewriteHostname("example.org").
+								WithURLRewritePrefixMatch("/").
+								Build(),
+						},
+						BackendRefs: []gatewayapi.HTTPBackendRef{
+							builder.NewHTTPBackendRef("fake-service").WithPort(80).Build(),
+						},
+					}},
+				},
+			}},
+			expected: func(routes []*gatewayapi.HTTPRoute) ingressRules {
+				return ingressRules{
+					SecretNameToSNIs: newSecretNameToSNIs(),
+					ServiceNameToParent: map[string]client.Object{
+						"httproute.default.basic-httproute.0": routes[0],
+					},
+					ServiceNameToServices: map[string]kongstate.Service{
+						"httproute.default.basic-httproute.0": {
+							Service: kong.Service{
+								ConnectTimeout: kong.Int(60000),
+								Host:           kong.String("httproute.default.basic-httproute.0"),
+								Name:           kong.String("httproute.default.basic-httproute.0"),
+								Protocol:       kong.String("http"),
+								ReadTimeout:    kong.Int(60000),
+								Retries:        kong.Int(5),
+								WriteTimeout:   kong.Int(60000),
+							},
+							Backends: kongstate.ServiceBackends{
+								builder.NewKongstateServiceBackend("fake-service").WithPortNumber(80).Build(),
+							},
+							Namespace: "default",
+							Routes: []kongstate.Route{{
+								Route: kong.Route{
+									Name: kong.String("httproute.default.basic-httproute.0.0"),
+									Paths: []*string{
+										kong.String("~/httpbin$"),
+										kong.String("~/httpbin(/.*)"),
+									},
+									PreserveHost: kong.Bool(true),
+									Protocols: []*string{
+										kong.String("http"),
+										kong.String("https"),
+									},
+									StripPath: lo.ToPtr(false),
+									Tags: []*string{
+										kong.String("k8s-name:basic-httproute"),
+										kong.String("k8s-namespace:default"),
+										kong.String("k8s-kind:HTTPRoute"),
+										kong.String("k8s-group:gateway.networking.k8s.io"),
+										kong.String("k8s-version:v1beta1"),
+									},
+									RegexPriority: kong.Int(16),
+								},
+								Ingress: k8sObjectInfoOfHTTPRoute(routes[0]),
+								Plugins: []kong.Plugin{
+									{
+										Name: kong.String("request-transformer"),
+										Config: kong.Configuration{
+											"replace": map[string]interface{}{
+												"headers": []string{"host:example.org"},
+												"uri":     `$(uri_captures[1] == nil and "/" or uri_captures[1])`,
+											},
+										},
+									},
+								},
+							}},
+							Parent: routes[0],
+						},
+					},
+				}
+			},
+		},
+		{
+			msg: "an HTTPRoute with URLRewrite filter replacing prefix match can't be routed with exact path matches",
+			routes: []*gatewayapi.HTTPRoute{{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "basic-httproute",
+					Namespace: corev1.NamespaceDefault,
+				},
+				Spec: gatewayapi.HTTPRouteSpec{
+					CommonRouteSpec: commonRouteSpecMock("fake-gateway"),
+					Rules: []gatewayapi.HTTPRouteRule{{
+						Matches: []gatewayapi.HTTPRouteMatch{
+							builder.NewHTTPRouteMatch().WithPathExact("/httpbin").Build(),
+						},
+						Filters: []gatewayapi.HTTPRouteFilter{
+							builder.NewHTTPRouteURLRewriteFilter().WithURLRewritePrefixMatch("/").Build(),
+						},
+						BackendRefs: []gatewayapi.HTTPBackendRef{
+							builder.NewHTTPBackendRef("fake-service").WithPort(80).Build(),
+						},
+					}},
+				},
+			}},
+			expected: func(routes []*gatewayapi.HTTPRoute) ingressRules {
+				return ingressRules{
+					SecretNameToSNIs:      newSecretNameToSNIs(),
+					ServiceNameToParent:   map[string]client.Object{},
+					ServiceNameToServices: make(map[string]kongstate.Service),
+				}
+			},
+			errs: []error{
+				translators.ErrRouteValidationReplacePrefixMatchRequiresPathPrefix,
+			},
+		},
 		{
 			msg: "an HTTPRoute with regex path matches is supported",
 			routes: []*gatewayapi.HTTPRoute{{
@@ -499,6 +643,7 @@ func TestIngressRulesFromHTTPRoutes(t *testing.T) {
 										kong.String("k8s-group:gateway.networking.k8s.io"),
 										kong.String("k8s-version:v1beta1"),
 									},
+									RegexPriority: kong.Int(17),
 								},
 								Ingress: k8sObjectInfoOfHTTPRoute(routes[0]),
 							}},
@@ -579,6 +724,7 @@ func TestIngressRulesFromHTTPRoutes(t *testing.T) {
 											kong.String("k8s-group:gateway.networking.k8s.io"),
 											kong.String("k8s-version:v1beta1"),
 										},
+										RegexPriority: kong.Int(20),
 									},
 									Ingress: k8sObjectInfoOfHTTPRoute(routes[0]),
 								},
@@ -663,6 +809,7 @@ func TestIngressRulesFromHTTPRoutes(t *testing.T) {
 										kong.String("k8s-group:gateway.networking.k8s.io"),
 										kong.String("k8s-version:v1beta1"),
 									},
+									RegexPriority: kong.Int(20),
 								},
 								Ingress: k8sObjectInfoOfHTTPRoute(routes[0]),
 							}},
@@ -703,6 +850,7 @@ func TestIngressRulesFromHTTPRoutes(t *testing.T) {
 										kong.String("k8s-group:gateway.networking.k8s.io"),
 										kong.String("k8s-version:v1beta1"),
 									},
+									RegexPriority: kong.Int(20),
 								},
 								Ingress: k8sObjectInfoOfHTTPRoute(routes[0]),
 							}},
@@ -801,6 +949,7 @@ func TestIngressRulesFromHTTPRoutes(t *testing.T) {
 											kong.String("k8s-group:gateway.networking.k8s.io"),
 											kong.String("k8s-version:v1beta1"),
 										},
+										RegexPriority: kong.Int(20),
 									},
 									Ingress: k8sObjectInfoOfHTTPRoute(routes[0]),
 								},
@@ -844,6 +993,7 @@ func TestIngressRulesFromHTTPRoutes(t *testing.T) {
 											kong.String("k8s-group:gateway.networking.k8s.io"),
 											kong.String("k8s-version:v1beta1"),
 										},
+										RegexPriority: kong.Int(20),
 									},
 									Ingress: k8sObjectInfoOfHTTPRoute(routes[0]),
 								},
@@ -949,6 +1099,7 @@ func TestIngressRulesFromHTTPRoutes(t *testing.T) {
 											kong.String("k8s-group:gateway.networking.k8s.io"),
 											kong.String("k8s-version:v1beta1"),
 										},
+										RegexPriority: kong.Int(14),
 									},
 									Ingress: k8sObjectInfoOfHTTPRoute(routes[0]),
 									Plugins: []kong.Plugin{
@@ -982,6 +1133,7 @@ func TestIngressRulesFromHTTPRoutes(t *testing.T) {
 											kong.String("k8s-group:gateway.networking.k8s.io"),
 											kong.String("k8s-version:v1beta1"),
 										},
+										RegexPriority: kong.Int(14),
 									},
 									Ingress: k8sObjectInfoOfHTTPRoute(routes[0]),
 									Plugins: []kong.Plugin{
@@ -1089,6 +1241,7 @@ func TestIngressRulesFromHTTPRoutes(t *testing.T) {
 											kong.String("k8s-group:gateway.networking.k8s.io"),
 											kong.String("k8s-version:v1beta1"),
 										},
+										RegexPriority: kong.Int(14),
 									},
 									Ingress: k8sObjectInfoOfHTTPRoute(routes[0]),
 								},
@@ -1116,6 +1269,7 @@ func TestIngressRulesFromHTTPRoutes(t *testing.T) {
 											kong.String("k8s-group:gateway.networking.k8s.io"),
 											kong.String("k8s-version:v1beta1"),
 										},
+										RegexPriority: kong.Int(14),
 									},
 									Ingress: k8sObjectInfoOfHTTPRoute(routes[0]),
 								},
@@ -1146,6 +1300,7 @@ func TestIngressRulesFromHTTPRoutes(t *testing.T) {
 											kong.String("k8s-group:gateway.networking.k8s.io"),
 											kong.String("k8s-version:v1beta1"),
 										},
+										RegexPriority: kong.Int(14),
 									},
 									Ingress: k8sObjectInfoOfHTTPRoute(routes[0]),
 								},
@@ -1275,6 +1430,7 @@ func TestIngressRulesFromHTTPRoutes(t *testing.T) {
 											kong.String("k8s-group:gateway.networking.k8s.io"),
 											kong.String("k8s-version:v1beta1"),
 										},
+										RegexPriority: kong.Int(14),
 									},
 									Ingress: k8sObjectInfoOfHTTPRoute(routes[0]),
 								},
@@ -1302,6 +1458,7 @@ func TestIngressRulesFromHTTPRoutes(t *testing.T) {
 											kong.String("k8s-group:gateway.networking.k8s.io"),
 											kong.String("k8s-version:v1beta1"),
 										},
+										RegexPriority: kong.Int(14),
 									},
 									Ingress: k8sObjectInfoOfHTTPRoute(routes[0]),
 								},
@@ -1329,6 +1486,7 @@ func TestIngressRulesFromHTTPRoutes(t *testing.T) {
 											kong.String("k8s-group:gateway.networking.k8s.io"),
 											kong.String("k8s-version:v1beta1"),
 										},
+										RegexPriority: kong.Int(14),
 									},
 									Ingress: k8sObjectInfoOfHTTPRoute(routes[0]),
 									Plugins: []kong.Plugin{
@@ -2191,3 +2349,569 @@ func commonRouteSpecMock(parentReferentName string) gatewayapi.CommonRouteSpec {
 		}},
 	}
 }
+
+func TestIngressRulesFromHTTPRoutesWithExtensionRefPlugins(t *testing.T) {
+	httpRoute := &gatewayapi.HTTPRoute{
+		TypeMeta: metav1.TypeMeta{Kind: "HTTPRoute", APIVersion: gatewayv1beta1.GroupVersion.String()},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "httproute-with-plugins",
+			Namespace: corev1.NamespaceDefault,
+		},
+		Spec: gatewayapi.HTTPRouteSpec{
+			CommonRouteSpec: commonRouteSpecMock("fake-gateway"),
+			Rules: []gatewayapi.HTTPRouteRule{
+				{
+					Matches: []gatewayapi.HTTPRouteMatch{
+						builder.NewHTTPRouteMatch().WithPathPrefix("/with-plugins").Build(),
+					},
+					Filters: []gatewayapi.HTTPRouteFilter{
+						{
+							Type: gatewayapi.HTTPRouteFilterExtensionRef,
+							ExtensionRef: &gatewayapi.LocalObjectReference{
+								Group: gatewayapi.Group(kongv1.GroupVersion.Group),
+								Kind:  gatewayapi.Kind("KongPlugin"),
+								Name:  "key-auth",
+							},
+						},
+						{
+							Type: gatewayapi.HTTPRouteFilterExtensionRef,
+							ExtensionRef: &gatewayapi.LocalObjectReference{
+								Group: gatewayapi.Group("example.com"),
+								Kind:  gatewayapi.Kind("UnknownFilter"),
+								Name:  "unknown",
+							},
+						},
+					},
+					BackendRefs: []gatewayapi.HTTPBackendRef{
+						builder.NewHTTPBackendRef("fake-service").WithPort(80).Build(),
+					},
+				},
+				{
+					Matches: []gatewayapi.HTTPRouteMatch{
+						builder.NewHTTPRouteMatch().WithPathPrefix("/without-plugins").Build(),
+					},
+					BackendRefs: []gatewayapi.HTTPBackendRef{
+						builder.NewHTTPBackendRef("fake-service").WithPort(80).Build(),
+					},
+				},
+			},
+		},
+	}
+
+	for _, expressionRoutes := range []bool{false, true} {
+		expressionRoutes := expressionRoutes
+		t.Run(fmt.Sprintf("expression routes: %t", expressionRoutes), func(t *testing.T) {
+			fakestore, err := store.NewFakeStore(store.FakeObjects{})
+			require.NoError(t, err)
+			p := mustNewParser(t, fakestore)
+			p.featureFlags.ExpressionRoutes = expressionRoutes
+
+			result := newIngressRules()
+			if expressionRoutes {
+				p.ingressRulesFromHTTPRoutesUsingExpressionRoutes([]*gatewayapi.HTTPRoute{httpRoute}, &result)
+			} else {
+				require.NoError(t, p.ingressRulesFromHTTPRoute(&result, httpRoute))
+			}
+
+			var routes []kongstate.Route
+			for _, service := range result.ServiceNameToServices {
+				routes = append(routes, service.Routes...)
+			}
+			require.Len(t, routes, 2)
+
+			// The plugin should be attached only to the route generated for the rule with the filter.
+			for _, route := range routes {
+				var paths []string
+				if expressionRoutes {
+					paths = append(paths, *route.Expression)
+				} else {
+					paths = lo.Map(route.Paths, func(p *string, _ int) string { return *p })
+				}
+				if lo.SomeBy(paths, func(p string) bool { return strings.Contains(p, "/with-plugins") }) {
+					require.Equal(t, []string{"key-auth"}, route.ExtensionRefPlugins)
+				} else {
+					require.Empty(t, route.ExtensionRefPlugins)
+				}
+			}
+		})
+	}
+}
+
+func TestIngressRulesFromHTTPRoutesWithQueryParamMatchesOnTraditionalRouter(t *testing.T) {
+	httpRoute := &gatewayapi.HTTPRoute{
+		TypeMeta: metav1.TypeMeta{Kind: "HTTPRoute", APIVersion: gatewayv1beta1.GroupVersion.String()},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "httproute-with-query-params",
+			Namespace: corev1.NamespaceDefault,
+		},
+		Spec: gatewayapi.HTTPRouteSpec{
+			CommonRouteSpec: commonRouteSpecMock("fake-gateway"),
+			Rules: []gatewayapi.HTTPRouteRule{
+				{
+					Matches: []gatewayapi.HTTPRouteMatch{
+						builder.NewHTTPRouteMatch().WithPathPrefix("/with-query").WithQueryParam("foo", "bar").Build(),
+					},
+					BackendRefs: []gatewayapi.HTTPBackendRef{
+						builder.NewHTTPBackendRef("fake-service").WithPort(80).Build(),
+					},
+				},
+				{
+					Matches: []gatewayapi.HTTPRouteMatch{
+						builder.NewHTTPRouteMatch().WithPathPrefix("/without-query").Build(),
+					},
+					BackendRefs: []gatewayapi.HTTPBackendRef{
+						builder.NewHTTPBackendRef("fake-service").WithPort(80).Build(),
+					},
+				},
+			},
+		},
+	}
+
+	fakestore, err := store.NewFakeStore(store.FakeObjects{})
+	require.NoError(t, err)
+	p := mustNewParser(t, fakestore)
+	require.NoError(t, validateHTTPRoute(httpRoute, p.featureFlags))
+
+	result := newIngressRules()
+	require.NoError(t, p.ingressRulesFromHTTPRoute(&result, httpRoute))
+
+	var routes []kongstate.Route
+	for _, service := range result.ServiceNameToServices {
+		routes = append(routes, service.Routes...)
+	}
+	require.Len(t, routes, 2)
+
+	// The guard plugin should be attached only to the route generated for the match with query params.
+	for _, route := range routes {
+		paths := lo.Map(route.Paths, func(p *string, _ int) string { return *p })
+		if lo.SomeBy(paths, func(p string) bool { return strings.Contains(p, "/with-query") }) {
+			require.Len(t, route.Plugins, 1)
+			require.Equal(t, translators.QueryParamsGuardPluginName, *route.Plugins[0].Name)
+			require.Contains(t, route.Plugins[0].Config["access"].([]string)[0], `if arg("foo") ~= "bar" then`)
+		} else {
+			require.Empty(t, route.Plugins)
+		}
+	}
+}
+
+func TestIngressRulesFromHTTPRoutesWithURLRewriteReplacePrefixMatchRegexPriority(t *testing.T) {
+	// Rules have different backends for their matches not to be consolidated into a single route.
+	rule := func(backend string, match gatewayapi.HTTPRouteMatch, filters ...gatewayapi.HTTPRouteFilter) gatewayapi.HTTPRouteRule {
+		return gatewayapi.HTTPRouteRule{
+			Matches: []gatewayapi.HTTPRouteMatch{match},
+			Filters: filters,
+			BackendRefs: []gatewayapi.HTTPBackendRef{
+				builder.NewHTTPBackendRef(backend).WithPort(80).Build(),
+			},
+		}
+	}
+	httpRoute := &gatewayapi.HTTPRoute{
+		TypeMeta: metav1.TypeMeta{Kind: "HTTPRoute", APIVersion: gatewayv1beta1.GroupVersion.String()},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "httproute-with-url-rewrite",
+			Namespace: corev1.NamespaceDefault,
+		},
+		Spec: gatewayapi.HTTPRouteSpec{
+			CommonRouteSpec: commonRouteSpecMock("fake-gateway"),
+			Rules: []gatewayapi.HTTPRouteRule{
+				rule(
+					"rewrite",
+					builder.NewHTTPRouteMatch().WithPathPrefix("/api").Build(),
+					builder.NewHTTPRouteURLRewriteFilter().WithURLRewritePrefixMatch("/").Build(),
+				),
+				rule("longer-prefix", builder.NewHTTPRouteMatch().WithPathPrefix("/api/v1").Build()),
+				rule("shorter-prefix", builder.NewHTTPRouteMatch().WithPathPrefix("/a").Build()),
+				rule("exact", builder.NewHTTPRouteMatch().WithPathExact("/api").Build()),
+			},
+		},
+	}
+
+	fakestore, err := store.NewFakeStore(store.FakeObjects{})
+	require.NoError(t, err)
+	p := mustNewParser(t, fakestore)
+	require.NoError(t, validateHTTPRoute(httpRoute, p.featureFlags))
+
+	result := newIngressRules()
+	require.NoError(t, p.ingressRulesFromHTTPRoute(&result, httpRoute))
+
+	regexPriorities := make(map[string]int)
+	for _, service := range result.ServiceNameToServices {
+		for _, route := range service.Routes {
+			paths := strings.Join(lo.Map(route.Paths, func(p *string, _ int) string { return *p }), " ")
+			require.NotNil(t, route.RegexPriority, "route with paths %s should have regex_priority set", paths)
+			regexPriorities[paths] = *route.RegexPriority
+		}
+	}
+	require.Equal(t, map[string]int{
+		"~/api$ ~/api(/.*)":  8,
+		"~/api/v1$ /api/v1/": 14,
+		"~/a$ /a/":           4,
+		"~/api$":             9,
+	}, regexPriorities)
+
+	t.Log("the route capturing the remainder of the path has only regex paths, so its regex_priority has to be " +
+		"lower than the one of the longer prefix and higher than the one of the shorter prefix")
+	require.Less(t, regexPriorities["~/api$ ~/api(/.*)"], regexPriorities["~/api/v1$ /api/v1/"])
+	require.Greater(t, regexPriorities["~/api$ ~/api(/.*)"], regexPriorities["~/a$ /a/"])
+	require.Greater(t, regexPriorities["~/api$"], regexPriorities["~/api$ ~/api(/.*)"],
+		"exact match should take precedence over the prefix match of the same path")
+}
+
+func TestIngressRulesFromHTTPRoutesWithTimeouts(t *testing.T) {
+	httpRoute := &gatewayapi.HTTPRoute{
+		TypeMeta: metav1.TypeMeta{Kind: "HTTPRoute", APIVersion: gatewayv1beta1.GroupVersion.String()},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "httproute-with-timeouts",
+			Namespace: corev1.NamespaceDefault,
+		},
+		Spec: gatewayapi.HTTPRouteSpec{
+			CommonRouteSpec: commonRouteSpecMock("fake-gateway"),
+			Rules: []gatewayapi.HTTPRouteRule{
+				{
+					Matches: []gatewayapi.HTTPRouteMatch{
+						builder.NewHTTPRouteMatch().WithPathPrefix("/slow").Build(),
+					},
+					BackendRefs: []gatewayapi.HTTPBackendRef{
+						builder.NewHTTPBackendRef("fake-service").WithPort(80).Build(),
+					},
+					Timeouts: &gatewayapi.HTTPRouteTimeouts{
+						Request: lo.ToPtr(gatewayapi.Duration("2m")),
+					},
+				},
+				{
+					Matches: []gatewayapi.HTTPRouteMatch{
+						builder.NewHTTPRouteMatch().WithPathPrefix("/fast").Build(),
+					},
+					BackendRefs: []gatewayapi.HTTPBackendRef{
+						builder.NewHTTPBackendRef("fake-service").WithPort(80).Build(),
+					},
+					Timeouts: &gatewayapi.HTTPRouteTimeouts{
+						Request:        lo.ToPtr(gatewayapi.Duration("10s")),
+						BackendRequest: lo.ToPtr(gatewayapi.Duration("2s500ms")),
+					},
+				},
+				{
+					Matches: []gatewayapi.HTTPRouteMatch{
+						builder.NewHTTPRouteMatch().WithPathPrefix("/default").Build(),
+					},
+					BackendRefs: []gatewayapi.HTTPBackendRef{
+						builder.NewHTTPBackendRef("fake-service").WithPort(80).Build(),
+					},
+				},
+			},
+		},
+	}
+
+	testCases := []struct {
+		expressionRoutes bool
+		expectedServices map[string]int
+	}{
+		{
+			expectedServices: map[string]int{
+				"httproute.default.httproute-with-timeouts.0": 120000,
+				"httproute.default.httproute-with-timeouts.1": 2500,
+				"httproute.default.httproute-with-timeouts.2": DefaultServiceTimeout,
+			},
+		},
+		{
+			expressionRoutes: true,
+			expectedServices: map[string]int{
+				"httproute.default.httproute-with-timeouts._.0": 120000,
+				"httproute.default.httproute-with-timeouts._.1": 2500,
+				"httproute.default.httproute-with-timeouts._.2": DefaultServiceTimeout,
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(fmt.Sprintf("expression routes: %t", tc.expressionRoutes), func(t *testing.T) {
+			fakestore, err := store.NewFakeStore(store.FakeObjects{})
+			require.NoError(t, err)
+			p := mustNewParser(t, fakestore)
+			p.featureFlags.ExpressionRoutes = tc.expressionRoutes
+
+			result := newIngressRules()
+			if tc.expressionRoutes {
+				p.ingressRulesFromHTTPRoutesUsingExpressionRoutes([]*gatewayapi.HTTPRoute{httpRoute}, &result)
+			} else {
+				require.NoError(t, p.ingressRulesFromHTTPRoute(&result, httpRoute))
+			}
+
+			require.Len(t, result.ServiceNameToServices, len(tc.expectedServices))
+			for serviceName, expectedTimeout := range tc.expectedServices {
+				service, ok := result.ServiceNameToServices[serviceName]
+				require.True(t, ok, "service %s not found", serviceName)
+				require.Equal(t, expectedTimeout, *service.ConnectTimeout)
+				require.Equal(t, expectedTimeout, *service.ReadTimeout)
+				require.Equal(t, expectedTimeout, *service.WriteTimeout)
+				require.Equal(t, expectedTimeout != DefaultServiceTimeout, service.TimeoutsFromParent)
+				require.Len(t, service.Routes, 1)
+			}
+		})
+	}
+
+	t.Run("invalid timeout", func(t *testing.T) {
+		fakestore, err := store.NewFakeStore(store.FakeObjects{})
+		require.NoError(t, err)
+		p := mustNewParser(t, fakestore)
+
+		invalidHTTPRoute := httpRoute.DeepCopy()
+		invalidHTTPRoute.Spec.Rules[0].Timeouts.Request = lo.ToPtr(gatewayapi.Duration("1d"))
+		result := newIngressRules()
+		require.ErrorContains(t, p.ingressRulesFromHTTPRoute(&result, invalidHTTPRoute), `invalid request timeout "1d"`)
+	})
+}
+
+func TestIngressRulesFromHTTPRoutesWithSessionPersistence(t *testing.T) {
+	rule := func(path string) gatewayapi.HTTPRouteRule {
+		return gatewayapi.HTTPRouteRule{
+			Matches: []gatewayapi.HTTPRouteMatch{
+				builder.NewHTTPRouteMatch().WithPathPrefix(path).Build(),
+			},
+			BackendRefs: []gatewayapi.HTTPBackendRef{
+				builder.NewHTTPBackendRef("fake-service").WithPort(80).Build(),
+			},
+		}
+	}
+	httpRoute := &gatewayapi.HTTPRoute{
+		TypeMeta: metav1.TypeMeta{Kind: "HTTPRoute", APIVersion: gatewayv1beta1.GroupVersion.String()},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "httproute-with-session-persistence",
+			Namespace: corev1.NamespaceDefault,
+			Annotations: map[string]string{
+				"konghq.com/session-persistence-cookie": "session",
+				"konghq.com/session-persistence-rules":  "0,2",
+			},
+		},
+		Spec: gatewayapi.HTTPRouteSpec{
+			CommonRouteSpec: commonRouteSpecMock("fake-gateway"),
+			Rules:           []gatewayapi.HTTPRouteRule{rule("/cart"), rule("/static"), rule("/checkout")},
+		},
+	}
+	cookie := &kongv1beta1.KongUpstreamHash{Cookie: lo.ToPtr("session")}
+
+	testCases := []struct {
+		expressionRoutes bool
+		expectedServices map[string]*kongv1beta1.KongUpstreamHash
+	}{
+		{
+			// rules with the same backends and session persistence share a Kong service.
+			expectedServices: map[string]*kongv1beta1.KongUpstreamHash{
+				"httproute.default.httproute-with-session-persistence.0": cookie,
+				"httproute.default.httproute-with-session-persistence.1": nil,
+			},
+		},
+		{
+			expressionRoutes: true,
+			expectedServices: map[string]*kongv1beta1.KongUpstreamHash{
+				"httproute.default.httproute-with-session-persistence._.0": cookie,
+				"httproute.default.httproute-with-session-persistence._.1": nil,
+				"httproute.default.httproute-with-session-persistence._.2": cookie,
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(fmt.Sprintf("expression routes: %t", tc.expressionRoutes), func(t *testing.T) {
+			fakestore, err := store.NewFakeStore(store.FakeObjects{})
+			require.NoError(t, err)
+			p := mustNewParser(t, fakestore)
+			p.featureFlags.ExpressionRoutes = tc.expressionRoutes
+
+			result := newIngressRules()
+			if tc.expressionRoutes {
+				p.ingressRulesFromHTTPRoutesUsingExpressionRoutes([]*gatewayapi.HTTPRoute{httpRoute}, &result)
+			} else {
+				require.NoError(t, p.ingressRulesFromHTTPRoute(&result, httpRoute))
+			}
+
+			require.Len(t, result.ServiceNameToServices, len(tc.expectedServices))
+			for serviceName, expectedSessionPersistence := range tc.expectedServices {
+				service, ok := result.ServiceNameToServices[serviceName]
+				require.True(t, ok, "service %s not found", serviceName)
+				require.Equal(t, expectedSessionPersistence, service.SessionPersistence)
+			}
+		})
+	}
+}
+
+func TestIngressRulesFromHTTPRoutesWithRequestMirror(t *testing.T) {
+	newHTTPRouteWithMirror := func(mirrorNamespace *gatewayapi.Namespace) *gatewayapi.HTTPRoute {
+		return &gatewayapi.HTTPRoute{
+			TypeMeta: metav1.TypeMeta{Kind: "HTTPRoute", APIVersion: gatewayv1beta1.GroupVersion.String()},
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "mirrored-httproute",
+				Namespace: corev1.NamespaceDefault,
+			},
+			Spec: gatewayapi.HTTPRouteSpec{
+				CommonRouteSpec: commonRouteSpecMock("fake-gateway"),
+				Rules: []gatewayapi.HTTPRouteRule{{
+					Matches: []gatewayapi.HTTPRouteMatch{
+						builder.NewHTTPRouteMatch().WithPathPrefix("/httpbin").Build(),
+					},
+					Filters: []gatewayapi.HTTPRouteFilter{
+						{
+							Type: gatewayapi.HTTPRouteFilterRequestMirror,
+							RequestMirror: &gatewayapi.HTTPRequestMirrorFilter{
+								BackendRef: gatewayapi.BackendObjectReference{
+									Name:      "mirror-service",
+									Group:     lo.ToPtr(gatewayapi.Group("")),
+									Kind:      lo.ToPtr(gatewayapi.Kind("Service")),
+									Namespace: mirrorNamespace,
+									Port:      lo.ToPtr(gatewayapi.PortNumber(8080)),
+								},
+							},
+						},
+					},
+					BackendRefs: []gatewayapi.HTTPBackendRef{
+						builder.NewHTTPBackendRef("fake-service").WithPort(80).Build(),
+					},
+				}},
+			},
+		}
+	}
+
+	testCases := []struct {
+		name                       string
+		httpRoute                  *gatewayapi.HTTPRoute
+		requestMirroring           bool
+		expressionRoutes           bool
+		expectedMirrorService      string
+		expectedMirrorServiceCount int
+	}{
+		{
+			name:                       "mirror to a backend in the same namespace",
+			httpRoute:                  newHTTPRouteWithMirror(nil),
+			requestMirroring:           true,
+			expectedMirrorService:      "httproute.default.mirrored-httproute.mirror.default.mirror-service.8080",
+			expectedMirrorServiceCount: 1,
+		},
+		{
+			name:                       "mirror to a backend in the same namespace with expression routes",
+			httpRoute:                  newHTTPRouteWithMirror(nil),
+			requestMirroring:           true,
+			expressionRoutes:           true,
+			expectedMirrorService:      "httproute.default.mirrored-httproute.mirror.default.mirror-service.8080",
+			expectedMirrorServiceCount: 1,
+		},
+		{
+			name:             "mirror to a backend in another namespace not permitted by a ReferenceGrant",
+			httpRoute:        newHTTPRouteWithMirror(lo.ToPtr(gatewayapi.Namespace("other"))),
+			requestMirroring: true,
+		},
+		{
+			name:      "mirror ignored when the feature is disabled",
+			httpRoute: newHTTPRouteWithMirror(nil),
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			fakestore, err := store.NewFakeStore(store.FakeObjects{})
+			require.NoError(t, err)
+			p := mustNewParser(t, fakestore)
+			p.featureFlags.RequestMirroring = tc.requestMirroring
+			p.featureFlags.ExpressionRoutes = tc.expressionRoutes
+
+			result := newIngressRules()
+			if tc.expressionRoutes {
+				p.ingressRulesFromHTTPRoutesUsingExpressionRoutes([]*gatewayapi.HTTPRoute{tc.httpRoute}, &result)
+			} else {
+				require.NoError(t, p.ingressRulesFromHTTPRoute(&result, tc.httpRoute))
+			}
+
+			// One service for the rule's backends and one for each mirrored backend.
+			require.Len(t, result.ServiceNameToServices, 1+tc.expectedMirrorServiceCount)
+
+			var routes []kongstate.Route
+			for name, service := range result.ServiceNameToServices {
+				if name == tc.expectedMirrorService {
+					require.Empty(t, service.Routes, "mirror service should have no routes")
+					require.Equal(t, []kongstate.ServiceBackend{
+						builder.NewKongstateServiceBackend("mirror-service").WithPortNumber(8080).Build(),
+					}, service.Backends)
+					continue
+				}
+				routes = append(routes, service.Routes...)
+			}
+			require.Len(t, routes, 1)
+
+			mirrorPlugin, found := lo.Find(routes[0].Plugins, func(p kong.Plugin) bool {
+				return *p.Name == translators.RequestMirrorPluginName
+			})
+			if tc.expectedMirrorServiceCount == 0 {
+				require.False(t, found, "route should have no mirror plugin")
+				return
+			}
+			require.True(t, found, "route should have the mirror plugin")
+			expectedPlugin := translators.GenerateRequestMirrorPlugin([]string{tc.expectedMirrorService}, nil)
+			require.Equal(t, expectedPlugin.Config, mirrorPlugin.Config)
+		})
+	}
+}
+
+func TestIngressRulesFromHTTPRoutesWithRequestMirrorAndQueryParams(t *testing.T) {
+	httpRoute := &gatewayapi.HTTPRoute{
+		TypeMeta: metav1.TypeMeta{Kind: "HTTPRoute", APIVersion: gatewayv1beta1.GroupVersion.String()},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "mirrored-httproute",
+			Namespace: corev1.NamespaceDefault,
+		},
+		Spec: gatewayapi.HTTPRouteSpec{
+			CommonRouteSpec: commonRouteSpecMock("fake-gateway"),
+			Rules: []gatewayapi.HTTPRouteRule{{
+				Matches: []gatewayapi.HTTPRouteMatch{
+					builder.NewHTTPRouteMatch().WithPathPrefix("/httpbin").WithQueryParam("foo", "bar").Build(),
+				},
+				Filters: []gatewayapi.HTTPRouteFilter{
+					{
+						Type: gatewayapi.HTTPRouteFilterRequestMirror,
+						RequestMirror: &gatewayapi.HTTPRequestMirrorFilter{
+							BackendRef: gatewayapi.BackendObjectReference{
+								Name: "mirror-service",
+								Kind: lo.ToPtr(gatewayapi.Kind("Service")),
+								Port: lo.ToPtr(gatewayapi.PortNumber(8080)),
+							},
+						},
+					},
+				},
+				BackendRefs: []gatewayapi.HTTPBackendRef{
+					builder.NewHTTPBackendRef("fake-service").WithPort(80).Build(),
+				},
+			}},
+		},
+	}
+
+	fakestore, err := store.NewFakeStore(store.FakeObjects{})
+	require.NoError(t, err)
+	p := mustNewParser(t, fakestore)
+	p.featureFlags.RequestMirroring = true
+	p.featureFlags.QueryParamsGuardPlugin = true
+
+	result := newIngressRules()
+	require.NoError(t, p.ingressRulesFromHTTPRoute(&result, httpRoute))
+
+	var routes []kongstate.Route
+	for _, service := range result.ServiceNameToServices {
+		routes = append(routes, service.Routes...)
+	}
+	require.Len(t, routes, 1)
+
+	// Both the query params guard and mirroring are implemented with pre-function, so they share the plugin
+	// and requests rejected by the guard are not mirrored.
+	require.Len(t, routes[0].Plugins, 1)
+	access, ok := routes[0].Plugins[0].Config["access"].([]string)
+	require.True(t, ok)
+	require.Len(t, access, 1)
+	guardPlugin := translators.GenerateQueryParamsGuardPlugin(httpRoute.Spec.Rules[0].Matches[0].QueryParams, nil)
+	mirrorPlugin := translators.GenerateRequestMirrorPlugin(
+		[]string{"httproute.default.mirrored-httproute.mirror.default.mirror-service.8080"}, nil,
+	)
+	require.Equal(t,
+		guardPlugin.Config["access"].([]string)[0]+"do\n"+mirrorPlugin.Config["access"].([]string)[0]+"end\n",
+		access[0],
+	)
+}
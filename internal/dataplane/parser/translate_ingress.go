@@ -4,6 +4,7 @@ import (
 	"errors"
 	"fmt"
 	"sort"
+	"strconv"
 
 	"github.com/kong/go-kong/kong"
 	netv1 "k8s.io/api/networking/v1"
@@ -44,12 +45,7 @@ func (p *Parser) ingressRulesFromIngressV1() ingressRules {
 	}
 
 	// Translate Ingress objects into Kong Services.
-	servicesCache := IngressesV1ToKongServices(
-		p.featureFlags,
-		ingressList,
-		icp,
-		p.parsedObjectsCollector,
-	)
+	servicesCache := p.ingressesV1ToKongServices(ingressList, icp)
 	for i := range servicesCache {
 		service := servicesCache[i]
 		if err := translators.MaybeRewriteURI(&service, p.featureFlags.RewriteURIs); err != nil {
@@ -88,6 +84,39 @@ func IngressesV1ToKongServices(
 	}, parsedObjectsCollector)
 }
 
+// ingressesV1ToKongServices translates IngressV1 objects into Kong Services like IngressesV1ToKongServices does,
+// but translates each of the Ingresses separately, reusing the results cached for the ones that haven't changed
+// since the previous translation. Services shared by multiple Ingresses get the routes of all of them.
+func (p *Parser) ingressesV1ToKongServices(
+	ingresses []*netv1.Ingress,
+	icp kongv1alpha1.IngressClassParametersSpec,
+) KongServicesCache {
+	// IngressClassParameters are the only object other than an Ingress its translation depends on.
+	dependenciesVersion := strconv.FormatBool(icp.EnableLegacyRegexDetection)
+
+	result := make(KongServicesCache)
+	for _, ingress := range ingresses {
+		services, ok := p.ingressTranslationCache.get(ingress, dependenciesVersion)
+		if ok {
+			p.registerSuccessfullyParsedObject(ingress)
+		} else {
+			services = IngressesV1ToKongServices(p.featureFlags, []*netv1.Ingress{ingress}, icp, p.parsedObjectsCollector)
+			p.ingressTranslationCache.set(ingress, dependenciesVersion, services)
+		}
+
+		// Services are copied, as they're modified in further translation steps while the cached ones must not be.
+		for name, cached := range services {
+			service := cached.DeepCopy()
+			if existing, ok := result[name]; ok {
+				existing.Routes = append(existing.Routes, service.Routes...)
+				service = existing
+			}
+			result[name] = service
+		}
+	}
+	return result
+}
+
 // getDefaultBackendService picks the oldest Ingress with a DefaultBackend defined and returns a Kong Service for it.
 func getDefaultBackendService(allDefaultBackends []netv1.Ingress, expressionRoutes bool) (kongstate.Service, bool) {
 	sort.SliceStable(allDefaultBackends, func(i, j int) bool {
@@ -9,6 +9,7 @@ import (
 
 	"github.com/kong/kubernetes-ingress-controller/v2/internal/dataplane/kongstate"
 	"github.com/kong/kubernetes-ingress-controller/v2/internal/util"
+	kongv1beta1 "github.com/kong/kubernetes-ingress-controller/v2/pkg/apis/configuration/v1beta1"
 )
 
 func (p *Parser) ingressRulesFromTCPIngressV1beta1() ingressRules {
@@ -30,22 +31,7 @@ func (p *Parser) ingressRulesFromTCPIngressV1beta1() ingressRules {
 
 		var objectSuccessfullyParsed bool
 		for i, rule := range ingress.Spec.Rules {
-			r := kongstate.Route{
-				Ingress: util.FromK8sObject(ingress),
-				Route: kong.Route{
-					Name:      kong.String(ingress.Namespace + "." + ingress.Name + "." + strconv.Itoa(i)),
-					Protocols: kong.StringSlice("tcp", "tls"),
-					Destinations: []*kong.CIDRPort{
-						{
-							Port: kong.Int(rule.Port),
-						},
-					},
-					Tags: util.GenerateTagsForObject(ingress),
-				},
-			}
-			if host := rule.Host; host != "" {
-				r.SNIs = kong.StringSlice(host)
-			}
+			r := tcpIngressRuleToKongRoute(ingress, i, rule)
 
 			serviceName := fmt.Sprintf("%s.%s.%d", ingress.Namespace, rule.Backend.ServiceName, rule.Backend.ServicePort)
 			service, ok := result.ServiceNameToServices[serviceName]
@@ -105,15 +91,7 @@ func (p *Parser) ingressRulesFromUDPIngressV1beta1() ingressRules {
 		var objectSuccessfullyParsed bool
 		for i, rule := range ingress.Spec.Rules {
 			// generate the kong Route based on the listen port
-			route := kongstate.Route{
-				Ingress: util.FromK8sObject(ingress),
-				Route: kong.Route{
-					Name:         kong.String(ingress.Namespace + "." + ingress.Name + "." + strconv.Itoa(i) + ".udp"),
-					Protocols:    kong.StringSlice("udp"),
-					Destinations: []*kong.CIDRPort{{Port: kong.Int(rule.Port)}},
-					Tags:         util.GenerateTagsForObject(ingress),
-				},
-			}
+			route := udpIngressRuleToKongRoute(ingress, i, rule)
 
 			// generate the kong Service backend for the UDPIngress rules
 			host := fmt.Sprintf("%s.%s.%d.svc", rule.Backend.ServiceName, ingress.Namespace, rule.Backend.ServicePort)
@@ -152,3 +130,61 @@ func (p *Parser) ingressRulesFromUDPIngressV1beta1() ingressRules {
 
 	return result
 }
+
+// GenerateKongRoutesFromTCPIngress generates Kong routes from all rules of a TCPIngress.
+// It's used to validate TCPIngresses against Kong Gateway before they're translated.
+func GenerateKongRoutesFromTCPIngress(ingress *kongv1beta1.TCPIngress, expressionRoutes bool) []kongstate.Route {
+	routes := make([]kongstate.Route, 0, len(ingress.Spec.Rules))
+	for i, rule := range ingress.Spec.Rules {
+		routes = append(routes, tcpIngressRuleToKongRoute(ingress, i, rule))
+	}
+	if expressionRoutes {
+		applyExpressionToL4KongRoutes(routes)
+	}
+	return routes
+}
+
+// GenerateKongRoutesFromUDPIngress generates Kong routes from all rules of a UDPIngress.
+// It's used to validate UDPIngresses against Kong Gateway before they're translated.
+func GenerateKongRoutesFromUDPIngress(ingress *kongv1beta1.UDPIngress, expressionRoutes bool) []kongstate.Route {
+	routes := make([]kongstate.Route, 0, len(ingress.Spec.Rules))
+	for i, rule := range ingress.Spec.Rules {
+		routes = append(routes, udpIngressRuleToKongRoute(ingress, i, rule))
+	}
+	if expressionRoutes {
+		applyExpressionToL4KongRoutes(routes)
+	}
+	return routes
+}
+
+func tcpIngressRuleToKongRoute(ingress *kongv1beta1.TCPIngress, ruleNumber int, rule kongv1beta1.IngressRule) kongstate.Route {
+	r := kongstate.Route{
+		Ingress: util.FromK8sObject(ingress),
+		Route: kong.Route{
+			Name:      kong.String(ingress.Namespace + "." + ingress.Name + "." + strconv.Itoa(ruleNumber)),
+			Protocols: kong.StringSlice("tcp", "tls"),
+			Destinations: []*kong.CIDRPort{
+				{
+					Port: kong.Int(rule.Port),
+				},
+			},
+			Tags: util.GenerateTagsForObject(ingress),
+		},
+	}
+	if host := rule.Host; host != "" {
+		r.SNIs = kong.StringSlice(host)
+	}
+	return r
+}
+
+func udpIngressRuleToKongRoute(ingress *kongv1beta1.UDPIngress, ruleNumber int, rule kongv1beta1.UDPIngressRule) kongstate.Route {
+	return kongstate.Route{
+		Ingress: util.FromK8sObject(ingress),
+		Route: kong.Route{
+			Name:         kong.String(ingress.Namespace + "." + ingress.Name + "." + strconv.Itoa(ruleNumber) + ".udp"),
+			Protocols:    kong.StringSlice("udp"),
+			Destinations: []*kong.CIDRPort{{Port: kong.Int(rule.Port)}},
+			Tags:         util.GenerateTagsForObject(ingress),
+		},
+	}
+}
@@ -0,0 +1,32 @@
+package parser
+
+import (
+	"fmt"
+
+	"github.com/kong/kubernetes-ingress-controller/v2/internal/dataplane/kongstate"
+	"github.com/kong/kubernetes-ingress-controller/v2/internal/dataplane/parser/translators"
+)
+
+// applySessionPersistence configures Kong upstreams with session persistence requested by the parents
+// (e.g. HTTPRoute rules) of their Kong services. It's applied after the overrides (e.g. KongIngresses), so
+// upstreams whose explicitly configured load balancing conflicts with session persistence keep it, and the
+// conflict is reported as a translation failure of the parent.
+func (p *Parser) applySessionPersistence(upstreams []kongstate.Upstream) {
+	for i := range upstreams {
+		upstream := &upstreams[i]
+		if upstream.Service.SessionPersistence == nil {
+			continue
+		}
+		hash := *upstream.Service.SessionPersistence
+		if err := translators.ValidateSessionPersistenceForUpstream(upstream.Upstream, hash); err != nil {
+			if parent := upstream.Service.Parent; parent != nil {
+				p.registerTranslationFailure(
+					fmt.Sprintf("session persistence can't be applied to Kong upstream %s: %s", *upstream.Name, err),
+					parent,
+				)
+			}
+			continue
+		}
+		translators.ApplySessionPersistence(&upstream.Upstream, hash)
+	}
+}
@@ -0,0 +1,118 @@
+package parser
+
+import (
+	"testing"
+
+	"github.com/kong/go-kong/kong"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	gatewayv1beta1 "sigs.k8s.io/gateway-api/apis/v1beta1"
+
+	"github.com/kong/kubernetes-ingress-controller/v2/internal/dataplane/kongstate"
+	"github.com/kong/kubernetes-ingress-controller/v2/internal/gatewayapi"
+	"github.com/kong/kubernetes-ingress-controller/v2/internal/store"
+	kongv1beta1 "github.com/kong/kubernetes-ingress-controller/v2/pkg/apis/configuration/v1beta1"
+)
+
+func TestApplySessionPersistence(t *testing.T) {
+	conflictingParent := &gatewayapi.HTTPRoute{
+		TypeMeta:   metav1.TypeMeta{Kind: "HTTPRoute", APIVersion: gatewayv1beta1.GroupVersion.String()},
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "conflicting"},
+	}
+	upstreams := []kongstate.Upstream{
+		{
+			Upstream: kong.Upstream{Name: kong.String("without-session-persistence")},
+		},
+		{
+			Upstream: kong.Upstream{Name: kong.String("cookie"), Slots: kong.Int(100)},
+			Service: kongstate.Service{
+				SessionPersistence: &kongv1beta1.KongUpstreamHash{
+					Cookie:     kong.String("session"),
+					CookiePath: kong.String("/app"),
+				},
+			},
+		},
+		{
+			Upstream: kong.Upstream{Name: kong.String("header")},
+			Service: kongstate.Service{
+				SessionPersistence: &kongv1beta1.KongUpstreamHash{Header: kong.String("x-session")},
+			},
+		},
+		{
+			// explicit settings matching the ones of session persistence don't conflict.
+			Upstream: kong.Upstream{
+				Name:      kong.String("explicit-consistent-hashing"),
+				Algorithm: kong.String("consistent-hashing"),
+				HashOn:    kong.String("none"),
+			},
+			Service: kongstate.Service{
+				SessionPersistence: &kongv1beta1.KongUpstreamHash{Header: kong.String("x-session")},
+			},
+		},
+		{
+			Upstream: kong.Upstream{
+				Name:      kong.String("explicit-round-robin"),
+				Algorithm: kong.String("round-robin"),
+			},
+			Service: kongstate.Service{
+				SessionPersistence: &kongv1beta1.KongUpstreamHash{Header: kong.String("x-session")},
+				Parent:             conflictingParent,
+			},
+		},
+		{
+			Upstream: kong.Upstream{
+				Name:      kong.String("explicit-hash-on-ip"),
+				Algorithm: kong.String("consistent-hashing"),
+				HashOn:    kong.String("ip"),
+			},
+			Service: kongstate.Service{
+				SessionPersistence: &kongv1beta1.KongUpstreamHash{Cookie: kong.String("session")},
+				Parent:             conflictingParent,
+			},
+		},
+	}
+
+	fakestore, err := store.NewFakeStore(store.FakeObjects{})
+	require.NoError(t, err)
+	p := mustNewParser(t, fakestore)
+	p.applySessionPersistence(upstreams)
+
+	require.Equal(t, kong.Upstream{Name: kong.String("without-session-persistence")}, upstreams[0].Upstream)
+	require.Equal(t, kong.Upstream{
+		Name:             kong.String("cookie"),
+		Slots:            kong.Int(100),
+		Algorithm:        kong.String("consistent-hashing"),
+		HashOn:           kong.String("cookie"),
+		HashOnCookie:     kong.String("session"),
+		HashOnCookiePath: kong.String("/app"),
+	}, upstreams[1].Upstream)
+	require.Equal(t, kong.Upstream{
+		Name:         kong.String("header"),
+		Algorithm:    kong.String("consistent-hashing"),
+		HashOn:       kong.String("header"),
+		HashOnHeader: kong.String("x-session"),
+	}, upstreams[2].Upstream)
+	require.Equal(t, kong.Upstream{
+		Name:         kong.String("explicit-consistent-hashing"),
+		Algorithm:    kong.String("consistent-hashing"),
+		HashOn:       kong.String("header"),
+		HashOnHeader: kong.String("x-session"),
+	}, upstreams[3].Upstream)
+
+	// explicit settings conflicting with session persistence are kept and the conflict is reported.
+	require.Equal(t, kong.Upstream{
+		Name:      kong.String("explicit-round-robin"),
+		Algorithm: kong.String("round-robin"),
+	}, upstreams[4].Upstream)
+	require.Equal(t, kong.Upstream{
+		Name:      kong.String("explicit-hash-on-ip"),
+		Algorithm: kong.String("consistent-hashing"),
+		HashOn:    kong.String("ip"),
+	}, upstreams[5].Upstream)
+	translationFailures := p.popTranslationFailures()
+	require.Len(t, translationFailures, 2)
+	for _, failure := range translationFailures {
+		require.Contains(t, failure.Message(), "session persistence conflicts with the load balancing explicitly configured")
+		require.Equal(t, conflictingParent, failure.CausingObjects()[0])
+	}
+}
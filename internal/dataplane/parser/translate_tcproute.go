@@ -3,6 +3,7 @@ package parser
 import (
 	"fmt"
 
+	"github.com/kong/kubernetes-ingress-controller/v2/internal/dataplane/kongstate"
 	"github.com/kong/kubernetes-ingress-controller/v2/internal/dataplane/parser/translators"
 	"github.com/kong/kubernetes-ingress-controller/v2/internal/gatewayapi"
 )
@@ -51,24 +52,13 @@ func (p *Parser) ingressRulesFromTCPRoute(result *ingressRules, tcproute *gatewa
 	// first we grab the spec and gather some metdata about the object
 	spec := tcproute.Spec
 
-	// validation for TCPRoutes will happen at a higher layer, but in spite of that we run
-	// validation at this level as well as a fallback so that if routes are posted which
-	// are invalid somehow make it past validation (e.g. the webhook is not enabled) we can
-	// at least try to provide a helpful message about the situation in the manager logs.
-	if len(spec.Rules) == 0 {
-		return translators.ErrRouteValidationNoRules
+	if err := validateTCPRoute(tcproute); err != nil {
+		return err
 	}
 
 	// each rule may represent a different set of backend services that will be accepting
 	// traffic, so we make separate routes and Kong services for every present rule.
 	for ruleNumber, rule := range spec.Rules {
-		// TODO: add this to a generic TCPRoute validation, and then we should probably
-		//       simply be calling validation on each tcproute object at the begininning
-		//       of the topmost list.
-		if len(rule.BackendRefs) == 0 {
-			return fmt.Errorf("missing backendRef in rule")
-		}
-
 		// determine the routes needed to route traffic to services for this rule
 		routes, err := generateKongRoutesFromRouteRule(tcproute, ruleNumber, rule)
 		if err != nil {
@@ -89,3 +79,41 @@ func (p *Parser) ingressRulesFromTCPRoute(result *ingressRules, tcproute *gatewa
 
 	return nil
 }
+
+// GenerateKongRoutesFromTCPRoute generates Kong routes from all rules of a TCPRoute.
+// It's used to validate TCPRoutes against Kong Gateway before they're translated.
+func GenerateKongRoutesFromTCPRoute(tcproute *gatewayapi.TCPRoute, expressionRoutes bool) ([]kongstate.Route, error) {
+	if err := validateTCPRoute(tcproute); err != nil {
+		return nil, err
+	}
+
+	var routes []kongstate.Route
+	for ruleNumber, rule := range tcproute.Spec.Rules {
+		ruleRoutes, err := generateKongRoutesFromRouteRule(tcproute, ruleNumber, rule)
+		if err != nil {
+			return nil, err
+		}
+		routes = append(routes, ruleRoutes...)
+	}
+	if expressionRoutes {
+		applyExpressionToL4KongRoutes(routes)
+	}
+	return routes, nil
+}
+
+// validateTCPRoute validates TCPRoute, and return a translation error if the spec is invalid.
+// Validation for TCPRoutes will happen at a higher layer, but in spite of that we run
+// validation at this level as well as a fallback so that if routes are posted which
+// are invalid somehow make it past validation (e.g. the webhook is not enabled) we can
+// at least try to provide a helpful message about the situation in the manager logs.
+func validateTCPRoute(tcproute *gatewayapi.TCPRoute) error {
+	if len(tcproute.Spec.Rules) == 0 {
+		return translators.ErrRouteValidationNoRules
+	}
+	for _, rule := range tcproute.Spec.Rules {
+		if len(rule.BackendRefs) == 0 {
+			return fmt.Errorf("missing backendRef in rule")
+		}
+	}
+	return nil
+}
@@ -7,6 +7,7 @@ import (
 	"github.com/kong/go-kong/kong"
 	gatewayv1 "sigs.k8s.io/gateway-api/apis/v1"
 
+	"github.com/kong/kubernetes-ingress-controller/v2/internal/dataplane/kongstate"
 	"github.com/kong/kubernetes-ingress-controller/v2/internal/dataplane/parser/translators"
 	"github.com/kong/kubernetes-ingress-controller/v2/internal/gatewayapi"
 	"github.com/kong/kubernetes-ingress-controller/v2/internal/store"
@@ -56,11 +57,8 @@ func (p *Parser) ingressRulesFromTLSRoute(result *ingressRules, tlsroute *gatewa
 	// first we grab the spec and gather some metdata about the object
 	spec := tlsroute.Spec
 
-	if len(spec.Hostnames) == 0 {
-		return fmt.Errorf("no hostnames provided")
-	}
-	if len(spec.Rules) == 0 {
-		return translators.ErrRouteValidationNoRules
+	if err := validateTLSRoute(tlsroute); err != nil {
+		return err
 	}
 
 	tlsPassthrough, err := p.isTLSRoutePassthrough(tlsroute)
@@ -149,3 +147,36 @@ func (p *Parser) isTLSRoutePassthrough(tlsroute *gatewayapi.TLSRoute) (bool, err
 
 	return false, nil
 }
+
+// GenerateKongRoutesFromTLSRoute generates Kong routes from all rules of a TLSRoute.
+// It's used to validate TLSRoutes against Kong Gateway before they're translated.
+// As Gateways aren't known at that point, TLS passthrough isn't taken into account.
+func GenerateKongRoutesFromTLSRoute(tlsroute *gatewayapi.TLSRoute, expressionRoutes bool) ([]kongstate.Route, error) {
+	if err := validateTLSRoute(tlsroute); err != nil {
+		return nil, err
+	}
+
+	var routes []kongstate.Route
+	for ruleNumber, rule := range tlsroute.Spec.Rules {
+		ruleRoutes, err := generateKongRoutesFromRouteRule(tlsroute, ruleNumber, rule)
+		if err != nil {
+			return nil, err
+		}
+		routes = append(routes, ruleRoutes...)
+	}
+	if expressionRoutes {
+		applyExpressionToL4KongRoutes(routes)
+	}
+	return routes, nil
+}
+
+// validateTLSRoute validates TLSRoute, and return a translation error if the spec is invalid.
+func validateTLSRoute(tlsroute *gatewayapi.TLSRoute) error {
+	if len(tlsroute.Spec.Hostnames) == 0 {
+		return fmt.Errorf("no hostnames provided")
+	}
+	if len(tlsroute.Spec.Rules) == 0 {
+		return translators.ErrRouteValidationNoRules
+	}
+	return nil
+}
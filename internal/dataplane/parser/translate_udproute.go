@@ -3,6 +3,7 @@ package parser
 import (
 	"fmt"
 
+	"github.com/kong/kubernetes-ingress-controller/v2/internal/dataplane/kongstate"
 	"github.com/kong/kubernetes-ingress-controller/v2/internal/dataplane/parser/translators"
 	"github.com/kong/kubernetes-ingress-controller/v2/internal/gatewayapi"
 )
@@ -80,6 +81,27 @@ func (p *Parser) ingressRulesFromUDPRoute(result *ingressRules, udproute *gatewa
 	return nil
 }
 
+// GenerateKongRoutesFromUDPRoute generates Kong routes from all rules of a UDPRoute.
+// It's used to validate UDPRoutes against Kong Gateway before they're translated.
+func GenerateKongRoutesFromUDPRoute(udproute *gatewayapi.UDPRoute, expressionRoutes bool) ([]kongstate.Route, error) {
+	if err := validateUDPRoute(udproute); err != nil {
+		return nil, err
+	}
+
+	var routes []kongstate.Route
+	for ruleNumber, rule := range udproute.Spec.Rules {
+		ruleRoutes, err := generateKongRoutesFromRouteRule(udproute, ruleNumber, rule)
+		if err != nil {
+			return nil, err
+		}
+		routes = append(routes, ruleRoutes...)
+	}
+	if expressionRoutes {
+		applyExpressionToL4KongRoutes(routes)
+	}
+	return routes, nil
+}
+
 // validateUDPRoute validates UDPRoute, and return a translation error if the spec is invalid.
 // Validation for UDPRoutes will happen at a higher layer, but in spite of that we run
 // validation at this level as well as a fallback so that if routes are posted which
@@ -160,10 +160,14 @@ func generateKongServiceFromBackendRefWithRuleNumber(
 
 func applyExpressionToIngressRules(result *ingressRules) {
 	for _, svc := range result.ServiceNameToServices {
-		for i := range svc.Routes {
-			translators.ApplyExpressionToL4KongRoute(&svc.Routes[i])
-			svc.Routes[i].Destinations = nil
-			svc.Routes[i].SNIs = nil
-		}
+		applyExpressionToL4KongRoutes(svc.Routes)
+	}
+}
+
+func applyExpressionToL4KongRoutes(routes []kongstate.Route) {
+	for i := range routes {
+		translators.ApplyExpressionToL4KongRoute(&routes[i])
+		routes[i].Destinations = nil
+		routes[i].SNIs = nil
 	}
 }
@@ -0,0 +1,98 @@
+package parser
+
+import (
+	"sort"
+	"strings"
+
+	k8stypes "k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// translationCache caches results of translating Kubernetes objects, so that objects which haven't changed since
+// the previous BuildKongConfig call aren't translated again. Results are keyed by the UID and resourceVersion of
+// the translated object together with the versions of the objects its translation depends on.
+// Its methods are safe to call with a nil receiver, in which case nothing is cached.
+//
+// Cached results must not be modified by their users, as they're shared between BuildKongConfig calls.
+type translationCache[T any] struct {
+	entries map[k8stypes.UID]translationCacheEntry[T]
+
+	// used holds UIDs of the objects looked up or stored since the last call to evictUnused.
+	used sets.Set[k8stypes.UID]
+}
+
+type translationCacheEntry[T any] struct {
+	version string
+	result  T
+}
+
+func newTranslationCache[T any]() *translationCache[T] {
+	return &translationCache[T]{
+		entries: make(map[k8stypes.UID]translationCacheEntry[T]),
+		used:    sets.New[k8stypes.UID](),
+	}
+}
+
+// get returns the cached result of translating an object if neither the object nor its dependencies
+// (described by dependenciesVersion) have changed since the result was stored.
+func (c *translationCache[T]) get(obj client.Object, dependenciesVersion string) (T, bool) {
+	var zero T
+	if c == nil || !isCacheable(obj) {
+		return zero, false
+	}
+	c.used.Insert(obj.GetUID())
+
+	entry, ok := c.entries[obj.GetUID()]
+	if !ok || entry.version != translationVersion(obj, dependenciesVersion) {
+		return zero, false
+	}
+	return entry.result, true
+}
+
+// set stores the result of translating an object with dependencies described by dependenciesVersion.
+func (c *translationCache[T]) set(obj client.Object, dependenciesVersion string, result T) {
+	if c == nil || !isCacheable(obj) {
+		return
+	}
+	c.used.Insert(obj.GetUID())
+	c.entries[obj.GetUID()] = translationCacheEntry[T]{
+		version: translationVersion(obj, dependenciesVersion),
+		result:  result,
+	}
+}
+
+// evictUnused removes results of the objects which haven't been looked up since the previous call,
+// e.g. because they were deleted.
+func (c *translationCache[T]) evictUnused() {
+	if c == nil {
+		return
+	}
+	for uid := range c.entries {
+		if !c.used.Has(uid) {
+			delete(c.entries, uid)
+		}
+	}
+	c.used = sets.New[k8stypes.UID]()
+}
+
+// isCacheable tells whether an object's translation can be cached. Objects without a UID or resourceVersion
+// (e.g. ones which haven't been created in the cluster) can't be told apart from their other versions.
+func isCacheable(obj client.Object) bool {
+	return obj.GetUID() != "" && obj.GetResourceVersion() != ""
+}
+
+func translationVersion(obj client.Object, dependenciesVersion string) string {
+	return obj.GetResourceVersion() + "/" + dependenciesVersion
+}
+
+// objectsVersion returns a version of a set of objects that changes whenever any of them changes,
+// or when any of them is added or removed. It doesn't depend on the order of the objects.
+func objectsVersion[T client.Object](objs []T) string {
+	versions := make([]string, 0, len(objs))
+	for _, obj := range objs {
+		versions = append(versions, string(obj.GetUID())+":"+obj.GetResourceVersion())
+	}
+	sort.Strings(versions)
+	return strings.Join(versions, ",")
+}
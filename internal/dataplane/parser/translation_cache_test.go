@@ -0,0 +1,310 @@
+package parser
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/samber/lo"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	netv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8stypes "k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	gatewayv1beta1 "sigs.k8s.io/gateway-api/apis/v1beta1"
+
+	"github.com/kong/kubernetes-ingress-controller/v2/internal/annotations"
+	"github.com/kong/kubernetes-ingress-controller/v2/internal/dataplane/kongstate"
+	"github.com/kong/kubernetes-ingress-controller/v2/internal/gatewayapi"
+	"github.com/kong/kubernetes-ingress-controller/v2/internal/store"
+	"github.com/kong/kubernetes-ingress-controller/v2/internal/util/builder"
+	"github.com/kong/kubernetes-ingress-controller/v2/test/helpers/certificate"
+)
+
+func TestTranslationCache(t *testing.T) {
+	newService := func(uid k8stypes.UID, resourceVersion string) *corev1.Service {
+		return &corev1.Service{ObjectMeta: metav1.ObjectMeta{Name: "svc", UID: uid, ResourceVersion: resourceVersion}}
+	}
+
+	t.Run("result is returned as long as the object and its dependencies don't change", func(t *testing.T) {
+		c := newTranslationCache[string]()
+		_, ok := c.get(newService("uid", "1"), "deps-1")
+		require.False(t, ok)
+
+		c.set(newService("uid", "1"), "deps-1", "result")
+		result, ok := c.get(newService("uid", "1"), "deps-1")
+		require.True(t, ok)
+		require.Equal(t, "result", result)
+
+		_, ok = c.get(newService("uid", "2"), "deps-1")
+		require.False(t, ok, "result should not be returned for a changed object")
+		_, ok = c.get(newService("uid", "1"), "deps-2")
+		require.False(t, ok, "result should not be returned for changed dependencies")
+	})
+
+	t.Run("objects without UID or resourceVersion are not cached", func(t *testing.T) {
+		c := newTranslationCache[string]()
+		for _, obj := range []*corev1.Service{newService("", "1"), newService("uid", "")} {
+			c.set(obj, "", "result")
+			_, ok := c.get(obj, "")
+			require.False(t, ok)
+		}
+	})
+
+	t.Run("results of objects which weren't looked up are evicted", func(t *testing.T) {
+		c := newTranslationCache[string]()
+		c.set(newService("uid-1", "1"), "", "result-1")
+		c.set(newService("uid-2", "1"), "", "result-2")
+		c.evictUnused()
+		require.Len(t, c.entries, 2)
+
+		_, ok := c.get(newService("uid-1", "1"), "")
+		require.True(t, ok)
+		c.evictUnused()
+		require.Len(t, c.entries, 1)
+		require.Contains(t, c.entries, k8stypes.UID("uid-1"))
+	})
+
+	t.Run("nil cache caches nothing", func(t *testing.T) {
+		var c *translationCache[string]
+		c.set(newService("uid", "1"), "", "result")
+		_, ok := c.get(newService("uid", "1"), "")
+		require.False(t, ok)
+		c.evictUnused()
+	})
+
+	t.Run("objects version doesn't depend on their order", func(t *testing.T) {
+		services := []*corev1.Service{newService("uid-1", "1"), newService("uid-2", "1")}
+		require.Equal(t, objectsVersion(services), objectsVersion(lo.Reverse(lo.Shuffle(services))))
+		require.NotEqual(t, objectsVersion(services), objectsVersion(services[:1]))
+	})
+}
+
+func TestParser_BuildKongConfigReusesTranslations(t *testing.T) {
+	newIngress := func(resourceVersion, path string) *netv1.Ingress {
+		return &netv1.Ingress{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:            "ingress",
+				Namespace:       "default",
+				UID:             "ingress-uid",
+				ResourceVersion: resourceVersion,
+				Annotations: map[string]string{
+					annotations.IngressClassKey:                           annotations.DefaultIngressClass,
+					annotations.AnnotationPrefix + annotations.MethodsKey: "GET",
+				},
+			},
+			Spec: netv1.IngressSpec{
+				Rules: []netv1.IngressRule{{
+					IngressRuleValue: netv1.IngressRuleValue{
+						HTTP: &netv1.HTTPIngressRuleValue{
+							Paths: []netv1.HTTPIngressPath{{
+								Path:     path,
+								PathType: lo.ToPtr(netv1.PathTypeImplementationSpecific),
+								Backend: netv1.IngressBackend{
+									Service: &netv1.IngressServiceBackend{
+										Name: "svc",
+										Port: netv1.ServiceBackendPort{Number: 80},
+									},
+								},
+							}},
+						},
+					},
+				}},
+			},
+		}
+	}
+	httpRoute := &gatewayapi.HTTPRoute{
+		TypeMeta: metav1.TypeMeta{Kind: "HTTPRoute", APIVersion: gatewayv1beta1.GroupVersion.String()},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            "httproute",
+			Namespace:       "default",
+			UID:             "httproute-uid",
+			ResourceVersion: "1",
+		},
+		Spec: gatewayapi.HTTPRouteSpec{
+			Rules: []gatewayapi.HTTPRouteRule{{
+				BackendRefs: []gatewayapi.HTTPBackendRef{
+					builder.NewHTTPBackendRef("svc").WithNamespace("other").WithGroup("").WithKind("Service").WithPort(80).Build(),
+				},
+			}},
+		},
+	}
+	referenceGrant := &gatewayapi.ReferenceGrant{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            "grant",
+			Namespace:       "other",
+			UID:             "grant-uid",
+			ResourceVersion: "1",
+		},
+		Spec: gatewayapi.ReferenceGrantSpec{
+			From: []gatewayapi.ReferenceGrantFrom{{
+				Group:     gatewayapi.Group("gateway.networking.k8s.io"),
+				Kind:      "HTTPRoute",
+				Namespace: "default",
+			}},
+			To: []gatewayapi.ReferenceGrantTo{{Group: "", Kind: "Service"}},
+		},
+	}
+	services := []*corev1.Service{
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "svc", Namespace: "default"},
+			Spec:       corev1.ServiceSpec{Ports: []corev1.ServicePort{{Port: 80, TargetPort: intstr.FromInt(80)}}},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "svc", Namespace: "other"},
+			Spec:       corev1.ServiceSpec{Ports: []corev1.ServicePort{{Port: 80, TargetPort: intstr.FromInt(80)}}},
+		},
+	}
+
+	newStore := func(t *testing.T, objects store.FakeObjects) store.Storer {
+		objects.Services = services
+		s, err := store.NewFakeStore(objects)
+		require.NoError(t, err)
+		return s
+	}
+	ingressRoute := func(t *testing.T, result KongConfigBuildingResult) kongstate.Route {
+		service, ok := lo.Find(result.KongState.Services, func(s kongstate.Service) bool {
+			return *s.Name == "default.svc.80"
+		})
+		require.True(t, ok)
+		require.Len(t, service.Routes, 1)
+		return service.Routes[0]
+	}
+	httpRouteService := func(t *testing.T, result KongConfigBuildingResult) kongstate.Service {
+		service, ok := lo.Find(result.KongState.Services, func(s kongstate.Service) bool {
+			return *s.Name == "httproute.default.httproute.0"
+		})
+		require.True(t, ok)
+		return service
+	}
+
+	p := mustNewParser(t, newStore(t, store.FakeObjects{
+		IngressesV1: []*netv1.Ingress{newIngress("1", "/foo")},
+		HTTPRoutes:  []*gatewayapi.HTTPRoute{httpRoute},
+	}))
+	result := p.BuildKongConfig()
+	require.Equal(t, []*string{lo.ToPtr("/foo")}, ingressRoute(t, result).Paths)
+	require.Equal(t, []*string{lo.ToPtr("GET")}, ingressRoute(t, result).Methods)
+	require.Empty(t, httpRouteService(t, result).Backends, "backend should not be allowed without a ReferenceGrant")
+	require.ElementsMatch(t, []string{"ingress", "httproute"},
+		lo.Map(result.ConfiguredKubernetesObjects, func(o client.Object, _ int) string { return o.GetName() }))
+
+	cachedServices, ok := p.ingressTranslationCache.get(newIngress("1", "/foo"), "false")
+	require.True(t, ok)
+	require.Nil(t, cachedServices["default.svc.80"].Routes[0].Methods,
+		"modifications made after translation should not affect cached results")
+
+	t.Log("building again with an Ingress changed without changing its resourceVersion to verify the cached result is used")
+	p.storer = newStore(t, store.FakeObjects{
+		IngressesV1: []*netv1.Ingress{newIngress("1", "/bar")},
+		HTTPRoutes:  []*gatewayapi.HTTPRoute{httpRoute},
+	})
+	result = p.BuildKongConfig()
+	require.Equal(t, []*string{lo.ToPtr("/foo")}, ingressRoute(t, result).Paths)
+	require.Equal(t, []*string{lo.ToPtr("GET")}, ingressRoute(t, result).Methods)
+	require.ElementsMatch(t, []string{"ingress", "httproute"},
+		lo.Map(result.ConfiguredKubernetesObjects, func(o client.Object, _ int) string { return o.GetName() }),
+		"objects with cached translations should be reported as configured")
+
+	t.Log("building again with a changed resourceVersion of the Ingress and a ReferenceGrant the HTTPRoute depends on")
+	p.storer = newStore(t, store.FakeObjects{
+		IngressesV1:     []*netv1.Ingress{newIngress("2", "/bar")},
+		HTTPRoutes:      []*gatewayapi.HTTPRoute{httpRoute},
+		ReferenceGrants: []*gatewayapi.ReferenceGrant{referenceGrant},
+	})
+	result = p.BuildKongConfig()
+	require.Equal(t, []*string{lo.ToPtr("/bar")}, ingressRoute(t, result).Paths)
+	require.Len(t, httpRouteService(t, result).Backends, 1, "backend should be allowed by the ReferenceGrant")
+
+	t.Log("building again without the Ingress to verify its cached result is evicted")
+	p.storer = newStore(t, store.FakeObjects{
+		HTTPRoutes:      []*gatewayapi.HTTPRoute{httpRoute},
+		ReferenceGrants: []*gatewayapi.ReferenceGrant{referenceGrant},
+	})
+	p.BuildKongConfig()
+	require.Empty(t, p.ingressTranslationCache.entries)
+	require.Len(t, p.httpRouteTranslationCache.entries, 1)
+}
+
+func TestParser_GetServiceTargetsReusesTargets(t *testing.T) {
+	svc := &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{Name: "svc", Namespace: "default", UID: "svc-uid", ResourceVersion: "1"},
+		Spec:       corev1.ServiceSpec{Ports: []corev1.ServicePort{{Name: "http", Port: 80, TargetPort: intstr.FromInt(8080)}}},
+	}
+	newEndpointSlice := func(resourceVersion, address string) *discoveryv1.EndpointSlice {
+		return &discoveryv1.EndpointSlice{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:            "svc-1",
+				Namespace:       "default",
+				UID:             "endpointslice-uid",
+				ResourceVersion: resourceVersion,
+				Labels:          map[string]string{discoveryv1.LabelServiceName: "svc"},
+			},
+			Endpoints: []discoveryv1.Endpoint{{Addresses: []string{address}}},
+			Ports: []discoveryv1.EndpointPort{{
+				Name:     lo.ToPtr("http"),
+				Port:     lo.ToPtr(int32(8080)),
+				Protocol: lo.ToPtr(corev1.ProtocolTCP),
+			}},
+		}
+	}
+	newStore := func(t *testing.T, endpointSlices ...*discoveryv1.EndpointSlice) store.Storer {
+		s, err := store.NewFakeStore(store.FakeObjects{Services: []*corev1.Service{svc}, EndpointSlices: endpointSlices})
+		require.NoError(t, err)
+		return s
+	}
+	targetNames := func(targets []kongstate.Target) []string {
+		return lo.Map(targets, func(t kongstate.Target, _ int) string { return *t.Target.Target })
+	}
+
+	p := mustNewParser(t, newStore(t, newEndpointSlice("1", "10.0.0.1")))
+	targets := p.getServiceTargets(svc, &svc.Spec.Ports[0])
+	require.Equal(t, []string{"10.0.0.1:8080"}, targetNames(targets))
+	targets[0].Weight = lo.ToPtr(10)
+	require.Nil(t, p.getServiceTargets(svc, &svc.Spec.Ports[0])[0].Weight,
+		"modifications made after translation should not affect cached results")
+
+	t.Log("changing an EndpointSlice without changing its resourceVersion to verify the cached targets are used")
+	p.storer = newStore(t, newEndpointSlice("1", "10.0.0.2"))
+	require.Equal(t, []string{"10.0.0.1:8080"}, targetNames(p.getServiceTargets(svc, &svc.Spec.Ports[0])))
+
+	t.Log("changing the resourceVersion of the EndpointSlice")
+	p.storer = newStore(t, newEndpointSlice("2", "10.0.0.2"))
+	require.Equal(t, []string{"10.0.0.2:8080"}, targetNames(p.getServiceTargets(svc, &svc.Spec.Ports[0])))
+
+	t.Log("removing the EndpointSlice")
+	p.storer = newStore(t)
+	require.Empty(t, p.getServiceTargets(svc, &svc.Spec.Ports[0]))
+}
+
+func TestParser_GetCertFromSecretReusesCertificates(t *testing.T) {
+	cert, key := certificate.MustGenerateSelfSignedCertPEMFormat()
+	newSecret := func(resourceVersion string, data map[string][]byte) *corev1.Secret {
+		return &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:            "secret",
+				Namespace:       "default",
+				UID:             "secret-uid",
+				ResourceVersion: resourceVersion,
+			},
+			Data: data,
+		}
+	}
+	validData := map[string][]byte{corev1.TLSCertKey: cert, corev1.TLSPrivateKeyKey: key}
+
+	p := mustNewParser(t, lo.Must(store.NewFakeStore(store.FakeObjects{})))
+	_, _, err := p.getCertFromSecret(newSecret("1", nil))
+	require.Error(t, err)
+
+	t.Log("changing the Secret without changing its resourceVersion to verify the cached result is used")
+	_, _, err = p.getCertFromSecret(newSecret("1", validData))
+	require.Error(t, err)
+
+	t.Log("changing the resourceVersion of the Secret")
+	gotCert, gotKey, err := p.getCertFromSecret(newSecret("2", validData))
+	require.NoError(t, err)
+	require.Equal(t, strings.TrimSpace(string(cert)), gotCert)
+	require.Equal(t, strings.TrimSpace(string(key)), gotKey)
+}
@@ -4,21 +4,28 @@ import (
 	"encoding/json"
 	"fmt"
 	pathlib "path"
+	"regexp"
 	"sort"
 	"strings"
+	"time"
 
 	"github.com/kong/go-kong/kong"
+	"github.com/samber/lo"
 
 	"github.com/kong/kubernetes-ingress-controller/v2/internal/gatewayapi"
+	"github.com/kong/kubernetes-ingress-controller/v2/internal/util"
+	kongv1beta1 "github.com/kong/kubernetes-ingress-controller/v2/pkg/apis/configuration/v1beta1"
 )
 
 // KongServiceTranslation is a translation of a single HTTPRoute into metadata
 // that can be used to instantiate Kong routes and services.
 // Routes from this object should route traffic to BackendRefs from this object.
 type KongServiceTranslation struct {
-	Name        string
-	BackendRefs []gatewayapi.HTTPBackendRef
-	KongRoutes  []KongRouteTranslation
+	Name               string
+	BackendRefs        []gatewayapi.HTTPBackendRef
+	Timeouts           *gatewayapi.HTTPRouteTimeouts
+	SessionPersistence *kongv1beta1.KongUpstreamHash
+	KongRoutes         []KongRouteTranslation
 }
 
 // KongRouteTranslation is a translation of a single HTTPRoute rule into metadata
@@ -31,15 +38,52 @@ type KongRouteTranslation struct {
 
 // TranslateHTTPRoute translates a list of HTTPRoutes into a list of HTTPRouteTranslationMeta
 // objects that can be used to instantiate Kong routes and services.
-// The translation is done by grouping the HTTPRoutes by their backendRefs.
+// The translation is done by grouping the HTTPRoutes by their backendRefs, timeouts and session persistence.
 // This means that all the rules of a single HTTPRoute will be grouped together
-// if they share the same backendRefs.
+// if they share the same backendRefs, timeouts and session persistence.
 func TranslateHTTPRoute(route *gatewayapi.HTTPRoute) []*KongServiceTranslation {
 	index := httpRouteTranslationIndex{}
 	index.setRoute(route)
 	return index.translate()
 }
 
+// KongServiceTimeoutFromHTTPRouteTimeouts returns the timeout in milliseconds to be set as the connect, read
+// and write timeouts of a Kong service translated from HTTPRoute rules with the given timeouts.
+// Kong timeouts apply to every request proxied to an upstream, so the backendRequest timeout is used
+// when set and the request timeout otherwise. A zero duration disables the timeout, which for Kong
+// means using the maximum timeout it accepts. It returns nil when the timeouts don't set any of them.
+func KongServiceTimeoutFromHTTPRouteTimeouts(timeouts *gatewayapi.HTTPRouteTimeouts) (*int, error) {
+	if timeouts == nil {
+		return nil, nil
+	}
+
+	fieldName, duration := "backendRequest", timeouts.BackendRequest
+	if duration == nil {
+		fieldName, duration = "request", timeouts.Request
+	}
+	if duration == nil {
+		return nil, nil
+	}
+
+	d, err := time.ParseDuration(string(*duration))
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s timeout %q: %w", fieldName, *duration, err)
+	}
+	if d < 0 {
+		return nil, fmt.Errorf("invalid %s timeout %q: must not be negative", fieldName, *duration)
+	}
+
+	ms := d.Milliseconds()
+	if d == 0 || ms > KongServiceMaxTimeout {
+		ms = KongServiceMaxTimeout
+	}
+	// Kong doesn't accept timeouts lower than 1ms.
+	if ms < 1 {
+		ms = 1
+	}
+	return lo.ToPtr(int(ms)), nil
+}
+
 // -----------------------------------------------------------------------------
 // HTTPRoute Translation - Private - Index
 // -----------------------------------------------------------------------------
@@ -58,16 +102,22 @@ func (i *httpRouteTranslationIndex) setRoute(route *gatewayapi.HTTPRoute) {
 func (i *httpRouteTranslationIndex) extractRulesMeta(route *gatewayapi.HTTPRoute) {
 	i.rulesMeta = make([]httpRouteRuleMeta, 0, len(route.Spec.Rules))
 
+	// invalid session persistence annotations are reported when validating the HTTPRoute.
+	sessionPersistence, _ := SessionPersistenceForHTTPRouteRules(route)
 	for ruleNumber, rule := range route.Spec.Rules {
-		i.rulesMeta = append(i.rulesMeta, httpRouteRuleMeta{
+		ruleMeta := httpRouteRuleMeta{
 			RuleNumber: ruleNumber,
 			Rule:       rule,
-		})
+		}
+		if sessionPersistence != nil {
+			ruleMeta.SessionPersistence = sessionPersistence[ruleNumber]
+		}
+		i.rulesMeta = append(i.rulesMeta, ruleMeta)
 	}
 }
 
 func (i *httpRouteTranslationIndex) translate() []*KongServiceTranslation {
-	rulesGroupedByBackendRed := groupRulesByBackendRefs(i.rulesMeta)
+	rulesGroupedByBackendRed := groupRulesByKongServiceSettings(i.rulesMeta)
 	translations := make([]*KongServiceTranslation, 0, len(rulesGroupedByBackendRed))
 
 	for _, rulesByBackends := range rulesGroupedByBackendRed {
@@ -82,9 +132,11 @@ func (i *httpRouteTranslationIndex) translate() []*KongServiceTranslation {
 
 func (i *httpRouteTranslationIndex) translateToKongService(rulesMeta []httpRouteRuleMeta) *KongServiceTranslation {
 	return &KongServiceTranslation{
-		Name:        i.translateToKongServiceName(rulesMeta),
-		BackendRefs: i.translateToKongServiceBackends(rulesMeta),
-		KongRoutes:  nil,
+		Name:               i.translateToKongServiceName(rulesMeta),
+		BackendRefs:        i.translateToKongServiceBackends(rulesMeta),
+		Timeouts:           i.translateToKongServiceTimeouts(rulesMeta),
+		SessionPersistence: i.translateToKongServiceSessionPersistence(rulesMeta),
+		KongRoutes:         nil,
 	}
 }
 
@@ -113,6 +165,24 @@ func (i *httpRouteTranslationIndex) translateToKongServiceBackends(rulesMeta []h
 	return rulesMeta[0].Rule.BackendRefs
 }
 
+func (i *httpRouteTranslationIndex) translateToKongServiceTimeouts(rulesMeta []httpRouteRuleMeta) *gatewayapi.HTTPRouteTimeouts {
+	if len(rulesMeta) == 0 {
+		return nil
+	}
+	// get the timeouts from any rule, as they are all the same,
+	// because the rules are processed in groups with the same backendRefs and timeouts.
+	return rulesMeta[0].Rule.Timeouts
+}
+
+func (i *httpRouteTranslationIndex) translateToKongServiceSessionPersistence(rulesMeta []httpRouteRuleMeta) *kongv1beta1.KongUpstreamHash {
+	if len(rulesMeta) == 0 {
+		return nil
+	}
+	// get the session persistence from any rule, as it's the same for all of them,
+	// because the rules are processed in groups with the same session persistence.
+	return rulesMeta[0].SessionPersistence
+}
+
 func (i *httpRouteTranslationIndex) translateToKongServiceRoutes(s *KongServiceTranslation, rulesMeta []httpRouteRuleMeta) {
 	for _, rulesByFilter := range groupRulesByFilter(rulesMeta) {
 		// each filter group must be a separate Kong route, not eligible for consolidation
@@ -188,11 +258,12 @@ func (i *httpRouteTranslationIndex) translateToKongRouteName(matchesMeta httpRou
 	)
 }
 
-// groupRulesByBackendRefs groups the rules by their backendRefs.
-// The backendRefs are grouped by their key function.
+// groupRulesByKongServiceSettings groups the rules by their backendRefs, timeouts and session persistence.
+// Timeouts are set on Kong services and session persistence on their upstreams, so rules routing to the
+// same backends with different timeouts or session persistence have to be translated into separate Kong services.
 // The elements in the groups have the order of the original slice, but the groups themselves are not ordered.
-func groupRulesByBackendRefs(ruleEntries []httpRouteRuleMeta) map[string][]httpRouteRuleMeta {
-	return groupSliceByKeyFn(ruleEntries, httpRouteRuleMeta.getHTTPBackendRefsKey)
+func groupRulesByKongServiceSettings(ruleEntries []httpRouteRuleMeta) map[string][]httpRouteRuleMeta {
+	return groupSliceByKeyFn(ruleEntries, httpRouteRuleMeta.getKongServiceSettingsKey)
 }
 
 // groupRulesByFilter groups the rules by their filters.
@@ -219,8 +290,9 @@ func groupSliceByKeyFn[T any](vals []T, keyFn func(val T) string) map[string][]T
 // -----------------------------------------------------------------------------
 
 type httpRouteRuleMeta struct {
-	Rule       gatewayapi.HTTPRouteRule
-	RuleNumber int
+	Rule               gatewayapi.HTTPRouteRule
+	RuleNumber         int
+	SessionPersistence *kongv1beta1.KongUpstreamHash
 }
 
 // getFiltersKey computes a key from a list of filters.
@@ -229,10 +301,17 @@ func (m httpRouteRuleMeta) getFiltersKey() string {
 	return getSortedItemsString(m.Rule.Filters)
 }
 
-// getHTTPBackendRefsKey computes a key from a list of backendRefs.
-// The order of backedRefs is not important.
-func (m httpRouteRuleMeta) getHTTPBackendRefsKey() string {
-	return getSortedItemsString(m.Rule.BackendRefs)
+// getKongServiceSettingsKey computes a key from a list of backendRefs, the timeouts and the session
+// persistence of the rule. The order of backedRefs is not important.
+func (m httpRouteRuleMeta) getKongServiceSettingsKey() string {
+	key := getSortedItemsString(m.Rule.BackendRefs)
+	if m.Rule.Timeouts != nil {
+		key += "#" + mustMarshalJSON(m.Rule.Timeouts)
+	}
+	if m.SessionPersistence != nil {
+		key += "#" + mustMarshalJSON(m.SessionPersistence)
+	}
+	return key
 }
 
 func (m *httpRouteRuleMeta) matches() httpRouteMatchMetaList {
@@ -352,12 +431,13 @@ func mustMarshalJSON[T any](val T) string {
 
 // GeneratePluginsFromHTTPRouteFilters converts HTTPRouteFilter into Kong plugins.
 // path is the parameter to be used by the redirect plugin, to perform redirection.
-func GeneratePluginsFromHTTPRouteFilters(filters []gatewayapi.HTTPRouteFilter, path string, tags []*string) []kong.Plugin {
+func GeneratePluginsFromHTTPRouteFilters(filters []gatewayapi.HTTPRouteFilter, path string, tags []*string) ([]kong.Plugin, error) {
 	kongPlugins := make([]kong.Plugin, 0)
 	if len(filters) == 0 {
-		return kongPlugins
+		return kongPlugins, nil
 	}
 
+	var urlRewritePlugin *kong.Plugin
 	for _, filter := range filters {
 		switch filter.Type {
 		case gatewayapi.HTTPRouteFilterRequestHeaderModifier:
@@ -369,19 +449,346 @@ func GeneratePluginsFromHTTPRouteFilters(filters []gatewayapi.HTTPRouteFilter, p
 		case gatewayapi.HTTPRouteFilterResponseHeaderModifier:
 			kongPlugins = append(kongPlugins, generateResponseHeaderModifierKongPlugin(filter.ResponseHeaderModifier))
 
-		case gatewayapi.HTTPRouteFilterExtensionRef,
-			gatewayapi.HTTPRouteFilterRequestMirror,
-			gatewayapi.HTTPRouteFilterURLRewrite:
-			// not supported
+		case gatewayapi.HTTPRouteFilterURLRewrite:
+			plugin, err := generateRequestTransformerForURLRewrite(filter.URLRewrite)
+			if err != nil {
+				return nil, err
+			}
+			urlRewritePlugin = &plugin
+
+		case gatewayapi.HTTPRouteFilterRequestMirror:
+			// Mirroring requires additional Kong services to be generated for the mirrored backends,
+			// thus the plugin is generated by the parser with GenerateRequestMirrorPlugin.
+
+		case gatewayapi.HTTPRouteFilterExtensionRef:
+			// KongPlugins referenced by ExtensionRef filters are attached to routes
+			// by KongState.FillPlugins, see KongPluginNamesFromHTTPRouteFilters.
 		}
 	}
+
+	// Both the RequestHeaderModifier and the URLRewrite filters are implemented with the request-transformer
+	// plugin. As Kong allows only a single instance of a plugin per route, they have to be merged together.
+	if urlRewritePlugin != nil {
+		kongPlugins = mergeURLRewriteIntoRequestTransformer(kongPlugins, *urlRewritePlugin)
+	}
+
 	for _, p := range kongPlugins {
 		// This plugin is derived from an HTTPRoute filter, not a KongPlugin, so we apply tags indicating that
 		// HTTPRoute as the parent Kubernetes resource for these generated plugins.
 		p.Tags = tags
 	}
 
-	return kongPlugins
+	return kongPlugins, nil
+}
+
+// KongPluginNamesFromHTTPRouteFilters returns names of KongPlugins referenced by ExtensionRef filters.
+// ExtensionRef filters referencing other kinds of objects are ignored.
+func KongPluginNamesFromHTTPRouteFilters(filters []gatewayapi.HTTPRouteFilter) []string {
+	var names []string
+	for _, filter := range filters {
+		if filter.Type != gatewayapi.HTTPRouteFilterExtensionRef || filter.ExtensionRef == nil {
+			continue
+		}
+		if !util.IsKongPluginExtensionRef(*filter.ExtensionRef) {
+			continue
+		}
+		if !lo.Contains(names, string(filter.ExtensionRef.Name)) {
+			names = append(names, string(filter.ExtensionRef.Name))
+		}
+	}
+	return names
+}
+
+// ValidateHTTPRouteRuleFilters verifies that the filters of an HTTPRoute rule can be translated
+// into Kong configuration together with the rule's matches.
+func ValidateHTTPRouteRuleFilters(matches []gatewayapi.HTTPRouteMatch, filters []gatewayapi.HTTPRouteFilter) error {
+	_, hasRedirectFilter := lo.Find(filters, func(filter gatewayapi.HTTPRouteFilter) bool {
+		return filter.Type == gatewayapi.HTTPRouteFilterRequestRedirect
+	})
+	_, hasURLRewriteFilter := lo.Find(filters, func(filter gatewayapi.HTTPRouteFilter) bool {
+		return filter.Type == gatewayapi.HTTPRouteFilterURLRewrite
+	})
+	if hasRedirectFilter && hasURLRewriteFilter {
+		return ErrRouteValidationRedirectAndURLRewriteFilters
+	}
+
+	if !HasURLRewriteReplacePrefixMatchFilter(filters) {
+		return nil
+	}
+	// The prefix to replace is determined by the path prefix of the match, thus every match of the rule
+	// has to define one. Matches of other types give no prefix that could be replaced.
+	if len(matches) == 0 {
+		return ErrRouteValidationReplacePrefixMatchRequiresPathPrefix
+	}
+	for _, match := range matches {
+		if match.Path == nil || match.Path.Type == nil || *match.Path.Type != gatewayapi.PathMatchPathPrefix {
+			return ErrRouteValidationReplacePrefixMatchRequiresPathPrefix
+		}
+	}
+	return nil
+}
+
+// HasURLRewriteReplacePrefixMatchFilter returns true if any of the filters is a URLRewrite filter
+// replacing the matched path prefix. Routes with such a filter need to capture the part of the path
+// following the prefix, so it can be appended to the replacement by the request-transformer plugin.
+func HasURLRewriteReplacePrefixMatchFilter(filters []gatewayapi.HTTPRouteFilter) bool {
+	return lo.ContainsBy(filters, func(filter gatewayapi.HTTPRouteFilter) bool {
+		return filter.Type == gatewayapi.HTTPRouteFilterURLRewrite &&
+			filter.URLRewrite != nil &&
+			filter.URLRewrite.Path != nil &&
+			filter.URLRewrite.Path.Type == gatewayapi.PrefixMatchHTTPPathModifier
+	})
+}
+
+// generateRequestTransformerForURLRewrite generates a request-transformer plugin replacing the URI
+// and/or the Host header of the request as defined by the URLRewrite filter.
+func generateRequestTransformerForURLRewrite(modifier *gatewayapi.HTTPURLRewriteFilter) (kong.Plugin, error) {
+	if modifier == nil {
+		return kong.Plugin{}, fmt.Errorf("%s filter config is missing", gatewayapi.HTTPRouteFilterURLRewrite)
+	}
+
+	replace := make(map[string]interface{})
+	if modifier.Hostname != nil {
+		// The request-transformer plugin sets the upstream Host header when the host header is replaced.
+		replace["headers"] = []string{fmt.Sprintf("host:%s", *modifier.Hostname)}
+	}
+
+	if modifier.Path != nil {
+		switch modifier.Path.Type {
+		case gatewayapi.FullPathHTTPPathModifier:
+			if modifier.Path.ReplaceFullPath == nil {
+				return kong.Plugin{}, fmt.Errorf("%s path modifier requires replaceFullPath to be set", modifier.Path.Type)
+			}
+			replace["uri"] = *modifier.Path.ReplaceFullPath
+
+		case gatewayapi.PrefixMatchHTTPPathModifier:
+			if modifier.Path.ReplacePrefixMatch == nil {
+				return kong.Plugin{}, fmt.Errorf("%s path modifier requires replacePrefixMatch to be set", modifier.Path.Type)
+			}
+			replace["uri"] = generateReplacePrefixMatchURITemplate(*modifier.Path.ReplacePrefixMatch)
+
+		default:
+			return kong.Plugin{}, fmt.Errorf("unsupported %s path modifier type %q", gatewayapi.HTTPRouteFilterURLRewrite, modifier.Path.Type)
+		}
+	}
+
+	return kong.Plugin{
+		Name: kong.String("request-transformer"),
+		Config: kong.Configuration{
+			"replace": replace,
+		},
+	}, nil
+}
+
+// generateReplacePrefixMatchURITemplate generates the request-transformer template for the URI
+// replacing the matched path prefix. Routes generated for the matches capture the remainder of the
+// path following the prefix as the first capture group, or don't capture anything when the path
+// is equal to the prefix (see KongPathRegexCapturingPrefixRemainder).
+func generateReplacePrefixMatchURITemplate(replacement string) string {
+	replacement = strings.TrimSuffix(replacement, "/")
+	if replacement == "" {
+		return `$(uri_captures[1] == nil and "/" or uri_captures[1])`
+	}
+	return fmt.Sprintf(`%s$(uri_captures[1] == nil and "" or uri_captures[1])`, replacement)
+}
+
+// KongPathRegexCapturingPrefixRemainder returns a regular expression (without the Kong regex path prefix)
+// matching paths under the given prefix that captures the remainder of the path following the prefix
+// in the first capture group. The path equal to the prefix itself has to be matched separately.
+func KongPathRegexCapturingPrefixRemainder(prefix string) string {
+	return fmt.Sprintf("%s(/.*)", regexp.QuoteMeta(strings.TrimSuffix(prefix, "/")))
+}
+
+// mergeURLRewriteIntoRequestTransformer adds the configuration of the URLRewrite request-transformer
+// plugin to the request-transformer plugin generated for the RequestHeaderModifier filter if there's one.
+// Otherwise, the URLRewrite plugin is appended to the plugins.
+func mergeURLRewriteIntoRequestTransformer(plugins []kong.Plugin, urlRewritePlugin kong.Plugin) []kong.Plugin {
+	_, i, found := lo.FindIndexOf(plugins, func(p kong.Plugin) bool {
+		return p.Name != nil && *p.Name == "request-transformer"
+	})
+	if !found {
+		return append(plugins, urlRewritePlugin)
+	}
+
+	urlRewriteReplace, _ := urlRewritePlugin.Config["replace"].(map[string]interface{})
+	replace := make(map[string]interface{}, len(urlRewriteReplace))
+	var headers []string
+	if headerModifierReplace, ok := plugins[i].Config["replace"].(map[string][]string); ok {
+		headers = append(headers, headerModifierReplace["headers"]...)
+	}
+	if urlRewriteHeaders, ok := urlRewriteReplace["headers"].([]string); ok {
+		headers = append(headers, urlRewriteHeaders...)
+	}
+	if len(headers) > 0 {
+		replace["headers"] = headers
+	}
+	if uri, ok := urlRewriteReplace["uri"]; ok {
+		replace["uri"] = uri
+	}
+	plugins[i].Config["replace"] = replace
+
+	return plugins
+}
+
+// GenerateQueryParamsGuardPlugin generates the plugin enforcing query param matches of an HTTPRoute match
+// on a Kong route generated for the traditional router. The plugin runs after the route has been selected
+// by the other criteria of the match and rejects requests whose query params don't match with 404.
+// Only the first value of query params repeated in a request is considered. As in expression based
+// routes, regular expressions aren't anchored.
+func GenerateQueryParamsGuardPlugin(queryParams []gatewayapi.HTTPQueryParamMatch, tags []*string) kong.Plugin {
+	var code strings.Builder
+	code.WriteString("local args = kong.request.get_query()\n")
+	code.WriteString("local function arg(name)\n")
+	code.WriteString("  local v = args[name]\n")
+	code.WriteString("  if type(v) == \"table\" then v = v[1] end\n")
+	code.WriteString("  if v == true then v = \"\" end\n")
+	code.WriteString("  return v\n")
+	code.WriteString("end\n")
+
+	// According to the spec of HTTPQueryParamMatch only the first entry of equivalent
+	// query param names must be considered.
+	seenQueryParams := make(map[gatewayapi.HTTPHeaderName]struct{}, len(queryParams))
+	for _, queryParam := range queryParams {
+		if _, ok := seenQueryParams[queryParam.Name]; ok {
+			continue
+		}
+		seenQueryParams[queryParam.Name] = struct{}{}
+
+		name := luaStringLiteral(string(queryParam.Name))
+		value := luaStringLiteral(queryParam.Value)
+		if queryParam.Type != nil && *queryParam.Type == gatewayapi.QueryParamMatchRegularExpression {
+			fmt.Fprintf(&code, "if not arg(%s) or not ngx.re.find(arg(%s), %s, \"jo\") then\n", name, name, value)
+		} else {
+			fmt.Fprintf(&code, "if arg(%s) ~= %s then\n", name, value)
+		}
+		code.WriteString("  return kong.response.exit(404, { message = \"no Route matched with those values\" })\n")
+		code.WriteString("end\n")
+	}
+
+	return kong.Plugin{
+		Name: kong.String(QueryParamsGuardPluginName),
+		Config: kong.Configuration{
+			"access": []string{code.String()},
+		},
+		Tags: tags,
+	}
+}
+
+// KongServiceNameForRequestMirror generates the name of the Kong service (and its upstream) which requests
+// matched by the HTTPRoute are mirrored to by a RequestMirror filter referencing the given backend. The name is
+// in the format "httproute.<namespace>.<name>.mirror.<backend namespace>.<backend name>.<backend port>".
+func KongServiceNameForRequestMirror(httproute *gatewayapi.HTTPRoute, backendRef gatewayapi.BackendObjectReference) string {
+	namespace := httproute.Namespace
+	if backendRef.Namespace != nil {
+		namespace = string(*backendRef.Namespace)
+	}
+	port := "_"
+	if backendRef.Port != nil {
+		port = fmt.Sprint(*backendRef.Port)
+	}
+	return fmt.Sprintf("httproute.%s.%s.mirror.%s.%s.%s",
+		httproute.Namespace,
+		httproute.Name,
+		namespace,
+		backendRef.Name,
+		port,
+	)
+}
+
+// GenerateRequestMirrorPlugin generates the plugin mirroring requests to the given Kong upstreams. For each of them,
+// a target is picked by Kong's balancer and a copy of the request is sent to it in the background, so responses
+// of mirrored backends are ignored. Failures to mirror a request are only logged and never affect the request.
+// The code requires the resty.http and kong.runloop.balancer modules, which have to be allowed in Kong's sandbox.
+func GenerateRequestMirrorPlugin(upstreams []string, tags []*string) kong.Plugin {
+	var code strings.Builder
+	code.WriteString("local ok, err = pcall(function()\n")
+	code.WriteString("  local balancer = require(\"kong.runloop.balancer\")\n")
+	code.WriteString("  local http = require(\"resty.http\")\n")
+	code.WriteString("  local method = kong.request.get_method()\n")
+	code.WriteString("  local path = kong.request.get_path_with_query()\n")
+	code.WriteString("  local headers = kong.request.get_headers()\n")
+	code.WriteString("  local body = kong.request.get_raw_body()\n")
+	code.WriteString("  local function mirror(premature, target)\n")
+	code.WriteString("    if premature then return end\n")
+	code.WriteString("    local _, err = http.new():request_uri(\"http://\" .. target.ip .. \":\" .. target.port .. path, {\n")
+	code.WriteString("      method = method, headers = headers, body = body,\n")
+	code.WriteString("    })\n")
+	code.WriteString("    if err then kong.log.warn(\"failed to mirror request to \", target.host, \": \", err) end\n")
+	code.WriteString("  end\n")
+	code.WriteString("  for _, upstream in ipairs({")
+	for i, upstream := range upstreams {
+		if i > 0 {
+			code.WriteString(", ")
+		}
+		code.WriteString(luaStringLiteral(upstream))
+	}
+	code.WriteString("}) do\n")
+	code.WriteString("    local target = { type = \"name\", host = upstream, port = 80, try_count = 0, retries = 0 }\n")
+	code.WriteString("    local ok, err = balancer.execute(target, ngx.ctx)\n")
+	code.WriteString("    if ok then ok, err = ngx.timer.at(0, mirror, target) end\n")
+	code.WriteString("    if not ok then kong.log.warn(\"failed to mirror request to \", upstream, \": \", err) end\n")
+	code.WriteString("  end\n")
+	code.WriteString("end)\n")
+	code.WriteString("if not ok then kong.log.err(\"failed to mirror request: \", err) end\n")
+
+	return kong.Plugin{
+		Name: kong.String(RequestMirrorPluginName),
+		Config: kong.Configuration{
+			"access": []string{code.String()},
+		},
+		Tags: tags,
+	}
+}
+
+// AddPreFunctionPlugin adds the pre-function plugin to the plugins of a route. As a route can have only a single
+// plugin of each name, the code of the plugin is appended to the code of a pre-function plugin the route already
+// has, if there's one. It's run in its own block after that code, so it's not run for requests the former code
+// terminates (e.g. rejected by the plugin enforcing query param matches).
+func AddPreFunctionPlugin(plugins []kong.Plugin, plugin kong.Plugin) []kong.Plugin {
+	_, i, found := lo.FindIndexOf(plugins, func(p kong.Plugin) bool {
+		return p.Name != nil && *p.Name == *plugin.Name
+	})
+	if !found {
+		return append(plugins, plugin)
+	}
+
+	existing, _ := plugins[i].Config["access"].([]string)
+	added, _ := plugin.Config["access"].([]string)
+	if len(existing) == 0 {
+		plugins[i].Config["access"] = added
+		return plugins
+	}
+	code := existing[len(existing)-1]
+	for _, c := range added {
+		code += "do\n" + c + "end\n"
+	}
+	// a copy is made, as the configuration can be shared with plugins of other routes.
+	access := append(append([]string{}, existing[:len(existing)-1]...), code)
+	config := plugins[i].Config.DeepCopy()
+	config["access"] = access
+	plugins[i].Config = config
+	return plugins
+}
+
+// luaStringLiteral returns a Lua string literal of the given value, escaping any character
+// that could terminate the literal or is not printable.
+func luaStringLiteral(value string) string {
+	var b strings.Builder
+	b.WriteByte('"')
+	for i := 0; i < len(value); i++ {
+		c := value[i]
+		switch {
+		case c == '"' || c == '\\':
+			b.WriteByte('\\')
+			b.WriteByte(c)
+		case c < 0x20 || c == 0x7f:
+			fmt.Fprintf(&b, "\\%03d", c)
+		default:
+			b.WriteByte(c)
+		}
+	}
+	b.WriteByte('"')
+	return b.String()
 }
 
 // generateRequestRedirectKongPlugin generates configurations of plugins to satisfy the specification
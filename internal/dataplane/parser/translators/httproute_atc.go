@@ -37,6 +37,10 @@ func GenerateKongExpressionRoutesFromHTTPRouteMatches(
 		ExpressionRoutes: true,
 	}
 
+	if err := ValidateHTTPRouteRuleFilters(translation.Matches, translation.Filters); err != nil {
+		return nil, err
+	}
+
 	if len(translation.Matches) == 0 {
 		if len(hostnames) == 0 {
 			r.Expression = kong.String(CatchAllHTTPExpression)
@@ -57,7 +61,8 @@ func GenerateKongExpressionRoutesFromHTTPRouteMatches(
 	}
 
 	// if we do not need to generate a kong route for each match, we OR matchers from all matches together.
-	routeMatcher := atc.And(atc.Or(generateMatchersFromHTTPRouteMatches(translation.Matches)...))
+	capturePrefixRemainder := HasURLRewriteReplacePrefixMatchFilter(translation.Filters)
+	routeMatcher := atc.And(atc.Or(generateMatchersFromHTTPRouteMatches(translation.Matches, capturePrefixRemainder)...))
 	// Add matcher from parent httproute (hostnames, SNIs) to be ANDed with the matcher from match.
 	matchersFromParent := matchersFromParentHTTPRoute(hostnames, ingressObjectInfo.Annotations)
 	for _, matcher := range matchersFromParent {
@@ -66,7 +71,10 @@ func GenerateKongExpressionRoutesFromHTTPRouteMatches(
 
 	atc.ApplyExpression(&r.Route, routeMatcher, 1)
 	// generate plugins.
-	plugins := GeneratePluginsFromHTTPRouteFilters(translation.Filters, "", tags)
+	plugins, err := GeneratePluginsFromHTTPRouteFilters(translation.Filters, "", tags)
+	if err != nil {
+		return nil, err
+	}
 	r.Plugins = plugins
 	return []kongstate.Route{r}, nil
 }
@@ -90,7 +98,7 @@ func generateKongExpressionRoutesWithRequestRedirectFilter(
 			ExpressionRoutes: true,
 		}
 		// generate matcher for this HTTPRoute Match.
-		matcher := atc.And(generateMatcherFromHTTPRouteMatch(match))
+		matcher := atc.And(generateMatcherFromHTTPRouteMatch(match, false))
 
 		// add matcher from parent httproute (hostnames, protocols, SNIs) to be ANDed with the matcher from match.
 		matchersFromParent := matchersFromParentHTTPRoute(hostnames, ingressObjectInfo.Annotations)
@@ -104,7 +112,10 @@ func generateKongExpressionRoutesWithRequestRedirectFilter(
 		if match.Path != nil && match.Path.Value != nil {
 			path = *match.Path.Value
 		}
-		plugins := GeneratePluginsFromHTTPRouteFilters(translation.Filters, path, tags)
+		plugins, err := GeneratePluginsFromHTTPRouteFilters(translation.Filters, path, tags)
+		if err != nil {
+			return nil, err
+		}
 		matchRoute.Plugins = plugins
 
 		routes = append(routes, matchRoute)
@@ -112,20 +123,28 @@ func generateKongExpressionRoutesWithRequestRedirectFilter(
 	return routes, nil
 }
 
-func generateMatchersFromHTTPRouteMatches(matches []gatewayapi.HTTPRouteMatch) []atc.Matcher {
+func generateMatchersFromHTTPRouteMatches(matches []gatewayapi.HTTPRouteMatch, capturePrefixRemainder bool) []atc.Matcher {
 	ret := make([]atc.Matcher, 0, len(matches))
 	for _, match := range matches {
-		matcher := generateMatcherFromHTTPRouteMatch(match)
+		matcher := generateMatcherFromHTTPRouteMatch(match, capturePrefixRemainder)
 		ret = append(ret, matcher)
 	}
 	return ret
 }
 
-func generateMatcherFromHTTPRouteMatch(match gatewayapi.HTTPRouteMatch) atc.Matcher {
+// generateMatcherFromHTTPRouteMatch generates a matcher from a single HTTPRoute match.
+// When capturePrefixRemainder is set, the part of the path following the matched path prefix
+// is captured, so it can be used by the plugin implementing the URLRewrite filter.
+func generateMatcherFromHTTPRouteMatch(match gatewayapi.HTTPRouteMatch, capturePrefixRemainder bool) atc.Matcher {
 	matcher := atc.And()
 
 	if match.Path != nil {
-		pathMatcher := pathMatcherFromHTTPPathMatch(match.Path)
+		var pathMatcher atc.Matcher
+		if capturePrefixRemainder {
+			pathMatcher = pathMatcherCapturingPrefixRemainderFromHTTPPathMatch(match.Path)
+		} else {
+			pathMatcher = pathMatcherFromHTTPPathMatch(match.Path)
+		}
 		matcher.And(pathMatcher)
 	}
 
@@ -183,6 +202,28 @@ func pathMatcherFromHTTPPathMatch(pathMatch *gatewayapi.HTTPPathMatch) atc.Match
 	return nil // should be unreachable
 }
 
+// pathMatcherCapturingPrefixRemainderFromHTTPPathMatch generates a path matcher for a PathPrefix match
+// that captures the remainder of the path following the prefix in the first capture group.
+// Path matches of other types are translated as usual.
+func pathMatcherCapturingPrefixRemainderFromHTTPPathMatch(pathMatch *gatewayapi.HTTPPathMatch) atc.Matcher {
+	if pathMatch.Type == nil || *pathMatch.Type != gatewayapi.PathMatchPathPrefix {
+		return pathMatcherFromHTTPPathMatch(pathMatch)
+	}
+
+	path := ""
+	if pathMatch.Value != nil {
+		path = strings.TrimSuffix(*pathMatch.Value, "/")
+	}
+	captureRemainder := atc.NewPredicateHTTPPath(atc.OpRegexMatch, "^"+KongPathRegexCapturingPrefixRemainder(path))
+	if path == "" {
+		return captureRemainder
+	}
+	return atc.Or(
+		atc.NewPredicateHTTPPath(atc.OpEqual, path),
+		captureRemainder,
+	)
+}
+
 func headerMatcherFromHTTPHeaderMatch(headerMatch gatewayapi.HTTPHeaderMatch) atc.Matcher {
 	matchType := gatewayapi.HeaderMatchExact
 	if headerMatch.Type != nil {
@@ -540,7 +581,7 @@ func compareSplitHTTPRouteMatchesRelativePriority(match1, match2 SplitHTTPRouteM
 // based kong route with assigned priority.
 func KongExpressionRouteFromHTTPRouteMatchWithPriority(
 	httpRouteMatchWithPriority SplitHTTPRouteMatchToKongRoutePriority,
-) kongstate.Route {
+) (kongstate.Route, error) {
 	match := httpRouteMatchWithPriority.Match
 	httproute := httpRouteMatchWithPriority.Match.Source
 	tags := util.GenerateTagsForObject(httproute)
@@ -574,7 +615,11 @@ func KongExpressionRouteFromHTTPRouteMatchWithPriority(
 	hostnames := []string{match.Hostname}
 	matchers := matchersFromParentHTTPRoute(hostnames, httproute.Annotations)
 	// generate ATC matcher from split HTTPRouteMatch itself.
-	matchers = append(matchers, generateMatcherFromHTTPRouteMatch(match.Match))
+	var filters []gatewayapi.HTTPRouteFilter
+	if match.RuleIndex < len(httproute.Spec.Rules) {
+		filters = httproute.Spec.Rules[match.RuleIndex].Filters
+	}
+	matchers = append(matchers, generateMatcherFromHTTPRouteMatch(match.Match, HasURLRewriteReplacePrefixMatchFilter(filters)))
 
 	atc.ApplyExpression(&r.Route, atc.And(matchers...), httpRouteMatchWithPriority.Priority)
 
@@ -587,17 +632,24 @@ func KongExpressionRouteFromHTTPRouteMatchWithPriority(
 	// translate filters in the rule.
 	if match.RuleIndex < len(httproute.Spec.Rules) {
 		rule := httproute.Spec.Rules[match.RuleIndex]
+		if err := ValidateHTTPRouteRuleFilters(rule.Matches, rule.Filters); err != nil {
+			return kongstate.Route{}, err
+		}
+
 		path := ""
 		// since we have one match to translate, we do not need to generate request redirect for each match.
 		if match.Match.Path != nil && match.Match.Path.Value != nil {
 			path = *match.Match.Path.Value
 		}
 
-		plugins := GeneratePluginsFromHTTPRouteFilters(rule.Filters, path, tags)
+		plugins, err := GeneratePluginsFromHTTPRouteFilters(rule.Filters, path, tags)
+		if err != nil {
+			return kongstate.Route{}, err
+		}
 		r.Plugins = plugins
 	}
 
-	return r
+	return r, nil
 }
 
 // KongServiceNameFromSplitHTTPRouteMatch generates service name from split HTTPRoute match.
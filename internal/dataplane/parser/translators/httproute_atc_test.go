@@ -212,6 +212,52 @@ func TestGenerateKongExpressionRoutesFromHTTPRouteMatches(t *testing.T) {
 				},
 			},
 		},
+		{
+			name:              "multiple prefix matches with URL rewrite filter replacing prefix match",
+			routeName:         "url_rewrite.default.0.0",
+			ingressObjectInfo: util.K8sObjectInfo{},
+			matches: []gatewayapi.HTTPRouteMatch{
+				builder.NewHTTPRouteMatch().WithPathPrefix("/prefix/0").Build(),
+				builder.NewHTTPRouteMatch().WithPathPrefix("/prefix/1/").Build(),
+			},
+			filters: []gatewayapi.HTTPRouteFilter{
+				builder.NewHTTPRouteURLRewriteFilter().WithURLRewritePrefixMatch("/new").Build(),
+			},
+			expectedRoutes: []kongstate.Route{
+				{
+					Route: kong.Route{
+						Name:         kong.String("url_rewrite.default.0.0"),
+						PreserveHost: kong.Bool(true),
+						StripPath:    kong.Bool(false),
+						Expression:   kong.String(`((http.path == "/prefix/0") || (http.path ~ "^/prefix/0(/.*)")) || ((http.path == "/prefix/1") || (http.path ~ "^/prefix/1(/.*)"))`),
+						Priority:     kong.Int(1),
+					},
+					Plugins: []kong.Plugin{
+						{
+							Name: kong.String("request-transformer"),
+							Config: kong.Configuration{
+								"replace": map[string]interface{}{
+									"uri": `/new$(uri_captures[1] == nil and "" or uri_captures[1])`,
+								},
+							},
+						},
+					},
+					ExpressionRoutes: true,
+				},
+			},
+		},
+		{
+			name:              "exact match with URL rewrite filter replacing prefix match",
+			routeName:         "url_rewrite.default.0.0",
+			ingressObjectInfo: util.K8sObjectInfo{},
+			matches: []gatewayapi.HTTPRouteMatch{
+				builder.NewHTTPRouteMatch().WithPathExact("/exact").Build(),
+			},
+			filters: []gatewayapi.HTTPRouteFilter{
+				builder.NewHTTPRouteURLRewriteFilter().WithURLRewritePrefixMatch("/new").Build(),
+			},
+			expectedError: ErrRouteValidationReplacePrefixMatchRequiresPathPrefix,
+		},
 		{
 			name:      "routes with annotations to set protocols and SNIs",
 			routeName: "annotations_protocol_sni.default.0.0",
@@ -278,9 +324,10 @@ func TestGenerateKongExpressionRoutesFromHTTPRouteMatches(t *testing.T) {
 
 func TestGenerateMatcherFromHTTPRouteMatch(t *testing.T) {
 	testCases := []struct {
-		name       string
-		match      gatewayapi.HTTPRouteMatch
-		expression string
+		name                   string
+		match                  gatewayapi.HTTPRouteMatch
+		capturePrefixRemainder bool
+		expression             string
 	}{
 		{
 			name:       "empty prefix path match",
@@ -326,12 +373,30 @@ func TestGenerateMatcherFromHTTPRouteMatch(t *testing.T) {
 				Build(),
 			expression: `((http.path == "/prefix/0") || (http.path ^= "/prefix/0/")) && ((http.queries.foo == "bar") && (http.queries.id ~ "[0-9a-z-]+"))`,
 		},
+		{
+			name:                   "empty prefix path match capturing prefix remainder",
+			match:                  builder.NewHTTPRouteMatch().WithPathPrefix("/").Build(),
+			capturePrefixRemainder: true,
+			expression:             `http.path ~ "^(/.*)"`,
+		},
+		{
+			name:                   "non-empty prefix path match capturing prefix remainder",
+			match:                  builder.NewHTTPRouteMatch().WithPathPrefix("/prefix.0/").Build(),
+			capturePrefixRemainder: true,
+			expression:             `(http.path == "/prefix.0") || (http.path ~ "^/prefix\\.0(/.*)")`,
+		},
+		{
+			name:                   "exact path match is not affected by capturing prefix remainder",
+			match:                  builder.NewHTTPRouteMatch().WithPathExact("/exact/0").Build(),
+			capturePrefixRemainder: true,
+			expression:             `http.path == "/exact/0"`,
+		},
 	}
 
 	for _, tc := range testCases {
 		tc := tc
 		t.Run(tc.name, func(t *testing.T) {
-			require.Equal(t, tc.expression, generateMatcherFromHTTPRouteMatch(tc.match).Expression())
+			require.Equal(t, tc.expression, generateMatcherFromHTTPRouteMatch(tc.match, tc.capturePrefixRemainder).Expression())
 		})
 	}
 }
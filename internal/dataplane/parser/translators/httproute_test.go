@@ -6,8 +6,10 @@ import (
 	"github.com/kong/go-kong/kong"
 	"github.com/samber/lo"
 	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	"github.com/kong/kubernetes-ingress-controller/v2/internal/gatewayapi"
+	"github.com/kong/kubernetes-ingress-controller/v2/internal/util/builder"
 )
 
 func TestGeneratePluginsFromHTTPRouteFilters(t *testing.T) {
@@ -16,6 +18,7 @@ func TestGeneratePluginsFromHTTPRouteFilters(t *testing.T) {
 		filters         []gatewayapi.HTTPRouteFilter
 		path            string
 		expectedPlugins []kong.Plugin
+		expectedErr     bool
 	}{
 		{
 			name:            "no filters",
@@ -153,13 +156,365 @@ func TestGeneratePluginsFromHTTPRouteFilters(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "URL rewrite replacing hostname and full path",
+			filters: []gatewayapi.HTTPRouteFilter{
+				builder.NewHTTPRouteURLRewriteFilter().
+					WithURLRewriteHostname("example.org").
+					WithURLRewriteFullPath("/full/path").
+					Build(),
+			},
+			expectedPlugins: []kong.Plugin{
+				{
+					Name: kong.String("request-transformer"),
+					Config: kong.Configuration{
+						"replace": map[string]interface{}{
+							"headers": []string{"host:example.org"},
+							"uri":     "/full/path",
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "URL rewrite replacing prefix match",
+			filters: []gatewayapi.HTTPRouteFilter{
+				builder.NewHTTPRouteURLRewriteFilter().WithURLRewritePrefixMatch("/new/").Build(),
+			},
+			expectedPlugins: []kong.Plugin{
+				{
+					Name: kong.String("request-transformer"),
+					Config: kong.Configuration{
+						"replace": map[string]interface{}{
+							"uri": `/new$(uri_captures[1] == nil and "" or uri_captures[1])`,
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "URL rewrite replacing prefix match with /",
+			filters: []gatewayapi.HTTPRouteFilter{
+				builder.NewHTTPRouteURLRewriteFilter().WithURLRewritePrefixMatch("/").Build(),
+			},
+			expectedPlugins: []kong.Plugin{
+				{
+					Name: kong.String("request-transformer"),
+					Config: kong.Configuration{
+						"replace": map[string]interface{}{
+							"uri": `$(uri_captures[1] == nil and "/" or uri_captures[1])`,
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "URL rewrite merged with request header modifier",
+			filters: []gatewayapi.HTTPRouteFilter{
+				builder.NewHTTPRouteURLRewriteFilter().
+					WithURLRewriteHostname("example.org").
+					WithURLRewriteFullPath("/full/path").
+					Build(),
+				builder.NewHTTPRouteRequestHeaderModifierFilter().
+					WithRequestHeaderSet([]gatewayapi.HTTPHeader{{Name: "header-to-set", Value: "bar"}}).
+					Build(),
+			},
+			expectedPlugins: []kong.Plugin{
+				{
+					Name: kong.String("request-transformer"),
+					Config: kong.Configuration{
+						"add": map[string][]string{
+							"headers": {"header-to-set:bar"},
+						},
+						"replace": map[string]interface{}{
+							"headers": []string{"header-to-set:bar", "host:example.org"},
+							"uri":     "/full/path",
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "URL rewrite with missing full path",
+			filters: []gatewayapi.HTTPRouteFilter{
+				{
+					Type: gatewayapi.HTTPRouteFilterURLRewrite,
+					URLRewrite: &gatewayapi.HTTPURLRewriteFilter{
+						Path: &gatewayapi.HTTPPathModifier{
+							Type: gatewayapi.FullPathHTTPPathModifier,
+						},
+					},
+				},
+			},
+			expectedErr: true,
+		},
 	}
 
 	for _, tc := range testCases {
 		tc := tc
-		plugins := GeneratePluginsFromHTTPRouteFilters(tc.filters, tc.path, nil)
 		t.Run(tc.name, func(t *testing.T) {
+			plugins, err := GeneratePluginsFromHTTPRouteFilters(tc.filters, tc.path, nil)
+			if tc.expectedErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
 			require.Equal(t, tc.expectedPlugins, plugins)
 		})
 	}
 }
+
+func TestValidateHTTPRouteRuleFilters(t *testing.T) {
+	testCases := []struct {
+		name        string
+		matches     []gatewayapi.HTTPRouteMatch
+		filters     []gatewayapi.HTTPRouteFilter
+		expectedErr error
+	}{
+		{
+			name: "URL rewrite replacing full path with any matches",
+			matches: []gatewayapi.HTTPRouteMatch{
+				builder.NewHTTPRouteMatch().WithPathExact("/exact").Build(),
+				builder.NewHTTPRouteMatch().WithPathRegex("/regex/[a-z]+").Build(),
+			},
+			filters: []gatewayapi.HTTPRouteFilter{
+				builder.NewHTTPRouteURLRewriteFilter().WithURLRewriteFullPath("/full").Build(),
+			},
+		},
+		{
+			name: "URL rewrite replacing prefix match with prefix matches",
+			matches: []gatewayapi.HTTPRouteMatch{
+				builder.NewHTTPRouteMatch().WithPathPrefix("/prefix/0").Build(),
+				builder.NewHTTPRouteMatch().WithPathPrefix("/prefix/1").Build(),
+			},
+			filters: []gatewayapi.HTTPRouteFilter{
+				builder.NewHTTPRouteURLRewriteFilter().WithURLRewritePrefixMatch("/new").Build(),
+			},
+		},
+		{
+			name: "URL rewrite replacing prefix match with exact match",
+			matches: []gatewayapi.HTTPRouteMatch{
+				builder.NewHTTPRouteMatch().WithPathPrefix("/prefix/0").Build(),
+				builder.NewHTTPRouteMatch().WithPathExact("/exact").Build(),
+			},
+			filters: []gatewayapi.HTTPRouteFilter{
+				builder.NewHTTPRouteURLRewriteFilter().WithURLRewritePrefixMatch("/new").Build(),
+			},
+			expectedErr: ErrRouteValidationReplacePrefixMatchRequiresPathPrefix,
+		},
+		{
+			name: "URL rewrite replacing prefix match without matches",
+			filters: []gatewayapi.HTTPRouteFilter{
+				builder.NewHTTPRouteURLRewriteFilter().WithURLRewritePrefixMatch("/new").Build(),
+			},
+			expectedErr: ErrRouteValidationReplacePrefixMatchRequiresPathPrefix,
+		},
+		{
+			name: "URL rewrite with request redirect",
+			matches: []gatewayapi.HTTPRouteMatch{
+				builder.NewHTTPRouteMatch().WithPathExact("/exact").Build(),
+			},
+			filters: []gatewayapi.HTTPRouteFilter{
+				builder.NewHTTPRouteURLRewriteFilter().WithURLRewriteHostname("example.org").Build(),
+				builder.NewHTTPRouteRequestRedirectFilter().WithRequestRedirectStatusCode(301).Build(),
+			},
+			expectedErr: ErrRouteValidationRedirectAndURLRewriteFilters,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			err := ValidateHTTPRouteRuleFilters(tc.matches, tc.filters)
+			if tc.expectedErr != nil {
+				require.ErrorIs(t, err, tc.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestGenerateQueryParamsGuardPlugin(t *testing.T) {
+	plugin := GenerateQueryParamsGuardPlugin([]gatewayapi.HTTPQueryParamMatch{
+		{
+			Name:  "foo",
+			Value: "b\"a\r",
+		},
+		{
+			Type:  lo.ToPtr(gatewayapi.QueryParamMatchRegularExpression),
+			Name:  "id",
+			Value: `^\d+$`,
+		},
+		{
+			// Only the first entry of equivalent query param names is considered.
+			Name:  "foo",
+			Value: "ignored",
+		},
+	}, kong.StringSlice("tag"))
+
+	expectedCode := `local args = kong.request.get_query()
+local function arg(name)
+  local v = args[name]
+  if type(v) == "table" then v = v[1] end
+  if v == true then v = "" end
+  return v
+end
+if arg("foo") ~= "b\"a\013" then
+  return kong.response.exit(404, { message = "no Route matched with those values" })
+end
+if not arg("id") or not ngx.re.find(arg("id"), "^\\d+$", "jo") then
+  return kong.response.exit(404, { message = "no Route matched with those values" })
+end
+`
+	require.Equal(t, kong.Plugin{
+		Name: kong.String("pre-function"),
+		Config: kong.Configuration{
+			"access": []string{expectedCode},
+		},
+		Tags: kong.StringSlice("tag"),
+	}, plugin)
+}
+
+func TestKongServiceNameForRequestMirror(t *testing.T) {
+	httproute := &gatewayapi.HTTPRoute{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "route"}}
+
+	require.Equal(t, "httproute.default.route.mirror.default.mirror.8080",
+		KongServiceNameForRequestMirror(httproute, gatewayapi.BackendObjectReference{
+			Name: "mirror",
+			Port: lo.ToPtr(gatewayapi.PortNumber(8080)),
+		}),
+	)
+	require.Equal(t, "httproute.default.route.mirror.other.mirror._",
+		KongServiceNameForRequestMirror(httproute, gatewayapi.BackendObjectReference{
+			Name:      "mirror",
+			Namespace: lo.ToPtr(gatewayapi.Namespace("other")),
+		}),
+	)
+}
+
+func TestGenerateRequestMirrorPlugin(t *testing.T) {
+	plugin := GenerateRequestMirrorPlugin([]string{"mirror-a", "mirror-b"}, kong.StringSlice("tag"))
+
+	expectedCode := `local ok, err = pcall(function()
+  local balancer = require("kong.runloop.balancer")
+  local http = require("resty.http")
+  local method = kong.request.get_method()
+  local path = kong.request.get_path_with_query()
+  local headers = kong.request.get_headers()
+  local body = kong.request.get_raw_body()
+  local function mirror(premature, target)
+    if premature then return end
+    local _, err = http.new():request_uri("http://" .. target.ip .. ":" .. target.port .. path, {
+      method = method, headers = headers, body = body,
+    })
+    if err then kong.log.warn("failed to mirror request to ", target.host, ": ", err) end
+  end
+  for _, upstream in ipairs({"mirror-a", "mirror-b"}) do
+    local target = { type = "name", host = upstream, port = 80, try_count = 0, retries = 0 }
+    local ok, err = balancer.execute(target, ngx.ctx)
+    if ok then ok, err = ngx.timer.at(0, mirror, target) end
+    if not ok then kong.log.warn("failed to mirror request to ", upstream, ": ", err) end
+  end
+end)
+if not ok then kong.log.err("failed to mirror request: ", err) end
+`
+	require.Equal(t, kong.Plugin{
+		Name: kong.String("pre-function"),
+		Config: kong.Configuration{
+			"access": []string{expectedCode},
+		},
+		Tags: kong.StringSlice("tag"),
+	}, plugin)
+}
+
+func TestAddPreFunctionPlugin(t *testing.T) {
+	mirrorPlugin := kong.Plugin{
+		Name:   kong.String("pre-function"),
+		Config: kong.Configuration{"access": []string{"mirror()\n"}},
+	}
+
+	t.Run("plugin is appended when there's no pre-function plugin", func(t *testing.T) {
+		plugins := AddPreFunctionPlugin([]kong.Plugin{{Name: kong.String("request-transformer")}}, mirrorPlugin)
+		require.Len(t, plugins, 2)
+		require.Equal(t, mirrorPlugin, plugins[1])
+	})
+
+	t.Run("code is appended to the code of an existing pre-function plugin", func(t *testing.T) {
+		guardConfig := kong.Configuration{"access": []string{"if not matched() then return exit() end\n"}}
+		plugins := AddPreFunctionPlugin([]kong.Plugin{{
+			Name:   kong.String("pre-function"),
+			Config: guardConfig,
+		}}, mirrorPlugin)
+		require.Len(t, plugins, 1)
+		require.Equal(t, []string{"if not matched() then return exit() end\ndo\nmirror()\nend\n"}, plugins[0].Config["access"])
+		require.Equal(t, []string{"if not matched() then return exit() end\n"}, guardConfig["access"],
+			"configuration of the existing plugin is not modified in place")
+	})
+}
+
+func TestKongServiceTimeoutFromHTTPRouteTimeouts(t *testing.T) {
+	testCases := []struct {
+		name              string
+		timeouts          *gatewayapi.HTTPRouteTimeouts
+		expectedTimeout   *int
+		expectedErrorPart string
+	}{
+		{
+			name: "no timeouts",
+		},
+		{
+			name:     "empty timeouts",
+			timeouts: &gatewayapi.HTTPRouteTimeouts{},
+		},
+		{
+			name: "request timeout",
+			timeouts: &gatewayapi.HTTPRouteTimeouts{
+				Request: lo.ToPtr(gatewayapi.Duration("1h30m")),
+			},
+			expectedTimeout: lo.ToPtr(5400000),
+		},
+		{
+			name: "backendRequest timeout takes precedence over request timeout",
+			timeouts: &gatewayapi.HTTPRouteTimeouts{
+				Request:        lo.ToPtr(gatewayapi.Duration("10s")),
+				BackendRequest: lo.ToPtr(gatewayapi.Duration("500ms")),
+			},
+			expectedTimeout: lo.ToPtr(500),
+		},
+		{
+			name: "zero timeout disables the timeout",
+			timeouts: &gatewayapi.HTTPRouteTimeouts{
+				Request: lo.ToPtr(gatewayapi.Duration("0s")),
+			},
+			expectedTimeout: lo.ToPtr(KongServiceMaxTimeout),
+		},
+		{
+			name: "timeout exceeding the Kong maximum is capped",
+			timeouts: &gatewayapi.HTTPRouteTimeouts{
+				BackendRequest: lo.ToPtr(gatewayapi.Duration("99999h")),
+			},
+			expectedTimeout: lo.ToPtr(KongServiceMaxTimeout),
+		},
+		{
+			name: "invalid timeout",
+			timeouts: &gatewayapi.HTTPRouteTimeouts{
+				BackendRequest: lo.ToPtr(gatewayapi.Duration("5 seconds")),
+			},
+			expectedErrorPart: `invalid backendRequest timeout "5 seconds"`,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			timeout, err := KongServiceTimeoutFromHTTPRouteTimeouts(tc.timeouts)
+			if tc.expectedErrorPart != "" {
+				require.ErrorContains(t, err, tc.expectedErrorPart)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.expectedTimeout, timeout)
+		})
+	}
+}
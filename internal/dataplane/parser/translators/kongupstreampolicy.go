@@ -1,12 +1,23 @@
 package translators
 
 import (
+	"fmt"
+	"strconv"
+	"strings"
+
 	"github.com/kong/go-kong/kong"
 	"github.com/samber/lo"
 
+	"github.com/kong/kubernetes-ingress-controller/v2/internal/annotations"
+	"github.com/kong/kubernetes-ingress-controller/v2/internal/gatewayapi"
 	kongv1beta1 "github.com/kong/kubernetes-ingress-controller/v2/pkg/apis/configuration/v1beta1"
 )
 
+const (
+	// KongUpstreamAlgorithmConsistentHashing is the load balancing algorithm used for session persistence.
+	KongUpstreamAlgorithmConsistentHashing = "consistent-hashing"
+)
+
 // TranslateKongUpstreamPolicy translates KongUpstreamPolicySpec to kong.Upstream. It makes assumption that
 // KongUpstreamPolicySpec has been validated on the API level.
 func TranslateKongUpstreamPolicy(policy kongv1beta1.KongUpstreamPolicySpec) *kong.Upstream {
@@ -30,6 +41,155 @@ func TranslateKongUpstreamPolicy(policy kongv1beta1.KongUpstreamPolicySpec) *kon
 	}
 }
 
+// ValidateKongUpstreamPolicy checks constraints of KongUpstreamPolicySpec that aren't enforced on the API level
+// and that have to be met for the translated kong.Upstream to be accepted by Kong.
+func ValidateKongUpstreamPolicy(policy kongv1beta1.KongUpstreamPolicySpec) error {
+	if policy.HashOn == nil && policy.HashOnFallback == nil {
+		return nil
+	}
+	if policy.Algorithm == nil || *policy.Algorithm != KongUpstreamAlgorithmConsistentHashing {
+		return ErrKongUpstreamPolicyHashRequiresConsistentHashing
+	}
+	if policy.HashOn == nil {
+		return ErrKongUpstreamPolicyHashFallbackWithoutHashOn
+	}
+	if err := validateKongUpstreamHash(*policy.HashOn); err != nil {
+		return fmt.Errorf("invalid hashOn: %w", err)
+	}
+	if policy.HashOnFallback != nil {
+		if policy.HashOn.Cookie != nil || policy.HashOnFallback.Cookie != nil {
+			return ErrKongUpstreamPolicyHashFallbackWithCookie
+		}
+		if err := validateKongUpstreamHash(*policy.HashOnFallback); err != nil {
+			return fmt.Errorf("invalid hashOnFallback: %w", err)
+		}
+	}
+	return nil
+}
+
+// SessionPersistenceForHTTPRouteRules returns the session persistence requested by the annotations of an HTTPRoute
+// for each of its rules, as the hash the Kong upstreams generated for the rule have to use. The returned slice has
+// an entry for each rule, which is nil for rules without session persistence. It returns nil if no session
+// persistence is requested.
+func SessionPersistenceForHTTPRouteRules(httproute *gatewayapi.HTTPRoute) ([]*kongv1beta1.KongUpstreamHash, error) {
+	hash, err := sessionPersistenceFromAnnotations(httproute.Annotations)
+	if err != nil {
+		return nil, err
+	}
+	ruleIndexes, hasRuleIndexes := annotations.ExtractSessionPersistenceRules(httproute.Annotations)
+	if hash == nil {
+		if hasRuleIndexes {
+			return nil, ErrSessionPersistenceRulesWithoutCookieOrHeader
+		}
+		return nil, nil
+	}
+
+	rules := make([]*kongv1beta1.KongUpstreamHash, len(httproute.Spec.Rules))
+	if !hasRuleIndexes {
+		for i := range rules {
+			rules[i] = hash
+		}
+		return rules, nil
+	}
+	for _, ruleIndex := range ruleIndexes {
+		i, err := strconv.Atoi(strings.TrimSpace(ruleIndex))
+		if err != nil || i < 0 || i >= len(rules) {
+			return nil, fmt.Errorf("%w: %q", ErrSessionPersistenceInvalidRule, ruleIndex)
+		}
+		rules[i] = hash
+	}
+	return rules, nil
+}
+
+func sessionPersistenceFromAnnotations(anns map[string]string) (*kongv1beta1.KongUpstreamHash, error) {
+	cookie, hasCookie := annotations.ExtractSessionPersistenceCookie(anns)
+	cookiePath, hasCookiePath := annotations.ExtractSessionPersistenceCookiePath(anns)
+	header, hasHeader := annotations.ExtractSessionPersistenceHeader(anns)
+
+	switch {
+	case hasCookie && hasHeader:
+		return nil, ErrSessionPersistenceCookieAndHeader
+	case hasCookie:
+		if cookie == "" {
+			return nil, ErrSessionPersistenceEmptyName
+		}
+		hash := &kongv1beta1.KongUpstreamHash{Cookie: lo.ToPtr(cookie)}
+		if hasCookiePath {
+			hash.CookiePath = lo.ToPtr(cookiePath)
+		}
+		return hash, nil
+	case hasHeader:
+		if header == "" {
+			return nil, ErrSessionPersistenceEmptyName
+		}
+		if hasCookiePath {
+			return nil, ErrSessionPersistenceCookiePathWithoutCookie
+		}
+		return &kongv1beta1.KongUpstreamHash{Header: lo.ToPtr(header)}, nil
+	case hasCookiePath:
+		return nil, ErrSessionPersistenceCookiePathWithoutCookie
+	default:
+		return nil, nil
+	}
+}
+
+// TranslateSessionPersistence translates session persistence to the consistent-hashing kong.Upstream
+// implementing it.
+func TranslateSessionPersistence(hash kongv1beta1.KongUpstreamHash) *kong.Upstream {
+	return TranslateKongUpstreamPolicy(kongv1beta1.KongUpstreamPolicySpec{
+		Algorithm: lo.ToPtr(KongUpstreamAlgorithmConsistentHashing),
+		HashOn:    &hash,
+	})
+}
+
+// ApplySessionPersistence configures the upstream to use the consistent-hashing load balancing
+// implementing session persistence with the given hash.
+func ApplySessionPersistence(upstream *kong.Upstream, hash kongv1beta1.KongUpstreamHash) {
+	sessionPersistence := TranslateSessionPersistence(hash)
+	upstream.Algorithm = sessionPersistence.Algorithm
+	upstream.HashOn = sessionPersistence.HashOn
+	upstream.HashOnHeader = sessionPersistence.HashOnHeader
+	upstream.HashOnCookie = sessionPersistence.HashOnCookie
+	upstream.HashOnCookiePath = sessionPersistence.HashOnCookiePath
+}
+
+// ValidateSessionPersistenceForUpstream checks that the load balancing explicitly configured for the upstream
+// (e.g. by a KongIngress) doesn't conflict with the consistent-hashing implementing session persistence with
+// the given hash. Explicit settings matching the ones session persistence sets don't conflict.
+func ValidateSessionPersistenceForUpstream(upstream kong.Upstream, hash kongv1beta1.KongUpstreamHash) error {
+	sessionPersistence := TranslateSessionPersistence(hash)
+	differs := func(explicit, desired *string) bool {
+		return explicit != nil && (desired == nil || *explicit != *desired)
+	}
+	hashOn := upstream.HashOn
+	if hashOn != nil && *hashOn == "none" {
+		hashOn = nil
+	}
+	if differs(upstream.Algorithm, sessionPersistence.Algorithm) ||
+		differs(hashOn, sessionPersistence.HashOn) ||
+		differs(upstream.HashOnHeader, sessionPersistence.HashOnHeader) ||
+		differs(upstream.HashOnCookie, sessionPersistence.HashOnCookie) ||
+		differs(upstream.HashOnCookiePath, sessionPersistence.HashOnCookiePath) ||
+		upstream.HashOnQueryArg != nil ||
+		upstream.HashOnURICapture != nil {
+		return ErrSessionPersistenceUpstreamConflict
+	}
+	return nil
+}
+
+func validateKongUpstreamHash(hash kongv1beta1.KongUpstreamHash) error {
+	sources := lo.Filter([]*string{hash.Header, hash.Cookie, hash.QueryArg, hash.URICapture}, func(s *string, _ int) bool {
+		return s != nil
+	})
+	if len(sources) != 1 {
+		return ErrKongUpstreamPolicyHashSingleSource
+	}
+	if hash.CookiePath != nil && hash.Cookie == nil {
+		return ErrKongUpstreamPolicyHashCookiePathWithoutCookie
+	}
+	return nil
+}
+
 func translateHashOn(hashOn *kongv1beta1.KongUpstreamHash) *string {
 	if hashOn == nil {
 		return nil
@@ -6,8 +6,10 @@ import (
 	"github.com/kong/go-kong/kong"
 	"github.com/samber/lo"
 	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	"github.com/kong/kubernetes-ingress-controller/v2/internal/dataplane/parser/translators"
+	"github.com/kong/kubernetes-ingress-controller/v2/internal/gatewayapi"
 	kongv1beta1 "github.com/kong/kubernetes-ingress-controller/v2/pkg/apis/configuration/v1beta1"
 )
 
@@ -169,3 +171,198 @@ func TestTranslateKongUpstreamPolicy(t *testing.T) {
 		})
 	}
 }
+
+func TestSessionPersistenceForHTTPRouteRules(t *testing.T) {
+	cookie := &kongv1beta1.KongUpstreamHash{
+		Cookie:     lo.ToPtr("session"),
+		CookiePath: lo.ToPtr("/app"),
+	}
+	header := &kongv1beta1.KongUpstreamHash{
+		Header: lo.ToPtr("x-session"),
+	}
+
+	testCases := []struct {
+		name          string
+		annotations   map[string]string
+		expectedRules []*kongv1beta1.KongUpstreamHash
+		expectedError error
+	}{
+		{
+			name: "no session persistence",
+		},
+		{
+			name: "cookie for all rules",
+			annotations: map[string]string{
+				"konghq.com/session-persistence-cookie":      "session",
+				"konghq.com/session-persistence-cookie-path": "/app",
+			},
+			expectedRules: []*kongv1beta1.KongUpstreamHash{cookie, cookie, cookie},
+		},
+		{
+			name: "header for selected rules",
+			annotations: map[string]string{
+				"konghq.com/session-persistence-header": "x-session",
+				"konghq.com/session-persistence-rules":  "0, 2",
+			},
+			expectedRules: []*kongv1beta1.KongUpstreamHash{header, nil, header},
+		},
+		{
+			name: "cookie and header",
+			annotations: map[string]string{
+				"konghq.com/session-persistence-cookie": "session",
+				"konghq.com/session-persistence-header": "x-session",
+			},
+			expectedError: translators.ErrSessionPersistenceCookieAndHeader,
+		},
+		{
+			name: "cookie path without cookie",
+			annotations: map[string]string{
+				"konghq.com/session-persistence-cookie-path": "/app",
+			},
+			expectedError: translators.ErrSessionPersistenceCookiePathWithoutCookie,
+		},
+		{
+			name: "empty header name",
+			annotations: map[string]string{
+				"konghq.com/session-persistence-header": "",
+			},
+			expectedError: translators.ErrSessionPersistenceEmptyName,
+		},
+		{
+			name: "rules without cookie or header",
+			annotations: map[string]string{
+				"konghq.com/session-persistence-rules": "0",
+			},
+			expectedError: translators.ErrSessionPersistenceRulesWithoutCookieOrHeader,
+		},
+		{
+			name: "rule out of range",
+			annotations: map[string]string{
+				"konghq.com/session-persistence-header": "x-session",
+				"konghq.com/session-persistence-rules":  "3",
+			},
+			expectedError: translators.ErrSessionPersistenceInvalidRule,
+		},
+		{
+			name: "rule not being an index",
+			annotations: map[string]string{
+				"konghq.com/session-persistence-header": "x-session",
+				"konghq.com/session-persistence-rules":  "first",
+			},
+			expectedError: translators.ErrSessionPersistenceInvalidRule,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			httproute := &gatewayapi.HTTPRoute{
+				ObjectMeta: metav1.ObjectMeta{Annotations: tc.annotations},
+				Spec: gatewayapi.HTTPRouteSpec{
+					Rules: make([]gatewayapi.HTTPRouteRule, 3),
+				},
+			}
+			rules, err := translators.SessionPersistenceForHTTPRouteRules(httproute)
+			require.ErrorIs(t, err, tc.expectedError)
+			require.Equal(t, tc.expectedRules, rules)
+		})
+	}
+}
+
+func TestTranslateSessionPersistence(t *testing.T) {
+	require.Equal(t, &kong.Upstream{
+		Algorithm:    lo.ToPtr("consistent-hashing"),
+		HashOn:       lo.ToPtr("header"),
+		HashOnHeader: lo.ToPtr("x-session"),
+	}, translators.TranslateSessionPersistence(kongv1beta1.KongUpstreamHash{Header: lo.ToPtr("x-session")}))
+}
+
+func TestValidateKongUpstreamPolicy(t *testing.T) {
+	testCases := []struct {
+		name          string
+		policy        kongv1beta1.KongUpstreamPolicySpec
+		expectedError error
+	}{
+		{
+			name: "policy without hashing",
+			policy: kongv1beta1.KongUpstreamPolicySpec{
+				Algorithm: lo.ToPtr("round-robin"),
+				Slots:     lo.ToPtr(100),
+			},
+		},
+		{
+			name: "consistent hashing with a fallback",
+			policy: kongv1beta1.KongUpstreamPolicySpec{
+				Algorithm:      lo.ToPtr("consistent-hashing"),
+				HashOn:         &kongv1beta1.KongUpstreamHash{Header: lo.ToPtr("x-user")},
+				HashOnFallback: &kongv1beta1.KongUpstreamHash{QueryArg: lo.ToPtr("user")},
+			},
+		},
+		{
+			name: "hashing with another algorithm",
+			policy: kongv1beta1.KongUpstreamPolicySpec{
+				Algorithm: lo.ToPtr("least-connections"),
+				HashOn:    &kongv1beta1.KongUpstreamHash{Header: lo.ToPtr("x-user")},
+			},
+			expectedError: translators.ErrKongUpstreamPolicyHashRequiresConsistentHashing,
+		},
+		{
+			name: "hashing without an algorithm",
+			policy: kongv1beta1.KongUpstreamPolicySpec{
+				HashOn: &kongv1beta1.KongUpstreamHash{Header: lo.ToPtr("x-user")},
+			},
+			expectedError: translators.ErrKongUpstreamPolicyHashRequiresConsistentHashing,
+		},
+		{
+			name: "fallback without hashOn",
+			policy: kongv1beta1.KongUpstreamPolicySpec{
+				Algorithm:      lo.ToPtr("consistent-hashing"),
+				HashOnFallback: &kongv1beta1.KongUpstreamHash{Header: lo.ToPtr("x-user")},
+			},
+			expectedError: translators.ErrKongUpstreamPolicyHashFallbackWithoutHashOn,
+		},
+		{
+			name: "hash with multiple sources",
+			policy: kongv1beta1.KongUpstreamPolicySpec{
+				Algorithm: lo.ToPtr("consistent-hashing"),
+				HashOn: &kongv1beta1.KongUpstreamHash{
+					Header: lo.ToPtr("x-user"),
+					Cookie: lo.ToPtr("user"),
+				},
+			},
+			expectedError: translators.ErrKongUpstreamPolicyHashSingleSource,
+		},
+		{
+			name: "cookie path without a cookie",
+			policy: kongv1beta1.KongUpstreamPolicySpec{
+				Algorithm: lo.ToPtr("consistent-hashing"),
+				HashOn: &kongv1beta1.KongUpstreamHash{
+					Header:     lo.ToPtr("x-user"),
+					CookiePath: lo.ToPtr("/"),
+				},
+			},
+			expectedError: translators.ErrKongUpstreamPolicyHashCookiePathWithoutCookie,
+		},
+		{
+			name: "fallback with cookie hashing",
+			policy: kongv1beta1.KongUpstreamPolicySpec{
+				Algorithm:      lo.ToPtr("consistent-hashing"),
+				HashOn:         &kongv1beta1.KongUpstreamHash{Cookie: lo.ToPtr("user")},
+				HashOnFallback: &kongv1beta1.KongUpstreamHash{Header: lo.ToPtr("x-user")},
+			},
+			expectedError: translators.ErrKongUpstreamPolicyHashFallbackWithCookie,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			err := translators.ValidateKongUpstreamPolicy(tc.policy)
+			if tc.expectedError == nil {
+				require.NoError(t, err)
+				return
+			}
+			require.ErrorIs(t, err, tc.expectedError)
+		})
+	}
+}
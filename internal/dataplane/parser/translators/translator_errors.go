@@ -3,8 +3,21 @@ package translators
 import "errors"
 
 var (
-	ErrRouteValidationNoRules                          = errors.New("no rules provided")
-	ErrRouteValidationQueryParamMatchesUnsupported     = errors.New("query param matches are not yet supported")
-	ErrRouteValidationNoMatchRulesOrHostnamesSpecified = errors.New("no match rules or hostnames specified")
-	ErrRotueValidationRuleNoBackendRef                 = errors.New("no backendRefs in rule")
+	ErrRouteValidationNoRules                              = errors.New("no rules provided")
+	ErrRouteValidationQueryParamMatchesUnsupported         = errors.New("query param matches require the " + QueryParamsGuardPluginName + " plugin with Kong's traditional router")
+	ErrRouteValidationNoMatchRulesOrHostnamesSpecified     = errors.New("no match rules or hostnames specified")
+	ErrRotueValidationRuleNoBackendRef                     = errors.New("no backendRefs in rule")
+	ErrRouteValidationRedirectAndURLRewriteFilters         = errors.New("RequestRedirect and URLRewrite filters cannot be used in the same rule")
+	ErrRouteValidationReplacePrefixMatchRequiresPathPrefix = errors.New("URLRewrite filter with ReplacePrefixMatch requires PathPrefix matches")
+	ErrSessionPersistenceCookieAndHeader                   = errors.New("session persistence can't use both a cookie and a header")
+	ErrSessionPersistenceCookiePathWithoutCookie           = errors.New("session persistence cookie path requires a cookie")
+	ErrSessionPersistenceEmptyName                         = errors.New("session persistence cookie or header name can't be empty")
+	ErrSessionPersistenceRulesWithoutCookieOrHeader        = errors.New("session persistence rules require a cookie or a header")
+	ErrSessionPersistenceInvalidRule                       = errors.New("session persistence rules have to be indexes of the HTTPRoute's rules")
+	ErrSessionPersistenceUpstreamConflict                  = errors.New("session persistence conflicts with the load balancing explicitly configured for the upstream")
+	ErrKongUpstreamPolicyHashRequiresConsistentHashing     = errors.New("hashOn and hashOnFallback require the consistent-hashing algorithm")
+	ErrKongUpstreamPolicyHashSingleSource                  = errors.New("exactly one of header, cookie, queryArg and uriCapture has to be set")
+	ErrKongUpstreamPolicyHashCookiePathWithoutCookie       = errors.New("cookiePath requires a cookie")
+	ErrKongUpstreamPolicyHashFallbackWithoutHashOn         = errors.New("hashOnFallback requires hashOn")
+	ErrKongUpstreamPolicyHashFallbackWithCookie            = errors.New("hashOnFallback can't be used with a cookie")
 )
@@ -7,4 +7,16 @@ const (
 	// ControllerPathRegexPrefix is the prefix string used to indicate that the controller should treat a path as a
 	// regular expression. The controller replaces this prefix with KongPathRegexPrefix when sending routes to Kong.
 	ControllerPathRegexPrefix = "/~"
+
+	// QueryParamsGuardPluginName is the name of the plugin enforcing HTTPRoute query param matches
+	// on Kong routes generated for the traditional router, which cannot match query params.
+	QueryParamsGuardPluginName = "pre-function"
+
+	// RequestMirrorPluginName is the name of the plugin mirroring requests matched by HTTPRoute rules
+	// with RequestMirror filters to the mirrored backends.
+	RequestMirrorPluginName = "pre-function"
+
+	// KongServiceMaxTimeout is the maximum connect, read and write timeout in milliseconds accepted by Kong
+	// for a service. It is used when HTTPRoute timeouts disable timeouts by setting a zero duration.
+	KongServiceMaxTimeout = 2147483646
 )
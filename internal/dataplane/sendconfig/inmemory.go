@@ -54,6 +54,7 @@ func (s UpdateStrategyInMemory) Update(ctx context.Context, targetState ContentW
 	resourceErrorsParseErr error,
 ) {
 	dblessConfig := s.configConverter.Convert(targetState.Content)
+	dblessConfig.CustomEntities = targetState.CustomEntities
 	config, err := json.Marshal(dblessConfig)
 	if err != nil {
 		return fmt.Errorf("constructing kong configuration: %w", err), nil, nil
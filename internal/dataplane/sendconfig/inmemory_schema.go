@@ -1,7 +1,13 @@
 package sendconfig
 
 import (
+	"encoding/json"
+	"fmt"
+
 	"github.com/kong/deck/file"
+	"github.com/kong/go-kong/kong"
+
+	"github.com/kong/kubernetes-ingress-controller/v2/internal/dataplane/deckgen"
 )
 
 // DBLessConfig is the configuration that is sent to Kong's data-plane via its `POST /config` endpoint after being
@@ -10,6 +16,38 @@ import (
 type DBLessConfig struct {
 	file.Content
 	ConsumerGroupConsumerRelationships []ConsumerGroupConsumerRelationship `json:"consumer_group_consumers,omitempty"`
+	ConsumerGroupPlugins               []ConsumerGroupPlugin               `json:"consumer_group_plugins,omitempty"`
+
+	// CustomEntities are marshalled as top-level entities of the configuration.
+	CustomEntities deckgen.CustomEntities `json:"-"`
+}
+
+// MarshalJSON marshals the configuration along with its custom entities.
+func (c DBLessConfig) MarshalJSON() ([]byte, error) {
+	type dblessConfig DBLessConfig
+	config, err := json.Marshal(dblessConfig(c))
+	if err != nil {
+		return nil, err
+	}
+	if len(c.CustomEntities) == 0 {
+		return config, nil
+	}
+
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(config, &fields); err != nil {
+		return nil, err
+	}
+	for entity, entities := range c.CustomEntities {
+		if _, ok := fields[entity]; ok {
+			return nil, fmt.Errorf("custom entity %q conflicts with a field of the configuration", entity)
+		}
+		raw, err := json.Marshal(entities)
+		if err != nil {
+			return nil, err
+		}
+		fields[entity] = raw
+	}
+	return json.Marshal(fields)
 }
 
 // ConsumerGroupConsumerRelationship is a relationship between a ConsumerGroup and a Consumer.
@@ -18,6 +56,14 @@ type ConsumerGroupConsumerRelationship struct {
 	Consumer      string `json:"consumer"`
 }
 
+// ConsumerGroupPlugin is an override of a plugin's configuration for consumers in a ConsumerGroup.
+type ConsumerGroupPlugin struct {
+	ID            *string            `json:"id,omitempty"`
+	Name          *string            `json:"name,omitempty"`
+	ConsumerGroup string             `json:"consumer_group"`
+	Config        kong.Configuration `json:"config,omitempty"`
+}
+
 type DefaultContentToDBLessConfigConverter struct{}
 
 func (DefaultContentToDBLessConfigConverter) Convert(content *file.Content) DBLessConfig {
@@ -84,6 +130,7 @@ func cleanUpNullsInPluginConfigs(state *file.Content) {
 //
 // In their place it creates relationships slices:
 //   - ConsumerGroupConsumerRelationships
+//   - ConsumerGroupPlugins
 func convertConsumerGroups(dblessConfig *DBLessConfig) {
 	// DBLess schema does not support Consumer.Groups field...
 	for i, c := range dblessConfig.Content.Consumers {
@@ -99,8 +146,16 @@ func convertConsumerGroups(dblessConfig *DBLessConfig) {
 		dblessConfig.Content.Consumers[i].Groups = nil
 	}
 	// DBLess schema does not support ConsumerGroups.Consumers and ConsumerGroups.Plugins fields so we need to remove
-	// them.
-	for i := range dblessConfig.Content.ConsumerGroups {
+	// them. Plugins are kept as standalone entities referring to their ConsumerGroup.
+	for i, cg := range dblessConfig.Content.ConsumerGroups {
+		for _, p := range cg.Plugins {
+			dblessConfig.ConsumerGroupPlugins = append(dblessConfig.ConsumerGroupPlugins, ConsumerGroupPlugin{
+				ID:            p.ID,
+				Name:          p.Name,
+				ConsumerGroup: cg.FriendlyName(),
+				Config:        p.Config,
+			})
+		}
 		dblessConfig.Content.ConsumerGroups[i].Consumers = nil
 		dblessConfig.Content.ConsumerGroups[i].Plugins = nil
 	}
@@ -8,6 +8,7 @@ import (
 	"github.com/kong/go-kong/kong"
 	"github.com/stretchr/testify/require"
 
+	"github.com/kong/kubernetes-ingress-controller/v2/internal/dataplane/deckgen"
 	"github.com/kong/kubernetes-ingress-controller/v2/internal/dataplane/sendconfig"
 )
 
@@ -28,6 +29,18 @@ func TestDBLessConfigMarshalToJSON(t *testing.T) {
 				Consumer:      "c1",
 			},
 		},
+		ConsumerGroupPlugins: []sendconfig.ConsumerGroupPlugin{
+			{
+				Name:          kong.String("rate-limiting-advanced"),
+				ConsumerGroup: "cg1",
+				Config:        kong.Configuration{"limit": []int{10}},
+			},
+		},
+		CustomEntities: deckgen.CustomEntities{
+			"keyauth_enc_credentials": {
+				{"consumer": "c1", "key": "little-rabbits-be-good"},
+			},
+		},
 	}
 
 	expected := `{
@@ -41,6 +54,21 @@ func TestDBLessConfigMarshalToJSON(t *testing.T) {
       "consumer_group": "cg1",
       "consumer": "c1"
     }
+  ],
+  "consumer_group_plugins": [
+    {
+      "name": "rate-limiting-advanced",
+      "consumer_group": "cg1",
+      "config": {
+        "limit": [10]
+      }
+    }
+  ],
+  "keyauth_enc_credentials": [
+    {
+      "consumer": "c1",
+      "key": "little-rabbits-be-good"
+    }
   ]
 }`
 	b, err := json.Marshal(dblessConfig)
@@ -137,6 +165,12 @@ func TestDefaultContentToDBLessConfigConverter(t *testing.T) {
 						Consumer:      "c1",
 					},
 				},
+				ConsumerGroupPlugins: []sendconfig.ConsumerGroupPlugin{
+					{
+						Name:          kong.String("p1"),
+						ConsumerGroup: "cg1",
+					},
+				},
 			},
 		},
 		{
@@ -201,6 +235,12 @@ func TestDefaultContentToDBLessConfigConverter(t *testing.T) {
 						Consumer:      "c1",
 					},
 				},
+				ConsumerGroupPlugins: []sendconfig.ConsumerGroupPlugin{
+					{
+						ID:            kong.String("p1"),
+						ConsumerGroup: "cg1",
+					},
+				},
 			},
 		},
 		{
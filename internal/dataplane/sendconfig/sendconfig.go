@@ -38,18 +38,20 @@ type AdminAPIClient interface {
 }
 
 // PerformUpdate writes `targetContent` to Kong Admin API specified by `kongConfig`.
+// `customEntities` are written only by the in-memory update strategy.
 func PerformUpdate(
 	ctx context.Context,
 	logger logr.Logger,
 	client AdminAPIClient,
 	config Config,
 	targetContent *file.Content,
+	customEntities deckgen.CustomEntities,
 	promMetrics *metrics.CtrlFuncMetrics,
 	updateStrategyResolver UpdateStrategyResolver,
 	configChangeDetector ConfigurationChangeDetector,
 ) ([]byte, []failures.ResourceFailure, error) {
 	oldSHA := client.LastConfigSHA()
-	newSHA, err := deckgen.GenerateSHA(targetContent)
+	newSHA, err := deckgen.GenerateSHA(targetContent, customEntities)
 	if err != nil {
 		return oldSHA, []failures.ResourceFailure{}, err
 	}
@@ -74,8 +76,9 @@ func PerformUpdate(
 	logger = logger.WithValues("update_strategy", updateStrategy.Type())
 	timeStart := time.Now()
 	err, resourceErrors, resourceErrorsParseErr := updateStrategy.Update(ctx, ContentWithHash{
-		Content: targetContent,
-		Hash:    newSHA,
+		Content:        targetContent,
+		CustomEntities: customEntities,
+		Hash:           newSHA,
 	})
 	duration := time.Since(timeStart)
 
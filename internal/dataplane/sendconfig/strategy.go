@@ -9,13 +9,16 @@ import (
 	"github.com/kong/go-kong/kong"
 
 	"github.com/kong/kubernetes-ingress-controller/v2/internal/adminapi"
+	"github.com/kong/kubernetes-ingress-controller/v2/internal/dataplane/deckgen"
 	"github.com/kong/kubernetes-ingress-controller/v2/internal/metrics"
 )
 
 // ContentWithHash encapsulates file.Content along with its precalculated hash.
 type ContentWithHash struct {
 	Content *file.Content
-	Hash    []byte
+	// CustomEntities are entities that file.Content has no place for. Only the in-memory strategy sends them.
+	CustomEntities deckgen.CustomEntities
+	Hash           []byte
 }
 
 // UpdateStrategy is the way we approach updating data-plane's configuration, depending on its type.
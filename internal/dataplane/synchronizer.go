@@ -28,6 +28,19 @@ const (
 	// See Also: https://github.com/Kong/kubernetes-ingress-controller/issues/1398
 	DefaultSyncSeconds float32 = 3.0
 
+	// DefaultSyncDebounce is the default time to wait for further changes after a change
+	// before updating the DataplaneClient, so that bursts of changes result in a single update.
+	DefaultSyncDebounce = 250 * time.Millisecond
+
+	// DefaultSyncMaxStaleness is the default maximum time a change can wait for an update
+	// of the DataplaneClient when further changes keep postponing it.
+	DefaultSyncMaxStaleness = 10 * time.Second
+
+	// DefaultResyncPeriod is the default period of updates of the DataplaneClient that happen
+	// regardless of changes. They are a safety net for changes of configuration inputs that
+	// aren't notified (e.g. licenses).
+	DefaultResyncPeriod = time.Minute
+
 	DefaultCacheSyncWaitDuration = 5 * time.Second
 )
 
@@ -35,8 +48,9 @@ const (
 // Synchronizer - Public Types
 // -----------------------------------------------------------------------------
 
-// Synchronizer is a threadsafe object which starts a goroutine to updates
-// the data-plane at regular intervals.
+// Synchronizer is a threadsafe object which starts a goroutine to update
+// the data-plane whenever the configuration changes, and at regular intervals
+// as a safety net.
 type Synchronizer struct {
 	logger logr.Logger
 
@@ -46,7 +60,11 @@ type Synchronizer struct {
 
 	// server configuration, flow control, channels and utility attributes
 	stagger         time.Duration
-	syncTicker      *time.Ticker
+	debounce        time.Duration
+	maxStaleness    time.Duration
+	resyncPeriod    time.Duration
+	syncTriggers    []<-chan struct{}
+	changes         chan struct{}
 	configApplied   bool
 	isServerRunning bool
 	initWaitPeriod  time.Duration
@@ -56,13 +74,47 @@ type Synchronizer struct {
 
 type SynchronizerOption func(*Synchronizer)
 
-// WithStagger returns a SynchronizerOption which sets the stagger period.
+// WithStagger returns a SynchronizerOption which sets the stagger period, the minimum
+// time between updates of the DataplaneClient.
 func WithStagger(period time.Duration) SynchronizerOption {
 	return func(s *Synchronizer) {
 		s.stagger = period
 	}
 }
 
+// WithDebounce returns a SynchronizerOption which sets the time to wait for further changes
+// after a change before updating the DataplaneClient.
+func WithDebounce(period time.Duration) SynchronizerOption {
+	return func(s *Synchronizer) {
+		s.debounce = period
+	}
+}
+
+// WithMaxStaleness returns a SynchronizerOption which sets the maximum time a change can wait
+// for an update of the DataplaneClient when further changes keep postponing it.
+func WithMaxStaleness(period time.Duration) SynchronizerOption {
+	return func(s *Synchronizer) {
+		s.maxStaleness = period
+	}
+}
+
+// WithResyncPeriod returns a SynchronizerOption which sets the period of updates of the
+// DataplaneClient that happen regardless of changes. A non-positive period disables them.
+func WithResyncPeriod(period time.Duration) SynchronizerOption {
+	return func(s *Synchronizer) {
+		s.resyncPeriod = period
+	}
+}
+
+// WithSyncTriggers returns a SynchronizerOption which adds channels notifying about changes
+// that should be synchronized to the DataplaneClient (e.g. changes of the Gateway clients).
+// When the DataplaneClient implements ConfigChangesNotifier, its changes are always synchronized.
+func WithSyncTriggers(triggers ...<-chan struct{}) SynchronizerOption {
+	return func(s *Synchronizer) {
+		s.syncTriggers = append(s.syncTriggers, triggers...)
+	}
+}
+
 // WithInitCacheSyncDuration returns a SynchronizerOption which sets the initial wait period.
 func WithInitCacheSyncDuration(period time.Duration) SynchronizerOption {
 	return func(s *Synchronizer) {
@@ -78,6 +130,10 @@ func NewSynchronizer(logger logr.Logger, client Client, opts ...SynchronizerOpti
 	synchronizer := &Synchronizer{
 		logger:          logger,
 		stagger:         time.Duration(DefaultSyncSeconds),
+		debounce:        DefaultSyncDebounce,
+		maxStaleness:    DefaultSyncMaxStaleness,
+		resyncPeriod:    DefaultResyncPeriod,
+		changes:         make(chan struct{}, 1),
 		initWaitPeriod:  DefaultCacheSyncWaitDuration,
 		dataplaneClient: client,
 		configApplied:   false,
@@ -88,6 +144,10 @@ func NewSynchronizer(logger logr.Logger, client Client, opts ...SynchronizerOpti
 		opt(synchronizer)
 	}
 
+	if notifier, ok := client.(ConfigChangesNotifier); ok {
+		synchronizer.syncTriggers = append(synchronizer.syncTriggers, notifier.ConfigChanges())
+	}
+
 	return synchronizer, nil
 }
 
@@ -96,8 +156,10 @@ func NewSynchronizer(logger logr.Logger, client Client, opts ...SynchronizerOpti
 // -----------------------------------------------------------------------------
 
 // Start starts the goroutine synchronization server that will perform an
-// Update() on the provided dataplane.Client according to the provided stagger
-// time, or using the DefaultSyncSeconds if not otherwise provided.
+// Update() on the provided dataplane.Client whenever any of the sync triggers
+// notifies about a change, and every resync period. Updates are debounced and
+// happen no more often than the provided stagger time, or DefaultSyncSeconds
+// if not otherwise provided.
 //
 // To stop the server, the provided context must be Done().
 func (p *Synchronizer) Start(ctx context.Context) error {
@@ -116,7 +178,9 @@ func (p *Synchronizer) Start(ctx context.Context) error {
 		return fmt.Errorf("server is already running")
 	}
 
-	p.syncTicker = time.NewTicker(p.stagger)
+	for _, trigger := range p.syncTriggers {
+		go p.forwardChanges(ctx, trigger)
+	}
 	go p.startUpdateServer(ctx)
 	p.isServerRunning = true
 
@@ -160,9 +224,23 @@ func (p *Synchronizer) NeedLeaderElection() bool {
 // -----------------------------------------------------------------------------
 
 // startUpdateServer runs a server in a background goroutine that is responsible for
-// updating the kong proxy backend at regular intervals.
+// updating the kong proxy backend whenever the configuration changes.
 func (p *Synchronizer) startUpdateServer(ctx context.Context) {
-	var initialConfig sync.Once
+	var (
+		initialConfig sync.Once
+		syncTimer     = time.NewTimer(0)
+		// The initial update is pending, it's performed as soon as the server starts.
+		schedule = newSyncSchedule(time.Now())
+		// resyncC stays nil when periodic updates are disabled.
+		resyncC <-chan time.Time
+	)
+	defer syncTimer.Stop()
+	if p.resyncPeriod > 0 {
+		resyncTicker := time.NewTicker(p.resyncPeriod)
+		defer resyncTicker.Stop()
+		resyncC = resyncTicker.C
+	}
+
 	for {
 		select {
 		case <-ctx.Done():
@@ -170,7 +248,6 @@ func (p *Synchronizer) startUpdateServer(ctx context.Context) {
 			if err := ctx.Err(); err != nil && !errors.Is(err, context.Canceled) {
 				p.logger.Error(err, "context completed with error")
 			}
-			p.syncTicker.Stop()
 
 			p.lock.Lock()
 			defer p.lock.Unlock()
@@ -179,9 +256,28 @@ func (p *Synchronizer) startUpdateServer(ctx context.Context) {
 
 			return
 
-		case <-p.syncTicker.C:
+		case <-p.changes:
+			schedule.changed(time.Now())
+			resetTimer(syncTimer, p.timeUntilSync(schedule))
+
+		case <-resyncC:
+			if !schedule.pending {
+				// Resyncs aren't debounced, there are no further changes to wait for.
+				schedule.changed(time.Now().Add(-p.debounce))
+				resetTimer(syncTimer, p.timeUntilSync(schedule))
+			}
+
+		case <-syncTimer.C:
+			if !schedule.pending {
+				continue
+			}
+			// Changes notified during the update are going to be synchronized by the following one.
+			schedule.synced(time.Now())
 			if err := p.dataplaneClient.Update(ctx); err != nil {
 				p.logger.Error(err, "could not update kong admin")
+				// Retry once the stagger time passes, as if the configuration changed.
+				schedule.changed(time.Now().Add(-p.debounce))
+				resetTimer(syncTimer, p.timeUntilSync(schedule))
 				continue
 			}
 			initialConfig.Do(p.markConfigApplied)
@@ -189,6 +285,80 @@ func (p *Synchronizer) startUpdateServer(ctx context.Context) {
 	}
 }
 
+// forwardChanges forwards notifications from a sync trigger to the update server until the
+// trigger is closed or the context is done.
+func (p *Synchronizer) forwardChanges(ctx context.Context, trigger <-chan struct{}) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case _, ok := <-trigger:
+			if !ok {
+				return
+			}
+			select {
+			case p.changes <- struct{}{}:
+			default:
+				// A notification is already pending, it covers this change too.
+			}
+		}
+	}
+}
+
+// timeUntilSync returns the time to wait before the next update of the DataplaneClient:
+// the debounce period after the last change, but no later than the max staleness after the
+// first change since the previous update, and no earlier than the stagger time after it.
+func (p *Synchronizer) timeUntilSync(s syncSchedule) time.Duration {
+	syncAt := s.lastChange.Add(p.debounce)
+	if deadline := s.firstChange.Add(p.maxStaleness); deadline.Before(syncAt) {
+		syncAt = deadline
+	}
+	if earliest := s.lastSync.Add(p.stagger); earliest.After(syncAt) {
+		syncAt = earliest
+	}
+	return time.Until(syncAt)
+}
+
+// syncSchedule tracks changes that haven't been synchronized to the DataplaneClient yet.
+type syncSchedule struct {
+	pending     bool
+	firstChange time.Time
+	lastChange  time.Time
+	lastSync    time.Time
+}
+
+func newSyncSchedule(now time.Time) syncSchedule {
+	return syncSchedule{
+		pending:     true,
+		firstChange: now,
+		lastChange:  now,
+	}
+}
+
+func (s *syncSchedule) changed(at time.Time) {
+	if !s.pending {
+		s.pending = true
+		s.firstChange = at
+	}
+	s.lastChange = at
+}
+
+func (s *syncSchedule) synced(at time.Time) {
+	s.pending = false
+	s.lastSync = at
+}
+
+// resetTimer resets a timer that may have already fired or been stopped.
+func resetTimer(t *time.Timer, d time.Duration) {
+	if !t.Stop() {
+		select {
+		case <-t.C:
+		default:
+		}
+	}
+	t.Reset(d)
+}
+
 // -----------------------------------------------------------------------------
 // Synchronizer - Private Methods - Helper
 // -----------------------------------------------------------------------------
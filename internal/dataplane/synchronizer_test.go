@@ -33,6 +33,7 @@ func TestSynchronizer(t *testing.T) {
 		zapr.NewLogger(zap.NewNop()),
 		c,
 		WithStagger(testSynchronizerTick),
+		WithResyncPeriod(testSynchronizerTick),
 		WithInitCacheSyncDuration(testSynchronizerTick),
 	)
 	require.NoError(t, err)
@@ -98,6 +99,7 @@ func TestSynchronizer_IsReadyDoesntBlockWhenDataPlaneIsBlocked(t *testing.T) {
 				zapr.NewLogger(zap.NewNop()),
 				c,
 				WithStagger(testSynchronizerTick),
+				WithResyncPeriod(testSynchronizerTick),
 				WithInitCacheSyncDuration(testSynchronizerTick),
 			)
 			require.NoError(t, err)
@@ -123,6 +125,131 @@ func TestSynchronizer_IsReadyDoesntBlockWhenDataPlaneIsBlocked(t *testing.T) {
 	}
 }
 
+func TestSynchronizer_SyncsOnChanges(t *testing.T) {
+	if testing.Short() {
+		t.SkipNow()
+	}
+
+	c := &fakeDataplaneClient{dbmode: "off"}
+	changes := make(chan struct{}, 1)
+	notifyChange := func() {
+		select {
+		case changes <- struct{}{}:
+		default:
+		}
+	}
+
+	const (
+		stagger      = testSynchronizerTick
+		debounce     = testSynchronizerTick * 5
+		maxStaleness = testSynchronizerTick * 20
+	)
+	s, err := NewSynchronizer(
+		zapr.NewLogger(zap.NewNop()),
+		c,
+		WithStagger(stagger),
+		WithDebounce(debounce),
+		WithMaxStaleness(maxStaleness),
+		WithResyncPeriod(0),
+		WithSyncTriggers(changes),
+		WithInitCacheSyncDuration(testSynchronizerTick),
+	)
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	require.NoError(t, s.Start(ctx))
+
+	t.Log("verifying that the initial update happens right after start")
+	require.Eventually(t, func() bool { return s.IsReady() }, time.Second, testSynchronizerTick)
+	require.Equal(t, 1, c.totalUpdates())
+
+	t.Log("verifying that no updates happen without changes")
+	time.Sleep(testSynchronizerTick * 10)
+	require.Equal(t, 1, c.totalUpdates())
+
+	t.Log("verifying that a burst of changes results in a single update")
+	for i := 0; i < 3; i++ {
+		notifyChange()
+		time.Sleep(testSynchronizerTick)
+	}
+	require.Eventually(t, func() bool { return c.totalUpdates() == 2 }, time.Second, testSynchronizerTick)
+	time.Sleep(debounce * 2)
+	require.Equal(t, 2, c.totalUpdates())
+
+	t.Log("verifying that changes that keep coming are synchronized after the max staleness")
+	start := time.Now()
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		ticker := time.NewTicker(testSynchronizerTick)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				notifyChange()
+			}
+		}
+	}()
+	require.Eventually(t, func() bool { return c.totalUpdates() == 3 }, time.Second, testSynchronizerTick)
+	require.GreaterOrEqual(t, time.Since(start), maxStaleness)
+}
+
+func TestSynchronizer_TimeUntilSync(t *testing.T) {
+	s := &Synchronizer{
+		stagger:      3 * time.Second,
+		debounce:     time.Second,
+		maxStaleness: 10 * time.Second,
+	}
+	now := time.Now()
+
+	testCases := []struct {
+		name     string
+		schedule syncSchedule
+		expected time.Duration
+	}{
+		{
+			name: "debounce after the last change",
+			schedule: syncSchedule{
+				pending:     true,
+				firstChange: now.Add(-2 * time.Second),
+				lastChange:  now,
+				lastSync:    now.Add(-time.Minute),
+			},
+			expected: time.Second,
+		},
+		{
+			name: "max staleness after the first change",
+			schedule: syncSchedule{
+				pending:     true,
+				firstChange: now.Add(-9500 * time.Millisecond),
+				lastChange:  now,
+				lastSync:    now.Add(-time.Minute),
+			},
+			expected: 500 * time.Millisecond,
+		},
+		{
+			name: "stagger after the last update",
+			schedule: syncSchedule{
+				pending:     true,
+				firstChange: now,
+				lastChange:  now,
+				lastSync:    now.Add(-time.Second),
+			},
+			expected: 2 * time.Second,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			assert.InDelta(t, tc.expected, s.timeUntilSync(tc.schedule), float64(100*time.Millisecond))
+		})
+	}
+}
+
 // fakeDataplaneClient fakes the dataplane.Client interface so that we can
 // unit test the dataplane.Synchronizer.
 type fakeDataplaneClient struct {
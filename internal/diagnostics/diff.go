@@ -0,0 +1,257 @@
+package diagnostics
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/kong/deck/file"
+	"github.com/kong/go-kong/kong"
+	"github.com/samber/lo"
+
+	"github.com/kong/kubernetes-ingress-controller/v2/internal/util"
+)
+
+const (
+	entityTypeService     = "service"
+	entityTypeRoute       = "route"
+	entityTypePlugin      = "plugin"
+	entityTypeConsumer    = "consumer"
+	entityTypeCertificate = "certificate"
+)
+
+// configDiffResponse is the response of the config diff endpoint. It describes Kong entities added, removed
+// or changed in the last failed configuration when compared to the last successful one.
+type configDiffResponse struct {
+	Added   []entityDiff `json:"added"`
+	Removed []entityDiff `json:"removed"`
+	Changed []entityDiff `json:"changed"`
+}
+
+// entityDiff identifies a Kong entity that differs between configurations along with the Kubernetes objects
+// it was generated from. Values of entities are omitted as they may contain sensitive data.
+type entityDiff struct {
+	Type string `json:"type"`
+	Key  string `json:"key"`
+	// ChangedFields lists top-level fields of a changed entity that differ between configurations.
+	ChangedFields []string `json:"changedFields,omitempty"`
+	// Sources are the Kubernetes objects the entity was generated from, read from its tags.
+	Sources []objectReference `json:"sources"`
+}
+
+// diffableEntity is a Kong entity flattened out of a configuration, without its nested entities.
+type diffableEntity struct {
+	entityType string
+	key        string
+	tags       []*string
+	// value is the entity marshalled to JSON with its nested entities removed.
+	value map[string]interface{}
+}
+
+// diffConfigs returns an entity-level diff from the from configuration to the to configuration.
+func diffConfigs(from, to file.Content) (configDiffResponse, error) {
+	fromEntities, err := flattenConfig(from)
+	if err != nil {
+		return configDiffResponse{}, err
+	}
+	toEntities, err := flattenConfig(to)
+	if err != nil {
+		return configDiffResponse{}, err
+	}
+
+	diff := configDiffResponse{
+		Added:   []entityDiff{},
+		Removed: []entityDiff{},
+		Changed: []entityDiff{},
+	}
+	for id, toEntity := range toEntities {
+		fromEntity, ok := fromEntities[id]
+		if !ok {
+			diff.Added = append(diff.Added, newEntityDiff(toEntity))
+			continue
+		}
+		changedFields := changedFields(fromEntity.value, toEntity.value)
+		if len(changedFields) == 0 {
+			continue
+		}
+		entry := newEntityDiff(toEntity, fromEntity)
+		entry.ChangedFields = changedFields
+		diff.Changed = append(diff.Changed, entry)
+	}
+	for id, fromEntity := range fromEntities {
+		if _, ok := toEntities[id]; !ok {
+			diff.Removed = append(diff.Removed, newEntityDiff(fromEntity))
+		}
+	}
+
+	for _, entries := range [][]entityDiff{diff.Added, diff.Removed, diff.Changed} {
+		sort.Slice(entries, func(i, j int) bool {
+			if entries[i].Type != entries[j].Type {
+				return entries[i].Type < entries[j].Type
+			}
+			return entries[i].Key < entries[j].Key
+		})
+	}
+	return diff, nil
+}
+
+func newEntityDiff(entities ...diffableEntity) entityDiff {
+	entry := entityDiff{
+		Type:    entities[0].entityType,
+		Key:     entities[0].key,
+		Sources: []objectReference{},
+	}
+	for _, entity := range entities {
+		if source, ok := objectReferenceFromTags(entity.tags); ok && !lo.Contains(entry.Sources, source) {
+			entry.Sources = append(entry.Sources, source)
+		}
+	}
+	return entry
+}
+
+// flattenConfig returns services, routes, plugins, consumers and certificates of a configuration
+// indexed by their types and keys.
+func flattenConfig(content file.Content) (map[string]diffableEntity, error) {
+	entities := map[string]diffableEntity{}
+	add := func(entityType, key string, tags []*string, value interface{}) error {
+		b, err := json.Marshal(value)
+		if err != nil {
+			return fmt.Errorf("failed to marshal %s %s: %w", entityType, key, err)
+		}
+		var m map[string]interface{}
+		if err := json.Unmarshal(b, &m); err != nil {
+			return fmt.Errorf("failed to unmarshal %s %s: %w", entityType, key, err)
+		}
+		entities[entityType+"/"+key] = diffableEntity{entityType: entityType, key: key, tags: tags, value: m}
+		return nil
+	}
+	addPlugins := func(scope string, plugins []*file.FPlugin) error {
+		for _, p := range plugins {
+			if err := add(entityTypePlugin, pluginKey(scope, p.Plugin), p.Tags, p.Plugin); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	addRoute := func(r file.FRoute) error {
+		key := keyOf(r.Name, r.ID)
+		if err := add(entityTypeRoute, key, r.Tags, r.Route); err != nil {
+			return err
+		}
+		return addPlugins("route:"+key, r.Plugins)
+	}
+
+	for _, s := range content.Services {
+		key := keyOf(s.Name, s.ID)
+		if err := add(entityTypeService, key, s.Tags, s.Service); err != nil {
+			return nil, err
+		}
+		if err := addPlugins("service:"+key, s.Plugins); err != nil {
+			return nil, err
+		}
+		for _, r := range s.Routes {
+			if err := addRoute(*r); err != nil {
+				return nil, err
+			}
+		}
+	}
+	for _, r := range content.Routes {
+		if err := addRoute(r); err != nil {
+			return nil, err
+		}
+	}
+	for _, c := range content.Consumers {
+		key := keyOf(c.Username, c.CustomID, c.ID)
+		// credentials are compared as a part of the consumer.
+		consumer := c
+		consumer.Plugins = nil
+		if err := add(entityTypeConsumer, key, c.Tags, consumer); err != nil {
+			return nil, err
+		}
+		if err := addPlugins("consumer:"+key, c.Plugins); err != nil {
+			return nil, err
+		}
+	}
+	for _, p := range content.Plugins {
+		if err := add(entityTypePlugin, pluginKey("", p.Plugin), p.Tags, p.Plugin); err != nil {
+			return nil, err
+		}
+	}
+	for _, c := range content.Certificates {
+		snis := lo.Map(c.SNIs, func(sni kong.SNI, _ int) string { return lo.FromPtr(sni.Name) })
+		key := keyOf(c.ID, kong.String(strings.Join(snis, ",")))
+		if err := add(entityTypeCertificate, key, c.Tags, c); err != nil {
+			return nil, err
+		}
+	}
+	return entities, nil
+}
+
+// pluginKey identifies a plugin by its name, instance name and the entity it's attached to. Plugins defined
+// at the top level of a configuration reference the entities they're attached to.
+func pluginKey(scope string, p kong.Plugin) string {
+	if scope == "" {
+		switch {
+		case p.Route != nil:
+			scope = "route:" + keyOf(p.Route.Name, p.Route.ID)
+		case p.Service != nil:
+			scope = "service:" + keyOf(p.Service.Name, p.Service.ID)
+		case p.Consumer != nil:
+			scope = "consumer:" + keyOf(p.Consumer.Username, p.Consumer.CustomID, p.Consumer.ID)
+		default:
+			scope = "global"
+		}
+	}
+	key := lo.FromPtr(p.Name)
+	if p.InstanceName != nil {
+		key += "." + *p.InstanceName
+	}
+	return key + "@" + scope
+}
+
+// keyOf returns the first non-empty value.
+func keyOf(values ...*string) string {
+	for _, v := range values {
+		if v != nil && *v != "" {
+			return *v
+		}
+	}
+	return ""
+}
+
+// changedFields returns sorted names of top-level fields that differ between the values.
+func changedFields(from, to map[string]interface{}) []string {
+	var fields []string
+	for _, field := range lo.Union(lo.Keys(from), lo.Keys(to)) {
+		if !reflect.DeepEqual(from[field], to[field]) {
+			fields = append(fields, field)
+		}
+	}
+	sort.Strings(fields)
+	return fields
+}
+
+// objectReferenceFromTags reads the reference of the Kubernetes object a Kong entity was generated from
+// from the entity's tags.
+func objectReferenceFromTags(tags []*string) (objectReference, bool) {
+	var ref objectReference
+	for _, tag := range lo.Map(tags, func(tag *string, _ int) string { return lo.FromPtr(tag) }) {
+		switch {
+		case strings.HasPrefix(tag, util.K8sNameTagPrefix):
+			ref.Name = strings.TrimPrefix(tag, util.K8sNameTagPrefix)
+		case strings.HasPrefix(tag, util.K8sNamespaceTagPrefix):
+			ref.Namespace = strings.TrimPrefix(tag, util.K8sNamespaceTagPrefix)
+		case strings.HasPrefix(tag, util.K8sKindTagPrefix):
+			ref.Kind = strings.TrimPrefix(tag, util.K8sKindTagPrefix)
+		case strings.HasPrefix(tag, util.K8sGroupTagPrefix):
+			ref.Group = strings.TrimPrefix(tag, util.K8sGroupTagPrefix)
+		case strings.HasPrefix(tag, util.K8sVersionTagPrefix):
+			ref.Version = strings.TrimPrefix(tag, util.K8sVersionTagPrefix)
+		case strings.HasPrefix(tag, util.K8sUIDTagPrefix):
+			ref.UID = strings.TrimPrefix(tag, util.K8sUIDTagPrefix)
+		}
+	}
+	return ref, ref.Name != "" && ref.Kind != ""
+}
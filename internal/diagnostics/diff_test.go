@@ -0,0 +1,83 @@
+package diagnostics
+
+import (
+	"testing"
+
+	"github.com/kong/deck/file"
+	"github.com/kong/go-kong/kong"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiffConfigs(t *testing.T) {
+	ingressTags := kong.StringSlice(
+		"k8s-name:ingress", "k8s-namespace:default", "k8s-kind:Ingress",
+		"k8s-uid:ingress-uid", "k8s-group:networking.k8s.io", "k8s-version:v1",
+	)
+	serviceTags := kong.StringSlice("k8s-name:svc", "k8s-namespace:default", "k8s-kind:Service", "k8s-version:v1")
+	pluginTags := kong.StringSlice("k8s-name:auth", "k8s-namespace:default", "k8s-kind:KongPlugin", "k8s-group:configuration.konghq.com")
+	ingressRef := objectReference{
+		Group: "networking.k8s.io", Version: "v1", Kind: "Ingress", Namespace: "default", Name: "ingress", UID: "ingress-uid",
+	}
+	serviceRef := objectReference{Version: "v1", Kind: "Service", Namespace: "default", Name: "svc"}
+	pluginRef := objectReference{Group: "configuration.konghq.com", Kind: "KongPlugin", Namespace: "default", Name: "auth"}
+
+	successful := file.Content{
+		Services: []file.FService{{
+			Service: kong.Service{Name: kong.String("default.svc.80"), Port: kong.Int(80), Tags: serviceTags},
+			Routes: []*file.FRoute{{
+				Route: kong.Route{Name: kong.String("default.ingress.svc..80"), Paths: kong.StringSlice("/"), Tags: ingressTags},
+			}},
+		}},
+		Consumers: []file.FConsumer{{
+			Consumer: kong.Consumer{Username: kong.String("consumer")},
+		}},
+	}
+	failed := file.Content{
+		Services: []file.FService{{
+			Service: kong.Service{Name: kong.String("default.svc.80"), Port: kong.Int(80), Tags: serviceTags},
+			Routes: []*file.FRoute{{
+				Route: kong.Route{Name: kong.String("default.ingress.svc..80"), Paths: kong.StringSlice("/new"), Tags: ingressTags},
+				Plugins: []*file.FPlugin{{
+					Plugin: kong.Plugin{Name: kong.String("key-auth"), Tags: pluginTags},
+				}},
+			}},
+			Plugins: []*file.FPlugin{{
+				Plugin: kong.Plugin{Name: kong.String("key-auth"), Tags: pluginTags},
+			}},
+		}},
+	}
+
+	diff, err := diffConfigs(successful, failed)
+	require.NoError(t, err)
+	assert.Equal(t, configDiffResponse{
+		Added: []entityDiff{
+			{Type: entityTypePlugin, Key: "key-auth@route:default.ingress.svc..80", Sources: []objectReference{pluginRef}},
+			{Type: entityTypePlugin, Key: "key-auth@service:default.svc.80", Sources: []objectReference{pluginRef}},
+		},
+		Removed: []entityDiff{
+			{Type: entityTypeConsumer, Key: "consumer", Sources: []objectReference{}},
+		},
+		Changed: []entityDiff{
+			{Type: entityTypeRoute, Key: "default.ingress.svc..80", ChangedFields: []string{"paths"}, Sources: []objectReference{ingressRef}},
+		},
+	}, diff)
+
+	t.Run("identical configurations", func(t *testing.T) {
+		diff, err := diffConfigs(failed, failed)
+		require.NoError(t, err)
+		assert.Empty(t, diff.Added)
+		assert.Empty(t, diff.Removed)
+		assert.Empty(t, diff.Changed)
+	})
+
+	t.Run("sources of a changed entity from both configurations", func(t *testing.T) {
+		from := file.Content{Services: []file.FService{{Service: kong.Service{Name: kong.String("svc"), Tags: serviceTags}}}}
+		to := file.Content{Services: []file.FService{{Service: kong.Service{Name: kong.String("svc"), Tags: ingressTags}}}}
+		diff, err := diffConfigs(from, to)
+		require.NoError(t, err)
+		require.Len(t, diff.Changed, 1)
+		assert.Equal(t, []string{"tags"}, diff.Changed[0].ChangedFields)
+		assert.Equal(t, []objectReference{ingressRef, serviceRef}, diff.Changed[0].Sources)
+	})
+}
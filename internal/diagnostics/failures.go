@@ -0,0 +1,70 @@
+package diagnostics
+
+import (
+	"time"
+
+	"github.com/kong/kubernetes-ingress-controller/v2/internal/dataplane/failures"
+	"github.com/kong/kubernetes-ingress-controller/v2/internal/util"
+)
+
+const (
+	// resourceFailureSourceTranslation marks failures of translating Kubernetes objects into Kong configuration.
+	resourceFailureSourceTranslation = "translation"
+	// resourceFailureSourceKong marks failures of Kubernetes objects whose Kong entities were rejected by Kong.
+	resourceFailureSourceKong = "kong"
+)
+
+// resourceFailuresResponse is the response of the resource failures endpoint.
+type resourceFailuresResponse struct {
+	// SyncTimestamp is the time the last sync started at. It's nil until the first sync.
+	SyncTimestamp *time.Time        `json:"syncTimestamp,omitempty"`
+	Failures      []resourceFailure `json:"failures"`
+}
+
+// resourceFailure describes a failure of a single Kubernetes object.
+type resourceFailure struct {
+	Object        objectReference `json:"object"`
+	Source        string          `json:"source"`
+	Reason        string          `json:"reason"`
+	SyncTimestamp time.Time       `json:"syncTimestamp"`
+}
+
+type objectReference struct {
+	Group     string `json:"group"`
+	Version   string `json:"version"`
+	Kind      string `json:"kind"`
+	Namespace string `json:"namespace,omitempty"`
+	Name      string `json:"name"`
+	UID       string `json:"uid,omitempty"`
+}
+
+// resourceFailuresResponseFromDump flattens failures from the dump into entries of single causing objects.
+func resourceFailuresResponseFromDump(dump util.FailuresDump) resourceFailuresResponse {
+	response := resourceFailuresResponse{
+		SyncTimestamp: &dump.SyncTimestamp,
+		Failures:      make([]resourceFailure, 0, len(dump.TranslationFailures)+len(dump.KongFailures)),
+	}
+	appendFailures := func(source string, resourceFailures []failures.ResourceFailure) {
+		for _, failure := range resourceFailures {
+			for _, obj := range failure.CausingObjects() {
+				gvk := obj.GetObjectKind().GroupVersionKind()
+				response.Failures = append(response.Failures, resourceFailure{
+					Object: objectReference{
+						Group:     gvk.Group,
+						Version:   gvk.Version,
+						Kind:      gvk.Kind,
+						Namespace: obj.GetNamespace(),
+						Name:      obj.GetName(),
+						UID:       string(obj.GetUID()),
+					},
+					Source:        source,
+					Reason:        failure.Message(),
+					SyncTimestamp: dump.SyncTimestamp,
+				})
+			}
+		}
+	}
+	appendFailures(resourceFailureSourceTranslation, dump.TranslationFailures)
+	appendFailures(resourceFailureSourceKong, dump.KongFailures)
+	return response
+}
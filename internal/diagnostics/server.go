@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"net/http"
 	"net/http/pprof"
+	"reflect"
 	"sync"
 	"time"
 
@@ -22,6 +23,9 @@ type Server struct {
 	ProfilingEnabled bool
 	ConfigDumps      util.ConfigDumpDiagnostic
 	ConfigLock       *sync.RWMutex
+
+	// lastFailures holds resource failures of the last sync. It's guarded by ConfigLock.
+	lastFailures resourceFailuresResponse
 }
 
 var (
@@ -85,6 +89,10 @@ func (s *Server) receiveConfig(ctx context.Context) {
 				successfulConfigDump = dump.Config
 			}
 			s.ConfigLock.Unlock()
+		case dump := <-s.ConfigDumps.Failures:
+			s.ConfigLock.Lock()
+			s.lastFailures = resourceFailuresResponseFromDump(dump)
+			s.ConfigLock.Unlock()
 		case <-ctx.Done():
 			if err := ctx.Err(); err != nil && !errors.Is(err, context.Canceled) {
 				s.Logger.Error(err, "shutting down diagnostic config collection: context completed with error")
@@ -115,6 +123,8 @@ func installProfilingHandlers(mux *http.ServeMux) {
 func (s *Server) installDumpHandlers(mux *http.ServeMux) {
 	mux.HandleFunc("/debug/config/successful", s.lastConfig(&successfulConfigDump))
 	mux.HandleFunc("/debug/config/failed", s.lastConfig(&failedConfigDump))
+	mux.HandleFunc("/debug/config/resource-failures", s.lastResourceFailures)
+	mux.HandleFunc("/debug/config/diff", s.configDiff)
 }
 
 // redirectTo redirects request to a certain destination.
@@ -134,3 +144,39 @@ func (s *Server) lastConfig(config *file.Content) func(rw http.ResponseWriter, r
 		s.ConfigLock.RUnlock()
 	}
 }
+
+// lastResourceFailures serves translation failures and failures of Kong entities rejected by Kong
+// that occurred during the last sync.
+func (s *Server) lastResourceFailures(rw http.ResponseWriter, _ *http.Request) {
+	rw.Header().Set("Content-Type", "application/json")
+	s.ConfigLock.RLock()
+	response := s.lastFailures
+	s.ConfigLock.RUnlock()
+	if response.Failures == nil {
+		// no sync has happened yet.
+		response.Failures = []resourceFailure{}
+	}
+	if err := json.NewEncoder(rw).Encode(response); err != nil {
+		rw.WriteHeader(http.StatusInternalServerError)
+	}
+}
+
+// configDiff serves an entity-level diff from the last successful configuration to the last failed one.
+func (s *Server) configDiff(rw http.ResponseWriter, _ *http.Request) {
+	s.ConfigLock.RLock()
+	defer s.ConfigLock.RUnlock()
+
+	if reflect.DeepEqual(failedConfigDump, file.Content{}) {
+		http.Error(rw, "no failed configuration has been recorded", http.StatusNotFound)
+		return
+	}
+	diff, err := diffConfigs(successfulConfigDump, failedConfigDump)
+	if err != nil {
+		http.Error(rw, fmt.Sprintf("failed to diff configurations: %v", err), http.StatusInternalServerError)
+		return
+	}
+	rw.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(rw).Encode(diff); err != nil {
+		rw.WriteHeader(http.StatusInternalServerError)
+	}
+}
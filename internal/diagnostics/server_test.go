@@ -0,0 +1,150 @@
+package diagnostics
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/kong/deck/file"
+	"github.com/kong/go-kong/kong"
+	"github.com/samber/lo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/kong/kubernetes-ingress-controller/v2/internal/dataplane/failures"
+	"github.com/kong/kubernetes-ingress-controller/v2/internal/util"
+)
+
+func TestServerResourceFailures(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	s := &Server{
+		Logger:     logr.Discard(),
+		ConfigLock: &sync.RWMutex{},
+		ConfigDumps: util.ConfigDumpDiagnostic{
+			Configs:  make(chan util.ConfigDump, 1),
+			Failures: make(chan util.FailuresDump, 1),
+		},
+	}
+	mux := http.NewServeMux()
+	s.installDumpHandlers(mux)
+	go s.receiveConfig(ctx)
+
+	getResourceFailures := func() resourceFailuresResponse {
+		rw := httptest.NewRecorder()
+		mux.ServeHTTP(rw, httptest.NewRequest(http.MethodGet, "/debug/config/resource-failures", nil))
+		require.Equal(t, http.StatusOK, rw.Code)
+		var response resourceFailuresResponse
+		require.NoError(t, json.NewDecoder(rw.Body).Decode(&response))
+		return response
+	}
+
+	t.Run("no failures before the first sync", func(t *testing.T) {
+		response := getResourceFailures()
+		assert.Nil(t, response.SyncTimestamp)
+		assert.Empty(t, response.Failures)
+	})
+
+	t.Run("failures of the last sync", func(t *testing.T) {
+		service := &corev1.Service{
+			TypeMeta:   metav1.TypeMeta{APIVersion: "v1", Kind: "Service"},
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "service", UID: "service-uid"},
+		}
+		ingress := &metav1.PartialObjectMetadata{
+			TypeMeta:   metav1.TypeMeta{APIVersion: "networking.k8s.io/v1", Kind: "Ingress"},
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "ingress"},
+		}
+		syncTimestamp := time.Date(2023, 10, 1, 12, 0, 0, 0, time.UTC)
+		s.ConfigDumps.Failures <- util.FailuresDump{
+			SyncTimestamp:       syncTimestamp,
+			TranslationFailures: []failures.ResourceFailure{lo.Must(failures.NewResourceFailure("translation", service, ingress))},
+			KongFailures:        []failures.ResourceFailure{lo.Must(failures.NewResourceFailure("rejected", service))},
+		}
+
+		var response resourceFailuresResponse
+		require.Eventually(t, func() bool {
+			response = getResourceFailures()
+			return response.SyncTimestamp != nil
+		}, time.Second, 10*time.Millisecond)
+
+		assert.True(t, syncTimestamp.Equal(*response.SyncTimestamp))
+		serviceRef := objectReference{Version: "v1", Kind: "Service", Namespace: "default", Name: "service", UID: "service-uid"}
+		ingressRef := objectReference{Group: "networking.k8s.io", Version: "v1", Kind: "Ingress", Namespace: "default", Name: "ingress"}
+		require.Len(t, response.Failures, 3)
+		for i, expected := range []resourceFailure{
+			{Object: serviceRef, Source: resourceFailureSourceTranslation, Reason: "translation"},
+			{Object: ingressRef, Source: resourceFailureSourceTranslation, Reason: "translation"},
+			{Object: serviceRef, Source: resourceFailureSourceKong, Reason: "rejected"},
+		} {
+			actual := response.Failures[i]
+			assert.True(t, syncTimestamp.Equal(actual.SyncTimestamp))
+			actual.SyncTimestamp = time.Time{}
+			assert.Equal(t, expected, actual)
+		}
+	})
+
+	t.Run("failures are not shared between servers", func(t *testing.T) {
+		other := &Server{Logger: logr.Discard(), ConfigLock: &sync.RWMutex{}}
+		otherMux := http.NewServeMux()
+		other.installDumpHandlers(otherMux)
+
+		rw := httptest.NewRecorder()
+		otherMux.ServeHTTP(rw, httptest.NewRequest(http.MethodGet, "/debug/config/resource-failures", nil))
+		require.Equal(t, http.StatusOK, rw.Code)
+		var response resourceFailuresResponse
+		require.NoError(t, json.NewDecoder(rw.Body).Decode(&response))
+		assert.Nil(t, response.SyncTimestamp)
+		assert.Empty(t, response.Failures)
+	})
+}
+
+func TestServerConfigDiff(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	s := &Server{
+		Logger:     logr.Discard(),
+		ConfigLock: &sync.RWMutex{},
+		ConfigDumps: util.ConfigDumpDiagnostic{
+			Configs:  make(chan util.ConfigDump, 2),
+			Failures: make(chan util.FailuresDump, 1),
+		},
+	}
+	mux := http.NewServeMux()
+	s.installDumpHandlers(mux)
+	go s.receiveConfig(ctx)
+
+	getDiff := func() *httptest.ResponseRecorder {
+		rw := httptest.NewRecorder()
+		mux.ServeHTTP(rw, httptest.NewRequest(http.MethodGet, "/debug/config/diff", nil))
+		return rw
+	}
+
+	require.Equal(t, http.StatusNotFound, getDiff().Code, "no diff should be served before a failed configuration")
+
+	s.ConfigDumps.Configs <- util.ConfigDump{Config: file.Content{
+		Services: []file.FService{{Service: kong.Service{Name: kong.String("svc"), Port: kong.Int(80)}}},
+	}}
+	s.ConfigDumps.Configs <- util.ConfigDump{Failed: true, Config: file.Content{
+		Services: []file.FService{{Service: kong.Service{Name: kong.String("svc"), Port: kong.Int(8080)}}},
+	}}
+
+	var rw *httptest.ResponseRecorder
+	require.Eventually(t, func() bool {
+		rw = getDiff()
+		return rw.Code == http.StatusOK
+	}, time.Second, 10*time.Millisecond)
+	var diff configDiffResponse
+	require.NoError(t, json.NewDecoder(rw.Body).Decode(&diff))
+	require.Len(t, diff.Changed, 1)
+	assert.Equal(t, entityTypeService, diff.Changed[0].Type)
+	assert.Equal(t, []string{"port"}, diff.Changed[0].ChangedFields)
+}
@@ -14,11 +14,13 @@ type (
 	BackendObjectReference    = gatewayv1.BackendObjectReference
 	BackendRef                = gatewayv1.BackendRef
 	CommonRouteSpec           = gatewayv1.CommonRouteSpec
+	Duration                  = gatewayv1.Duration
 	Gateway                   = gatewayv1.Gateway
 	GatewayAddress            = gatewayv1.GatewayAddress
 	GatewayClass              = gatewayv1.GatewayClass
 	GatewayClassSpec          = gatewayv1.GatewayClassSpec
 	GatewayClassStatus        = gatewayv1.GatewayClassStatus
+	GatewayConditionReason    = gatewayv1.GatewayConditionReason
 	GatewayController         = gatewayv1.GatewayController
 	GatewayList               = gatewayv1.GatewayList
 	GatewaySpec               = gatewayv1.GatewaySpec
@@ -32,8 +34,10 @@ type (
 	HTTPHeaderMatch           = gatewayv1.HTTPHeaderMatch
 	HTTPHeaderName            = gatewayv1.HTTPHeaderName
 	HTTPMethod                = gatewayv1.HTTPMethod
+	HTTPPathModifier          = gatewayv1.HTTPPathModifier
 	HTTPPathMatch             = gatewayv1.HTTPPathMatch
 	HTTPQueryParamMatch       = gatewayv1.HTTPQueryParamMatch
+	HTTPRequestMirrorFilter   = gatewayv1.HTTPRequestMirrorFilter
 	HTTPRequestRedirectFilter = gatewayv1.HTTPRequestRedirectFilter
 	HTTPRoute                 = gatewayv1.HTTPRoute
 	HTTPRouteFilter           = gatewayv1.HTTPRouteFilter
@@ -42,12 +46,15 @@ type (
 	HTTPRouteRule             = gatewayv1.HTTPRouteRule
 	HTTPRouteSpec             = gatewayv1.HTTPRouteSpec
 	HTTPRouteStatus           = gatewayv1.HTTPRouteStatus
+	HTTPRouteTimeouts         = gatewayv1.HTTPRouteTimeouts
+	HTTPURLRewriteFilter      = gatewayv1.HTTPURLRewriteFilter
 	Hostname                  = gatewayv1.Hostname
 	Kind                      = gatewayv1.Kind
 	Listener                  = gatewayv1.Listener
 	ListenerConditionReason   = gatewayv1.ListenerConditionReason
 	ListenerConditionType     = gatewayv1.ListenerConditionType
 	ListenerStatus            = gatewayv1.ListenerStatus
+	LocalObjectReference      = gatewayv1.LocalObjectReference
 	Namespace                 = gatewayv1.Namespace
 	ObjectName                = gatewayv1.ObjectName
 	ParentReference           = gatewayv1.ParentReference
@@ -103,6 +110,7 @@ const (
 	GatewayConditionAccepted              = gatewayv1.GatewayConditionAccepted
 	GatewayConditionProgrammed            = gatewayv1.GatewayConditionProgrammed
 	GatewayReasonAccepted                 = gatewayv1.GatewayReasonAccepted
+	GatewayReasonInvalid                  = gatewayv1.GatewayReasonInvalid
 	GatewayReasonPending                  = gatewayv1.GatewayReasonPending
 	GatewayReasonProgrammed               = gatewayv1.GatewayReasonProgrammed
 	HTTPMethodDelete                      = gatewayv1.HTTPMethodDelete
@@ -141,6 +149,7 @@ const (
 	PathMatchExact                        = gatewayv1.PathMatchExact
 	PathMatchPathPrefix                   = gatewayv1.PathMatchPathPrefix
 	PathMatchRegularExpression            = gatewayv1.PathMatchRegularExpression
+	PrefixMatchHTTPPathModifier           = gatewayv1.PrefixMatchHTTPPathModifier
 	QueryParamMatchExact                  = gatewayv1.QueryParamMatchExact
 	QueryParamMatchRegularExpression      = gatewayv1.QueryParamMatchRegularExpression
 	RouteConditionAccepted                = gatewayv1.RouteConditionAccepted
@@ -153,6 +162,7 @@ const (
 	RouteReasonNotAllowedByListeners      = gatewayv1.RouteReasonNotAllowedByListeners
 	RouteReasonRefNotPermitted            = gatewayv1.RouteReasonRefNotPermitted
 	RouteReasonResolvedRefs               = gatewayv1.RouteReasonResolvedRefs
+	RouteReasonUnsupportedValue           = gatewayv1.RouteReasonUnsupportedValue
 	TCPProtocolType                       = gatewayv1.TCPProtocolType
 	TLSModePassthrough                    = gatewayv1.TLSModePassthrough
 	TLSModeTerminate                      = gatewayv1.TLSModeTerminate
@@ -83,4 +83,24 @@ var (
 		Version:  gatewayv1beta1.GroupVersion.Version,
 		Resource: "httproutes",
 	}
+	GRPCRouteGVResource = metav1.GroupVersionResource{
+		Group:    gatewayv1alpha2.GroupVersion.Group,
+		Version:  gatewayv1alpha2.GroupVersion.Version,
+		Resource: "grpcroutes",
+	}
+	TCPRouteGVResource = metav1.GroupVersionResource{
+		Group:    gatewayv1alpha2.GroupVersion.Group,
+		Version:  gatewayv1alpha2.GroupVersion.Version,
+		Resource: "tcproutes",
+	}
+	TLSRouteGVResource = metav1.GroupVersionResource{
+		Group:    gatewayv1alpha2.GroupVersion.Group,
+		Version:  gatewayv1alpha2.GroupVersion.Version,
+		Resource: "tlsroutes",
+	}
+	UDPRouteGVResource = metav1.GroupVersionResource{
+		Group:    gatewayv1alpha2.GroupVersion.Group,
+		Version:  gatewayv1alpha2.GroupVersion.Version,
+		Resource: "udproutes",
+	}
 )
@@ -58,21 +58,30 @@ type Config struct {
 	GracefulShutdownTimeout           *time.Duration
 
 	// Kong Proxy configurations
-	APIServerHost               string
-	APIServerQPS                int
-	APIServerBurst              int
-	APIServerCAData             []byte
-	APIServerCertData           []byte
-	APIServerKeyData            []byte
-	MetricsAddr                 string
-	ProbeAddr                   string
-	KongAdminURLs               []string
-	KongAdminSvc                OptionalNamespacedName
-	GatewayDiscoveryDNSStrategy cfgtypes.DNSStrategy
-	KongAdminSvcPortNames       []string
-	ProxySyncSeconds            float32
-	InitCacheSyncDuration       time.Duration
-	ProxyTimeoutSeconds         float32
+	APIServerHost                    string
+	APIServerQPS                     int
+	APIServerBurst                   int
+	APIServerCAData                  []byte
+	APIServerCertData                []byte
+	APIServerKeyData                 []byte
+	MetricsAddr                      string
+	ProbeAddr                        string
+	KongAdminURLs                    []string
+	KongAdminSvc                     OptionalNamespacedName
+	GatewayDiscoveryDNSStrategy      cfgtypes.DNSStrategy
+	KongAdminSvcPortNames            []string
+	ProxySyncSeconds                 float32
+	ProxySyncDebounce                time.Duration
+	ProxySyncMaxStaleness            time.Duration
+	ProxyResyncPeriod                time.Duration
+	InitCacheSyncDuration            time.Duration
+	ProxyTimeoutSeconds              float32
+	LastValidConfigSecret            string
+	LastValidConfigEncryptionKeyFile string
+	ProxyCanaryPercentage            int
+	ProxyCanarySoakTime              time.Duration
+	ProxyCanaryProbeURL              string
+	ProxyCanaryProbeErrorRate        float64
 
 	// Kubernetes configurations
 	KubeconfigPath           string
@@ -83,8 +92,12 @@ type Config struct {
 	FilterTags               []string
 	WatchNamespaces          []string
 	GatewayAPIControllerName string
+	ManagedGatewayImage      string
 	Impersonate              string
 
+	// Managed Gateways configurations
+	ManagedGatewayAdminClientCACertFile string
+
 	// Ingress status
 	PublishServiceUDP       OptionalNamespacedName
 	PublishService          OptionalNamespacedName
@@ -186,12 +199,34 @@ func (c *Config) FlagSet() *pflag.FlagSet {
 	flagSet.StringVar(&c.MetricsAddr, "metrics-bind-address", fmt.Sprintf(":%v", MetricsPort), "The address the metric endpoint binds to.")
 	flagSet.StringVar(&c.ProbeAddr, "health-probe-bind-address", fmt.Sprintf(":%v", HealthzPort), "The address the probe endpoint binds to.")
 	flagSet.Float32Var(&c.ProxySyncSeconds, "proxy-sync-seconds", dataplane.DefaultSyncSeconds,
-		"Define the rate (in seconds) in which configuration updates will be applied to the Kong Admin API.")
+		"Define the minimum interval (in seconds) between configuration updates applied to the Kong Admin API.")
+	flagSet.DurationVar(&c.ProxySyncDebounce, "proxy-sync-debounce", dataplane.DefaultSyncDebounce,
+		"Time to wait for further changes of Kubernetes objects after a change before applying configuration to the Kong Admin API.")
+	flagSet.DurationVar(&c.ProxySyncMaxStaleness, "proxy-sync-max-staleness", dataplane.DefaultSyncMaxStaleness,
+		"Maximum time a change of Kubernetes objects can wait to be applied to the Kong Admin API when further changes keep postponing it.")
+	flagSet.DurationVar(&c.ProxyResyncPeriod, "proxy-resync-period", dataplane.DefaultResyncPeriod,
+		"Period of configuration updates applied to the Kong Admin API regardless of changes of Kubernetes objects. Set to 0 to disable them.")
 	flagSet.Float32Var(&c.ProxyTimeoutSeconds, "proxy-timeout-seconds", dataplane.DefaultTimeoutSeconds,
 		"Sets the timeout (in seconds) for all requests to Kong's Admin API.")
+	flagSet.StringVar(&c.LastValidConfigSecret, "last-valid-config-secret", "",
+		`Name of a Secret in the controller's namespace to persist the last valid configuration in, so that it can be used as a fallback after restarts. Leave this empty to keep it only in memory.`)
+	flagSet.StringVar(&c.LastValidConfigEncryptionKeyFile, "last-valid-config-encryption-key-file", "",
+		`Path to a file containing a base64-encoded 32-byte key the configuration persisted with --last-valid-config-secret is encrypted with. Required when --last-valid-config-secret is set.`)
+	flagSet.IntVar(&c.ProxyCanaryPercentage, "proxy-canary-percentage", 0,
+		`Percentage of Kong Gateways to apply configuration to first, before applying it to the rest of them when they stay healthy. At least one Gateway is a canary. Set to 0 to apply configuration to all Gateways at once.`)
+	flagSet.DurationVar(&c.ProxyCanarySoakTime, "proxy-canary-soak-time", dataplane.DefaultCanarySoakTime,
+		`Time to wait after applying configuration to canary Gateways before checking their health.`)
+	flagSet.StringVar(&c.ProxyCanaryProbeURL, "proxy-canary-probe-url", "",
+		`URL requested to check the health of canary Gateways in addition to their Admin API status. `+dataplane.CanaryProbeHostPlaceholder+` is replaced with the host of a canary's Admin API URL. Requests that fail, time out after --proxy-timeout-seconds or get 5xx responses are errors.`)
+	flagSet.Float64Var(&c.ProxyCanaryProbeErrorRate, "proxy-canary-probe-max-error-rate", 0,
+		`Maximum rate (between 0 and 1) of failed requests to --proxy-canary-probe-url for canary Gateways to be considered healthy.`)
 
 	// Kubernetes configurations
 	flagSet.Var(flags.NewValidatedValue(&c.GatewayAPIControllerName, gatewayAPIControllerNameFromFlagValue, flags.WithDefault(string(gateway.GetControllerName()))), "gateway-api-controller-name", "The controller name to match on Gateway API resources.")
+	flagSet.StringVar(&c.ManagedGatewayImage, "managed-gateway-image", gateway.DefaultManagedGatewayImage,
+		"Kong Gateway image to run in data-planes provisioned for Gateways of managed GatewayClasses, unless overridden with the konghq.com/managed-gateway-image annotation of a GatewayClass. Used only when the ManagedGateways feature gate is enabled.")
+	flagSet.StringVar(&c.ManagedGatewayAdminClientCACertFile, "managed-gateway-admin-client-ca-cert-file", "",
+		"CA certificate file Admin APIs of data-planes provisioned for managed Gateways verify client certificates with. Defaults to the certificate set with --kong-admin-tls-client-cert(-file), which has to be self-signed then. Used only when the ManagedGateways feature gate is enabled.")
 	flagSet.StringVar(&c.KubeconfigPath, "kubeconfig", "", "Path to the kubeconfig file.")
 	flagSet.StringVar(&c.IngressClassName, "ingress-class", annotations.DefaultIngressClass, `Name of the ingress class to route through this controller.`)
 	flagSet.StringVar(&c.LeaderElectionID, "election-id", "5b374a9e.konghq.com", `Election id to use for status update.`)
@@ -242,6 +277,10 @@ func (c *Config) FlagSet() *pflag.FlagSet {
 		`admission server PEM certificate value`)
 	flagSet.StringVar(&c.AdmissionServer.Key, "admission-webhook-key", "",
 		`admission server PEM private key value`)
+	flagSet.Var(flags.NewValidatedValue(&c.AdmissionServer.RouteConflictPolicy, routeConflictPolicyFromFlagValue, flags.WithDefault(admission.RouteConflictPolicyOff), flags.WithTypeNameOverride[admission.RouteConflictPolicy]("route-conflict-policy")),
+		"admission-webhook-route-conflict-policy", `How the admission webhook handles Ingresses and HTTPRoutes whose routes exactly overlap with routes of other objects. One of: off, warn, deny.`)
+	flagSet.BoolVar(&c.AdmissionServer.EnableMutation, "admission-webhook-enable-mutation", false,
+		`Serve the mutating admission webhook normalizing deprecated configuration at `+admission.MutatingWebhookPath+` path of the admission server.`)
 
 	// Diagnostics
 	flagSet.BoolVar(&c.EnableProfiling, "profiling", false, fmt.Sprintf("Enable profiling via web interface host:%v/debug/pprof/", DiagnosticsPort))
@@ -309,6 +348,23 @@ func (c *Config) Resolve() error {
 	return nil
 }
 
+// managedGatewayAdminClientCACert returns the PEM-encoded CA certificate Admin APIs of data-planes provisioned for
+// managed Gateways verify client certificates with.
+func (c *Config) managedGatewayAdminClientCACert() (string, error) {
+	path := c.ManagedGatewayAdminClientCACertFile
+	if path == "" {
+		if c.KongAdminAPIConfig.TLSClient.Cert != "" {
+			return c.KongAdminAPIConfig.TLSClient.Cert, nil
+		}
+		path = c.KongAdminAPIConfig.TLSClient.CertFile
+	}
+	cert, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to read managed gateways Admin API client CA certificate from path '%s': %w", path, err)
+	}
+	return string(cert), nil
+}
+
 func (c *Config) GetKubeconfig() (*rest.Config, error) {
 	config, err := clientcmd.BuildConfigFromFlags(c.APIServerHost, c.KubeconfigPath)
 	if err != nil {
@@ -10,7 +10,9 @@ import (
 	k8stypes "k8s.io/apimachinery/pkg/types"
 
 	"github.com/kong/kubernetes-ingress-controller/v2/internal/adminapi"
+	"github.com/kong/kubernetes-ingress-controller/v2/internal/admission"
 	cfgtypes "github.com/kong/kubernetes-ingress-controller/v2/internal/manager/config/types"
+	"github.com/kong/kubernetes-ingress-controller/v2/internal/manager/featuregates"
 	dataplaneutil "github.com/kong/kubernetes-ingress-controller/v2/internal/util/dataplane"
 )
 
@@ -53,6 +55,14 @@ func dnsStrategyFromFlagValue(flagValue string) (cfgtypes.DNSStrategy, error) {
 	return strategy, nil
 }
 
+func routeConflictPolicyFromFlagValue(flagValue string) (admission.RouteConflictPolicy, error) {
+	policy := admission.RouteConflictPolicy(flagValue)
+	if err := policy.Validate(); err != nil {
+		return admission.RouteConflictPolicy(""), err
+	}
+	return policy, nil
+}
+
 // Validate validates the config. It should be used to validate the config variables' interdependencies.
 // When a single variable is to be validated, *FromFlagValue function should be implemented.
 func (c *Config) Validate() error {
@@ -71,6 +81,15 @@ func (c *Config) Validate() error {
 	if err := c.validateKongAdminAPI(); err != nil {
 		return fmt.Errorf("invalid kong admin api configuration: %w", err)
 	}
+	if err := c.validateManagedGateways(); err != nil {
+		return fmt.Errorf("invalid managed gateways configuration: %w", err)
+	}
+	if err := c.validateCanaryRollout(); err != nil {
+		return fmt.Errorf("invalid canary rollout configuration: %w", err)
+	}
+	if err := c.validateLastValidConfigPersistence(); err != nil {
+		return fmt.Errorf("invalid last valid configuration persistence configuration: %w", err)
+	}
 
 	return nil
 }
@@ -106,6 +125,50 @@ func (c *Config) validateKongAdminAPI() error {
 	return nil
 }
 
+func (c *Config) validateManagedGateways() error {
+	if !c.FeatureGates[featuregates.ManagedGatewaysFeature] {
+		return nil
+	}
+
+	// Admin APIs of the provisioned data-planes are configured along with the discovered ones,
+	// which is possible only when the Admin API endpoints are dynamically managed.
+	if c.KongAdminSvc.IsAbsent() {
+		return fmt.Errorf("--kong-admin-svc has to be set when using the %s feature gate", featuregates.ManagedGatewaysFeature)
+	}
+	if c.ManagedGatewayImage == "" {
+		return errors.New("--managed-gateway-image cannot be empty")
+	}
+	// Admin APIs of the provisioned data-planes are exposed only to clients presenting a certificate,
+	// so the controller needs one.
+	if c.KongAdminAPIConfig.TLSClient.Cert == "" && c.KongAdminAPIConfig.TLSClient.CertFile == "" {
+		return fmt.Errorf("--kong-admin-tls-client-cert or --kong-admin-tls-client-cert-file has to be set when using the %s feature gate", featuregates.ManagedGatewaysFeature)
+	}
+	return nil
+}
+
+func (c *Config) validateCanaryRollout() error {
+	if c.ProxyCanaryPercentage < 0 || c.ProxyCanaryPercentage > 100 {
+		return fmt.Errorf("--proxy-canary-percentage has to be between 0 and 100, got %d", c.ProxyCanaryPercentage)
+	}
+	if c.ProxyCanarySoakTime < 0 {
+		return fmt.Errorf("--proxy-canary-soak-time cannot be negative, got %s", c.ProxyCanarySoakTime)
+	}
+	if c.ProxyCanaryProbeErrorRate < 0 || c.ProxyCanaryProbeErrorRate > 1 {
+		return fmt.Errorf("--proxy-canary-probe-max-error-rate has to be between 0 and 1, got %v", c.ProxyCanaryProbeErrorRate)
+	}
+	return nil
+}
+
+func (c *Config) validateLastValidConfigPersistence() error {
+	if c.LastValidConfigSecret == "" {
+		return nil
+	}
+	if c.LastValidConfigEncryptionKeyFile == "" {
+		return errors.New("--last-valid-config-encryption-key-file has to be set when using --last-valid-config-secret")
+	}
+	return nil
+}
+
 func validateClientTLS(clientTLS adminapi.TLSClientConfig) error {
 	if clientTLS.Cert != "" && clientTLS.CertFile != "" {
 		return errors.New("both client certificate and client certificate file specified, only one allowed")
@@ -140,3 +203,16 @@ func (c *Config) ValidateGatewayDiscovery(dbMode string) error {
 	}
 	return nil
 }
+
+// ValidateManagedGateways returns error if managed Gateways are enabled and dbMode is not configured to db-less mode.
+// Data-planes provisioned for managed Gateways run in db-less mode, so they can't be configured along with
+// db-backed Kong Gateways.
+func (c *Config) ValidateManagedGateways(dbMode string) error {
+	if !c.FeatureGates[featuregates.ManagedGatewaysFeature] {
+		return nil
+	}
+	if !dataplaneutil.IsDBLessMode(dbMode) {
+		return fmt.Errorf("the %s feature gate is only supported in dbless mode, not db %s", featuregates.ManagedGatewaysFeature, dbMode)
+	}
+	return nil
+}
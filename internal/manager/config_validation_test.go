@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"fmt"
 	"testing"
+	"time"
 
 	"github.com/samber/mo"
 	"github.com/stretchr/testify/require"
@@ -12,6 +13,7 @@ import (
 	"github.com/kong/kubernetes-ingress-controller/v2/internal/adminapi"
 	"github.com/kong/kubernetes-ingress-controller/v2/internal/controllers/gateway"
 	"github.com/kong/kubernetes-ingress-controller/v2/internal/manager"
+	"github.com/kong/kubernetes-ingress-controller/v2/internal/manager/featuregates"
 )
 
 func TestConfigValidatedVars(t *testing.T) {
@@ -284,6 +286,98 @@ func TestConfigValidate(t *testing.T) {
 			require.ErrorContains(t, c.Validate(), "both admin token and admin token file specified, only one allowed")
 		})
 	})
+
+	t.Run("Managed Gateways", func(t *testing.T) {
+		validWithManagedGateways := func() manager.Config {
+			return manager.Config{
+				FeatureGates:        map[string]bool{featuregates.ManagedGatewaysFeature: true},
+				KongAdminSvc:        mo.Some(k8stypes.NamespacedName{Namespace: "kong", Name: "admin-svc"}),
+				ManagedGatewayImage: gateway.DefaultManagedGatewayImage,
+				KongAdminAPIConfig: adminapi.HTTPClientOpts{
+					TLSClient: adminapi.TLSClientConfig{CertFile: "client.crt", KeyFile: "client.key"},
+				},
+			}
+		}
+
+		t.Run("enabled with gateway discovery is accepted", func(t *testing.T) {
+			c := validWithManagedGateways()
+			require.NoError(t, c.Validate())
+		})
+
+		t.Run("enabled with no gateway discovery is rejected", func(t *testing.T) {
+			c := validWithManagedGateways()
+			c.KongAdminSvc = manager.OptionalNamespacedName{}
+			require.ErrorContains(t, c.Validate(), "--kong-admin-svc has to be set when using the ManagedGateways feature gate")
+		})
+
+		t.Run("enabled with no image is rejected", func(t *testing.T) {
+			c := validWithManagedGateways()
+			c.ManagedGatewayImage = ""
+			require.ErrorContains(t, c.Validate(), "--managed-gateway-image cannot be empty")
+		})
+
+		t.Run("enabled with no admin api client certificate is rejected", func(t *testing.T) {
+			c := validWithManagedGateways()
+			c.KongAdminAPIConfig.TLSClient = adminapi.TLSClientConfig{}
+			require.ErrorContains(t, c.Validate(), "--kong-admin-tls-client-cert or --kong-admin-tls-client-cert-file has to be set when using the ManagedGateways feature gate")
+		})
+
+		t.Run("disabled should not require gateway discovery", func(t *testing.T) {
+			c := validWithManagedGateways()
+			c.FeatureGates = nil
+			c.KongAdminSvc = manager.OptionalNamespacedName{}
+			require.NoError(t, c.Validate())
+		})
+	})
+
+	t.Run("Canary Rollout", func(t *testing.T) {
+		validWithCanaryRollout := func() manager.Config {
+			return manager.Config{
+				ProxyCanaryPercentage:     20,
+				ProxyCanarySoakTime:       time.Second,
+				ProxyCanaryProbeURL:       "http://{host}:8000/health",
+				ProxyCanaryProbeErrorRate: 0.1,
+			}
+		}
+
+		t.Run("valid configuration is accepted", func(t *testing.T) {
+			c := validWithCanaryRollout()
+			require.NoError(t, c.Validate())
+		})
+
+		t.Run("percentage above 100 is rejected", func(t *testing.T) {
+			c := validWithCanaryRollout()
+			c.ProxyCanaryPercentage = 101
+			require.ErrorContains(t, c.Validate(), "--proxy-canary-percentage has to be between 0 and 100")
+		})
+
+		t.Run("negative soak time is rejected", func(t *testing.T) {
+			c := validWithCanaryRollout()
+			c.ProxyCanarySoakTime = -time.Second
+			require.ErrorContains(t, c.Validate(), "--proxy-canary-soak-time cannot be negative")
+		})
+
+		t.Run("probe error rate above 1 is rejected", func(t *testing.T) {
+			c := validWithCanaryRollout()
+			c.ProxyCanaryProbeErrorRate = 1.5
+			require.ErrorContains(t, c.Validate(), "--proxy-canary-probe-max-error-rate has to be between 0 and 1")
+		})
+	})
+
+	t.Run("Last Valid Config Persistence", func(t *testing.T) {
+		t.Run("secret with encryption key file is accepted", func(t *testing.T) {
+			c := manager.Config{
+				LastValidConfigSecret:            "last-valid-config",
+				LastValidConfigEncryptionKeyFile: "/etc/secrets/last-valid-config-key/key",
+			}
+			require.NoError(t, c.Validate())
+		})
+
+		t.Run("secret with no encryption key file is rejected", func(t *testing.T) {
+			c := manager.Config{LastValidConfigSecret: "last-valid-config"}
+			require.ErrorContains(t, c.Validate(), "--last-valid-config-encryption-key-file has to be set")
+		})
+	})
 }
 
 func TestConfigValidateGatewayDiscovery(t *testing.T) {
@@ -335,3 +429,46 @@ func TestConfigValidateGatewayDiscovery(t *testing.T) {
 		})
 	}
 }
+
+func TestConfigValidateManagedGateways(t *testing.T) {
+	testCases := []struct {
+		name            string
+		managedGateways bool
+		dbMode          string
+		expectError     bool
+	}{
+		{
+			name:            "managed gateways disabled should pass in db-backed mode",
+			managedGateways: false,
+			dbMode:          "postgres",
+			expectError:     false,
+		},
+		{
+			name:            "managed gateways enabled should pass in db-less mode",
+			managedGateways: true,
+			dbMode:          "off",
+			expectError:     false,
+		},
+		{
+			name:            "managed gateways enabled should not pass in db-backed mode",
+			managedGateways: true,
+			dbMode:          "postgres",
+			expectError:     true,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			c := &manager.Config{
+				FeatureGates: map[string]bool{featuregates.ManagedGatewaysFeature: tc.managedGateways},
+			}
+			err := c.ValidateManagedGateways(tc.dbMode)
+			if !tc.expectError {
+				require.NoError(t, err)
+			} else {
+				require.Error(t, err)
+			}
+		})
+	}
+}
@@ -17,6 +17,7 @@ import (
 	"github.com/kong/kubernetes-ingress-controller/v2/internal/controllers/gateway"
 	ctrlref "github.com/kong/kubernetes-ingress-controller/v2/internal/controllers/reference"
 	"github.com/kong/kubernetes-ingress-controller/v2/internal/dataplane"
+	"github.com/kong/kubernetes-ingress-controller/v2/internal/dataplane/parser"
 	"github.com/kong/kubernetes-ingress-controller/v2/internal/manager/featuregates"
 	"github.com/kong/kubernetes-ingress-controller/v2/internal/util/kubernetes/object/status"
 )
@@ -65,6 +66,8 @@ func setupControllers(
 	featureGates map[string]bool,
 	kongAdminAPIEndpointsNotifier configuration.EndpointsNotifier,
 	adminAPIsDiscoverer configuration.AdminAPIsDiscoverer,
+	managedGateways gateway.ManagedGatewaysConfig,
+	parserFeatureFlags parser.FeatureFlags,
 ) []ControllerDef {
 	referenceIndexers := ctrlref.NewCacheIndexers(ctrl.LoggerFrom(ctx).WithName("controllers").WithName("reference-indexers"))
 
@@ -271,6 +274,7 @@ func setupControllers(
 					WatchNamespaces:      c.WatchNamespaces,
 					CacheSyncTimeout:     c.CacheSyncTimeout,
 					ReferenceIndexers:    referenceIndexers,
+					ManagedGateways:      managedGateways,
 				},
 			},
 		},
@@ -286,12 +290,15 @@ func setupControllers(
 					Resource: "httproutes",
 				}),
 				Controller: &gateway.HTTPRouteReconciler{
-					Client:           mgr.GetClient(),
-					Log:              ctrl.LoggerFrom(ctx).WithName("controllers").WithName("HTTPRoute"),
-					Scheme:           mgr.GetScheme(),
-					DataplaneClient:  dataplaneClient,
-					CacheSyncTimeout: c.CacheSyncTimeout,
-					StatusQueue:      kubernetesStatusQueue,
+					Client:                 mgr.GetClient(),
+					Log:                    ctrl.LoggerFrom(ctx).WithName("controllers").WithName("HTTPRoute"),
+					Scheme:                 mgr.GetScheme(),
+					DataplaneClient:        dataplaneClient,
+					CacheSyncTimeout:       c.CacheSyncTimeout,
+					StatusQueue:            kubernetesStatusQueue,
+					ExpressionRoutes:       parserFeatureFlags.ExpressionRoutes,
+					QueryParamsGuardPlugin: parserFeatureFlags.QueryParamsGuardPlugin,
+					RequestMirroring:       parserFeatureFlags.RequestMirroring,
 				},
 			},
 		},
@@ -25,6 +25,19 @@ const (
 	// RewriteURIsFeature is the name of the feature-gate for enabling/disabling konghq.com/rewrite annotation.
 	RewriteURIsFeature = "RewriteURIs"
 
+	// RequestMirroringFeature is the name of the feature-gate for enabling/disabling translation of HTTPRoute
+	// RequestMirror filters. It requires Kong's sandbox to allow the modules the mirroring code requires.
+	RequestMirroringFeature = "RequestMirroring"
+
+	// ManagedGatewaysFeature is the name of the feature-gate for enabling/disabling the managed GatewayClass mode,
+	// in which Kong data-planes are provisioned for Gateways of GatewayClasses not annotated as unmanaged.
+	ManagedGatewaysFeature = "ManagedGateways"
+
+	// FallbackConfigurationFeature is the name of the feature-gate for enabling/disabling the fallback configuration
+	// mode, in which objects rejected by Kong are excluded from the configuration instead of the whole configuration
+	// being replaced with the last valid one.
+	FallbackConfigurationFeature = "FallbackConfiguration"
+
 	// DocsURL provides a link to the documentation for feature gates in the KIC repository.
 	DocsURL = "https://github.com/Kong/kubernetes-ingress-controller/blob/main/FEATURE_GATES.md"
 )
@@ -60,9 +73,12 @@ func (fg FeatureGates) Enabled(feature string) bool {
 // NOTE: if you're adding a new feature gate, it needs to be added here.
 func GetFeatureGatesDefaults() map[string]bool {
 	return map[string]bool{
-		GatewayFeature:      true,
-		GatewayAlphaFeature: false,
-		FillIDsFeature:      true,
-		RewriteURIsFeature:  false,
+		GatewayFeature:               true,
+		GatewayAlphaFeature:          false,
+		FillIDsFeature:               true,
+		RewriteURIsFeature:           false,
+		RequestMirroringFeature:      false,
+		ManagedGatewaysFeature:       false,
+		FallbackConfigurationFeature: false,
 	}
 }
@@ -19,6 +19,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/manager"
 
 	"github.com/kong/kubernetes-ingress-controller/v2/internal/adminapi"
+	"github.com/kong/kubernetes-ingress-controller/v2/internal/admission/validation/consumers/credentials"
 	"github.com/kong/kubernetes-ingress-controller/v2/internal/clients"
 	"github.com/kong/kubernetes-ingress-controller/v2/internal/controllers/gateway"
 	"github.com/kong/kubernetes-ingress-controller/v2/internal/dataplane"
@@ -115,6 +116,9 @@ func Run(
 	if err != nil {
 		return err
 	}
+	if err := c.ValidateManagedGateways(kongStartUpConfig.DBMode); err != nil {
+		return err
+	}
 
 	kongSemVersion := semver.Version{Major: v.Major(), Minor: v.Minor(), Patch: v.Patch()}
 
@@ -166,27 +170,41 @@ func Run(
 		logger,
 		featureGates,
 		routerFlavor,
+		kongStartUpConfig.AvailablePlugins,
 		c.UpdateStatus,
 	)
 
+	cache := store.NewCacheStores()
+	storer := store.New(cache, c.IngressClassName, logger)
+
+	// Credential types unknown to the controller can only be sent to DB-less gateways, as decK doesn't support them.
+	credentialRegistry := credentials.NewRegistry(clientsManager, dataplaneutil.IsDBLessMode(dbMode))
+
 	setupLog.Info("Starting Admission Server")
-	if err := setupAdmissionServer(ctx, c, clientsManager, mgr.GetClient(), logger, parserFeatureFlags); err != nil {
+	if err := setupAdmissionServer(
+		ctx, c, clientsManager, mgr.GetClient(), logger, parserFeatureFlags, storer, credentialRegistry,
+	); err != nil {
 		return err
 	}
 
-	cache := store.NewCacheStores()
 	configParser, err := parser.NewParser(
 		logger,
-		store.New(cache, c.IngressClassName, logger),
+		storer,
 		parserFeatureFlags,
 	)
 	if err != nil {
 		return fmt.Errorf("failed to create parser: %w", err)
 	}
+	configParser.InjectCredentialRegistry(credentialRegistry)
 
 	updateStrategyResolver := sendconfig.NewDefaultUpdateStrategyResolver(kongConfig, logger)
 	configurationChangeDetector := sendconfig.NewDefaultConfigurationChangeDetector(logger)
 	kongConfigFetcher := configfetcher.NewDefaultKongLastGoodConfigFetcher(parserFeatureFlags.FillIDs)
+	if c.LastValidConfigSecret != "" {
+		if err := setupLastValidConfigPersistence(ctx, setupLog, c, kongConfigFetcher); err != nil {
+			return fmt.Errorf("failed to set up last valid configuration persistence: %w", err)
+		}
+	}
 	dataplaneClient, err := dataplane.NewKongClient(
 		logger,
 		time.Duration(c.ProxyTimeoutSeconds*float32(time.Second)),
@@ -205,9 +223,24 @@ func Run(
 	if err != nil {
 		return fmt.Errorf("failed to initialize kong data-plane client: %w", err)
 	}
+	if c.ProxyCanaryPercentage > 0 {
+		dataplaneClient.EnableCanaryRollout(canaryRolloutConfig(c))
+	}
+	if featureGates.Enabled(featuregates.FallbackConfigurationFeature) {
+		if dataplaneutil.IsDBLessMode(dbMode) {
+			dataplaneClient.EnableFallbackConfiguration()
+		} else {
+			setupLog.Info("FallbackConfiguration feature gate is only supported with DB-less Kong Gateways, ignoring it")
+		}
+	}
 
 	setupLog.Info("Initializing Dataplane Synchronizer")
-	synchronizer, err := setupDataplaneSynchronizer(logger, mgr, dataplaneClient, c.ProxySyncSeconds, c.InitCacheSyncDuration)
+	// Configuration has to be sent to Gateways as soon as they're discovered, not only when it changes.
+	var syncTriggers []<-chan struct{}
+	if gatewayClientsChanges, ok := clientsManager.SubscribeToGatewayClientsChanges(); ok {
+		syncTriggers = append(syncTriggers, coalesceNotifications(gatewayClientsChanges))
+	}
+	synchronizer, err := setupDataplaneSynchronizer(logger, mgr, dataplaneClient, c, syncTriggers...)
 	if err != nil {
 		return fmt.Errorf("unable to initialize dataplane synchronizer: %w", err)
 	}
@@ -227,6 +260,13 @@ func Run(
 		return err
 	}
 
+	var managedGatewayAdminClientCACert string
+	if featureGates[featuregates.ManagedGatewaysFeature] {
+		if managedGatewayAdminClientCACert, err = c.managedGatewayAdminClientCACert(); err != nil {
+			return err
+		}
+	}
+
 	setupLog.Info("Starting Enabled Controllers")
 	controllers := setupControllers(
 		ctx,
@@ -239,6 +279,15 @@ func Run(
 		featureGates,
 		clientsManager,
 		adminAPIsDiscoverer,
+		gateway.ManagedGatewaysConfig{
+			Enabled:             featureGates[featuregates.ManagedGatewaysFeature],
+			Image:               c.ManagedGatewayImage,
+			RouterFlavor:        routerFlavor,
+			AdminClientCACert:   managedGatewayAdminClientCACert,
+			AdminAPIsDiscoverer: adminAPIsDiscoverer,
+			AdminAPIsNotifier:   clientsManager,
+		},
+		parserFeatureFlags,
 	)
 	for _, c := range controllers {
 		if err := c.MaybeSetupWithManager(mgr); err != nil {
@@ -2,9 +2,13 @@ package manager
 
 import (
 	"context"
+	"encoding/base64"
 	"errors"
 	"fmt"
 	"io"
+	"net/http"
+	"os"
+	"strings"
 	"time"
 
 	"github.com/avast/retry-go/v4"
@@ -24,8 +28,11 @@ import (
 
 	"github.com/kong/kubernetes-ingress-controller/v2/internal/adminapi"
 	"github.com/kong/kubernetes-ingress-controller/v2/internal/admission"
+	"github.com/kong/kubernetes-ingress-controller/v2/internal/admission/lint"
+	"github.com/kong/kubernetes-ingress-controller/v2/internal/admission/validation/consumers/credentials"
 	"github.com/kong/kubernetes-ingress-controller/v2/internal/clients"
 	"github.com/kong/kubernetes-ingress-controller/v2/internal/dataplane"
+	"github.com/kong/kubernetes-ingress-controller/v2/internal/dataplane/configfetcher"
 	"github.com/kong/kubernetes-ingress-controller/v2/internal/dataplane/parser"
 	"github.com/kong/kubernetes-ingress-controller/v2/internal/manager/scheme"
 	"github.com/kong/kubernetes-ingress-controller/v2/internal/util"
@@ -136,22 +143,26 @@ func setupDataplaneSynchronizer(
 	logger logr.Logger,
 	mgr manager.Manager,
 	dataplaneClient dataplane.Client,
-	proxySyncSeconds float32,
-	initCacheSyncWait time.Duration,
+	c *Config,
+	syncTriggers ...<-chan struct{},
 ) (*dataplane.Synchronizer, error) {
-	if proxySyncSeconds < dataplane.DefaultSyncSeconds {
+	if c.ProxySyncSeconds < dataplane.DefaultSyncSeconds {
 		logger.Info(fmt.Sprintf(
 			"WARNING: --proxy-sync-seconds is configured for %fs, in DBLESS mode this may result in"+
 				" problems of inconsistency in the proxy state. For DBLESS mode %fs+ is recommended (3s is the default).",
-			proxySyncSeconds, dataplane.DefaultSyncSeconds,
+			c.ProxySyncSeconds, dataplane.DefaultSyncSeconds,
 		))
 	}
 
 	dataplaneSynchronizer, err := dataplane.NewSynchronizer(
 		logger.WithName("dataplane-synchronizer"),
 		dataplaneClient,
-		dataplane.WithStagger(time.Duration(proxySyncSeconds*float32(time.Second))),
-		dataplane.WithInitCacheSyncDuration(initCacheSyncWait),
+		dataplane.WithStagger(time.Duration(c.ProxySyncSeconds*float32(time.Second))),
+		dataplane.WithDebounce(c.ProxySyncDebounce),
+		dataplane.WithMaxStaleness(c.ProxySyncMaxStaleness),
+		dataplane.WithResyncPeriod(c.ProxyResyncPeriod),
+		dataplane.WithSyncTriggers(syncTriggers...),
+		dataplane.WithInitCacheSyncDuration(c.InitCacheSyncDuration),
 	)
 	if err != nil {
 		return nil, err
@@ -165,6 +176,79 @@ func setupDataplaneSynchronizer(
 	return dataplaneSynchronizer, nil
 }
 
+// coalesceNotifications returns a channel receiving notifications from the given one. The given channel is
+// drained continuously, also when nobody receives from the returned one (e.g. when the controller isn't
+// the leader), and notifications that aren't received in time are coalesced into a single one.
+func coalesceNotifications(notifications <-chan struct{}) <-chan struct{} {
+	coalesced := make(chan struct{}, 1)
+	go func() {
+		for range notifications {
+			select {
+			case coalesced <- struct{}{}:
+			default:
+			}
+		}
+	}()
+	return coalesced
+}
+
+// setupLastValidConfigPersistence makes the fetcher persist the last valid configuration in the Secret configured
+// with --last-valid-config-secret in the controller's namespace, encrypted with the key read from
+// --last-valid-config-encryption-key-file, and loads the configuration persisted there.
+// Failing to load the configuration isn't fatal, the controller just starts without it.
+func setupLastValidConfigPersistence(
+	ctx context.Context,
+	logger logr.Logger,
+	c *Config,
+	fetcher *configfetcher.DefaultKongLastGoodConfigFetcher,
+) error {
+	podNN, err := util.GetPodNN()
+	if err != nil {
+		return fmt.Errorf("failed to determine the controller's namespace: %w", err)
+	}
+	kubeClient, err := c.GetKubeClient()
+	if err != nil {
+		return fmt.Errorf("failed to get kubernetes client: %w", err)
+	}
+
+	encodedKey, err := os.ReadFile(c.LastValidConfigEncryptionKeyFile)
+	if err != nil {
+		return fmt.Errorf("failed to read encryption key file: %w", err)
+	}
+	encryptionKey, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(encodedKey)))
+	if err != nil {
+		return fmt.Errorf("failed to decode encryption key: %w", err)
+	}
+
+	secretNN := k8stypes.NamespacedName{Namespace: podNN.Namespace, Name: c.LastValidConfigSecret}
+	storage, err := configfetcher.NewSecretLastValidConfigStorage(kubeClient, secretNN, encryptionKey)
+	if err != nil {
+		return fmt.Errorf("failed to create last valid configuration storage: %w", err)
+	}
+	fetcher.EnablePersistence(storage)
+	if err := fetcher.LoadPersistedConfig(ctx, logger); err != nil {
+		logger.Error(err, "failed to load persisted last valid configuration", "secret", secretNN)
+	}
+	return nil
+}
+
+// canaryRolloutConfig returns the configuration of staged rollouts of configuration to Gateways.
+func canaryRolloutConfig(c *Config) dataplane.CanaryRolloutConfig {
+	healthChecks := []dataplane.CanaryHealthCheck{dataplane.CanaryStatusHealthCheck{}}
+	if c.ProxyCanaryProbeURL != "" {
+		healthChecks = append(healthChecks, dataplane.CanaryProbeHealthCheck{
+			URL:          c.ProxyCanaryProbeURL,
+			MaxErrorRate: c.ProxyCanaryProbeErrorRate,
+			Timeout:      time.Duration(c.ProxyTimeoutSeconds * float32(time.Second)),
+		})
+	}
+	return dataplane.CanaryRolloutConfig{
+		Percentage:   c.ProxyCanaryPercentage,
+		SoakTime:     c.ProxyCanarySoakTime,
+		HealthChecks: healthChecks,
+	}
+}
+
 func setupAdmissionServer(
 	ctx context.Context,
 	managerConfig *Config,
@@ -172,6 +256,8 @@ func setupAdmissionServer(
 	managerClient client.Client,
 	logger logr.Logger,
 	parserFeatures parser.FeatureFlags,
+	routesLister admission.RoutesLister,
+	credentialRegistry *credentials.Registry,
 ) error {
 	admissionLogger := logger.WithName("admission-server")
 
@@ -181,16 +267,32 @@ func setupAdmissionServer(
 	}
 
 	adminAPIServicesProvider := admission.NewDefaultAdminAPIServicesProvider(clientsManager)
-	srv, err := admission.MakeTLSServer(ctx, &managerConfig.AdmissionServer, &admission.RequestHandler{
+	var handler http.Handler = &admission.RequestHandler{
 		Validator: admission.NewKongHTTPValidator(
 			admissionLogger,
 			managerClient,
 			managerConfig.IngressClassName,
 			adminAPIServicesProvider,
 			parserFeatures,
+			routesLister,
+			managerConfig.AdmissionServer.RouteConflictPolicy,
+			credentialRegistry,
 		),
-		Logger: admissionLogger,
-	}, admissionLogger)
+		Linters: lint.DefaultLinters(managerClient, parserFeatures),
+		Logger:  admissionLogger,
+	}
+	if managerConfig.AdmissionServer.EnableMutation {
+		mux := http.NewServeMux()
+		mux.Handle("/", handler)
+		mux.Handle(admission.MutatingWebhookPath, admission.NewMutatingRequestHandler(
+			admissionLogger,
+			managerClient,
+			managerConfig.IngressClassName,
+			parserFeatures,
+		))
+		handler = mux
+	}
+	srv, err := admission.MakeTLSServer(ctx, &managerConfig.AdmissionServer, handler, admissionLogger)
 	if err != nil {
 		return err
 	}
@@ -12,6 +12,7 @@ import (
 	"github.com/kong/go-kong/kong"
 	"github.com/samber/lo"
 	"golang.org/x/sync/errgroup"
+	"k8s.io/apimachinery/pkg/util/sets"
 
 	"github.com/kong/kubernetes-ingress-controller/v2/internal/adminapi"
 )
@@ -22,6 +23,8 @@ type KongStartUpOptions struct {
 	DBMode       string
 	RouterFlavor string
 	Version      kong.Version
+	// AvailablePlugins are names of plugins available on all Kong Gateways.
+	AvailablePlugins sets.Set[string]
 }
 
 // ValidateRoots checks if all provided kong roots are the same given that we
@@ -56,10 +59,25 @@ func ValidateRoots(roots []Root, skipCACerts bool) (*KongStartUpOptions, error)
 		return nil, err
 	}
 
+	// Kong Gateways may have different plugins installed, so only those available on all of them can be used.
+	var availablePlugins sets.Set[string]
+	for i, root := range roots {
+		plugins, err := AvailablePluginsFromRoot(root)
+		if err != nil {
+			return nil, err
+		}
+		if i == 0 {
+			availablePlugins = plugins
+			continue
+		}
+		availablePlugins = availablePlugins.Intersection(plugins)
+	}
+
 	return &KongStartUpOptions{
-		DBMode:       dbMode,
-		RouterFlavor: routerFlavor,
-		Version:      kongVersion,
+		DBMode:           dbMode,
+		RouterFlavor:     routerFlavor,
+		Version:          kongVersion,
+		AvailablePlugins: availablePlugins,
 	}, nil
 }
 
@@ -112,6 +130,20 @@ func RouterFlavorFromRoot(r Root) (string, error) {
 	return routerFlavorStr, nil
 }
 
+// AvailablePluginsFromRoot returns names of plugins available on the Kong Gateway.
+func AvailablePluginsFromRoot(r Root) (sets.Set[string], error) {
+	const pluginsKey, availableOnServerKey = "plugins", "available_on_server"
+	plugins, ok := r[pluginsKey].(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf("invalid %q type, expected a map[string]any got %T", pluginsKey, r[pluginsKey])
+	}
+	availableOnServer, ok := plugins[availableOnServerKey].(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf("invalid %q type, expected a map[string]any got %T", availableOnServerKey, plugins[availableOnServerKey])
+	}
+	return sets.KeySet(availableOnServer), nil
+}
+
 func KongVersionFromRoot(r Root) (kong.Version, error) {
 	v := kong.VersionFromInfo(r)
 	kv, err := kong.ParseSemanticVersion(v)
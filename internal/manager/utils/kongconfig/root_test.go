@@ -6,6 +6,7 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/util/sets"
 
 	"github.com/kong/kubernetes-ingress-controller/v2/internal/versions"
 )
@@ -44,10 +45,29 @@ func TestValidateRoots(t *testing.T) {
 			assert.Equal(t, tc.expectedDBMode, kongOptions.DBMode)
 			assert.Equal(t, tc.expectedRouterFlavor, kongOptions.RouterFlavor)
 			assert.Equal(t, tc.expectedKongVersion, kongOptions.Version.String())
+			assert.True(t, kongOptions.AvailablePlugins.Has("pre-function"))
 		})
 	}
 }
 
+func TestValidateRootsAvailablePlugins(t *testing.T) {
+	var root Root
+	require.NoError(t, json.Unmarshal([]byte(dblessConfigJSON3_4_1), &root))
+
+	// Another Kong Gateway with only some of the plugins installed.
+	var otherRoot Root
+	require.NoError(t, json.Unmarshal([]byte(dblessConfigJSON3_4_1), &otherRoot))
+	otherRoot["plugins"] = map[string]any{
+		"available_on_server": map[string]any{
+			"prometheus": map[string]any{"version": "3.4.1"},
+		},
+	}
+
+	kongOptions, err := ValidateRoots([]Root{root, otherRoot}, false)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"prometheus"}, sets.List(kongOptions.AvailablePlugins))
+}
+
 const dblessConfigJSON3_4_1 = `
 {
 	"node_id": "69d063c5-761b-4bab-a426-c89da49a9409",
@@ -136,6 +136,7 @@ type CacheStores struct {
 	KongIngress                    cache.Store
 	TCPIngress                     cache.Store
 	UDPIngress                     cache.Store
+	KongUpstreamPolicy             cache.Store
 	IngressClassParametersV1alpha1 cache.Store
 
 	l *sync.RWMutex
@@ -166,6 +167,7 @@ func NewCacheStores() CacheStores {
 		KongIngress:                    cache.NewStore(keyFunc),
 		TCPIngress:                     cache.NewStore(keyFunc),
 		UDPIngress:                     cache.NewStore(keyFunc),
+		KongUpstreamPolicy:             cache.NewStore(keyFunc),
 		IngressClassParametersV1alpha1: cache.NewStore(keyFunc),
 
 		l: &sync.RWMutex{},
@@ -267,6 +269,8 @@ func (c CacheStores) Get(obj runtime.Object) (item interface{}, exists bool, err
 		return c.TCPIngress.Get(obj)
 	case *kongv1beta1.UDPIngress:
 		return c.UDPIngress.Get(obj)
+	case *kongv1beta1.KongUpstreamPolicy:
+		return c.KongUpstreamPolicy.Get(obj)
 	case *kongv1alpha1.IngressClassParameters:
 		return c.IngressClassParametersV1alpha1.Get(obj)
 	}
@@ -327,6 +331,8 @@ func (c CacheStores) Add(obj runtime.Object) error {
 		return c.TCPIngress.Add(obj)
 	case *kongv1beta1.UDPIngress:
 		return c.UDPIngress.Add(obj)
+	case *kongv1beta1.KongUpstreamPolicy:
+		return c.KongUpstreamPolicy.Add(obj)
 	case *kongv1alpha1.IngressClassParameters:
 		return c.IngressClassParametersV1alpha1.Add(obj)
 	default:
@@ -388,6 +394,8 @@ func (c CacheStores) Delete(obj runtime.Object) error {
 		return c.TCPIngress.Delete(obj)
 	case *kongv1beta1.UDPIngress:
 		return c.UDPIngress.Delete(obj)
+	case *kongv1beta1.KongUpstreamPolicy:
+		return c.KongUpstreamPolicy.Delete(obj)
 	case *kongv1alpha1.IngressClassParameters:
 		return c.IngressClassParametersV1alpha1.Delete(obj)
 	default:
@@ -395,6 +403,55 @@ func (c CacheStores) Delete(obj runtime.Object) error {
 	}
 }
 
+// ListAllStores returns all the cache.Stores held by the CacheStores.
+func (c CacheStores) ListAllStores() []cache.Store {
+	return []cache.Store{
+		c.IngressV1,
+		c.IngressClassV1,
+		c.Service,
+		c.Secret,
+		c.EndpointSlice,
+		c.HTTPRoute,
+		c.UDPRoute,
+		c.TCPRoute,
+		c.TLSRoute,
+		c.GRPCRoute,
+		c.ReferenceGrant,
+		c.Gateway,
+		c.Plugin,
+		c.ClusterPlugin,
+		c.Consumer,
+		c.ConsumerGroup,
+		c.KongIngress,
+		c.TCPIngress,
+		c.UDPIngress,
+		c.KongUpstreamPolicy,
+		c.IngressClassParametersV1alpha1,
+	}
+}
+
+// TakeSnapshot returns new CacheStores holding the objects currently stored in the CacheStores.
+// Later changes of the CacheStores don't affect the snapshot. Objects aren't copied, as objects
+// in the CacheStores are only ever replaced, never modified in place.
+func (c CacheStores) TakeSnapshot() (CacheStores, error) {
+	c.l.RLock()
+	defer c.l.RUnlock()
+
+	snapshot := NewCacheStores()
+	for _, s := range c.ListAllStores() {
+		for _, item := range s.List() {
+			obj, ok := item.(runtime.Object)
+			if !ok {
+				return CacheStores{}, fmt.Errorf("%T is not a runtime.Object", item)
+			}
+			if err := snapshot.Add(obj); err != nil {
+				return CacheStores{}, err
+			}
+		}
+	}
+	return snapshot, nil
+}
+
 // New creates a new object store to be used in the ingress controller.
 func New(cs CacheStores, ingressClass string, logger logr.Logger) Storer {
 	return Store{
@@ -1015,6 +1072,8 @@ func mkObjFromGVK(gvk schema.GroupVersionKind) (runtime.Object, error) {
 		return &kongv1.KongConsumer{}, nil
 	case kongv1beta1.SchemeGroupVersion.WithKind("KongConsumerGroup"):
 		return &kongv1beta1.KongConsumerGroup{}, nil
+	case kongv1beta1.SchemeGroupVersion.WithKind("KongUpstreamPolicy"):
+		return &kongv1beta1.KongUpstreamPolicy{}, nil
 	case kongv1alpha1.SchemeGroupVersion.WithKind("IngressClassParameters"):
 		return &kongv1alpha1.IngressClassParameters{}, nil
 	default:
@@ -89,6 +89,32 @@ spec:
 	require.NotEmpty(t, gotIng.TypeMeta.Kind)
 }
 
+func TestCacheStoresTakeSnapshot(t *testing.T) {
+	svc := &corev1.Service{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "svc"}}
+	ing := &netv1.Ingress{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "ing"}}
+	cs := NewCacheStores()
+	require.NoError(t, cs.Add(svc))
+	require.NoError(t, cs.Add(ing))
+
+	snapshot, err := cs.TakeSnapshot()
+	require.NoError(t, err)
+
+	t.Log("verifying that the snapshot holds the same objects")
+	got, exists, err := snapshot.Get(svc)
+	require.NoError(t, err)
+	require.True(t, exists)
+	assert.Equal(t, svc, got)
+	assert.Len(t, snapshot.IngressV1.List(), 1)
+
+	t.Log("verifying that the snapshot isn't affected by later changes")
+	require.NoError(t, cs.Delete(svc))
+	require.NoError(t, cs.Add(&corev1.Secret{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "secret"}}))
+	_, exists, err = snapshot.Get(svc)
+	require.NoError(t, err)
+	assert.True(t, exists)
+	assert.Empty(t, snapshot.Secret.List())
+}
+
 func TestGetIngressClassHandling(t *testing.T) {
 	tests := []struct {
 		name string
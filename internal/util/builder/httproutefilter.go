@@ -94,3 +94,52 @@ func (b *HTTPRouteFilterBuilder) WithRequestHeaderRemove(headerNames []string) *
 	b.httpRouteFilter.RequestHeaderModifier.Remove = headerNames
 	return b
 }
+
+// NewHTTPRouteURLRewriteFilter builds a URL rewrite HTTPRoute filter.
+func NewHTTPRouteURLRewriteFilter() *HTTPRouteFilterBuilder {
+	filter := gatewayapi.HTTPRouteFilter{
+		Type:       gatewayapi.HTTPRouteFilterURLRewrite,
+		URLRewrite: &gatewayapi.HTTPURLRewriteFilter{},
+	}
+	return &HTTPRouteFilterBuilder{httpRouteFilter: filter}
+}
+
+// WithURLRewriteHostname sets hostname of URL rewrite filter.
+func (b *HTTPRouteFilterBuilder) WithURLRewriteHostname(hostname string) *HTTPRouteFilterBuilder {
+	if b.httpRouteFilter.Type != gatewayapi.HTTPRouteFilterURLRewrite ||
+		b.httpRouteFilter.URLRewrite == nil {
+		return b
+	}
+
+	preciseHostname := (gatewayapi.PreciseHostname)(hostname)
+	b.httpRouteFilter.URLRewrite.Hostname = lo.ToPtr(preciseHostname)
+	return b
+}
+
+// WithURLRewriteFullPath sets the path of URL rewrite filter to replace the full path.
+func (b *HTTPRouteFilterBuilder) WithURLRewriteFullPath(path string) *HTTPRouteFilterBuilder {
+	if b.httpRouteFilter.Type != gatewayapi.HTTPRouteFilterURLRewrite ||
+		b.httpRouteFilter.URLRewrite == nil {
+		return b
+	}
+
+	b.httpRouteFilter.URLRewrite.Path = &gatewayapi.HTTPPathModifier{
+		Type:            gatewayapi.FullPathHTTPPathModifier,
+		ReplaceFullPath: lo.ToPtr(path),
+	}
+	return b
+}
+
+// WithURLRewritePrefixMatch sets the path of URL rewrite filter to replace the matched path prefix.
+func (b *HTTPRouteFilterBuilder) WithURLRewritePrefixMatch(prefix string) *HTTPRouteFilterBuilder {
+	if b.httpRouteFilter.Type != gatewayapi.HTTPRouteFilterURLRewrite ||
+		b.httpRouteFilter.URLRewrite == nil {
+		return b
+	}
+
+	b.httpRouteFilter.URLRewrite.Path = &gatewayapi.HTTPPathModifier{
+		Type:               gatewayapi.PrefixMatchHTTPPathModifier,
+		ReplacePrefixMatch: lo.ToPtr(prefix),
+	}
+	return b
+}
@@ -1,6 +1,12 @@
 package util
 
-import "github.com/kong/deck/file"
+import (
+	"time"
+
+	"github.com/kong/deck/file"
+
+	"github.com/kong/kubernetes-ingress-controller/v2/internal/dataplane/failures"
+)
 
 // ConfigDump contains a config dump and a flag indicating that the config was not successfully applid.
 type ConfigDump struct {
@@ -8,8 +14,19 @@ type ConfigDump struct {
 	Failed bool
 }
 
+// FailuresDump contains resource failures that occurred during a single configuration sync.
+type FailuresDump struct {
+	// SyncTimestamp is the time the sync that produced the failures started at.
+	SyncTimestamp time.Time
+	// TranslationFailures are failures of translating Kubernetes objects into Kong configuration.
+	TranslationFailures []failures.ResourceFailure
+	// KongFailures are failures of Kubernetes objects whose Kong entities were rejected by Kong.
+	KongFailures []failures.ResourceFailure
+}
+
 // ConfigDumpDiagnostic contains settings and channels for receiving diagnostic configuration dumps.
 type ConfigDumpDiagnostic struct {
 	DumpsIncludeSensitive bool
 	Configs               chan ConfigDump
+	Failures              chan FailuresDump
 }
@@ -32,6 +32,7 @@ import (
 
 	"github.com/kong/kubernetes-ingress-controller/v2/internal/annotations"
 	"github.com/kong/kubernetes-ingress-controller/v2/internal/gatewayapi"
+	kongv1 "github.com/kong/kubernetes-ingress-controller/v2/pkg/apis/configuration/v1"
 )
 
 // ParseNameNS parses a string searching a namespace and name.
@@ -147,6 +148,12 @@ func IsBackendRefGroupKindSupported(gatewayAPIGroup *gatewayapi.Group, gatewayAP
 	return ok
 }
 
+// IsKongPluginExtensionRef checks if the object referenced by an ExtensionRef
+// filter of an HTTPRoute is a KongPlugin.
+func IsKongPluginExtensionRef(ref gatewayapi.LocalObjectReference) bool {
+	return string(ref.Group) == kongv1.GroupVersion.Group && string(ref.Kind) == "KongPlugin"
+}
+
 const (
 	K8sNamespaceTagPrefix = "k8s-namespace:"
 	K8sNameTagPrefix      = "k8s-name:"
@@ -0,0 +1,12 @@
+package object
+
+import "time"
+
+// CredentialRotation describes a rotated credential of a KongConsumer whose previous value is kept in the
+// configuration applied to Kong until the grace period of the rotation ends.
+type CredentialRotation struct {
+	// Secret is the name of the credential Secret.
+	Secret string
+	// ExpiresAt is the time the grace period of the rotation ends at.
+	ExpiresAt time.Time
+}
@@ -12,6 +12,7 @@ type (
 type objectConfigurationStatus struct {
 	generation int64
 	succeeded  bool
+	fallback   FallbackStatus
 }
 
 type ConfigurationStatus string
@@ -22,6 +23,19 @@ const (
 	ConfigurationStatusUnknown   ConfigurationStatus = "Unknown"
 )
 
+// FallbackStatus tells how an object excluded from the configuration because Kong rejected it
+// (or an object it depends on) is represented in the fallback configuration applied instead.
+type FallbackStatus string
+
+const (
+	// FallbackStatusNotExcluded is used for objects which weren't excluded from the configuration.
+	FallbackStatusNotExcluded FallbackStatus = ""
+	// FallbackStatusRemoved is used for objects left out of the fallback configuration.
+	FallbackStatusRemoved FallbackStatus = "Removed"
+	// FallbackStatusLastValid is used for objects whose last valid version was used in the fallback configuration.
+	FallbackStatusLastValid FallbackStatus = "LastValid"
+)
+
 // ConfigurationStatusSet is a de-duplicate set to store the configure status
 // (succeeded, failed, unknown) of kubernetes objects.
 type ConfigurationStatusSet struct {
@@ -35,6 +49,22 @@ func NewConfigurationStatusSet() *ConfigurationStatusSet {
 }
 
 func (s *ConfigurationStatusSet) Insert(obj client.Object, succeeded bool) {
+	s.insert(obj, objectConfigurationStatus{
+		generation: obj.GetGeneration(),
+		succeeded:  succeeded,
+	})
+}
+
+// InsertExcluded stores the object as failed to be configured and excluded from the configuration,
+// with the fallback status telling how it is represented in the fallback configuration.
+func (s *ConfigurationStatusSet) InsertExcluded(obj client.Object, fallback FallbackStatus) {
+	s.insert(obj, objectConfigurationStatus{
+		generation: obj.GetGeneration(),
+		fallback:   fallback,
+	})
+}
+
+func (s *ConfigurationStatusSet) insert(obj client.Object, status objectConfigurationStatus) {
 	if s.store == nil {
 		s.store = make(map[gvk]map[k8stypes.NamespacedName]objectConfigurationStatus)
 	}
@@ -47,17 +77,38 @@ func (s *ConfigurationStatusSet) Insert(obj client.Object, succeeded bool) {
 	if s.store[objGVK] == nil {
 		s.store[objGVK] = make(map[k8stypes.NamespacedName]objectConfigurationStatus)
 	}
-	s.store[objGVK][nsName] = objectConfigurationStatus{
-		generation: obj.GetGeneration(),
-		succeeded:  succeeded,
-	}
+	s.store[objGVK][nsName] = status
 }
 
 func (s *ConfigurationStatusSet) Get(obj client.Object) ConfigurationStatus {
-	if s.store == nil {
+	status, ok := s.get(obj)
+	if !ok {
 		return ConfigurationStatusUnknown
 	}
 
+	if !status.succeeded {
+		return ConfigurationStatusFailed
+	}
+
+	return ConfigurationStatusSucceeded
+}
+
+// GetFallbackStatus returns how the object is represented in the fallback configuration. FallbackStatusNotExcluded
+// is returned when the object wasn't excluded from the configuration or its status is unknown.
+func (s *ConfigurationStatusSet) GetFallbackStatus(obj client.Object) FallbackStatus {
+	status, ok := s.get(obj)
+	if !ok {
+		return FallbackStatusNotExcluded
+	}
+	return status.fallback
+}
+
+// get returns the stored status of the object unless it's unknown.
+func (s *ConfigurationStatusSet) get(obj client.Object) (objectConfigurationStatus, bool) {
+	if s.store == nil {
+		return objectConfigurationStatus{}, false
+	}
+
 	objGVK := gvk(obj.GetObjectKind().GroupVersionKind().String())
 	nsName := k8stypes.NamespacedName{
 		Namespace: obj.GetNamespace(),
@@ -66,23 +117,19 @@ func (s *ConfigurationStatusSet) Get(obj client.Object) ConfigurationStatus {
 
 	gvkMap, ok := s.store[objGVK]
 	if !ok {
-		return ConfigurationStatusUnknown
+		return objectConfigurationStatus{}, false
 	}
 
 	status, ok := gvkMap[nsName]
 	if !ok {
-		return ConfigurationStatusUnknown
+		return objectConfigurationStatus{}, false
 	}
 
 	// if the object generation is newer than the generation of current configuration,
 	// the latest specification of the object may still not configured on Kong gateway, so "Unknown" is returned.
 	if status.generation < obj.GetGeneration() {
-		return ConfigurationStatusUnknown
+		return objectConfigurationStatus{}, false
 	}
 
-	if !status.succeeded {
-		return ConfigurationStatusFailed
-	}
-
-	return ConfigurationStatusSucceeded
+	return status, true
 }
@@ -83,6 +83,47 @@ func TestObjectConfigurationStatusSet(t *testing.T) {
 	require.Equal(t, ConfigurationStatusSucceeded, set.Get(tcp))
 }
 
+func TestObjectConfigurationStatusSet_Excluded(t *testing.T) {
+	ing := &netv1.Ingress{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:  corev1.NamespaceDefault,
+			Name:       "test-ingress",
+			Generation: 1,
+		},
+	}
+	ing.SetGroupVersionKind(ingGVK)
+	tcp := &kongv1beta1.TCPIngress{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:  corev1.NamespaceDefault,
+			Name:       "test-tcpingress",
+			Generation: 1,
+		},
+	}
+	tcp.SetGroupVersionKind(tcpGVK)
+
+	set := &ConfigurationStatusSet{}
+	require.Equal(t, FallbackStatusNotExcluded, set.GetFallbackStatus(ing))
+
+	t.Log("verifying that excluded objects are reported as failed")
+	set.InsertExcluded(ing, FallbackStatusLastValid)
+	set.Insert(tcp, true)
+	require.Equal(t, ConfigurationStatusFailed, set.Get(ing))
+	require.Equal(t, FallbackStatusLastValid, set.GetFallbackStatus(ing))
+	require.Equal(t, FallbackStatusNotExcluded, set.GetFallbackStatus(tcp))
+
+	t.Log("verifying that inserting an object again overrides its fallback status")
+	set.Insert(ing, true)
+	require.Equal(t, ConfigurationStatusSucceeded, set.Get(ing))
+	require.Equal(t, FallbackStatusNotExcluded, set.GetFallbackStatus(ing))
+	set.InsertExcluded(ing, FallbackStatusRemoved)
+	require.Equal(t, FallbackStatusRemoved, set.GetFallbackStatus(ing))
+
+	t.Log("verifying that the fallback status of newer generations is not known")
+	ing.Generation = 2
+	require.Equal(t, ConfigurationStatusUnknown, set.Get(ing))
+	require.Equal(t, FallbackStatusNotExcluded, set.GetFallbackStatus(ing))
+}
+
 // -----------------------------------------------------------------------------
 // Testing Utilities
 // -----------------------------------------------------------------------------
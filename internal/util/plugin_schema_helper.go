@@ -3,21 +3,26 @@ package util
 import (
 	"context"
 	"fmt"
+	"sync"
 
 	"github.com/kong/go-kong/kong"
 )
 
 // PluginSchemaStore retrives a schema of a Plugin from Kong.
+// It also retrieves schemas of entities provided by plugins (e.g. credentials of consumers).
 type PluginSchemaStore struct {
-	client  *kong.Client
-	schemas map[string]map[string]interface{}
+	client        *kong.Client
+	lock          sync.RWMutex
+	schemas       map[string]map[string]interface{}
+	entitySchemas map[string]map[string]interface{}
 }
 
 // NewPluginSchemaStore creates a PluginSchemaStore.
 func NewPluginSchemaStore(client *kong.Client) *PluginSchemaStore {
 	return &PluginSchemaStore{
-		client:  client,
-		schemas: make(map[string]map[string]interface{}),
+		client:        client,
+		schemas:       make(map[string]map[string]interface{}),
+		entitySchemas: make(map[string]map[string]interface{}),
 	}
 }
 
@@ -30,7 +35,10 @@ func (p *PluginSchemaStore) Schema(ctx context.Context, pluginName string) (map[
 	}
 
 	// lookup in cache
-	if schema, ok := p.schemas[pluginName]; ok {
+	p.lock.RLock()
+	schema, ok := p.schemas[pluginName]
+	p.lock.RUnlock()
+	if ok {
 		return schema, nil
 	}
 
@@ -39,6 +47,32 @@ func (p *PluginSchemaStore) Schema(ctx context.Context, pluginName string) (map[
 	if err != nil {
 		return nil, err
 	}
+	p.lock.Lock()
 	p.schemas[pluginName] = schema
+	p.lock.Unlock()
+	return schema, nil
+}
+
+// EntitySchema retrieves schema of an entity (e.g. keyauth_credentials).
+// Like in Schema, the responses are cached.
+func (p *PluginSchemaStore) EntitySchema(ctx context.Context, entityName string) (map[string]interface{}, error) {
+	if entityName == "" {
+		return nil, fmt.Errorf("entityName can not be empty")
+	}
+
+	p.lock.RLock()
+	schema, ok := p.entitySchemas[entityName]
+	p.lock.RUnlock()
+	if ok {
+		return schema, nil
+	}
+
+	schema, err := p.client.Schemas.Get(ctx, entityName)
+	if err != nil {
+		return nil, err
+	}
+	p.lock.Lock()
+	p.entitySchemas[entityName] = schema
+	p.lock.Unlock()
 	return schema, nil
 }
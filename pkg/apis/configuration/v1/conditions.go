@@ -52,3 +52,55 @@ const (
 	// ReasonPending is used with the ConditionProgrammed when the status is "Unknown".
 	ReasonPending ConditionReason = "Pending"
 )
+
+const (
+	// ConditionExcluded indicates that the current version of the object has been excluded from
+	// the configuration applied to Kong, because Kong rejected the configuration generated from
+	// the object or from an object it depends on. It's only set when the fallback configuration
+	// (FallbackConfiguration feature gate) is enabled.
+	//
+	// Resources that support this condition are:
+	//
+	// * KongConsumer
+	// * KongConsumerGroup
+	//
+	// The condition is only present on the resource when it's True.
+	//
+	// Possible reasons for this condition to be True are:
+	//
+	// * "Removed"
+	// * "LastValidVersionUsed"
+	//
+	ConditionExcluded ConditionType = "Excluded"
+
+	// ReasonRemoved is used with the ConditionExcluded condition when the object has been left
+	// out of the configuration.
+	ReasonRemoved ConditionReason = "Removed"
+
+	// ReasonLastValidVersionUsed is used with the ConditionExcluded condition when the last valid
+	// version of the object has been used in the configuration instead of the current one.
+	ReasonLastValidVersionUsed ConditionReason = "LastValidVersionUsed"
+)
+
+const (
+	// ConditionCredentialRotationInProgress indicates that previous values of the object's rotated
+	// credentials are kept in the configuration applied to Kong until the grace periods of their
+	// rotations, declared with the konghq.com/credential-rotation-grace-period annotation of
+	// credential Secrets, end.
+	//
+	// Resources that support this condition are:
+	//
+	// * KongConsumer
+	//
+	// The condition is only present on the resource when it's True.
+	//
+	// Possible reasons for this condition to be True are:
+	//
+	// * "PreviousCredentialsKept"
+	//
+	ConditionCredentialRotationInProgress ConditionType = "CredentialRotationInProgress"
+
+	// ReasonPreviousCredentialsKept is used with the ConditionCredentialRotationInProgress condition
+	// when previous values of rotated credentials are kept in the configuration.
+	ReasonPreviousCredentialsKept ConditionReason = "PreviousCredentialsKept"
+)
@@ -14,6 +14,33 @@ type NamespacedConfigSource struct {
 	SecretValue NamespacedSecretValueFromSource `json:"secretKeyRef,omitempty"`
 }
 
+// ConfigPatch is a JSON patch (RFC6902) to add values from Secret to the generated configuration.
+// It is an equivalent of the following patch:
+// `{"op": "add", "path": {.Path}, "value": {.ComputedValueFrom}}`.
+// +kubebuilder:object:generate=true
+type ConfigPatch struct {
+	// Path is the JSON-Pointer value (RFC6901) that references a location within the target configuration.
+	// +kubebuilder:validation:Required
+	Path string `json:"path"`
+	// ValueFrom is the reference to a key of a secret where the patched value comes from.
+	// The value must be a valid JSON document.
+	// +kubebuilder:validation:Required
+	ValueFrom ConfigSource `json:"valueFrom"`
+}
+
+// NamespacedConfigPatch is a JSON patch to add values from secrets to KongClusterPlugin
+// to the generated configuration of plugin in Kong.
+// +kubebuilder:object:generate=true
+type NamespacedConfigPatch struct {
+	// Path is the JSON-Pointer value (RFC6901) that references a location within the target configuration.
+	// +kubebuilder:validation:Required
+	Path string `json:"path"`
+	// ValueFrom is the reference to a key of a secret where the patched value comes from.
+	// The value must be a valid JSON document.
+	// +kubebuilder:validation:Required
+	ValueFrom NamespacedConfigSource `json:"valueFrom"`
+}
+
 // SecretValueFromSource represents the source of a secret value.
 // +kubebuilder:object:generate=true
 type SecretValueFromSource struct {
@@ -48,6 +48,8 @@ type KongConsumer struct {
 	Credentials []string `json:"credentials,omitempty"`
 	// ConsumerGroups are references to consumer groups (that consumer wants to be part of)
 	// provisioned in Kong.
+	// Consumer groups in other namespaces are referenced as <namespace>/<name>. Such references
+	// have to be permitted by a ReferenceGrant in the namespace of the consumer group.
 	ConsumerGroups []string `json:"consumerGroups,omitempty"`
 
 	// Status represents the current status of the KongConsumer resource.
@@ -70,6 +72,7 @@ type KongConsumerStatus struct {
 	// Known condition types are:
 	//
 	// * "Programmed"
+	// * "CredentialRotationInProgress"
 	//
 	// +listType=map
 	// +listMapKey=type
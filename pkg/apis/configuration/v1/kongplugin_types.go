@@ -62,6 +62,15 @@ type KongPlugin struct {
 	// Only one of `config` or `configFrom` may be used in a KongPlugin, not both at once.
 	ConfigFrom *ConfigSource `json:"configFrom,omitempty"`
 
+	// ConfigPatches represents JSON patches to the configuration of the plugin.
+	// Each item means a JSON patch to add something in the configuration,
+	// where path is specified in `path` and value is in `valueFrom` referencing
+	// a key in a secret.
+	// When Config is specified, patches will be applied to the configuration in Config.
+	// Otherwise, patches will be applied to an empty object.
+	// `configFrom` and `configPatches` may not be used in a KongPlugin at the same time.
+	ConfigPatches []ConfigPatch `json:"configPatches,omitempty"`
+
 	// PluginName is the name of the plugin to which to apply the config.
 	// +kubebuilder:validation:Required
 	PluginName string `json:"plugin,omitempty"`
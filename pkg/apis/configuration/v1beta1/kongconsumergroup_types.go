@@ -17,6 +17,7 @@ limitations under the License.
 package v1beta1
 
 import (
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -35,6 +36,9 @@ type KongConsumerGroup struct {
 	metav1.TypeMeta   `json:",inline"`
 	metav1.ObjectMeta `json:"metadata,omitempty"`
 
+	// Spec contains the KongConsumerGroup configuration.
+	Spec KongConsumerGroupSpec `json:"spec,omitempty"`
+
 	// Status represents the current status of the KongConsumer resource.
 	Status KongConsumerGroupStatus `json:"status,omitempty"`
 }
@@ -48,6 +52,29 @@ type KongConsumerGroupList struct {
 	Items           []KongConsumerGroup `json:"items"`
 }
 
+// KongConsumerGroupSpec defines the desired state of KongConsumerGroup.
+type KongConsumerGroupSpec struct {
+	// Plugins are overrides of plugins' configuration for consumers in the group,
+	// e.g. limits of the rate-limiting-advanced plugin for a tier of consumers.
+	// The overridden plugins still have to be configured with KongPlugins or KongClusterPlugins.
+	// +listType=map
+	// +listMapKey=plugin
+	Plugins []KongConsumerGroupPlugin `json:"plugins,omitempty"`
+}
+
+// KongConsumerGroupPlugin is an override of a plugin's configuration for consumers in a KongConsumerGroup.
+type KongConsumerGroupPlugin struct {
+	// PluginName is the name of the plugin whose configuration is overridden.
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	PluginName string `json:"plugin"`
+
+	// Config contains the plugin configuration overriding the one of the plugin
+	// for consumers in the group.
+	// +kubebuilder:validation:Type=object
+	Config apiextensionsv1.JSON `json:"config,omitempty"`
+}
+
 // KongConsumerGroupStatus represents the current status of the KongConsumerGroup resource.
 type KongConsumerGroupStatus struct {
 	// Conditions describe the current conditions of the KongConsumerGroup.
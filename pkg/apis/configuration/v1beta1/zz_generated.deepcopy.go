@@ -81,6 +81,7 @@ func (in *KongConsumerGroup) DeepCopyInto(out *KongConsumerGroup) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
 	in.Status.DeepCopyInto(&out.Status)
 }
 
@@ -134,6 +135,44 @@ func (in *KongConsumerGroupList) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KongConsumerGroupPlugin) DeepCopyInto(out *KongConsumerGroupPlugin) {
+	*out = *in
+	in.Config.DeepCopyInto(&out.Config)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KongConsumerGroupPlugin.
+func (in *KongConsumerGroupPlugin) DeepCopy() *KongConsumerGroupPlugin {
+	if in == nil {
+		return nil
+	}
+	out := new(KongConsumerGroupPlugin)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KongConsumerGroupSpec) DeepCopyInto(out *KongConsumerGroupSpec) {
+	*out = *in
+	if in.Plugins != nil {
+		in, out := &in.Plugins, &out.Plugins
+		*out = make([]KongConsumerGroupPlugin, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KongConsumerGroupSpec.
+func (in *KongConsumerGroupSpec) DeepCopy() *KongConsumerGroupSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(KongConsumerGroupSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *KongConsumerGroupStatus) DeepCopyInto(out *KongConsumerGroupStatus) {
 	*out = *in
@@ -39,18 +39,10 @@ var commonSkippedTests = []string{
 	tests.HTTPRouteRequestMirror.ShortName,
 	// https://github.com/Kong/kubernetes-ingress-controller/issues/4165
 	tests.HTTPRouteRequestMultipleMirrors.ShortName,
-	// https://github.com/Kong/kubernetes-ingress-controller/issues/4914
-	tests.HTTPRouteTimeoutBackendRequest.ShortName,
-	// https://github.com/Kong/kubernetes-ingress-controller/issues/4915
-	tests.HTTPRouteTimeoutRequest.ShortName,
 
 	// experimental conformance
 	// https://github.com/Kong/kubernetes-ingress-controller/issues/3684
 	tests.HTTPRouteRedirectPath.ShortName,
-	// https://github.com/Kong/kubernetes-ingress-controller/issues/3685
-	tests.HTTPRouteRewriteHost.ShortName,
-	// https://github.com/Kong/kubernetes-ingress-controller/issues/3686
-	tests.HTTPRouteRewritePath.ShortName,
 
 	// TLS
 	// https://github.com/Kong/kubernetes-ingress-controller/issues/4562
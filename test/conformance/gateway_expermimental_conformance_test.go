@@ -44,6 +44,10 @@ func TestGatewayExperimentalConformance(t *testing.T) {
 					suite.SupportHTTPRouteQueryParamMatching,
 					suite.SupportHTTPRouteMethodMatching,
 					suite.SupportHTTPRouteResponseHeaderModification,
+					suite.SupportHTTPRouteHostRewrite,
+					suite.SupportHTTPRoutePathRewrite,
+					suite.SupportHTTPRouteRequestTimeout,
+					suite.SupportHTTPRouteBackendTimeout,
 				),
 			},
 			ConformanceProfiles: sets.New(
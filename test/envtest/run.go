@@ -55,6 +55,7 @@ func ConfigForEnvConfig(t *testing.T, envcfg *rest.Config, opts ...mocks.AdminAP
 	cfg.UpdateStatus = false
 	// Shorten the wait in tests.
 	cfg.ProxySyncSeconds = 0.1
+	cfg.ProxySyncDebounce = 10 * time.Millisecond
 	cfg.InitCacheSyncDuration = 0
 
 	p, err := freeport.GetFreePort()
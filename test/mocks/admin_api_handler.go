@@ -184,6 +184,14 @@ func (m *AdminAPIHandler) WasWorkspaceCreated() bool {
 func formatDefaultDBLessRootResponse(version string) []byte {
 	const defaultDBLessRootResponse = `{
 		"version": "%s",
+		"plugins": {
+			"available_on_server": {
+				"pre-function": {
+					"version": "3.4.1",
+					"priority": 1000000
+				}
+			}
+		},
 		"configuration": {
 			"database": "off",
 			"router_flavor": "traditional",
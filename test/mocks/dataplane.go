@@ -43,6 +43,14 @@ func (d Dataplane) KubernetesObjectConfigurationStatus(obj client.Object) k8sobj
 	return d.ObjectsStatuses[obj.GetNamespace()][obj.GetName()]
 }
 
+func (d Dataplane) KubernetesObjectFallbackStatus(client.Object) k8sobj.FallbackStatus {
+	return k8sobj.FallbackStatusNotExcluded
+}
+
+func (d Dataplane) KubernetesObjectCredentialRotations(client.Object) []k8sobj.CredentialRotation {
+	return nil
+}
+
 func (d Dataplane) KubernetesObjectIsConfigured(obj client.Object) bool {
 	return d.ObjectsStatuses[obj.GetNamespace()][obj.GetName()] == k8sobj.ConfigurationStatusSucceeded
 }